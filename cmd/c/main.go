@@ -268,7 +268,7 @@ func GetFacilities(planetID C.int) (roboticsFactory, shipyard, researchLab, alli
 
 //export Build
 func Build(planetID, ogameID, nbr C.int) (errorMsg *C.char) {
-	err := bot.Build(ogame2.CelestialID(planetID), ogame2.ID(ogameID), int64(nbr))
+	_, err := bot.Build(ogame2.CelestialID(planetID), ogame2.ID(ogameID), int64(nbr))
 	if err != nil {
 		errorMsg = C.CString(err.Error())
 	}
@@ -295,7 +295,7 @@ func BuildProduction(planetID, ogameID, nbr C.int) (errorMsg *C.char) {
 
 //export BuildBuilding
 func BuildBuilding(planetID, buildingID C.int) (errorMsg *C.char) {
-	err := bot.BuildBuilding(ogame2.CelestialID(planetID), ogame2.ID(buildingID))
+	_, err := bot.BuildBuilding(ogame2.CelestialID(planetID), ogame2.ID(buildingID))
 	if err != nil {
 		errorMsg = C.CString(err.Error())
 	}
@@ -304,7 +304,7 @@ func BuildBuilding(planetID, buildingID C.int) (errorMsg *C.char) {
 
 //export BuildTechnology
 func BuildTechnology(planetID, technologyID C.int) (errorMsg *C.char) {
-	err := bot.BuildTechnology(ogame2.CelestialID(planetID), ogame2.ID(technologyID))
+	_, err := bot.BuildTechnology(ogame2.CelestialID(planetID), ogame2.ID(technologyID))
 	if err != nil {
 		errorMsg = C.CString(err.Error())
 	}
@@ -80,6 +80,7 @@ type OverviewExtractorBytes interface {
 	ExtractFleetDeutSaveFactor(pageHTML []byte) float64
 	ExtractOverviewProduction(pageHTML []byte) ([]ogame.Quantifiable, int64, error)
 	ExtractOverviewShipSumCountdownFromBytes(pageHTML []byte) int64
+	ExtractPlanetRelocation(pageHTML []byte) (ogame.PlanetRelocation, error)
 	ExtractUserInfos(pageHTML []byte) (ogame.UserInfos, error)
 }
 
@@ -242,11 +243,13 @@ type DefensesExtractorBytesDoc interface {
 type EventListExtractorBytes interface {
 	ExtractAttacks(pageHTML []byte, ownCoords []ogame.Coordinate) ([]ogame.AttackEvent, error)
 	ExtractFleetsFromEventList(pageHTML []byte) []ogame.Fleet
+	ExtractEventList(pageHTML []byte) ([]ogame.FleetEvent, error)
 }
 
 type EventListExtractorDoc interface {
 	ExtractAttacksFromDoc(doc *goquery.Document, ownCoords []ogame.Coordinate) ([]ogame.AttackEvent, error)
 	ExtractFleetsFromEventListFromDoc(doc *goquery.Document) []ogame.Fleet
+	ExtractEventListFromDoc(doc *goquery.Document) ([]ogame.FleetEvent, error)
 }
 
 type EventListExtractorBytesDoc interface {
@@ -263,6 +266,7 @@ type TraderAuctioneerExtractorBytes interface {
 // to activate an item on the overview page.
 type BuffActivationExtractorBytes interface {
 	ExtractBuffActivation(pageHTML []byte) (string, []ogame.Item, error)
+	ExtractShopItems(pageHTML []byte) (string, []ogame.ShopItem, error)
 }
 
 type MessagesCombatReportExtractorBytes interface {
@@ -281,6 +285,7 @@ type MessagesCombatReportExtractorBytesDoc interface {
 // DestroyRocketsExtractorBytes popups that shows up when clicking to destroy rockets on the defenses page.
 type DestroyRocketsExtractorBytes interface {
 	ExtractDestroyRockets(pageHTML []byte) (abm, ipm int64, token string, err error)
+	ExtractMissiles(pageHTML []byte) (ogame.Missiles, error)
 }
 
 type EmpireExtractorBytes interface {
@@ -330,6 +335,34 @@ type MessagesExpeditionExtractorBytesDoc interface {
 	MessagesExpeditionExtractorDoc
 }
 
+// MessagesOtherExtractorBytes ajax page that display the messages of the "Other" tab
+type MessagesOtherExtractorBytes interface {
+	ExtractOtherMessages(pageHTML []byte) ([]ogame.OtherMessage, int64, error)
+}
+
+type MessagesOtherExtractorDoc interface {
+	ExtractOtherMessagesFromDoc(doc *goquery.Document) ([]ogame.OtherMessage, int64, error)
+}
+
+type MessagesOtherExtractorBytesDoc interface {
+	MessagesOtherExtractorBytes
+	MessagesOtherExtractorDoc
+}
+
+// MessagesUnionsTransportExtractorBytes ajax page that display the messages of the "Unions/Transport" tab
+type MessagesUnionsTransportExtractorBytes interface {
+	ExtractUnionsTransportMessages(pageHTML []byte) ([]ogame.UnionsTransportMessage, int64, error)
+}
+
+type MessagesUnionsTransportExtractorDoc interface {
+	ExtractUnionsTransportMessagesFromDoc(doc *goquery.Document) ([]ogame.UnionsTransportMessage, int64, error)
+}
+
+type MessagesUnionsTransportExtractorBytesDoc interface {
+	MessagesUnionsTransportExtractorBytes
+	MessagesUnionsTransportExtractorDoc
+}
+
 // FederationExtractorBytes popup when we click to create a union for our attacking fleet
 type FederationExtractorBytes interface {
 	ExtractFederation(pageHTML []byte) url.Values
@@ -338,6 +371,7 @@ type FederationExtractorBytes interface {
 // GalaxyExtractorBytes ajax page containing galaxy information in galaxy page
 type GalaxyExtractorBytes interface {
 	ExtractGalaxyInfos(pageHTML []byte, botPlayerName string, botPlayerID, botPlayerRank int64) (ogame.SystemInfos, error)
+	ExtractPlanetMoveToken(pageHTML []byte) (string, error)
 }
 
 // FetchResourcesExtractorBytes "fetchResources" ajax page
@@ -348,12 +382,18 @@ type FetchResourcesExtractorBytes interface {
 // TraderImportExportExtractorBytes ajax page Merchant -> Import/Export
 type TraderImportExportExtractorBytes interface {
 	ExtractOfferOfTheDay(pageHTML []byte) (int64, string, ogame.PlanetResources, ogame.Multiplier, error)
+	ExtractOfferOfTheDayDetails(pageHTML []byte) (ogame.OfferOfTheDay, error)
 }
 
 type TraderImportExportExtractorDoc interface {
 	ExtractOfferOfTheDayFromDoc(doc *goquery.Document) (price int64, importToken string, planetResources ogame.PlanetResources, multiplier ogame.Multiplier, err error)
 }
 
+// TraderResourcesExtractorBytes ajax page Merchant -> Resources
+type TraderResourcesExtractorBytes interface {
+	ExtractTraderResources(pageHTML []byte) (ogame.TraderResources, error)
+}
+
 // FetchTechsExtractorBytes ajax page fetchTechs
 type FetchTechsExtractorBytes interface {
 	ExtractTechs(pageHTML []byte) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error)
@@ -409,6 +449,11 @@ type MessagesMarketplaceExtractorBytes interface {
 	ExtractMarketplaceMessages(pageHTML []byte) ([]ogame.MarketplaceMessage, int64, error)
 }
 
+// MarketplaceExtractorBytes listings of the marketplace buying and selling tabs
+type MarketplaceExtractorBytes interface {
+	ExtractMarketplaceOffers(pageHTML []byte) ([]ogame.MarketplaceOffer, int64, error)
+}
+
 type LfBuildingsExtractorBytes interface {
 	ExtractUpgradeToken(pageHTML []byte) (string, error)
 	ExtractLfBuildings(pageHTML []byte) (ogame.LfBuildings, error)
@@ -455,11 +500,23 @@ type ResourcesBuildingsExtractorBytesDoc interface {
 
 // PremiumExtractorBytes ajax page when click to buy an officer
 type PremiumExtractorBytes interface {
+	ExtractOfficers(pageHTML []byte) ([]ogame.Officer, error)
 	ExtractPremiumToken(pageHTML []byte, days int64) (token string, err error)
 }
 
+// PremiumExtractorDoc premium page
+type PremiumExtractorDoc interface {
+	ExtractOfficersFromDoc(doc *goquery.Document) ([]ogame.Officer, error)
+}
+
+type PremiumExtractorBytesDoc interface {
+	PremiumExtractorBytes
+	PremiumExtractorDoc
+}
+
 type PlanetLayerExtractorDoc interface {
 	ExtractAbandonInformation(doc *goquery.Document) (abandonToken string, token string)
+	ExtractPlanetRenameToken(doc *goquery.Document) string
 }
 
 type TechnologyDetailsExtractorBytes interface {
@@ -477,6 +534,21 @@ type TechnologyDetailsExtractorBytesDoc interface {
 	TechnologyDetailsExtractorDoc
 }
 
+// ChatExtractorBytes chat page and ajaxChat conversation history
+type ChatExtractorBytes interface {
+	ExtractChatContacts(pageHTML []byte) ([]ogame.ChatContact, error)
+	ExtractChatHistory(pageHTML []byte) ([]ogame.ChatMsg, error)
+}
+
+type ChatExtractorDoc interface {
+	ExtractChatContactsFromDoc(doc *goquery.Document) ([]ogame.ChatContact, error)
+}
+
+type ChatExtractorBytesDoc interface {
+	ChatExtractorBytes
+	ChatExtractorDoc
+}
+
 // Extractor ...
 type Extractor interface {
 	GetLanguage() string
@@ -486,6 +558,7 @@ type Extractor interface {
 	GetLifeformEnabled() bool
 	SetLifeformEnabled(lifeformEnabled bool)
 
+	ChatExtractorBytesDoc
 	DefensesExtractorBytesDoc
 	EspionageReportExtractorBytesDoc
 	EventListExtractorBytesDoc
@@ -498,10 +571,13 @@ type Extractor interface {
 	MessagesCombatReportExtractorBytesDoc
 	MessagesEspionageReportExtractorBytesDoc
 	MessagesExpeditionExtractorBytesDoc
+	MessagesOtherExtractorBytesDoc
+	MessagesUnionsTransportExtractorBytesDoc
 	MissileAttackLayerExtractorBytesDoc
 	MovementExtractorBytesDoc
 	OverviewExtractorBytesDoc
 	PreferencesExtractorBytesDoc
+	PremiumExtractorBytesDoc
 	ResearchExtractorBytesDoc
 	ResourcesBuildingsExtractorBytesDoc
 	ResourcesSettingsExtractorBytesDoc
@@ -517,10 +593,11 @@ type Extractor interface {
 	GalaxyExtractorBytes
 	JumpGateLayerExtractorBytes
 	MessagesMarketplaceExtractorBytes
+	MarketplaceExtractorBytes
 	PhalanxExtractorBytes
-	PremiumExtractorBytes
 	TraderAuctioneerExtractorBytes
 	TraderImportExportExtractorBytes
+	TraderResourcesExtractorBytes
 
 	PlanetLayerExtractorDoc
 	TraderImportExportExtractorDoc
@@ -77,6 +77,11 @@ func (e *Extractor) ExtractDestroyRockets(pageHTML []byte) (abm, ipm int64, toke
 	panic("implement me")
 }
 
+// ExtractMissiles ...
+func (e *Extractor) ExtractMissiles(pageHTML []byte) (ogame.Missiles, error) {
+	panic("implement me")
+}
+
 // ExtractCancelFleetToken ...
 func (e *Extractor) ExtractCancelFleetToken(pageHTML []byte, fleetID ogame.FleetID) (string, error) {
 	panic("implement me")
@@ -97,6 +102,26 @@ func (e *Extractor) ExtractExpeditionMessagesFromDoc(doc *goquery.Document) ([]o
 	panic("implement me")
 }
 
+// ExtractOtherMessages ...
+func (e *Extractor) ExtractOtherMessages(pageHTML []byte) ([]ogame.OtherMessage, int64, error) {
+	panic("implement me")
+}
+
+// ExtractOtherMessagesFromDoc ...
+func (e *Extractor) ExtractOtherMessagesFromDoc(doc *goquery.Document) ([]ogame.OtherMessage, int64, error) {
+	panic("implement me")
+}
+
+// ExtractUnionsTransportMessages ...
+func (e *Extractor) ExtractUnionsTransportMessages(pageHTML []byte) ([]ogame.UnionsTransportMessage, int64, error) {
+	panic("implement me")
+}
+
+// ExtractUnionsTransportMessagesFromDoc ...
+func (e *Extractor) ExtractUnionsTransportMessagesFromDoc(doc *goquery.Document) ([]ogame.UnionsTransportMessage, int64, error) {
+	panic("implement me")
+}
+
 // ExtractTearDownButtonEnabled ...
 func (e *Extractor) ExtractTearDownButtonEnabled(pageHTML []byte) bool {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -223,6 +248,12 @@ func (e *Extractor) extractAttacks(pageHTML []byte, clock clockwork.Clock, ownCo
 	return e.extractAttacksFromDoc(doc, clock, ownCoords)
 }
 
+// ExtractTraderResources ...
+func (e *Extractor) ExtractTraderResources(pageHTML []byte) (ogame.TraderResources, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractTraderResourcesFromDoc(doc)
+}
+
 // ExtractOfferOfTheDay ...
 func (e *Extractor) ExtractOfferOfTheDay(pageHTML []byte) (int64, string, ogame.PlanetResources, ogame.Multiplier, error) {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -513,7 +544,7 @@ func (e *Extractor) ExtractFleetDispatchACSFromDoc(doc *goquery.Document) []ogam
 
 // ExtractEspionageReportMessageIDsFromDoc ...
 func (e *Extractor) ExtractEspionageReportMessageIDsFromDoc(doc *goquery.Document) ([]ogame.EspionageReportSummary, int64) {
-	return extractEspionageReportMessageIDsFromDoc(doc)
+	return extractEspionageReportMessageIDsFromDoc(doc, e.GetLocation())
 }
 
 // ExtractCombatReportMessagesFromDoc ...
@@ -746,6 +777,11 @@ func (e *Extractor) ExtractAbandonInformation(doc *goquery.Document) (string, st
 	return extractAbandonInformation(doc)
 }
 
+// ExtractPlanetRenameToken ...
+func (e *Extractor) ExtractPlanetRenameToken(doc *goquery.Document) string {
+	return extractPlanetRenameToken(doc)
+}
+
 // </ Extract from doc> -------------------------------------------------------
 
 // <Works with []byte only> ---------------------------------------------------
@@ -913,3 +949,63 @@ func (e *Extractor) ExtractLfResearch(pageHTML []byte) (ogame.LfResearches, erro
 func (e *Extractor) ExtractLfResearchFromDoc(doc *goquery.Document) (ogame.LfResearches, error) {
 	panic("not implemented")
 }
+
+// ExtractChatContacts ...
+func (e *Extractor) ExtractChatContacts(pageHTML []byte) ([]ogame.ChatContact, error) {
+	panic("not implemented")
+}
+
+// ExtractChatContactsFromDoc ...
+func (e *Extractor) ExtractChatContactsFromDoc(doc *goquery.Document) ([]ogame.ChatContact, error) {
+	panic("not implemented")
+}
+
+// ExtractChatHistory ...
+func (e *Extractor) ExtractChatHistory(pageHTML []byte) ([]ogame.ChatMsg, error) {
+	panic("not implemented")
+}
+
+// ExtractPlanetRelocation ...
+func (e *Extractor) ExtractPlanetRelocation(pageHTML []byte) (ogame.PlanetRelocation, error) {
+	panic("not implemented")
+}
+
+// ExtractPlanetMoveToken ...
+func (e *Extractor) ExtractPlanetMoveToken(pageHTML []byte) (string, error) {
+	panic("not implemented")
+}
+
+// ExtractOfficers ...
+func (e *Extractor) ExtractOfficers(pageHTML []byte) ([]ogame.Officer, error) {
+	panic("not implemented")
+}
+
+// ExtractOfficersFromDoc ...
+func (e *Extractor) ExtractOfficersFromDoc(doc *goquery.Document) ([]ogame.Officer, error) {
+	panic("not implemented")
+}
+
+// ExtractShopItems ...
+func (e *Extractor) ExtractShopItems(pageHTML []byte) (string, []ogame.ShopItem, error) {
+	panic("not implemented")
+}
+
+// ExtractMarketplaceOffers ...
+func (e *Extractor) ExtractMarketplaceOffers(pageHTML []byte) ([]ogame.MarketplaceOffer, int64, error) {
+	panic("not implemented")
+}
+
+// ExtractOfferOfTheDayDetails ...
+func (e *Extractor) ExtractOfferOfTheDayDetails(pageHTML []byte) (ogame.OfferOfTheDay, error) {
+	panic("not implemented")
+}
+
+// ExtractEventList ...
+func (e *Extractor) ExtractEventList(pageHTML []byte) ([]ogame.FleetEvent, error) {
+	panic("not implemented")
+}
+
+// ExtractEventListFromDoc ...
+func (e *Extractor) ExtractEventListFromDoc(doc *goquery.Document) ([]ogame.FleetEvent, error) {
+	panic("not implemented")
+}
@@ -915,11 +915,22 @@ func TestExtractEspionageReportMessageIDs(t *testing.T) {
 	assert.Equal(t, ogame.Coordinate{4, 117, 6, ogame.PlanetType}, msgs[0].Target)
 	assert.Equal(t, 0.5, msgs[0].LootPercentage)
 	assert.Equal(t, "Fleet Command", msgs[0].From)
+	assert.Equal(t, time.Date(2018, 7, 8, 2, 16, 16, 0, time.UTC), msgs[0].CreatedAt)
+	assert.Equal(t, "sr-en-152-a1ccdadbf7fad0d2c8f16aa6e322b456f0cc1d08", msgs[0].APIKey)
+	assert.Equal(t, "", msgs[1].APIKey)
 	assert.Equal(t, ogame.Action, msgs[1].Type)
 	assert.Equal(t, "Space Monitoring", msgs[1].From)
 	assert.Equal(t, ogame.Coordinate{4, 117, 9, ogame.PlanetType}, msgs[1].Target)
 }
 
+func TestExtractEspionageReportMessageIDsLocation(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/messages.html")
+	e := NewExtractor()
+	e.SetLocation(time.FixedZone("OGT", 3600))
+	msgs, _ := e.ExtractEspionageReportMessageIDs(pageHTMLBytes)
+	assert.Equal(t, time.Date(2018, 7, 8, 1, 16, 16, 0, time.UTC), msgs[0].CreatedAt.UTC())
+}
+
 func TestExtractEspionageReportMessageIDsLootPercentage(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/messages_loot_percentage.html")
 	msgs, _ := NewExtractor().ExtractEspionageReportMessageIDs(pageHTMLBytes)
@@ -1630,7 +1641,12 @@ func TestExtractPlanetsMoon(t *testing.T) {
 	assert.Equal(t, ogame.Coordinate{4, 116, 12, ogame.MoonType}, planets[0].Moon.Coordinate)
 	assert.Equal(t, int64(0), planets[0].Moon.Fields.Built)
 	assert.Equal(t, int64(1), planets[0].Moon.Fields.Total)
+	assert.Equal(t, planets[0].Temperature, planets[0].Moon.Temperature)
+	assert.Equal(t, "8e0e6034049bd64e18a1804b42f179", planets[0].Moon.GetImgID())
 	assert.Nil(t, planets[1].Moon)
+
+	moons := NewExtractor().ExtractMoons(pageHTMLBytes)
+	assert.Equal(t, planets[0].Temperature, moons[0].Temperature)
 }
 
 func TestExtractPlanets_fieldsFilled(t *testing.T) {
@@ -159,6 +159,9 @@ func extractMoonsFromDoc(doc *goquery.Document) []ogame.Moon {
 		if err != nil {
 			return
 		}
+		if planet, err := extractPlanetFromSelection(s.Closest("div.smallplanet")); err == nil {
+			moon.Temperature = planet.Temperature
+		}
 		res = append(res, moon)
 	})
 	return res
@@ -502,6 +505,33 @@ func extractOfferOfTheDayFromDoc(doc *goquery.Document) (price int64, importToke
 	return
 }
 
+func extractTraderResourcesFromDoc(doc *goquery.Document) (res ogame.TraderResources, err error) {
+	script := doc.Find("script").Text()
+	m := regexp.MustCompile(`var [tT]oken\s?=\s?"([^"]*)";`).FindStringSubmatch(script)
+	if len(m) != 2 {
+		return res, errors.New("failed to extract trader resources token")
+	}
+	res.Token = m[1]
+	m = regexp.MustCompile(`var tradeRates\s?=\s?({[^;]*});`).FindStringSubmatch(script)
+	if len(m) != 2 {
+		return res, errors.New("failed to extract trader resources rates")
+	}
+	var rates struct {
+		Metal     float64 `json:"metal"`
+		Crystal   float64 `json:"crystal"`
+		Deuterium float64 `json:"deuterium"`
+	}
+	if err = json.Unmarshal([]byte(m[1]), &rates); err != nil {
+		return
+	}
+	res.Rates = ogame.Ratio{Metal: rates.Metal, Crystal: rates.Crystal, Deuterium: rates.Deuterium}
+	m = regexp.MustCompile(`var traderCosts\s?=\s?(\d+);`).FindStringSubmatch(script)
+	if len(m) == 2 {
+		res.Fee = utils.DoParseI64(m[1])
+	}
+	return res, nil
+}
+
 func extractProductionFromDoc(doc *goquery.Document) ([]ogame.Quantifiable, error) {
 	res := make([]ogame.Quantifiable, 0)
 	active := doc.Find("table.construction")
@@ -595,7 +625,7 @@ func extractFleetDispatchACSFromDoc(doc *goquery.Document) []ogame.ACSValues {
 	return out
 }
 
-func extractEspionageReportMessageIDsFromDoc(doc *goquery.Document) ([]ogame.EspionageReportSummary, int64) {
+func extractEspionageReportMessageIDsFromDoc(doc *goquery.Document, location *time.Location) ([]ogame.EspionageReportSummary, int64) {
 	msgs := make([]ogame.EspionageReportSummary, 0)
 	nbPage := utils.DoParseI64(doc.Find("ul.pagination li").Last().AttrOr("data-page", "1"))
 	doc.Find("li.msg").Each(func(i int, s *goquery.Selection) {
@@ -614,6 +644,11 @@ func extractEspionageReportMessageIDsFromDoc(doc *goquery.Document) ([]ogame.Esp
 				if spanLink.Find("figure").HasClass("moon") {
 					report.Target.Type = ogame.MoonType
 				}
+				report.CreatedAt, _ = time.ParseInLocation("02.01.2006 15:04:05", s.Find("span.msg_date").Text(), location)
+				apiKeyTitle := s.Find("span.icon_apikey").AttrOr("title", "")
+				if m := regexp.MustCompile(`'(sr-[^']+)'`).FindStringSubmatch(apiKeyTitle); len(m) == 2 {
+					report.APIKey = m[1]
+				}
 				if messageType == ogame.Report {
 					s.Find("div.compacting").Each(func(i int, s *goquery.Selection) {
 						if regexp.MustCompile(`%`).MatchString(s.Text()) {
@@ -1408,6 +1443,10 @@ func extractAbandonInformation(doc *goquery.Document) (string, string) {
 	return abandonToken, token
 }
 
+func extractPlanetRenameToken(doc *goquery.Document) string {
+	return doc.Find("form#planetMaintenance input[name=token]").AttrOr("value", "")
+}
+
 func extractPlanetCoordinate(pageHTML []byte) (ogame.Coordinate, error) {
 	m := regexp.MustCompile(`<meta name="ogame-planet-coordinates" content="(\d+):(\d+):(\d+)"/>`).FindSubmatch(pageHTML)
 	if len(m) == 0 {
@@ -1782,13 +1821,20 @@ func extractGalaxyInfos(pageHTML []byte, botPlayerName string, botPlayerID, botP
 
 	debris16Div := doc.Find("div#debris16")
 	if debris16Div.Size() > 0 {
-		lis := debris16Div.Find("ul.ListLinks li")
-		metalTxt := lis.First().Text()
-		crystalTxt := lis.Eq(1).Text()
-		pathfindersTxt := lis.Eq(2).Text()
-		res.ExpeditionDebris.Metal = utils.ParseInt(prefixedNumRgx.FindStringSubmatch(metalTxt)[1])
-		res.ExpeditionDebris.Crystal = utils.ParseInt(prefixedNumRgx.FindStringSubmatch(crystalTxt)[1])
-		res.ExpeditionDebris.PathfindersNeeded = utils.ParseInt(prefixedNumRgx.FindStringSubmatch(pathfindersTxt)[1])
+		parsePrefixedNum := func(txt string) int64 {
+			m := prefixedNumRgx.FindStringSubmatch(txt)
+			if len(m) != 2 {
+				return 0
+			}
+			return utils.ParseInt(m[1])
+		}
+		contents := debris16Div.Find("ul.ListLinks li.debris-content")
+		res.ExpeditionDebris.Metal = parsePrefixedNum(contents.Eq(0).Text())
+		res.ExpeditionDebris.Crystal = parsePrefixedNum(contents.Eq(1).Text())
+		if contents.Size() > 2 {
+			res.ExpeditionDebris.Deuterium = parsePrefixedNum(contents.Eq(2).Text())
+		}
+		res.ExpeditionDebris.PathfindersNeeded = parsePrefixedNum(debris16Div.Find("ul.ListLinks li.debris-recyclers").Text())
 	}
 
 	debris17Div := doc.Find("div#debris17")
@@ -2033,6 +2079,9 @@ func extractPlanetFromSelection(s *goquery.Selection) (ogame.Planet, error) {
 	res.Temperature.Max = utils.DoParseI64(m[9])
 
 	res.Moon, _ = extractMoonFromPlanetSelection(s)
+	if res.Moon != nil {
+		res.Moon.Temperature = res.Temperature
+	}
 
 	return res, nil
 }
@@ -42,12 +42,30 @@ func (e Extractor) ExtractExpeditionMessages(pageHTML []byte) ([]ogame.Expeditio
 	return e.ExtractExpeditionMessagesFromDoc(doc)
 }
 
+// ExtractOtherMessages ...
+func (e Extractor) ExtractOtherMessages(pageHTML []byte) ([]ogame.OtherMessage, int64, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return e.ExtractOtherMessagesFromDoc(doc)
+}
+
+// ExtractUnionsTransportMessages ...
+func (e Extractor) ExtractUnionsTransportMessages(pageHTML []byte) ([]ogame.UnionsTransportMessage, int64, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return e.ExtractUnionsTransportMessagesFromDoc(doc)
+}
+
 // ExtractMarketplaceMessages ...
 func (e Extractor) ExtractMarketplaceMessages(pageHTML []byte) ([]ogame.MarketplaceMessage, int64, error) {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
 	return e.ExtractMarketplaceMessagesFromDoc(doc, e.GetLocation())
 }
 
+// ExtractMarketplaceOffers ...
+func (e Extractor) ExtractMarketplaceOffers(pageHTML []byte) ([]ogame.MarketplaceOffer, int64, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractMarketplaceOffersFromDoc(doc)
+}
+
 // ExtractDefense ...
 func (e Extractor) ExtractDefense(pageHTML []byte) (ogame.DefensesInfos, error) {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -167,6 +185,16 @@ func (e Extractor) ExtractExpeditionMessagesFromDoc(doc *goquery.Document) ([]og
 	return extractExpeditionMessagesFromDoc(doc, e.GetLocation())
 }
 
+// ExtractOtherMessagesFromDoc ...
+func (e Extractor) ExtractOtherMessagesFromDoc(doc *goquery.Document) ([]ogame.OtherMessage, int64, error) {
+	return extractOtherMessagesFromDoc(doc, e.GetLocation())
+}
+
+// ExtractUnionsTransportMessagesFromDoc ...
+func (e Extractor) ExtractUnionsTransportMessagesFromDoc(doc *goquery.Document) ([]ogame.UnionsTransportMessage, int64, error) {
+	return extractUnionsTransportMessagesFromDoc(doc, e.GetLocation())
+}
+
 // ExtractMarketplaceMessagesFromDoc ...
 func (e Extractor) ExtractMarketplaceMessagesFromDoc(doc *goquery.Document, location *time.Location) ([]ogame.MarketplaceMessage, int64, error) {
 	return extractMarketplaceMessagesFromDoc(doc, location)
@@ -1,10 +1,13 @@
 package v7
 
 import (
+	"bytes"
+	"github.com/PuerkitoBio/goquery"
 	"github.com/alaingilbert/clockwork"
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 )
@@ -209,6 +212,14 @@ func TestExtractMarketplaceMessages(t *testing.T) {
 	assert.Equal(t, "164ba9f6e5cbfdaa03c061730767d779", msgs[3].Token)
 }
 
+func TestExtractMarketplaceOffers(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.2/en/create_offer.html")
+	offers, nbPage, err := NewExtractor().ExtractMarketplaceOffers(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(offers))
+	assert.Equal(t, int64(1), nbPage)
+}
+
 func TestExtractExpeditionMessages(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.2/en/expedition_messages.html")
 	e := NewExtractor()
@@ -244,6 +255,7 @@ func TestExtractGalaxyExpeditionDebris(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(0), infos.ExpeditionDebris.Metal)
 	assert.Equal(t, int64(2300), infos.ExpeditionDebris.Crystal)
+	assert.Equal(t, int64(0), infos.ExpeditionDebris.Deuterium)
 	assert.Equal(t, int64(1), infos.ExpeditionDebris.PathfindersNeeded)
 }
 
@@ -319,3 +331,130 @@ func TestGetConstructionsV7(t *testing.T) {
 	assert.Equal(t, ogame.EnergyTechnologyID, researchID)
 	assert.Equal(t, int64(271), researchCountdown)
 }
+
+func TestExtractOtherMessages(t *testing.T) {
+	msg := func(id, title, content string) string {
+		return `<li class="msg" data-msg-id="` + id + `"><div class="msg_head"><span class="msg_title blue_txt">` + title + `</span>
+<span class="msg_date fright">22.04.2020 00:12:06</span><span class="msg_sender">Fleet Command</span></div>
+<span class="msg_content">` + content + `</span></li>`
+	}
+	link := func(icon, coord string) string {
+		return `<a href="#" class="txt_link"><figure class="planetIcon ` + icon + ` tooltip js_hideTipOnMobile" title=""></figure>Foo ` + coord + `</a>`
+	}
+	pageHTML := `<ul class="pagination"><li data-page="1"></li><li data-page="2"></li></ul>` +
+		msg("1", `Moon destruction `+link("moon", "[1:2:3]"), `The weapons of the Deathstars fire at the moon. Moon destruction chance: 12 %, Deathstar destruction chance: 25,5 %. The moon has been destroyed!`) +
+		msg("2", `Return of a fleet`, `Your fleet is returning from planet `+link("planet", "[1:2:4]")+` to moon `+link("moon", "[1:2:3]")+`. The fleet is delivering:<br/>Metal: 1.234 Crystal: 56 Deuterium: 0`) +
+		msg("3", `Officers`, `Your <a href="https://s1-en.ogame.gameforge.com/game/index.php?page=premium&amp;openDetail=5">Geologist</a> will expire in 3 days.`) +
+		msg("4", `Tutorial`, `<a href="https://s1-en.ogame.gameforge.com/game/index.php?page=ingame&amp;component=tutorial">Task</a> completed! You have received the following reward: 2 x Light Fighter`) +
+		msg("5", `Retour d'une flotte`, `Votre flotte revient de la planète `+link("planet", "[1:2:4]")+` vers la planète `+link("planet", "[1:2:5]")+`. Elle livre :<br/>Métal : 10 Cristal : 20 Deutérium : 30`) +
+		msg("6", `Retour d'une flotte`, `Votre flotte revient.`)
+	e := NewExtractor()
+	e.SetLocation(time.FixedZone("OGT", 3600))
+	msgs, nbPage, err := e.ExtractOtherMessages([]byte(pageHTML))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), nbPage)
+	assert.Equal(t, 6, len(msgs))
+	assert.Equal(t, time.Date(2020, 4, 21, 23, 12, 6, 0, time.UTC), msgs[0].CreatedAt.UTC())
+	assert.Equal(t, "Fleet Command", msgs[0].Sender)
+
+	assert.Equal(t, ogame.MoonDestructionOtherMessage, msgs[0].Type)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType}, msgs[0].Coordinate)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType}, msgs[0].MoonDestruction.Moon)
+	assert.Equal(t, 0.12, msgs[0].MoonDestruction.MoonChance)
+	assert.Equal(t, 0.255, msgs[0].MoonDestruction.DeathstarsChance)
+	assert.True(t, msgs[0].MoonDestruction.MoonDestroyed)
+	assert.False(t, msgs[0].MoonDestruction.DeathstarsDestroyed)
+
+	assert.Equal(t, ogame.FleetReturnOtherMessage, msgs[1].Type)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 4, Type: ogame.PlanetType}, msgs[1].FleetReturn.From)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType}, msgs[1].FleetReturn.To)
+	assert.Equal(t, ogame.Resources{Metal: 1234, Crystal: 56}, msgs[1].FleetReturn.Resources)
+
+	assert.Equal(t, ogame.OfficerExpiryOtherMessage, msgs[2].Type)
+	assert.Equal(t, "geologist", msgs[2].OfficerExpiry.Officer)
+	assert.False(t, msgs[2].OfficerExpiry.Expired)
+
+	assert.Equal(t, ogame.TutorialRewardOtherMessage, msgs[3].Type)
+	assert.Equal(t, "2 x Light Fighter", msgs[3].TutorialReward.Reward)
+
+	// Recognized from the markup, whatever the language
+	assert.Equal(t, ogame.FleetReturnOtherMessage, msgs[4].Type)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 5, Type: ogame.PlanetType}, msgs[4].FleetReturn.To)
+	assert.Equal(t, ogame.Resources{Metal: 10, Crystal: 20, Deuterium: 30}, msgs[4].FleetReturn.Resources)
+
+	assert.Equal(t, ogame.UnknownOtherMessage, msgs[5].Type)
+	assert.Equal(t, "Votre flotte revient.", msgs[5].Content)
+}
+
+func TestExtractLinkedCoords(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/message_foreign_fleet_sighted.html")
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	assert.Equal(t, []ogame.Coordinate{
+		{Galaxy: 4, System: 184, Position: 10, Type: ogame.PlanetType},
+		{Galaxy: 4, System: 212, Position: 8, Type: ogame.PlanetType},
+	}, extractLinkedCoords(doc.Find("span.espionageDefText")))
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<span><a href="#"><figure class="planetIcon moon"></figure>Moon [1:2:3]</a>
+<a href="#"><figure class="planetIcon tf"></figure>[1:2:4]</a></span><span>[5:6:7]</span>`))
+	assert.Equal(t, []ogame.Coordinate{
+		{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType},
+		{Galaxy: 1, System: 2, Position: 4, Type: ogame.DebrisType},
+	}, extractLinkedCoords(doc.Find("span").First()))
+	assert.Equal(t, []ogame.Coordinate{{Galaxy: 5, System: 6, Position: 7, Type: ogame.PlanetType}}, extractLinkedCoords(doc.Find("span").Last()))
+}
+
+func TestExtractUnionsTransportMessages(t *testing.T) {
+	pageHTML := `<ul class="pagination"><li data-page="1"></li></ul>
+<li class="msg" data-msg-id="11"><div class="msg_head"><span class="msg_title blue_txt">Reaching a planet</span>
+<span class="msg_date fright">22.04.2020 00:12:06</span><span class="msg_sender">Fleet Command</span></div>
+<span class="msg_content">Your fleet from planet Foo <a href="#">[1:2:3]</a> reaches the planet Bar <a href="#">[1:2:4]</a> and delivers its goods:<br/>Metal: 10.000 Crystal: 5.000 Deuterium: 1.500</span></li>
+<li class="msg" data-msg-id="12"><div class="msg_head"><span class="msg_title blue_txt">ACS invitation</span>
+<span class="msg_date fright">22.04.2020 00:13:06</span><span class="msg_sender">Bob</span></div>
+<span class="msg_content">You have been invited to an ACS attack on <a href="#">[4:5:6]</a>, arrival 22.04.2020 01:30:00.
+<a href="https://s1-en.ogame.gameforge.com/game/index.php?page=ingame&amp;component=fleetdispatch&amp;union=4321">Join</a></span></li>
+<li class="msg" data-msg-id="13"><div class="msg_head"><span class="msg_title blue_txt">Something else</span></div>
+<span class="msg_content">Nothing to parse</span></li>`
+	e := NewExtractor()
+	e.SetLocation(time.FixedZone("OGT", 3600))
+	msgs, nbPage, err := e.ExtractUnionsTransportMessages([]byte(pageHTML))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), nbPage)
+	assert.Equal(t, 3, len(msgs))
+
+	assert.Equal(t, ogame.TransportArrivalMessage, msgs[0].Type)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}, msgs[0].Transport.Origin)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 4, Type: ogame.PlanetType}, msgs[0].Transport.Destination)
+	assert.Equal(t, ogame.Resources{Metal: 10000, Crystal: 5000, Deuterium: 1500}, msgs[0].Transport.Resources)
+
+	assert.Equal(t, ogame.ACSInvitationMessage, msgs[1].Type)
+	assert.Equal(t, "Bob", msgs[1].Sender)
+	assert.Equal(t, int64(4321), msgs[1].ACSInvitation.UnionID)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 5, Position: 6, Type: ogame.PlanetType}, msgs[1].ACSInvitation.Target)
+	assert.Equal(t, time.Date(2020, 4, 22, 0, 30, 0, 0, time.UTC), msgs[1].ACSInvitation.ArrivalTime.UTC())
+
+	assert.Equal(t, ogame.UnknownUnionsTransportMessage, msgs[2].Type)
+	assert.Nil(t, msgs[2].Transport)
+}
+
+func TestExtractUnionsTransportMessagesMoonTarget(t *testing.T) {
+	// Coordinates links of a real page, a moon [4:49:9] and a planet [4:113:6]
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.2/en/messages.html")
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	linkHTML := func(coord string) string {
+		html, _ := goquery.OuterHtml(doc.Find(`.msg_title a.txt_link:contains("` + coord + `")`).First())
+		return html
+	}
+	moonLink, planetLink := linkHTML("[4:49:9]"), linkHTML("[4:113:6]")
+	assert.Contains(t, moonLink, "planetIcon moon")
+	assert.Contains(t, planetLink, "planetIcon planet")
+	invitation := func(id, target string) string {
+		return `<li class="msg" data-msg-id="` + id + `"><div class="msg_head"><span class="msg_title blue_txt">ACS invitation</span>
+<span class="msg_date fright">22.04.2020 00:13:06</span><span class="msg_sender">Bob</span></div>
+<span class="msg_content">You have been invited to an ACS attack on ` + target + `, arrival 22.04.2020 01:30:00.
+<a href="https://s1-en.ogame.gameforge.com/game/index.php?page=ingame&amp;component=fleetdispatch&amp;union=4321">Join</a></span></li>`
+	}
+	msgs, _, err := NewExtractor().ExtractUnionsTransportMessages([]byte(invitation("1", moonLink) + invitation("2", planetLink)))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 49, Position: 9, Type: ogame.MoonType}, msgs[0].ACSInvitation.Target)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 113, Position: 6, Type: ogame.PlanetType}, msgs[1].ACSInvitation.Target)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/alaingilbert/ogame/pkg/utils"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -730,6 +731,220 @@ func extractExpeditionMessagesFromDoc(doc *goquery.Document, location *time.Loca
 	return msgs, nbPage, nil
 }
 
+var (
+	coordsRgx              = regexp.MustCompile(`\[(\d+):(\d+):(\d+)]`)
+	percentRgx             = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s?%`)
+	labeledNumberRgx       = regexp.MustCompile(`[^\s\d:]+\s?:\s*(\d[\d.,]*)`)
+	premiumDetailRgx       = regexp.MustCompile(`[?&]openDetail=(\d+)`)
+	tutorialLinkRgx        = regexp.MustCompile(`[?&](?:page|component)=tutorial\b`)
+	moonDestroyedRgx       = regexp.MustCompile(`(?i)moon (?:has been|was) destroyed`)
+	deathstarsDestroyedRgx = regexp.MustCompile(`(?i)(?:deathstars?|fleet) (?:has been|have been|was|were) destroyed`)
+)
+
+// officers by their id in the premium page links (page=premium&openDetail=id)
+var officers = map[int64]string{2: "commander", 3: "admiral", 4: "engineer", 5: "geologist", 6: "technocrat"}
+
+// extractOtherMessagesFromDoc parses the "Other" tab (24). The message kind is recognized from the markup:
+// the coordinates links and their celestial icon, the announced chances, the premium and tutorial links.
+// The outcome of a moon destruction and the expiry of an officer are only read from the english texts.
+func extractOtherMessagesFromDoc(doc *goquery.Document, location *time.Location) ([]ogame.OtherMessage, int64, error) {
+	msgs := make([]ogame.OtherMessage, 0)
+	nbPage := utils.DoParseI64(doc.Find("ul.pagination li").Last().AttrOr("data-page", "1"))
+	doc.Find("li.msg").Each(func(i int, s *goquery.Selection) {
+		id, err := utils.ParseI64(s.AttrOr("data-msg-id", ""))
+		if err != nil {
+			return
+		}
+		msg := ogame.OtherMessage{ID: id}
+		msg.CreatedAt, _ = time.ParseInLocation("02.01.2006 15:04:05", s.Find(".msg_date").Text(), location)
+		titleSel := s.Find(".msg_title")
+		msg.Title = strings.TrimSpace(titleSel.Text())
+		msg.Sender = strings.TrimSpace(s.Find(".msg_sender").Text())
+		contentSel := s.Find("span.msg_content")
+		msg.Content, _ = contentSel.Html()
+		msg.Content = strings.TrimSpace(msg.Content)
+		titleCoords := extractLinkedCoords(titleSel)
+		contentCoords := extractLinkedCoords(contentSel)
+		if coords := append(titleCoords, contentCoords...); len(coords) > 0 {
+			msg.Coordinate = coords[0]
+		}
+		parseOtherMessage(&msg, contentSel, titleCoords, contentCoords)
+		msgs = append(msgs, msg)
+	})
+	return msgs, nbPage, nil
+}
+
+func extractCoords(v string) (out []ogame.Coordinate) {
+	for _, m := range coordsRgx.FindAllStringSubmatch(v, -1) {
+		out = append(out, ogame.Coordinate{
+			Galaxy:   utils.DoParseI64(m[1]),
+			System:   utils.DoParseI64(m[2]),
+			Position: utils.DoParseI64(m[3]),
+			Type:     ogame.PlanetType,
+		})
+	}
+	return
+}
+
+// extractLinkedCoords returns the coordinates of the links of sel, typed from the celestial icon of the link
+// (figure.planetIcon moon or tf). The coordinates of the text are returned when no link has coordinates.
+func extractLinkedCoords(sel *goquery.Selection) (out []ogame.Coordinate) {
+	sel.Find("a").Each(func(_ int, a *goquery.Selection) {
+		coords := extractCoords(a.Text())
+		if len(coords) == 0 {
+			return
+		}
+		coord := coords[0]
+		if a.Find("figure.planetIcon.moon").Length() > 0 {
+			coord.Type = ogame.MoonType
+		} else if a.Find("figure.planetIcon.tf").Length() > 0 {
+			coord.Type = ogame.DebrisType
+		}
+		out = append(out, coord)
+	})
+	if len(out) == 0 {
+		out = extractCoords(sel.Text())
+	}
+	return
+}
+
+// parseOtherMessage sets the type and the typed field of msg from its content
+func parseOtherMessage(msg *ogame.OtherMessage, contentSel *goquery.Selection, titleCoords, contentCoords []ogame.Coordinate) {
+	text := strings.TrimSpace(contentSel.Text())
+	chances := percentRgx.FindAllStringSubmatch(text, -1)
+	var officerID int64
+	isTutorial := false
+	contentSel.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href := a.AttrOr("href", "")
+		if m := premiumDetailRgx.FindStringSubmatch(href); len(m) == 2 && officerID == 0 {
+			officerID = utils.DoParseI64(m[1])
+		}
+		isTutorial = isTutorial || tutorialLinkRgx.MatchString(href)
+	})
+	switch {
+	case len(titleCoords) > 0 && len(chances) >= 2:
+		res := &ogame.MoonDestructionResult{Moon: titleCoords[0]}
+		res.Moon.Type = ogame.MoonType
+		res.MoonChance = parsePercent(chances[0][1])
+		res.DeathstarsChance = parsePercent(chances[1][1])
+		res.MoonDestroyed = moonDestroyedRgx.MatchString(text)
+		res.DeathstarsDestroyed = deathstarsDestroyedRgx.MatchString(text)
+		msg.Type, msg.MoonDestruction = ogame.MoonDestructionOtherMessage, res
+	case len(contentCoords) >= 2 && len(labeledNumberRgx.FindAllString(text, -1)) >= 3:
+		notice := &ogame.FleetReturnNotice{From: contentCoords[0], To: contentCoords[1], Resources: extractLabeledResources(text)}
+		msg.Type, msg.FleetReturn = ogame.FleetReturnOtherMessage, notice
+	case officers[officerID] != "":
+		notice := &ogame.OfficerExpiryNotice{Officer: officers[officerID]}
+		notice.Expired = strings.Contains(strings.ToLower(text), "expired")
+		msg.Type, msg.OfficerExpiry = ogame.OfficerExpiryOtherMessage, notice
+	case isTutorial:
+		notice := &ogame.TutorialRewardNotice{Reward: text}
+		if idx := strings.LastIndex(text, ":"); idx != -1 {
+			notice.Reward = strings.TrimSpace(text[idx+1:])
+		}
+		msg.Type, msg.TutorialReward = ogame.TutorialRewardOtherMessage, notice
+	}
+}
+
+// parsePercent parses "12" or "12,5" as 0.12 and 0.125
+func parsePercent(v string) float64 {
+	f, _ := strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+	return f / 100
+}
+
+// extractLabeledResources returns the first three labeled numbers of text as metal, crystal and deuterium,
+// the order the game lists them in, eg: "Metal: 1.000 Crystal: 500 Deuterium: 0"
+func extractLabeledResources(text string) (res ogame.Resources) {
+	values := make([]int64, 3)
+	for i, m := range labeledNumberRgx.FindAllStringSubmatch(text, 3) {
+		values[i] = utils.ParseInt(m[1])
+	}
+	res.Metal, res.Crystal, res.Deuterium = values[0], values[1], values[2]
+	return
+}
+
+var (
+	unionIDRgx  = regexp.MustCompile(`[?&]union=(\d+)`)
+	msgDateRgx  = regexp.MustCompile(`\d{2}\.\d{2}\.\d{4} \d{2}:\d{2}:\d{2}`)
+	resourceRgx = regexp.MustCompile(`(?i)(metal|crystal|deuterium):\s*[\d.,]+`)
+)
+
+// extractUnionsTransportMessagesFromDoc parses the "Unions/Transport" tab (23).
+// ACS invitations are recognized from the union link, transports from the origin/destination coordinates and the
+// delivered resources (english labels). Moons are recognized from the celestial icon of the coordinates links.
+func extractUnionsTransportMessagesFromDoc(doc *goquery.Document, location *time.Location) ([]ogame.UnionsTransportMessage, int64, error) {
+	msgs := make([]ogame.UnionsTransportMessage, 0)
+	nbPage := utils.DoParseI64(doc.Find("ul.pagination li").Last().AttrOr("data-page", "1"))
+	doc.Find("li.msg").Each(func(i int, s *goquery.Selection) {
+		id, err := utils.ParseI64(s.AttrOr("data-msg-id", ""))
+		if err != nil {
+			return
+		}
+		msg := ogame.UnionsTransportMessage{ID: id}
+		msg.CreatedAt, _ = time.ParseInLocation("02.01.2006 15:04:05", s.Find(".msg_date").Text(), location)
+		msg.Title = strings.TrimSpace(s.Find(".msg_title").Text())
+		msg.Sender = strings.TrimSpace(s.Find(".msg_sender").Text())
+		contentSel := s.Find("span.msg_content")
+		msg.Content, _ = contentSel.Html()
+		msg.Content = strings.TrimSpace(msg.Content)
+		text := strings.TrimSpace(contentSel.Text())
+		coords := extractLinkedCoords(contentSel) // typed from the celestial icon of the links
+
+		unionID := utils.DoParseI64(s.Find("[data-union-id]").AttrOr("data-union-id", ""))
+		s.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+			if m := unionIDRgx.FindStringSubmatch(a.AttrOr("href", "")); len(m) == 2 && unionID == 0 {
+				unionID = utils.DoParseI64(m[1])
+			}
+			return unionID == 0
+		})
+		if unionID != 0 {
+			invitation := &ogame.ACSInvitation{UnionID: unionID}
+			if len(coords) > 0 {
+				invitation.Target = coords[len(coords)-1]
+			}
+			if m := msgDateRgx.FindString(text); m != "" {
+				invitation.ArrivalTime, _ = time.ParseInLocation("02.01.2006 15:04:05", m, location)
+			}
+			msg.Type, msg.ACSInvitation = ogame.ACSInvitationMessage, invitation
+		} else if len(coords) >= 2 && resourceRgx.MatchString(text) {
+			transport := &ogame.TransportArrival{Origin: coords[0], Destination: coords[1], Resources: extractLabeledResources(text)}
+			msg.Type, msg.Transport = ogame.TransportArrivalMessage, transport
+		}
+		msgs = append(msgs, msg)
+	})
+	return msgs, nbPage, nil
+}
+
+func extractMarketplaceOffersFromDoc(doc *goquery.Document) ([]ogame.MarketplaceOffer, int64, error) {
+	offers := make([]ogame.MarketplaceOffer, 0)
+	nbPage := utils.DoParseI64(doc.Find("ul.pagination li").Last().AttrOr("data-page", "1"))
+	tab := ogame.MarketplaceTab(doc.Find("#marketItems").AttrOr("data-tab", ""))
+	doc.Find("div.row.item").Each(func(i int, s *goquery.Selection) {
+		id, err := utils.ParseI64(s.AttrOr("data-itemid", ""))
+		if err != nil {
+			return
+		}
+		item := s.Find(".offer .thumbnail")
+		price := s.Find(".price .thumbnail")
+		offer := ogame.MarketplaceOffer{ID: id, Tab: tab}
+		offer.ItemType = utils.DoParseI64(item.AttrOr("data-type", ""))
+		if offer.ItemType == ogame.MarketplaceItemItemType {
+			offer.ItemRef = item.AttrOr("data-id", "")
+		} else {
+			offer.ItemID = utils.DoParseI64(item.AttrOr("data-id", ""))
+		}
+		offer.Quantity = utils.ParseInt(s.Find(".offer .quantity").Text())
+		offer.PriceType = utils.DoParseI64(price.AttrOr("data-id", ""))
+		offer.Price = utils.ParseInt(s.Find(".price .quantity").Text())
+		offer.PlayerName = strings.TrimSpace(s.Find(".playerName").Text())
+		offers = append(offers, offer)
+	})
+	return offers, nbPage, nil
+}
+
+var marketplaceTokenRgx = regexp.MustCompile(`token=([^&]+)`)
+var marketTransactionIDRgx = regexp.MustCompile(`marketTransactionId=([^&]+)`)
+
 func extractMarketplaceMessagesFromDoc(doc *goquery.Document, location *time.Location) ([]ogame.MarketplaceMessage, int64, error) {
 	msgs := make([]ogame.MarketplaceMessage, 0)
 	tab := utils.DoParseI64(doc.Find("ul.pagination li").Last().AttrOr("data-tab", ""))
@@ -738,13 +953,13 @@ func extractMarketplaceMessagesFromDoc(doc *goquery.Document, location *time.Loc
 		if idStr, exists := s.Attr("data-msg-id"); exists {
 			if id, err := utils.ParseI64(idStr); err == nil {
 				href := s.Find("a.js_actionCollect").AttrOr("href", "")
-				m := regexp.MustCompile(`token=([^&]+)`).FindStringSubmatch(href)
+				m := marketplaceTokenRgx.FindStringSubmatch(href)
 				var token string
 				var marketTransactionID int64
 				if len(m) == 2 {
 					token = m[1]
 				}
-				m = regexp.MustCompile(`marketTransactionId=([^&]+)`).FindStringSubmatch(href)
+				m = marketTransactionIDRgx.FindStringSubmatch(href)
 				if len(m) == 2 {
 					marketTransactionIDStr := m[1]
 					marketTransactionID = utils.DoParseI64(marketTransactionIDStr)
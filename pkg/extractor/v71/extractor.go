@@ -73,6 +73,12 @@ func (e *Extractor) ExtractDestroyRocketsFromDoc(doc *goquery.Document) (abm, ip
 	return extractDestroyRocketsFromDoc(doc)
 }
 
+// ExtractMissiles ...
+func (e *Extractor) ExtractMissiles(pageHTML []byte) (ogame.Missiles, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractMissilesFromDoc(doc)
+}
+
 // ExtractIPM ...
 func (e *Extractor) ExtractIPM(pageHTML []byte) (duration int64, max int64, token string) {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -118,6 +124,17 @@ func (e *Extractor) ExtractAttacksFromDoc(doc *goquery.Document, ownCoords []oga
 	return e.extractAttacksFromDoc(doc, clockwork.NewRealClock(), ownCoords)
 }
 
+// ExtractEventList ...
+func (e *Extractor) ExtractEventList(pageHTML []byte) ([]ogame.FleetEvent, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractEventListFromDoc(doc, clockwork.NewRealClock())
+}
+
+// ExtractEventListFromDoc ...
+func (e *Extractor) ExtractEventListFromDoc(doc *goquery.Document) ([]ogame.FleetEvent, error) {
+	return extractEventListFromDoc(doc, clockwork.NewRealClock())
+}
+
 // ExtractAttacks ...
 func (e *Extractor) ExtractAttacks(pageHTML []byte, ownCoords []ogame.Coordinate) ([]ogame.AttackEvent, error) {
 	return e.extractAttacks(pageHTML, clockwork.NewRealClock(), ownCoords)
@@ -1,6 +1,9 @@
 package v71
 
 import (
+	"bytes"
+
+	"github.com/PuerkitoBio/goquery"
 	"github.com/alaingilbert/clockwork"
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/stretchr/testify/assert"
@@ -37,6 +40,12 @@ func TestExtractAttacksACS_v72(t *testing.T) {
 	assert.Equal(t, int64(14028), attacks[0].ID)
 	assert.Equal(t, int64(14029), attacks[1].ID)
 	assert.Equal(t, int64(673019), attacks[2].ID)
+	assert.Equal(t, int64(14028), attacks[0].UnionID)
+	assert.Equal(t, 3, len(attacks[0].Partners))
+	assert.Equal(t, int64(673009), attacks[0].Partners[0].FleetID)
+	assert.Equal(t, int64(106734), attacks[0].Partners[0].AttackerID)
+	assert.Equal(t, 2, len(attacks[1].Partners))
+	assert.Nil(t, attacks[2].Partners)
 }
 
 func TestExtractIsMobile(t *testing.T) {
@@ -215,6 +224,18 @@ func TestExtractDestroyRockets(t *testing.T) {
 	assert.Equal(t, int64(6), ipm)
 }
 
+func TestExtractMissiles(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.6.2/en/destroy_rockets.html")
+	missiles, err := NewExtractor().ExtractMissiles(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(24), missiles.AntiBallisticMissiles)
+	assert.Equal(t, int64(6), missiles.InterplanetaryMissiles)
+	assert.Equal(t, int64(4), missiles.SiloLevel)
+	assert.Equal(t, int64(40), missiles.Capacity)
+	assert.Equal(t, int64(4), missiles.FreeABM())
+	assert.Equal(t, int64(2), missiles.FreeIPM())
+}
+
 func TestExtractEspionageReport(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.1/en/spy_report.html")
 	e := NewExtractor()
@@ -340,3 +361,39 @@ func TestV71ExtractEspionageReportMessages(t *testing.T) {
 	assert.Equal(t, 0.5, msgs[1].LootPercentage)
 	assert.Equal(t, 0.5, msgs[2].LootPercentage)
 }
+
+func TestExtractEventList_v72(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.2/en/eventlist_multipleACS.html")
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	events, err := extractEventListFromDoc(doc, clockwork.NewFakeClock())
+	assert.NoError(t, err)
+	assert.Equal(t, 12, len(events))
+
+	assert.Equal(t, int64(672919), events[0].ID)
+	assert.Equal(t, ogame.Transport, events[0].MissionType)
+	assert.False(t, events[0].ReturnFlight)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 116, Position: 6, Type: ogame.PlanetType}, events[0].Origin)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 208, Position: 8, Type: ogame.PlanetType}, events[0].Destination)
+	assert.Equal(t, int64(23), events[0].Ships.SmallCargo)
+	assert.Equal(t, &ogame.Resources{Metal: 91115, Crystal: 44628, Deuterium: 13900}, events[0].Resources)
+	assert.Equal(t, int64(672914), events[2].ID)
+	assert.True(t, events[2].ReturnFlight)
+	assert.Equal(t, int64(15), events[5].Ships.LargeCargo)
+
+	// Two ACS attacks, grouped by union
+	assert.Equal(t, int64(14028), events[9].ID)
+	assert.Equal(t, ogame.GroupedAttack, events[9].MissionType)
+	assert.Equal(t, int64(14028), events[9].UnionID)
+	assert.Equal(t, int64(2), events[9].Ships.LightFighter)
+	assert.Equal(t, int64(1), events[9].Ships.SmallCargo)
+	assert.Equal(t, int64(14029), events[10].UnionID)
+	assert.Equal(t, int64(1), events[10].Ships.LightFighter)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 116, Position: 10, Type: ogame.PlanetType}, events[10].Destination)
+
+	assert.Equal(t, int64(673019), events[11].ID)
+	assert.Equal(t, ogame.Attack, events[11].MissionType)
+	assert.Equal(t, int64(0), events[11].UnionID)
+	assert.Equal(t, int64(106734), events[11].PlayerID)
+	assert.Equal(t, "Notriv", events[11].PlayerName)
+	assert.Nil(t, events[11].Resources)
+}
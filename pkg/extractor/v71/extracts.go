@@ -805,6 +805,23 @@ func extractEspionageReportFromDoc(doc *goquery.Document, location *time.Locatio
 	return report, nil
 }
 
+func extractMissilesFromDoc(doc *goquery.Document) (ogame.Missiles, error) {
+	var res ogame.Missiles
+	rows := doc.Find("#rocketForm table tr")
+	if rows.Size() < 3 {
+		return res, errors.New("failed to find missiles table")
+	}
+	res.AntiBallisticMissiles = utils.DoParseI64(rows.Eq(1).Find("td").Eq(1).Text())
+	res.InterplanetaryMissiles = utils.DoParseI64(rows.Eq(2).Find("td").Eq(1).Text())
+	// "A missile silo on level 4 can hold 20 interplanetary missiles or 40 anti-ballistic missiles."
+	nums := regexp.MustCompile(`\d+`).FindAllString(doc.Find("span.capacity").Text(), -1)
+	if len(nums) > 0 {
+		res.SiloLevel = utils.DoParseI64(nums[0])
+	}
+	res.Capacity = ogame.MissileSiloCapacity(res.SiloLevel)
+	return res, nil
+}
+
 func extractDestroyRocketsFromDoc(doc *goquery.Document) (abm, ipm int64, token string, err error) {
 	scriptTxt := doc.Find("script").Text()
 	r := regexp.MustCompile(`missileToken = "([^"]+)"`)
@@ -1073,8 +1090,20 @@ func extractAttacksFromDoc(doc *goquery.Document, clock clockwork.Clock, ownCoor
 			if attack.UnionID != 0 {
 				if allianceAttack, ok := allianceAttacks[attack.UnionID]; ok {
 					if attack.Ships != nil {
+						if allianceAttack.Ships == nil {
+							allianceAttack.Ships = new(ogame.ShipsInfos)
+						}
 						allianceAttack.Ships.Add(*attack.Ships)
 					}
+					if partner {
+						allianceAttack.Partners = append(allianceAttack.Partners, ogame.AttackPartner{
+							FleetID:      attack.ID,
+							AttackerName: attack.AttackerName,
+							AttackerID:   attack.AttackerID,
+							Origin:       attack.Origin,
+							Ships:        attack.Ships,
+						})
+					}
 					if allianceAttack.AttackerID == 0 {
 						allianceAttack.AttackerID = attack.AttackerID
 					}
@@ -1119,6 +1148,125 @@ func extractAttacksFromDoc(doc *goquery.Document, clock clockwork.Clock, ownCoor
 	return out, nil
 }
 
+func extractEventListFromDoc(doc *goquery.Document, clock clockwork.Clock) ([]ogame.FleetEvent, error) {
+	out := make([]ogame.FleetEvent, 0)
+	if doc.Find("body").Size() == 1 && v6.ExtractOGameSessionFromDoc(doc) != "" && doc.Find("div#eventListWrap").Size() == 0 {
+		return out, ogame.ErrEventsBoxNotDisplayed
+	} else if doc.Find("div#eventListWrap").Size() == 0 {
+		return out, ogame.ErrNotLogged
+	}
+	unions := make(map[int64]int)
+	doc.Find("tr.eventFleet, tr.allianceAttack").Each(func(i int, s *goquery.Selection) {
+		event := ogame.FleetEvent{}
+		m := regexp.MustCompile(`eventRow-(union)?(\d+)`).FindStringSubmatch(s.AttrOr("id", ""))
+		if len(m) == 3 {
+			event.ID = utils.DoParseI64(m[2])
+		}
+		for _, class := range strings.Fields(s.AttrOr("class", "")) {
+			if m := regexp.MustCompile(`^(?:union)?union(\d+)$`).FindStringSubmatch(class); len(m) == 2 {
+				event.UnionID = utils.DoParseI64(m[1])
+			}
+		}
+		countDown := s.Find("td.countDown")
+		if countDown.HasClass("hostile") || countDown.Find(".hostile").Size() > 0 {
+			event.Relation = ogame.HostileEvent
+		} else if countDown.HasClass("friendly") || countDown.Find(".friendly").Size() > 0 {
+			event.Relation = ogame.FriendlyEvent
+		}
+		event.MissionType = ogame.MissionID(utils.DoParseI64(s.AttrOr("data-mission-type", "")))
+		if s.HasClass("allianceAttack") {
+			event.MissionType = ogame.GroupedAttack
+		}
+		event.ReturnFlight, _ = strconv.ParseBool(s.AttrOr("data-return-flight", "false"))
+		event.ArrivalTime = time.Unix(utils.DoParseI64(s.AttrOr("data-arrival-time", "")), 0)
+		event.ArriveIn = int64(clock.Until(event.ArrivalTime).Seconds())
+		linkSendMail := s.Find("td.sendMail a.sendMail")
+		event.PlayerID = utils.DoParseI64(linkSendMail.AttrOr("data-playerid", ""))
+		event.PlayerName = linkSendMail.AttrOr("title", "")
+		event.Origin = v6.ExtractCoord(s.Find("td.coordsOrigin").Text())
+		event.Origin.Type = ogame.PlanetType
+		if s.Find("td.originFleet figure").HasClass("moon") {
+			event.Origin.Type = ogame.MoonType
+		}
+		event.OriginName = strings.TrimSpace(s.Find("td.originFleet").Text())
+		event.Destination = v6.ExtractCoord(s.Find("td.destCoords").Text())
+		event.Destination.Type = ogame.PlanetType
+		if s.Find("td.destFleet figure").HasClass("moon") {
+			event.Destination.Type = ogame.MoonType
+		} else if s.Find("td.destFleet figure").HasClass("tf") {
+			event.Destination.Type = ogame.DebrisType
+		}
+		event.DestinationName = strings.TrimSpace(s.Find("td.destFleet").Text())
+		if event.MissionType == ogame.MissileAttack {
+			event.Missiles = utils.ParseInt(s.Find("td.detailsFleet span").First().Text())
+		}
+		if s.HasClass("partnerInfo") {
+			event.Ships, _ = extractEventFleetDetails(s)
+			if idx, ok := unions[event.UnionID]; ok {
+				union := &out[idx]
+				if event.Ships != nil {
+					if union.Ships == nil {
+						union.Ships = new(ogame.ShipsInfos)
+					}
+					union.Ships.Add(*event.Ships)
+				}
+			}
+			return
+		}
+		if s.HasClass("allianceAttack") {
+			// The ships of a union are the sum of the ships of its partners
+			unions[event.UnionID] = len(out)
+		} else {
+			event.Ships, event.Resources = extractEventFleetDetails(s)
+		}
+		out = append(out, event)
+	})
+	return out, nil
+}
+
+// extractEventFleetDetails extracts the ships and the shipment from the fleet details tooltip of an event row
+func extractEventFleetDetails(s *goquery.Selection) (*ogame.ShipsInfos, *ogame.Resources) {
+	movement, exists := s.Find("td.icon_movement span, td.icon_movement_reserve span").Attr("title")
+	if !exists {
+		return nil, nil
+	}
+	root, err := html.Parse(strings.NewReader(movement))
+	if err != nil {
+		return nil, nil
+	}
+	q := goquery.NewDocumentFromNode(root)
+	var ships *ogame.ShipsInfos
+	q.Find("tr").Each(func(i int, s *goquery.Selection) {
+		tds := s.Find("td")
+		name := strings.TrimSuffix(strings.TrimSpace(tds.Eq(0).Text()), ":")
+		nbrTxt := strings.TrimSpace(tds.Eq(1).Text())
+		shipID := ogame.ShipName2ID(name)
+		if !shipID.IsShip() {
+			return
+		}
+		if ships == nil {
+			ships = new(ogame.ShipsInfos)
+		}
+		if nbrTxt == "?" {
+			ships.Set(shipID, -1)
+		} else if nbr := utils.ParseInt(nbrTxt); nbr > 0 {
+			ships.Set(shipID, nbr)
+		}
+	})
+	var resources *ogame.Resources
+	if q.Find("th").Size() >= 2 {
+		trs := q.Find("tr")
+		if trs.Size() >= 3 {
+			resources = &ogame.Resources{
+				Metal:     utils.ParseInt(trs.Eq(trs.Size() - 3).Find("td").Eq(1).Text()),
+				Crystal:   utils.ParseInt(trs.Eq(trs.Size() - 2).Find("td").Eq(1).Text()),
+				Deuterium: utils.ParseInt(trs.Eq(trs.Size() - 1).Find("td").Eq(1).Text()),
+			}
+		}
+	}
+	return ships, resources
+}
+
 func extractDMCostsFromDoc(doc *goquery.Document) (ogame.DMCosts, error) {
 	tmp := func(s *goquery.Selection) (id ogame.ID, nbr, cost int64, canBuy, isComplete bool, buyAndActivate string, token string) {
 		imgAlt := s.Find("img.queuePic").AttrOr("alt", "")
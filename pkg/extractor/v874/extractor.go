@@ -23,6 +23,12 @@ func (e *Extractor) ExtractOfferOfTheDay(pageHTML []byte) (int64, string, ogame.
 	return e.ExtractOfferOfTheDayFromDoc(doc)
 }
 
+// ExtractOfferOfTheDayDetails ...
+func (e *Extractor) ExtractOfferOfTheDayDetails(pageHTML []byte) (ogame.OfferOfTheDay, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractOfferOfTheDayDetailsFromDoc(doc)
+}
+
 // ExtractOfferOfTheDayFromDoc ...
 func (e *Extractor) ExtractOfferOfTheDayFromDoc(doc *goquery.Document) (price int64, importToken string, planetResources ogame.PlanetResources, multiplier ogame.Multiplier, err error) {
 	return extractOfferOfTheDayFromDoc(doc)
@@ -40,6 +46,12 @@ func (e *Extractor) ExtractBuffActivation(pageHTML []byte) (string, []ogame.Item
 	return e.ExtractBuffActivationFromDoc(doc)
 }
 
+// ExtractShopItems extracts the token and every shop item, with the amount in inventory, from the buffActivation page
+func (e *Extractor) ExtractShopItems(pageHTML []byte) (string, []ogame.ShopItem, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractShopItemsFromDoc(doc)
+}
+
 // ExtractBuffActivationFromDoc ...
 func (e *Extractor) ExtractBuffActivationFromDoc(doc *goquery.Document) (string, []ogame.Item, error) {
 	return extractBuffActivationFromDoc(doc)
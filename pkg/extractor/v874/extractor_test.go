@@ -13,8 +13,40 @@ func TestExtractOfferOfTheDayPrice(t *testing.T) {
 	assert.Equal(t, "2a38193e2fa6047e1d92d2f2c71c00fd", token)
 }
 
+func TestExtractOfferOfTheDayDetails(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v8.7.4/en/traderImportExport.html")
+	offer, _ := NewExtractor().ExtractOfferOfTheDayDetails(pageHTMLBytes)
+	assert.Equal(t, int64(178224), offer.Price)
+	assert.Equal(t, "rare", offer.Rarity)
+	assert.Equal(t, "An extremely valuable container", offer.Description)
+	assert.Equal(t, int64(4500), offer.BargainCost)
+	assert.Equal(t, 1.5, offer.Multiplier.Crystal)
+}
+
 func TestExtractAuction(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v8.7.4/en/traderAuctioneer.html")
 	res, _ := NewExtractor().ExtractAuction(pageHTMLBytes)
 	assert.Equal(t, "43576386810cdf91a833a6239f323f66", res.Token)
 }
+
+func TestExtractShopItems(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.1/en/buffActivation.html")
+	token, items, err := NewExtractor().ExtractShopItems(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Len(t, items, 31)
+	var found bool
+	for _, item := range items {
+		if item.Ref == "05294270032e5dc968672425ab5611998c409166" {
+			found = true
+			assert.Equal(t, "Gold Metal Booster", item.Name)
+			assert.Equal(t, int64(6), item.Amount)
+			assert.Equal(t, int64(6), item.AmountFree)
+			assert.Equal(t, int64(25000), item.Costs)
+			assert.Equal(t, int64(604800), item.Duration)
+			assert.True(t, item.HasCategory("d8d49c315fa620d9c7f1f19963970dea59a0e3be"))
+			assert.False(t, item.IsOnCooldown())
+		}
+	}
+	assert.True(t, found)
+}
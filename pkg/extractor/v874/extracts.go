@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -38,6 +39,28 @@ func extractBuffActivationFromDoc(doc *goquery.Document) (token string, items []
 	return
 }
 
+func extractShopItemsFromDoc(doc *goquery.Document) (token string, items []ogame.ShopItem, err error) {
+	scriptTxt := doc.Find("script").Text()
+	m := regexp.MustCompile(`[tT]oken = "([^"]+)"`).FindStringSubmatch(scriptTxt)
+	if len(m) != 2 {
+		return "", nil, errors.New("failed to find activate token")
+	}
+	token = m[1]
+	m = regexp.MustCompile(`items_inventory = ({[^\n]+});\n`).FindStringSubmatch(scriptTxt)
+	if len(m) != 2 {
+		return "", nil, errors.New("failed to find items inventory")
+	}
+	var inventoryMap map[string]ogame.ShopItem
+	if err = json.Unmarshal([]byte(m[1]), &inventoryMap); err != nil {
+		return "", nil, err
+	}
+	for _, item := range inventoryMap {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return
+}
+
 func extractOfferOfTheDayFromDoc(doc *goquery.Document) (price int64, importToken string, planetResources ogame.PlanetResources, multiplier ogame.Multiplier, err error) {
 	s := doc.Find("div.js_import_price")
 	if s.Size() == 0 {
@@ -71,6 +94,25 @@ func extractOfferOfTheDayFromDoc(doc *goquery.Document) (price int64, importToke
 	return
 }
 
+func extractOfferOfTheDayDetailsFromDoc(doc *goquery.Document) (offer ogame.OfferOfTheDay, err error) {
+	offer.Price, offer.Token, offer.PlanetResources, offer.Multiplier, err = extractOfferOfTheDayFromDoc(doc)
+	if err != nil {
+		return
+	}
+	detail := doc.Find("div.left_content a.detail_button")
+	offer.Description = detail.AttrOr("title", "")
+	for _, class := range strings.Fields(detail.AttrOr("class", "")) {
+		if m := regexp.MustCompile(`^r_(\w+)_140px$`).FindStringSubmatch(class); len(m) == 2 {
+			offer.Rarity = m[1]
+		}
+	}
+	script := doc.Find("script").Text()
+	if m := regexp.MustCompile(`var importChangeCost\s?=\s?(\d+);`).FindStringSubmatch(script); len(m) == 2 {
+		offer.BargainCost = utils.DoParseI64(m[1])
+	}
+	return
+}
+
 // extractAuctionFromDoc extract auction information from page "traderAuctioneer"
 func extractAuctionFromDoc(doc *goquery.Document) (ogame.Auction, error) {
 	auction := ogame.Auction{}
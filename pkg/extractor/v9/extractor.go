@@ -157,3 +157,41 @@ func (e *Extractor) ExtractTearDownButtonEnabled(pageHTML []byte) bool {
 func (e *Extractor) ExtractTearDownButtonEnabledFromDoc(doc *goquery.Document) bool {
 	return extractTearDownButtonEnabledFromDoc(doc)
 }
+
+func (e *Extractor) ExtractChatContacts(pageHTML []byte) ([]ogame.ChatContact, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return e.ExtractChatContactsFromDoc(doc)
+}
+
+// ExtractChatContactsFromDoc ...
+func (e *Extractor) ExtractChatContactsFromDoc(doc *goquery.Document) ([]ogame.ChatContact, error) {
+	return extractChatContactsFromDoc(doc, e.GetLocation())
+}
+
+// ExtractChatHistory extracts the messages of a conversation loaded with ajaxChat
+func (e *Extractor) ExtractChatHistory(pageHTML []byte) ([]ogame.ChatMsg, error) {
+	return extractChatHistory(pageHTML, e.GetLocation())
+}
+
+// ExtractPlanetRelocation extracts the planet relocation state from the planetMoveInProgress
+// and planetMoveCooldown page variables
+func (e *Extractor) ExtractPlanetRelocation(pageHTML []byte) (ogame.PlanetRelocation, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractPlanetRelocationFromDoc(doc)
+}
+
+// ExtractPlanetMoveToken extracts the token used to relocate a planet from the galaxy page
+func (e *Extractor) ExtractPlanetMoveToken(pageHTML []byte) (string, error) {
+	return extractPlanetMoveToken(pageHTML)
+}
+
+// ExtractOfficers extracts officers hiring state and expiry from the premium page
+func (e *Extractor) ExtractOfficers(pageHTML []byte) ([]ogame.Officer, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return e.ExtractOfficersFromDoc(doc)
+}
+
+// ExtractOfficersFromDoc extracts officers hiring state and expiry from the premium page
+func (e *Extractor) ExtractOfficersFromDoc(doc *goquery.Document) ([]ogame.Officer, error) {
+	return extractOfficersFromDoc(doc, e.GetLocation())
+}
@@ -2,6 +2,7 @@ package v9
 
 import (
 	"io/ioutil"
+	"strconv"
 	"testing"
 	"time"
 
@@ -213,9 +214,15 @@ func TestExtractTechnologyDetails(t *testing.T) {
 	details, _ = NewExtractor().ExtractTechnologyDetails(pageHTMLBytes)
 	assert.False(t, details.TearDownEnabled)
 
+	assert.Equal(t, ogame.Resources{Metal: 6, Crystal: 3, Energy: 9}, details.TearDownPrice)
+	assert.Equal(t, time.Second, details.TearDownDuration)
+
 	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.4/en/lifeform/technologyDetails_supplies.html")
 	details, _ = NewExtractor().ExtractTechnologyDetails(pageHTMLBytes)
 	assert.True(t, details.TearDownEnabled)
+	assert.Equal(t, int64(269), details.EnergyConsumption)
+	assert.Equal(t, ogame.Resources{Metal: 199515, Crystal: 49878}, details.TearDownPrice)
+	assert.Equal(t, 2*time.Hour+16*time.Minute+time.Second, details.TearDownDuration)
 }
 
 func TestExtractOverviewProduction_ships(t *testing.T) {
@@ -227,3 +234,105 @@ func TestExtractOverviewProduction_ships(t *testing.T) {
 	assert.Equal(t, ogame.SmallCargoID, prod[1].ID)
 	assert.Equal(t, int64(1), prod[1].Nbr)
 }
+
+func TestExtractPlanetRelocation(t *testing.T) {
+	for _, sample := range []string{"v9.0.2/es/overview.html", "v9.0.2/en/overview_all_queues.html", "v9.0.2/es/defence.html", "v9.0.2/en/lifeform/defence.html"} {
+		pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/" + sample)
+		relocation, err := NewExtractor().ExtractPlanetRelocation(pageHTMLBytes)
+		assert.NoError(t, err, sample)
+		assert.False(t, relocation.InProgress, sample)
+		assert.Equal(t, int64(0), relocation.Cooldown, sample)
+	}
+
+	relocation, err := NewExtractor().ExtractPlanetRelocation([]byte(`<script>var planetMoveInProgress = true;</script><script>var planetMoveCooldown = 86400;</script>`))
+	assert.NoError(t, err)
+	assert.True(t, relocation.InProgress)
+	assert.Equal(t, int64(86400), relocation.Cooldown)
+
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v9.0.2/es/overview.html")
+	token, err := NewExtractor().ExtractPlanetMoveToken(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "cea85ac41cc0649df13bc49f6ca58168", token)
+}
+
+func TestExtractPlanetMoveToken(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v9.0.2/es/defence.html")
+	token, err := NewExtractor().ExtractPlanetMoveToken(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "06a8f9d854fc94b14cf87bc00206ed83", token)
+}
+
+func TestExtractOfficers(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v9.0.2/en/overview_all_queues.html")
+	officers, err := NewExtractor().ExtractOfficers(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(officers))
+	for _, officer := range officers {
+		assert.False(t, officer.Active)
+		assert.True(t, officer.ExpireAt.IsZero())
+	}
+	assert.Equal(t, ogame.OfficerCommander, officers[0].Type)
+	assert.Equal(t, ogame.OfficerTechnocrat, officers[4].Type)
+
+	// The officers bar only says "Still active for more than 6 days", the expiry is unknown
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/unversioned/research_bonus.html")
+	officers, err = NewExtractor().ExtractOfficers(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(officers))
+	for _, officer := range officers {
+		assert.True(t, officer.Active)
+		assert.True(t, officer.ExpireAt.IsZero())
+	}
+
+	_, err = NewExtractor().ExtractOfficers([]byte(`<div></div>`))
+	assert.Error(t, err)
+}
+
+func TestExtractChatContacts(t *testing.T) {
+	_, err := NewExtractor().ExtractChatContacts([]byte(`<div></div>`))
+	assert.Error(t, err)
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/fleets_1.html")
+	contacts, err := NewExtractor().ExtractChatContacts(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(contacts))
+	assert.Equal(t, int64(107009), contacts[0].PlayerID)
+	assert.Equal(t, "Constable Telesto", contacts[0].PlayerName)
+	assert.Equal(t, "got it", contacts[0].LastMessage)
+	assert.Equal(t, time.Date(2018, 7, 8, 6, 4, 1, 0, time.UTC), contacts[0].LastMessageDate)
+	assert.Equal(t, int64(0), contacts[0].Unread)
+	assert.False(t, contacts[0].Online)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.5/en/overview_ships.html")
+	contacts, err = NewExtractor().ExtractChatContacts(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(contacts))
+	assert.Equal(t, int64(110877), contacts[0].PlayerID)
+	assert.Equal(t, "Engineer Lambda", contacts[0].PlayerName)
+	assert.Equal(t, "you there?", contacts[0].LastMessage)
+	assert.Equal(t, 6, contacts[0].LastMessageDate.Hour()) // messages of the day only show the time
+	assert.Equal(t, 34, contacts[0].LastMessageDate.Minute())
+	assert.Equal(t, int64(2), contacts[0].Unread)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.0/en/overview.html")
+	contacts, err = NewExtractor().ExtractChatContacts(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Empty(t, contacts)
+}
+
+func TestExtractChatHistory(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/fleets_1.html")
+	for _, pageHTML := range []string{string(pageHTMLBytes), `{"content":` + strconv.Quote(string(pageHTMLBytes)) + `}`} {
+		msgs, err := NewExtractor().ExtractChatHistory([]byte(pageHTML))
+		assert.NoError(t, err)
+		assert.Equal(t, 7, len(msgs))
+		assert.Equal(t, int64(266478), msgs[0].ID)
+		assert.Equal(t, int64(107009), msgs[0].SenderID)
+		assert.Equal(t, "Constable Telesto", msgs[0].SenderName)
+		assert.Equal(t, "sup", msgs[0].Text)
+		assert.Equal(t, time.Date(2018, 7, 8, 6, 0, 30, 0, time.UTC).Unix(), msgs[0].Date)
+		assert.Equal(t, int64(266480), msgs[2].ID)
+		assert.Equal(t, int64(0), msgs[2].SenderID) // own message
+		assert.Equal(t, "Commodore Nomad", msgs[2].SenderName)
+		assert.Equal(t, "got it", msgs[6].Text)
+	}
+}
@@ -1,6 +1,8 @@
 package v9
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -725,15 +727,10 @@ func extractTechnologyDetailsFromDoc(doc *goquery.Document) (out ogame.Technolog
 	out.TechnologyID = ogame.ID(utils.DoParseI64(doc.Find("div#technologydetails").AttrOr("data-technology-id", "")))
 
 	durationStr := doc.Find("li.build_duration time").AttrOr("datetime", "")
-	rgx := regexp.MustCompile(`PT(?:(\d+)H)?(?:(\d+)M)?(\d+)S`)
-	m := rgx.FindStringSubmatch(durationStr)
-	if len(m) != 4 {
-		return out, fmt.Errorf("failed to extract duration: %s", durationStr)
+	out.ProductionDuration, err = parseISODuration(durationStr)
+	if err != nil {
+		return out, err
 	}
-	hour := time.Duration(utils.DoParseI64(m[1])) * time.Hour
-	min := time.Duration(utils.DoParseI64(m[2])) * time.Minute
-	sec := time.Duration(utils.DoParseI64(m[3])) * time.Second
-	out.ProductionDuration = hour + min + sec
 
 	out.Level = utils.DoParseI64(doc.Find("span.level").AttrOr("data-value", "")) - 1
 
@@ -744,9 +741,32 @@ func extractTechnologyDetailsFromDoc(doc *goquery.Document) (out ogame.Technolog
 
 	out.TearDownEnabled = extractTearDownButtonEnabledFromDoc(doc)
 
+	demolitionCosts := doc.Find("table.demolition_costs").First()
+	out.TearDownPrice.Metal = utils.DoParseI64(demolitionCosts.Find("tr.metal td").AttrOr("data-value", ""))
+	out.TearDownPrice.Crystal = utils.DoParseI64(demolitionCosts.Find("tr.crystal td").AttrOr("data-value", ""))
+	out.TearDownPrice.Deuterium = utils.DoParseI64(demolitionCosts.Find("tr.deuterium td").AttrOr("data-value", ""))
+	out.TearDownPrice.Energy = utils.DoParseI64(demolitionCosts.Find("tr.energy td").AttrOr("data-value", ""))
+	out.TearDownDuration, _ = parseISODuration(demolitionCosts.Find("tr.demolition_duration time").AttrOr("datetime", ""))
+
+	out.EnergyConsumption = utils.DoParseI64(doc.Find("li.additional_energy_consumption span.value").AttrOr("data-value", ""))
+	out.EnergyProduction = utils.DoParseI64(doc.Find("li.energy_production span.value").AttrOr("data-value", ""))
+
 	return out, err
 }
 
+// parseISODuration parses durations such as "PT5H6M4S"
+func parseISODuration(durationStr string) (time.Duration, error) {
+	rgx := regexp.MustCompile(`PT(?:(\d+)H)?(?:(\d+)M)?(\d+)S`)
+	m := rgx.FindStringSubmatch(durationStr)
+	if len(m) != 4 {
+		return 0, fmt.Errorf("failed to extract duration: %s", durationStr)
+	}
+	hour := time.Duration(utils.DoParseI64(m[1])) * time.Hour
+	min := time.Duration(utils.DoParseI64(m[2])) * time.Minute
+	sec := time.Duration(utils.DoParseI64(m[3])) * time.Second
+	return hour + min + sec, nil
+}
+
 func extractTearDownButtonEnabledFromDoc(doc *goquery.Document) (out bool) {
 	if doc.Find("button.downgrade").Length() == 1 {
 		if _, exists := doc.Find("button.downgrade").Attr("disabled"); !exists {
@@ -755,3 +775,117 @@ func extractTearDownButtonEnabledFromDoc(doc *goquery.Document) (out bool) {
 	}
 	return
 }
+
+var planetMoveInProgressRgx = regexp.MustCompile(`var planetMoveInProgress\s*=\s*(true|false);`)
+var planetMoveCooldownRgx = regexp.MustCompile(`var planetMoveCooldown\s*=\s*(-?\d+);`)
+
+func extractPlanetRelocationFromDoc(doc *goquery.Document) (out ogame.PlanetRelocation, err error) {
+	scriptTxt := doc.Find("script").Text()
+	if m := planetMoveInProgressRgx.FindStringSubmatch(scriptTxt); len(m) == 2 {
+		out.InProgress = m[1] == "true"
+	}
+	// planetMoveCooldown is negative once the cooldown is over
+	if m := planetMoveCooldownRgx.FindStringSubmatch(scriptTxt); len(m) == 2 {
+		out.Cooldown = utils.MaxInt(utils.DoParseI64(m[1]), 0)
+	}
+	return
+}
+
+func extractPlanetMoveToken(pageHTML []byte) (string, error) {
+	m := regexp.MustCompile(`(?:planetMoveToken|token)\s*[=:]\s*["']([^"']+)["']`).FindSubmatch(pageHTML)
+	if len(m) != 2 {
+		return "", errors.New("failed to find planet move token")
+	}
+	return string(m[1]), nil
+}
+
+func extractOfficersFromDoc(doc *goquery.Document, location *time.Location) ([]ogame.Officer, error) {
+	classes := map[int64]string{
+		ogame.OfficerCommander:  "commander",
+		ogame.OfficerAdmiral:    "admiral",
+		ogame.OfficerEngineer:   "engineer",
+		ogame.OfficerGeologist:  "geologist",
+		ogame.OfficerTechnocrat: "technocrat",
+	}
+	if doc.Find("div#officers").Size() == 0 {
+		return nil, errors.New("failed to find officers")
+	}
+	dateRgx := regexp.MustCompile(`(\d{2}\.\d{2}\.\d{4} \d{2}:\d{2}:\d{2})`)
+	out := make([]ogame.Officer, 0, len(classes))
+	for typ := ogame.OfficerCommander; typ <= ogame.OfficerTechnocrat; typ++ {
+		officer := ogame.Officer{Type: typ}
+		officer.Active = doc.Find("div#officers a." + classes[typ]).HasClass("on")
+		if officer.Active {
+			detail := doc.Find(`a.detail_button[ref="` + utils.FI64(typ) + `"]`)
+			txt := detail.AttrOr("title", "") + " " + detail.AttrOr("data-tooltip-title", "") + " " + detail.Closest(".officer, li").Find(".premium_info").Text()
+			if m := dateRgx.FindStringSubmatch(txt); len(m) == 2 {
+				officer.ExpireAt, _ = time.ParseInLocation("02.01.2006 15:04:05", m[1], location)
+			}
+		}
+		out = append(out, officer)
+	}
+	return out, nil
+}
+
+func extractChatContactsFromDoc(doc *goquery.Document, location *time.Location) (out []ogame.ChatContact, err error) {
+	items := doc.Find("#chatBar li.chat_bar_list_item[data-playerid], .playerlist_item[data-playerid]")
+	if items.Size() == 0 && doc.Find("#chatBar").Size() == 0 {
+		return out, errors.New("failed to find chat contacts")
+	}
+	items.Each(func(i int, s *goquery.Selection) {
+		c := ogame.ChatContact{}
+		c.PlayerID = utils.DoParseI64(s.AttrOr("data-playerid", "0"))
+		c.PlayerName = strings.TrimSpace(s.Find(".cb_playername, .playername").First().Text())
+		lastMsg := s.Find("li.chat_msg").Last()
+		c.LastMessage = strings.TrimSpace(lastMsg.Find(".msg_content").Text())
+		c.LastMessageDate, _ = parseChatDate(lastMsg.Find(".msg_head .msg_date").Text(), location)
+		c.Unread = utils.DoParseI64(s.Find(".new_msg_count").AttrOr("data-new-messages", "0"))
+		c.Online = s.Find(".playerstatus").HasClass("online")
+		out = append(out, c)
+	})
+	return
+}
+
+// parseChatDate parses the date of a chat message, messages of the day only show the time
+func parseChatDate(str string, location *time.Location) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	if date, err := time.ParseInLocation("02.01.2006 15:04:05", str, location); err == nil {
+		return date, nil
+	}
+	clock, err := time.ParseInLocation("15:04:05", str, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	now := time.Now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, location), nil
+}
+
+// extractChatHistory parses the messages of a conversation, either the html fragment or the json wrapping it.
+// The sender id is only known for the messages of the other player (data-foreign-player-id), own messages
+// (li.odd) have a sender id of 0.
+func extractChatHistory(pageHTML []byte, location *time.Location) (out []ogame.ChatMsg, err error) {
+	var res struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(pageHTML, &res); err == nil {
+		pageHTML = []byte(res.Content)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	if err != nil {
+		return out, err
+	}
+	doc.Find("li.chat_msg[data-chat-id]").Each(func(i int, s *goquery.Selection) {
+		m := ogame.ChatMsg{}
+		m.ID = utils.DoParseI64(s.AttrOr("data-chat-id", "0"))
+		if !s.HasClass("odd") {
+			m.SenderID = utils.DoParseI64(s.Closest("[data-foreign-player-id]").AttrOr("data-foreign-player-id", "0"))
+		}
+		m.SenderName = strings.TrimSpace(s.Find(".msg_head .msg_title").Text())
+		m.Text = strings.TrimSpace(s.Find(".msg_content").Text())
+		if date, err := parseChatDate(s.Find(".msg_head .msg_date").Text(), location); err == nil {
+			m.Date = date.Unix()
+		}
+		out = append(out, m)
+	})
+	return
+}
@@ -116,7 +116,9 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 
 func (c *Client) do(req *http.Request) (*http.Response, error) {
 	c.incrRPS()
-	req.Header.Add("User-Agent", c.userAgent)
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 	resp, err := c.Client.Do(req)
 	if err != nil {
 		return nil, err
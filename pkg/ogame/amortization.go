@@ -0,0 +1,47 @@
+package ogame
+
+import (
+	"math"
+	"time"
+)
+
+// Ratio trade rates between resources, eg: Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+type Ratio struct {
+	Metal     float64
+	Crystal   float64
+	Deuterium float64
+}
+
+// MetalValue converts resources to their value in metal using the trade rates
+func (r Ratio) MetalValue(res Resources) float64 {
+	if r.Metal <= 0 || r.Crystal <= 0 || r.Deuterium <= 0 {
+		r = Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+	}
+	return float64(res.Metal) +
+		float64(res.Crystal)*r.Metal/r.Crystal +
+		float64(res.Deuterium)*r.Metal/r.Deuterium
+}
+
+// Investment a possible upgrade, ranked by payback time in an amortization plan
+type Investment struct {
+	CelestialID      CelestialID // 0 for researches, which affect every planet
+	ID               ID
+	Level            int64
+	Cost             Resources     // total cost, including astrophysics levels and colony mines for a new planet
+	Gain             Resources     // hourly production gained
+	ConstructionTime time.Duration // time before the investment starts producing
+	PaybackTime      time.Duration // construction time + time to produce the cost back, -1 if it never pays back
+}
+
+// PaybackTime returns how long it takes for an hourly gain to pay back cost, -1 if it never does
+func PaybackTime(cost, hourlyGain Resources, rates Ratio) time.Duration {
+	gain := rates.MetalValue(hourlyGain)
+	if gain <= 0 {
+		return -1
+	}
+	hours := rates.MetalValue(cost) / gain
+	if hours > math.MaxInt64/float64(time.Hour) {
+		return -1
+	}
+	return time.Duration(hours * float64(time.Hour)).Round(time.Second)
+}
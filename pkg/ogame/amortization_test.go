@@ -0,0 +1,23 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatio_MetalValue(t *testing.T) {
+	rates := Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+	assert.Equal(t, 1000.0, rates.MetalValue(Resources{Metal: 1000}))
+	assert.Equal(t, 1500.0, rates.MetalValue(Resources{Crystal: 1000}))
+	assert.Equal(t, 3000.0, rates.MetalValue(Resources{Deuterium: 1000}))
+	assert.Equal(t, 3000.0, Ratio{}.MetalValue(Resources{Deuterium: 1000}))
+}
+
+func TestPaybackTime(t *testing.T) {
+	rates := Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+	assert.Equal(t, 10*time.Hour, PaybackTime(Resources{Metal: 10000}, Resources{Metal: 1000}, rates))
+	assert.Equal(t, 5*time.Hour, PaybackTime(Resources{Metal: 15000}, Resources{Crystal: 2000}, rates))
+	assert.Equal(t, time.Duration(-1), PaybackTime(Resources{Metal: 15000}, Resources{}, rates))
+}
@@ -0,0 +1,25 @@
+package ogame
+
+import (
+	"net/url"
+	"strings"
+)
+
+// APIKeyLang returns the language of a report api key (eg: "en" for "sr-en-152-..."), "en" if the key is malformed
+func APIKeyLang(apiKey string) string {
+	parts := strings.Split(apiKey, "-")
+	if len(parts) < 4 || parts[1] == "" {
+		return "en"
+	}
+	return parts[1]
+}
+
+// TrashsimURL returns the link to simulate an attack with the espionage report api key (sr-) on trashsim
+func TrashsimURL(apiKey string) string {
+	return "https://trashsim.oplanet.eu/" + APIKeyLang(apiKey) + "?SR_KEY=" + url.QueryEscape(apiKey)
+}
+
+// OgotchaURL returns the link to convert the combat report api key (cr-) on ogotcha
+func OgotchaURL(apiKey string) string {
+	return "https://ogotcha.oplanet.eu/" + APIKeyLang(apiKey) + "?CR_KEY=" + url.QueryEscape(apiKey)
+}
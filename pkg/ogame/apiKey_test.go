@@ -0,0 +1,17 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyLang(t *testing.T) {
+	assert.Equal(t, "fr", APIKeyLang("sr-fr-152-a1ccdadbf7fad0d2c8f16aa6e322b456f0cc1d08"))
+	assert.Equal(t, "en", APIKeyLang("invalid"))
+}
+
+func TestTrashsimURL(t *testing.T) {
+	assert.Equal(t, "https://trashsim.oplanet.eu/en?SR_KEY=sr-en-152-a1cc", TrashsimURL("sr-en-152-a1cc"))
+	assert.Equal(t, "https://ogotcha.oplanet.eu/de?CR_KEY=cr-de-152-a1cc", OgotchaURL("cr-de-152-a1cc"))
+}
@@ -19,6 +19,16 @@ type AttackEvent struct {
 	UnionID         int64
 	Missiles        int64
 	Ships           *ShipsInfos
+	Partners        []AttackPartner // fleets of the union, only for GroupedAttack
+}
+
+// AttackPartner a fleet taking part in an ACS attack
+type AttackPartner struct {
+	FleetID      int64
+	AttackerName string
+	AttackerID   int64
+	Origin       Coordinate
+	Ships        *ShipsInfos // nil when not visible
 }
 
 func (a AttackEvent) String() string {
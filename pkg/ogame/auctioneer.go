@@ -1,5 +1,18 @@
 package ogame
 
+// AuctioneerEvent event received on the auctioneer websocket, one of AuctioneerNewBid, AuctioneerNewAuction,
+// AuctioneerAuctionFinished, AuctioneerTimeRemaining or AuctioneerNextAuction
+type AuctioneerEvent interface {
+	isAuctioneerEvent()
+}
+
+// AuctionItem item sold by the auctioneer
+type AuctionItem struct {
+	UUID   string
+	Image  string
+	Rarity string // common, rare, epic
+}
+
 // AuctioneerNewBid ...
 type AuctioneerNewBid struct {
 	Sum       int64
@@ -18,6 +31,8 @@ type AuctioneerNewBid struct {
 type AuctioneerNewAuction struct {
 	AuctionID int64
 	Approx    int64
+	Item      AuctionItem
+	OldItem   AuctionItem // item of the auction that just finished
 }
 
 // AuctioneerAuctionFinished ...
@@ -45,3 +60,9 @@ type AuctioneerTimeRemaining struct {
 type AuctioneerNextAuction struct {
 	Secs int64
 }
+
+func (AuctioneerNewBid) isAuctioneerEvent()          {}
+func (AuctioneerNewAuction) isAuctioneerEvent()      {}
+func (AuctioneerAuctionFinished) isAuctioneerEvent() {}
+func (AuctioneerTimeRemaining) isAuctioneerEvent()   {}
+func (AuctioneerNextAuction) isAuctioneerEvent()     {}
@@ -0,0 +1,13 @@
+package ogame
+
+import "time"
+
+// ChatContact a conversation of the chat page
+type ChatContact struct {
+	PlayerID        int64
+	PlayerName      string
+	LastMessage     string
+	LastMessageDate time.Time
+	Unread          int64 // number of unread messages
+	Online          bool
+}
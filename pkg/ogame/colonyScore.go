@@ -0,0 +1,159 @@
+package ogame
+
+import (
+	"sort"
+)
+
+// colonyPositions approximate fields range and maximum temperature range of a new colony, by position.
+// The minimum temperature of a planet is 40°C below its maximum.
+var colonyPositions = [15]struct {
+	minFields, maxFields int64
+	minTemp, maxTemp     int64
+}{
+	{96, 172, 220, 260},
+	{104, 176, 170, 210},
+	{112, 182, 120, 160},
+	{118, 208, 70, 110},
+	{133, 213, 60, 100},
+	{146, 219, 50, 90},
+	{152, 225, 40, 80},
+	{156, 250, 30, 70},
+	{150, 228, 20, 60},
+	{142, 216, 10, 50},
+	{136, 205, 0, 40},
+	{125, 184, -10, 30},
+	{112, 172, -50, -10},
+	{100, 154, -90, -50},
+	{90, 143, -130, -90},
+}
+
+// ExpectedFields returns the fields range of a new colony at position, before the bonus of the server settings
+func ExpectedFields(position int64) (minFields, maxFields int64) {
+	if position < 1 || position > 15 {
+		return 0, 0
+	}
+	p := colonyPositions[position-1]
+	return p.minFields, p.maxFields
+}
+
+// ExpectedTemperature returns the average temperature of a new colony at position
+func ExpectedTemperature(position int64) Temperature {
+	if position < 1 || position > 15 {
+		return Temperature{}
+	}
+	p := colonyPositions[position-1]
+	maxTemp := (p.minTemp + p.maxTemp) / 2
+	return Temperature{Min: maxTemp - 40, Max: maxTemp}
+}
+
+// ColonyScoreWeights importance of each criteria of a colony slot score
+type ColonyScoreWeights struct {
+	Fields      float64 // bigger planets
+	Temperature float64 // colder planets (more deuterium), negative to prefer hotter planets (more solar satellites energy)
+	Proximity   float64 // close to the planets of the player
+	Threat      float64 // penalty for stronger active players around
+}
+
+// DefaultColonyScoreWeights favors big planets close to the player planets
+var DefaultColonyScoreWeights = ColonyScoreWeights{Fields: 1, Temperature: 0.2, Proximity: 0.5, Threat: 0.5}
+
+// ColonySlot scored candidate position for a new colony
+type ColonySlot struct {
+	Coordinate  Coordinate
+	MinFields   int64
+	MaxFields   int64
+	Temperature Temperature
+	Distance    int64   // systems to the closest planet of the player, -1 if none in the galaxy
+	Threat      float64 // stronger active players around, the closest weighting the most
+	Score       float64
+}
+
+// ColonyScorer scores the free positions of the galaxy for a new colony
+type ColonyScorer struct {
+	Weights     ColonyScoreWeights
+	Planets     []Coordinate // planets of the player
+	Rank        int64        // highscore rank of the player, players ranked better around a slot are threats
+	NbSystems   int64        // number of systems per galaxy
+	DonutSystem bool
+	ThreatRange int64 // systems around a slot checked for threats
+	BonusFields int64 // fields added to every planet by the server settings
+}
+
+// FreeColonySlots returns the empty positions of the systems
+func FreeColonySlots(systems []SystemInfos) []Coordinate {
+	out := make([]Coordinate, 0)
+	for _, system := range systems {
+		var i int64
+		for i = 1; i <= 15; i++ {
+			if system.Position(i) == nil {
+				out = append(out, Coordinate{Galaxy: system.Galaxy(), System: system.System(), Position: i, Type: PlanetType})
+			}
+		}
+	}
+	return out
+}
+
+// Score returns the scored slot, systems are the galaxy systems known around coord, used for the threat
+func (s ColonyScorer) Score(coord Coordinate, systems []SystemInfos) ColonySlot {
+	slot := ColonySlot{Coordinate: coord, Temperature: ExpectedTemperature(coord.Position), Distance: -1}
+	slot.MinFields, slot.MaxFields = ExpectedFields(coord.Position)
+	if slot.MaxFields > 0 {
+		slot.MinFields += s.BonusFields
+		slot.MaxFields += s.BonusFields
+	}
+	for _, planet := range s.Planets {
+		if planet.Galaxy != coord.Galaxy {
+			continue
+		}
+		distance := SystemDistance(s.NbSystems, planet.System, coord.System, s.DonutSystem)
+		if slot.Distance < 0 || distance < slot.Distance {
+			slot.Distance = distance
+		}
+	}
+	for _, system := range systems {
+		if system.Galaxy() != coord.Galaxy {
+			continue
+		}
+		distance := SystemDistance(s.NbSystems, system.System(), coord.System, s.DonutSystem)
+		if distance > s.ThreatRange {
+			continue
+		}
+		system.Each(func(planet *PlanetInfos) {
+			if isColonyThreat(planet, s.Rank) {
+				slot.Threat += 1 / float64(1+distance)
+			}
+		})
+	}
+
+	fields := float64(slot.MinFields+slot.MaxFields) / 2 / 250
+	temperature := float64(260-slot.Temperature.Max) / 390
+	var proximity float64
+	if slot.Distance >= 0 {
+		proximity = 1 / (1 + float64(slot.Distance)/10)
+	} else if len(s.Planets) == 0 {
+		proximity = 1
+	}
+	slot.Score = s.Weights.Fields*fields + s.Weights.Temperature*temperature + s.Weights.Proximity*proximity - s.Weights.Threat*slot.Threat
+	return slot
+}
+
+// RankSlots returns the free positions of the systems, best first
+func (s ColonyScorer) RankSlots(systems []SystemInfos) []ColonySlot {
+	out := make([]ColonySlot, 0)
+	for _, coord := range FreeColonySlots(systems) {
+		out = append(out, s.Score(coord, systems))
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// isColonyThreat returns true if planet belongs to an active player ranked better than rank
+func isColonyThreat(planet *PlanetInfos, rank int64) bool {
+	if planet == nil || planet.Destroyed || planet.Inactive || planet.Vacation || planet.Banned || planet.Administrator {
+		return false
+	}
+	if planet.Player.Rank <= 0 {
+		return false
+	}
+	return rank <= 0 || planet.Player.Rank < rank
+}
@@ -0,0 +1,67 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpectedFieldsAndTemperature(t *testing.T) {
+	minFields, maxFields := ExpectedFields(8)
+	assert.Equal(t, int64(156), minFields)
+	assert.Equal(t, int64(250), maxFields)
+	minFields, maxFields = ExpectedFields(16)
+	assert.Equal(t, int64(0), minFields)
+	assert.Equal(t, int64(0), maxFields)
+	assert.Equal(t, Temperature{Min: 10, Max: 50}, ExpectedTemperature(8))
+	assert.Equal(t, Temperature{Min: -150, Max: -110}, ExpectedTemperature(15))
+}
+
+func TestColonyScorer(t *testing.T) {
+	var crowded SystemInfos
+	crowded.Tmpgalaxy, crowded.Tmpsystem = 1, 100
+	strong := &PlanetInfos{}
+	strong.Player.Rank = 10
+	weak := &PlanetInfos{}
+	weak.Player.Rank = 500
+	inactive := &PlanetInfos{Inactive: true}
+	inactive.Player.Rank = 1
+	crowded.Tmpplanets[0] = strong
+	crowded.Tmpplanets[1] = weak
+	crowded.Tmpplanets[2] = inactive
+	var empty SystemInfos
+	empty.Tmpgalaxy, empty.Tmpsystem = 1, 110
+
+	assert.Equal(t, 12, len(FreeColonySlots([]SystemInfos{crowded})))
+
+	scorer := ColonyScorer{
+		Weights:     DefaultColonyScoreWeights,
+		Planets:     []Coordinate{{1, 100, 8, PlanetType}},
+		Rank:        100,
+		NbSystems:   499,
+		ThreatRange: 5,
+	}
+	systems := []SystemInfos{crowded, empty}
+	slot := scorer.Score(Coordinate{1, 100, 8, PlanetType}, systems)
+	assert.Equal(t, int64(0), slot.Distance)
+	assert.Equal(t, 1.0, slot.Threat)
+	far := scorer.Score(Coordinate{1, 110, 8, PlanetType}, systems)
+	assert.Equal(t, int64(10), far.Distance)
+	assert.Equal(t, 0.0, far.Threat)
+	otherGalaxy := scorer.Score(Coordinate{2, 100, 8, PlanetType}, systems)
+	assert.Equal(t, int64(-1), otherGalaxy.Distance)
+	assert.True(t, far.Score > otherGalaxy.Score)
+
+	scorer.BonusFields = 30
+	bonus := scorer.Score(Coordinate{1, 110, 8, PlanetType}, systems)
+	assert.Equal(t, far.MinFields+30, bonus.MinFields)
+	assert.Equal(t, far.MaxFields+30, bonus.MaxFields)
+	scorer.BonusFields = 0
+
+	ranked := scorer.RankSlots(systems)
+	assert.Equal(t, 27, len(ranked))
+	assert.Equal(t, Coordinate{1, 110, 8, PlanetType}, ranked[0].Coordinate)
+	for i := 1; i < len(ranked); i++ {
+		assert.True(t, ranked[i-1].Score >= ranked[i].Score)
+	}
+}
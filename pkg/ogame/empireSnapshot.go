@@ -0,0 +1,114 @@
+package ogame
+
+import "time"
+
+// EmpireSnapshot planets and moons of the empire page at a given time
+type EmpireSnapshot struct {
+	Time       time.Time
+	Celestials []EmpireCelestial
+}
+
+// Celestial returns the celestial with the given id, nil if it is not part of the snapshot
+func (s EmpireSnapshot) Celestial(id CelestialID) *EmpireCelestial {
+	for i := range s.Celestials {
+		if s.Celestials[i].ID == id {
+			return &s.Celestials[i]
+		}
+	}
+	return nil
+}
+
+// EmpireCelestialDiff changes of a celestial between two empire snapshots
+type EmpireCelestialDiff struct {
+	ID         CelestialID
+	Name       string
+	Type       CelestialType
+	Coordinate Coordinate
+	Added      bool         // celestial is only part of the newest snapshot
+	Removed    bool         // celestial is only part of the oldest snapshot
+	Resources  Resources    // signed difference of the resources
+	Changes    map[ID]int64 // signed difference of the buildings levels, ships and defenses, only the ones that changed
+}
+
+// EmpireDiff changes between two empire snapshots
+type EmpireDiff struct {
+	From       time.Time
+	To         time.Time
+	Researches map[ID]int64 // signed difference of the researches levels, only the ones that changed
+	Celestials []EmpireCelestialDiff
+}
+
+// DiffEmpire returns the changes between the snapshots a (oldest) and b (newest),
+// celestials without any change are omitted
+func DiffEmpire(a, b EmpireSnapshot) EmpireDiff {
+	diff := EmpireDiff{From: a.Time, To: b.Time, Researches: make(map[ID]int64)}
+	var researchesA, researchesB Researches
+	if len(a.Celestials) > 0 {
+		researchesA = a.Celestials[0].Researches
+	}
+	if len(b.Celestials) > 0 {
+		researchesB = b.Celestials[0].Researches
+	}
+	for _, tech := range Technologies {
+		if delta := researchesB.ByID(tech.GetID()) - researchesA.ByID(tech.GetID()); delta != 0 {
+			diff.Researches[tech.GetID()] = delta
+		}
+	}
+	for _, celestialB := range b.Celestials {
+		celestialA := a.Celestial(celestialB.ID)
+		if celestialA == nil {
+			celestialDiff := diffEmpireCelestial(EmpireCelestial{}, celestialB)
+			celestialDiff.Added = true
+			diff.Celestials = append(diff.Celestials, celestialDiff)
+			continue
+		}
+		celestialDiff := diffEmpireCelestial(*celestialA, celestialB)
+		if len(celestialDiff.Changes) > 0 || celestialDiff.Resources != (Resources{}) {
+			diff.Celestials = append(diff.Celestials, celestialDiff)
+		}
+	}
+	for _, celestialA := range a.Celestials {
+		if b.Celestial(celestialA.ID) == nil {
+			celestialDiff := diffEmpireCelestial(celestialA, EmpireCelestial{})
+			celestialDiff.ID, celestialDiff.Name, celestialDiff.Type, celestialDiff.Coordinate = celestialA.ID, celestialA.Name, celestialA.Type, celestialA.Coordinate
+			celestialDiff.Removed = true
+			diff.Celestials = append(diff.Celestials, celestialDiff)
+		}
+	}
+	return diff
+}
+
+func diffEmpireCelestial(a, b EmpireCelestial) EmpireCelestialDiff {
+	diff := EmpireCelestialDiff{
+		ID:         b.ID,
+		Name:       b.Name,
+		Type:       b.Type,
+		Coordinate: b.Coordinate,
+		Resources: Resources{
+			Metal:     b.Resources.Metal - a.Resources.Metal,
+			Crystal:   b.Resources.Crystal - a.Resources.Crystal,
+			Deuterium: b.Resources.Deuterium - a.Resources.Deuterium,
+			Energy:    b.Resources.Energy - a.Resources.Energy,
+		},
+		Changes: make(map[ID]int64),
+	}
+	set := func(id ID, delta int64) {
+		if delta != 0 {
+			diff.Changes[id] = delta
+		}
+	}
+	for _, building := range Buildings {
+		id := building.GetID()
+		if id == SolarSatelliteID { // counted with the ships
+			continue
+		}
+		set(id, b.Supplies.ByID(id)+b.Facilities.ByID(id)-a.Supplies.ByID(id)-a.Facilities.ByID(id))
+	}
+	for _, ship := range Ships {
+		set(ship.GetID(), b.Ships.ByID(ship.GetID())-a.Ships.ByID(ship.GetID()))
+	}
+	for _, defense := range Defenses {
+		set(defense.GetID(), b.Defenses.ByID(defense.GetID())-a.Defenses.ByID(defense.GetID()))
+	}
+	return diff
+}
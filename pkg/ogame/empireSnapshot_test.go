@@ -0,0 +1,33 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEmpire(t *testing.T) {
+	a := EmpireSnapshot{Time: time.Unix(1000, 0), Celestials: []EmpireCelestial{
+		{ID: 1, Name: "Home", Resources: Resources{Metal: 100}, Supplies: ResourcesBuildings{MetalMine: 10}, Researches: Researches{EnergyTechnology: 5}},
+		{ID: 2, Name: "Same", Ships: ShipsInfos{SmallCargo: 5}},
+		{ID: 3, Name: "Lost"},
+	}}
+	b := EmpireSnapshot{Time: time.Unix(2000, 0), Celestials: []EmpireCelestial{
+		{ID: 1, Name: "Home", Resources: Resources{Metal: 50}, Supplies: ResourcesBuildings{MetalMine: 11}, Facilities: Facilities{Shipyard: 2},
+			Ships: ShipsInfos{LargeCargo: 3, SolarSatellite: 4}, Researches: Researches{EnergyTechnology: 6}},
+		{ID: 2, Name: "Same", Ships: ShipsInfos{SmallCargo: 5}},
+		{ID: 4, Name: "New", Facilities: Facilities{RoboticsFactory: 1}},
+	}}
+	diff := DiffEmpire(a, b)
+	assert.Equal(t, time.Unix(1000, 0), diff.From)
+	assert.Equal(t, map[ID]int64{EnergyTechnologyID: 1}, diff.Researches)
+	assert.Equal(t, 3, len(diff.Celestials))
+	assert.Equal(t, CelestialID(1), diff.Celestials[0].ID)
+	assert.Equal(t, Resources{Metal: -50}, diff.Celestials[0].Resources)
+	assert.Equal(t, map[ID]int64{MetalMineID: 1, ShipyardID: 2, LargeCargoID: 3, SolarSatelliteID: 4}, diff.Celestials[0].Changes)
+	assert.True(t, diff.Celestials[1].Added)
+	assert.Equal(t, map[ID]int64{RoboticsFactoryID: 1}, diff.Celestials[1].Changes)
+	assert.True(t, diff.Celestials[2].Removed)
+	assert.Equal(t, "Lost", diff.Celestials[2].Name)
+}
@@ -1,6 +1,10 @@
 package ogame
 
-import "errors"
+import (
+	"errors"
+	"strings"
+	"time"
+)
 
 // ErrNotLogged returned when the bot is not logged
 var ErrNotLogged = errors.New("not logged")
@@ -20,6 +24,9 @@ var ErrOTPInvalid = errors.New("otp invalid")
 // ErrAccountNotFound returned when the account is not found
 var ErrAccountNotFound = errors.New("account not found")
 
+// ErrBearerTokenExpired returned when the gameforge bearer token is expired or revoked
+var ErrBearerTokenExpired = errors.New("bearer token expired")
+
 // ErrAccountBlocked returned when account is banned
 var ErrAccountBlocked = errors.New("account is blocked")
 
@@ -29,6 +36,9 @@ var ErrInvalidPlanetID = errors.New("invalid planet id")
 // ErrAllSlotsInUse returned when all slots are in use
 var ErrAllSlotsInUse = errors.New("all slots are in use")
 
+// ErrSlotsReserved returned when the free slots are reserved for other missions
+var ErrSlotsReserved = errors.New("free slots are reserved for other missions")
+
 // ErrBotInactive returned when the bot is not active
 var ErrBotInactive = errors.New("bot is not active")
 
@@ -62,3 +72,142 @@ var (
 	ErrNoEventsRunning                    = errors.New("there are currently no events running")
 	ErrPlanetAlreadyReservedForRelocation = errors.New("this planet has already been reserved for a relocation")
 )
+
+// ErrInvalidPlanetName returned when trying to rename a planet with an invalid name
+var ErrInvalidPlanetName = errors.New("invalid planet name")
+
+// ErrNotEnoughDarkMatter returned when the player does not have enough dark matter for an action
+var ErrNotEnoughDarkMatter = errors.New("not enough dark matter")
+
+// ErrInvalidTrade returned when a resource exchange does not respect the merchant rates
+var ErrInvalidTrade = errors.New("invalid trade")
+
+// ErrMarketplaceDisabled returned when the marketplace is not available on the server
+var ErrMarketplaceDisabled = errors.New("marketplace is disabled")
+
+// ErrDeadlineUnreachable returned when a fleet cannot arrive before the requested time, even at full speed
+var ErrDeadlineUnreachable = errors.New("fleet cannot arrive before the deadline")
+
+// ErrInvalidSpeed returned when a fleet speed is not available to the player character class
+var ErrInvalidSpeed = errors.New("invalid fleet speed")
+
+// ErrUnsupportedMessagesTab returned when the messages of a tab cannot be parsed
+var ErrUnsupportedMessagesTab = errors.New("unsupported messages tab")
+
+// ErrVacationModeLocked returned when vacation mode cannot be disabled yet, the error is a *VacationModeLockedError
+var ErrVacationModeLocked = errors.New("vacation mode cannot be disabled yet")
+
+// VacationModeLockedError returned when trying to disable vacation mode before its minimum duration is over
+type VacationModeLockedError struct {
+	Until time.Time // earliest time at which vacation mode can be disabled, zero if unknown
+}
+
+// NewVacationModeLockedError ...
+func NewVacationModeLockedError(until time.Time) *VacationModeLockedError {
+	return &VacationModeLockedError{Until: until}
+}
+
+func (e *VacationModeLockedError) Error() string {
+	if e.Until.IsZero() {
+		return ErrVacationModeLocked.Error()
+	}
+	return ErrVacationModeLocked.Error() + ", until " + e.Until.String()
+}
+
+// Is makes errors.Is(err, ErrVacationModeLocked) work
+func (e *VacationModeLockedError) Is(target error) bool {
+	return target == ErrVacationModeLocked
+}
+
+// ErrInvalidNickname returned when trying to change the player name to an invalid or already used name
+var ErrInvalidNickname = errors.New("invalid nickname")
+
+// ErrNicknameChangeCooldown returned when the player name was changed too recently to be changed again,
+// the error is a *NicknameChangeCooldownError
+var ErrNicknameChangeCooldown = errors.New("nickname was changed too recently")
+
+// NicknameChangeCooldownError returned when trying to change the player name before the weekly cooldown is over
+type NicknameChangeCooldownError struct {
+	Until time.Time // earliest time at which the name can be changed again, zero if unknown
+}
+
+// NewNicknameChangeCooldownError ...
+func NewNicknameChangeCooldownError(until time.Time) *NicknameChangeCooldownError {
+	return &NicknameChangeCooldownError{Until: until}
+}
+
+func (e *NicknameChangeCooldownError) Error() string {
+	if e.Until.IsZero() {
+		return ErrNicknameChangeCooldown.Error()
+	}
+	return ErrNicknameChangeCooldown.Error() + ", until " + e.Until.String()
+}
+
+// Is makes errors.Is(err, ErrNicknameChangeCooldown) work
+func (e *NicknameChangeCooldownError) Is(target error) bool {
+	return target == ErrNicknameChangeCooldown
+}
+
+// ErrNoExpeditionDebris returned when there is nothing to harvest in the expedition debris field
+var ErrNoExpeditionDebris = errors.New("no expedition debris field")
+
+// ErrTearDownNotAllowed returned when the game does not allow to tear down an object, the error is a *TearDownNotAllowedError
+var ErrTearDownNotAllowed = errors.New("tear down not allowed")
+
+// TearDownNotAllowedError returned when trying to tear down an object the game cannot demolish
+type TearDownNotAllowedError struct {
+	ID     ID
+	Reason string // why the game refuses it, and what to use instead when there is an alternative
+}
+
+// NewTearDownNotAllowedError ...
+func NewTearDownNotAllowedError(id ID, reason string) *TearDownNotAllowedError {
+	return &TearDownNotAllowedError{ID: id, Reason: reason}
+}
+
+func (e *TearDownNotAllowedError) Error() string {
+	return ErrTearDownNotAllowed.Error() + " for " + e.ID.String() + ": " + e.Reason
+}
+
+// Is makes errors.Is(err, ErrTearDownNotAllowed) work
+func (e *TearDownNotAllowedError) Is(target error) bool {
+	return target == ErrTearDownNotAllowed
+}
+
+// ErrAbandonNotSafe returned when abandoning a planet would lose something, the error is a *AbandonNotSafeError
+var ErrAbandonNotSafe = errors.New("abandon not safe")
+
+// AbandonNotSafeError returned by Abandon when the planet still has ships, resources or fleets coming to it
+type AbandonNotSafeError struct {
+	Coordinate Coordinate
+	Reasons    []string
+}
+
+// NewAbandonNotSafeError ...
+func NewAbandonNotSafeError(coord Coordinate, reasons []string) *AbandonNotSafeError {
+	return &AbandonNotSafeError{Coordinate: coord, Reasons: reasons}
+}
+
+func (e *AbandonNotSafeError) Error() string {
+	return ErrAbandonNotSafe.Error() + " for " + e.Coordinate.String() + ": " + strings.Join(e.Reasons, ", ")
+}
+
+// Is makes errors.Is(err, ErrAbandonNotSafe) work
+func (e *AbandonNotSafeError) Is(target error) bool {
+	return target == ErrAbandonNotSafe
+}
+
+// ErrCaptchaFailed returned when a gameforge captcha challenge could not be solved
+var ErrCaptchaFailed = errors.New("captcha failed")
+
+// ErrTooManyCaptchaFailures returned when the auto re-login is stopped after too many captcha failures
+var ErrTooManyCaptchaFailures = errors.New("too many captcha failures, auto re-login stopped")
+
+// ErrReportNotFound returned when no report matches the lookup
+var ErrReportNotFound = errors.New("report not found")
+
+// ErrReportTooOld returned when the latest report is older than the requested maximum age
+var ErrReportTooOld = errors.New("report too old")
+
+// ErrRefundRatioRequired returned when a tear down plan is requested without refund ratio, nothing is worth tearing down without refund
+var ErrRefundRatioRequired = errors.New("refund ratio is required")
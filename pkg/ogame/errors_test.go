@@ -0,0 +1,25 @@
+package ogame
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVacationModeLockedError(t *testing.T) {
+	until := time.Date(2019, 9, 4, 7, 54, 28, 0, time.UTC)
+	var err error = NewVacationModeLockedError(until)
+	assert.True(t, errors.Is(err, ErrVacationModeLocked))
+	var lockedErr *VacationModeLockedError
+	assert.True(t, errors.As(err, &lockedErr))
+	assert.Equal(t, until, lockedErr.Until)
+	assert.Equal(t, "vacation mode cannot be disabled yet", NewVacationModeLockedError(time.Time{}).Error())
+}
+
+func TestTearDownNotAllowedError(t *testing.T) {
+	var err error = NewTearDownNotAllowedError(RocketLauncherID, "ships and defenses cannot be demolished")
+	assert.True(t, errors.Is(err, ErrTearDownNotAllowed))
+	assert.Equal(t, "tear down not allowed for RocketLauncher: ships and defenses cannot be demolished", err.Error())
+}
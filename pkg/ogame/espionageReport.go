@@ -2,6 +2,8 @@ package ogame
 
 import (
 	"time"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
 )
 
 // EspionageReport detailed espionage report
@@ -205,6 +207,52 @@ func (r EspionageReport) DefensesInfos() *DefensesInfos {
 	}
 }
 
+// EspionageReportInfos typed content of an espionage report. A section the report does not include,
+// because not enough probes were sent, is nil: it is unknown, not empty.
+type EspionageReportInfos struct {
+	Resources          Resources
+	ResourcesBuildings *ResourcesBuildings
+	Facilities         *Facilities
+	Researches         *Researches
+	ShipsInfos         *ShipsInfos
+	DefensesInfos      *DefensesInfos
+}
+
+// Infos returns the typed content of the espionage report
+func (r EspionageReport) Infos() EspionageReportInfos {
+	return EspionageReportInfos{
+		Resources:          r.Resources,
+		ResourcesBuildings: r.ResourcesBuildings(),
+		Facilities:         r.Facilities(),
+		Researches:         r.Researches(),
+		ShipsInfos:         r.ShipsInfos(),
+		DefensesInfos:      r.DefensesInfos(),
+	}
+}
+
+// Unknown returns the names of the sections the report does not include
+func (i EspionageReportInfos) Unknown() []string {
+	out := make([]string, 0)
+	if i.ShipsInfos == nil {
+		out = append(out, "fleet")
+	}
+	if i.DefensesInfos == nil {
+		out = append(out, "defenses")
+	}
+	if i.ResourcesBuildings == nil || i.Facilities == nil {
+		out = append(out, "buildings")
+	}
+	if i.Researches == nil {
+		out = append(out, "researches")
+	}
+	return out
+}
+
+// IsComplete returns either or not every section of the report is known
+func (i EspionageReportInfos) IsComplete() bool {
+	return len(i.Unknown()) == 0
+}
+
 // PlunderRatio returns the plunder ratio
 func (r EspionageReport) PlunderRatio(characterClass CharacterClass) float64 {
 	plunderRatio := 0.5
@@ -229,6 +277,54 @@ func (r EspionageReport) Loot(characterClass CharacterClass) Resources {
 	}
 }
 
+// PlunderParams parameters of EspionageReport.Plunder
+type PlunderParams struct {
+	CharacterClass CharacterClass // attacker class, used to compute the plunder ratio
+	LootPercentage float64        // loot percentage shown in the report summary (0-1), overrides the plunder ratio when set
+	Protected      Resources      // resources that cannot be plundered (storage protection), per resource
+	Cargo          int64          // cargo capacity of the attacking fleet, 0 for unlimited
+}
+
+// Plunder returns the resources an attack would take from the scanned celestial.
+// The plundered share (loot percentage, or plunder ratio of the inactive/bandit/honorable rules) is applied to the
+// unprotected resources, then the take is limited by the cargo capacity, filled the way the game does:
+// metal up to a third of the capacity, crystal up to half of what is left, deuterium with the rest,
+// then metal up to half of what is left and crystal with the rest.
+func (r EspionageReport) Plunder(params PlunderParams) Resources {
+	ratio := params.LootPercentage
+	if ratio <= 0 {
+		ratio = r.PlunderRatio(params.CharacterClass)
+	}
+	plunderable := func(amount, protected int64) int64 {
+		amount -= protected
+		if amount <= 0 {
+			return 0
+		}
+		return int64(float64(amount) * ratio)
+	}
+	available := Resources{
+		Metal:     plunderable(r.Metal, params.Protected.Metal),
+		Crystal:   plunderable(r.Crystal, params.Protected.Crystal),
+		Deuterium: plunderable(r.Deuterium, params.Protected.Deuterium),
+	}
+	if params.Cargo <= 0 || available.Total() <= params.Cargo {
+		return available
+	}
+	var out Resources
+	capacity := params.Cargo
+	take := func(dst *int64, wanted, limit int64) {
+		amount := utils.MinInt(wanted-*dst, limit)
+		*dst += amount
+		capacity -= amount
+	}
+	take(&out.Metal, available.Metal, capacity/3)
+	take(&out.Crystal, available.Crystal, capacity/2)
+	take(&out.Deuterium, available.Deuterium, capacity)
+	take(&out.Metal, available.Metal, capacity/2)
+	take(&out.Crystal, available.Crystal, capacity)
+	return out
+}
+
 // IsDefenceless returns either or not the scanned planet has any defense (either ships or defense) against an attack
 // with ships. If no ShipsInfos or DefensesInfos is including in the espionage report due to the lack of enough probes,
 // the planet is assumed to be not defenceless.
@@ -49,3 +49,35 @@ func TestShipsInfos(t *testing.T) {
 	var nilShipsInfos *ShipsInfos = nil
 	assert.Equal(t, nilShipsInfos, er.ShipsInfos())
 }
+
+func TestEspionageReport_Infos(t *testing.T) {
+	er := EspionageReport{Resources: Resources{Metal: 100}, HasFleetInformation: true, LightFighter: utils.I64Ptr(5)}
+	infos := er.Infos()
+	assert.Equal(t, Resources{Metal: 100}, infos.Resources)
+	assert.Equal(t, int64(5), infos.ShipsInfos.LightFighter)
+	assert.Nil(t, infos.DefensesInfos)
+	assert.Nil(t, infos.Researches)
+	assert.Equal(t, []string{"defenses", "buildings", "researches"}, infos.Unknown())
+	assert.False(t, infos.IsComplete())
+
+	er = EspionageReport{HasFleetInformation: true, HasDefensesInformation: true, HasBuildingsInformation: true, HasResearchesInformation: true, MetalMine: utils.I64Ptr(20)}
+	infos = er.Infos()
+	assert.Equal(t, int64(20), infos.ResourcesBuildings.MetalMine)
+	assert.Equal(t, int64(0), infos.Researches.WeaponsTechnology)
+	assert.Empty(t, infos.Unknown())
+	assert.True(t, infos.IsComplete())
+}
+
+func TestEspionageReport_Plunder(t *testing.T) {
+	er := EspionageReport{Resources: Resources{Metal: 10000, Crystal: 6000, Deuterium: 2000}}
+	assert.Equal(t, Resources{Metal: 5000, Crystal: 3000, Deuterium: 1000}, er.Plunder(PlunderParams{}))
+	assert.Equal(t, Resources{Metal: 7500, Crystal: 4500, Deuterium: 1500}, er.Plunder(PlunderParams{LootPercentage: 0.75}))
+	assert.Equal(t, Resources{Metal: 4000, Crystal: 3000, Deuterium: 0}, er.Plunder(PlunderParams{Protected: Resources{Metal: 2000, Deuterium: 3000}}))
+
+	// 3000 capacity: 1000 metal, 1000 crystal, 1000 deuterium
+	assert.Equal(t, Resources{Metal: 1000, Crystal: 1000, Deuterium: 1000}, er.Plunder(PlunderParams{Cargo: 3000}))
+	// 6000 capacity: 2000 metal, 2000 crystal, 1000 deuterium, 500 metal, 500 crystal
+	assert.Equal(t, Resources{Metal: 2500, Crystal: 2500, Deuterium: 1000}, er.Plunder(PlunderParams{Cargo: 6000}))
+	// enough capacity
+	assert.Equal(t, Resources{Metal: 5000, Crystal: 3000, Deuterium: 1000}, er.Plunder(PlunderParams{Cargo: 100000}))
+}
@@ -0,0 +1,51 @@
+package ogame
+
+// expeditionFindTiers maximum resources find (in metal) of an expedition, by points of the top 1 player
+var expeditionFindTiers = []struct {
+	topScore int64
+	maxFind  int64
+}{
+	{100000000, 5000000},
+	{75000000, 4200000},
+	{50000000, 3600000},
+	{25000000, 3000000},
+	{5000000, 2400000},
+	{1000000, 1800000},
+	{100000, 1200000},
+	{10000, 500000},
+	{0, 40000},
+}
+
+// ExpeditionMaxFind returns the maximum resources an expedition can find, in metal (crystal is half, deuterium a third).
+// The cap depends on the points of the top 1 player and the economy speed of the universe, it is increased by 50%
+// for the Discoverer class and doubled when a Pathfinder is part of the fleet.
+func ExpeditionMaxFind(topScore, economySpeed int64, isDiscoverer, withPathfinder bool) int64 {
+	var maxFind int64
+	for _, tier := range expeditionFindTiers {
+		if topScore >= tier.topScore {
+			maxFind = tier.maxFind
+			break
+		}
+	}
+	if economySpeed > 1 {
+		maxFind *= economySpeed
+	}
+	if isDiscoverer {
+		maxFind = maxFind * 3 / 2
+	}
+	if withPathfinder {
+		maxFind *= 2
+	}
+	return maxFind
+}
+
+// ExpeditionFleet returns the fleet able to carry back the biggest find of an expedition: one Pathfinder when
+// withPathfinder is set, plus enough cargoID ships to carry maxFind resources.
+func ExpeditionFleet(maxFind int64, cargoID ID, withPathfinder bool, techs Researches, isCollector, isPioneers bool) (out ShipsInfos) {
+	if withPathfinder {
+		out.Pathfinder = 1
+		maxFind -= Pathfinder.GetCargoCapacity(techs, false, isCollector, isPioneers)
+	}
+	out.Set(cargoID, out.ByID(cargoID)+CargoShipsNeeded(cargoID, maxFind, techs, false, isCollector, isPioneers))
+	return out
+}
@@ -0,0 +1,25 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpeditionMaxFind(t *testing.T) {
+	assert.Equal(t, int64(40000), ExpeditionMaxFind(5000, 1, false, false))
+	assert.Equal(t, int64(1800000), ExpeditionMaxFind(1000000, 1, false, false))
+	assert.Equal(t, int64(5000000), ExpeditionMaxFind(200000000, 1, false, false))
+	assert.Equal(t, int64(3600000), ExpeditionMaxFind(1000000, 2, false, false))
+	assert.Equal(t, int64(2700000), ExpeditionMaxFind(1000000, 1, true, false))
+	assert.Equal(t, int64(5400000), ExpeditionMaxFind(1000000, 1, true, true))
+}
+
+func TestExpeditionFleet(t *testing.T) {
+	fleet := ExpeditionFleet(1000000, LargeCargoID, false, Researches{}, false, false)
+	assert.Equal(t, ShipsInfos{LargeCargo: 40}, fleet)
+	fleet = ExpeditionFleet(1000000, LargeCargoID, true, Researches{}, false, false)
+	assert.Equal(t, ShipsInfos{LargeCargo: 40, Pathfinder: 1}, fleet)
+	fleet = ExpeditionFleet(1000000, PathfinderID, true, Researches{}, false, false)
+	assert.Equal(t, ShipsInfos{Pathfinder: 100}, fleet)
+}
@@ -0,0 +1,146 @@
+package ogame
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// ExpeditionOutcome result of an expedition, as described by its message
+type ExpeditionOutcome int64
+
+// Expedition outcomes
+const (
+	ExpeditionNothing    ExpeditionOutcome = iota // nothing found, also includes unknown messages
+	ExpeditionResources                           // metal, crystal or deuterium found
+	ExpeditionDarkMatter                          // dark matter found
+	ExpeditionShips                               // ships joined the fleet
+	ExpeditionItem                                // item added to the inventory
+	ExpeditionTrader                              // merchant contacted
+	ExpeditionPirates                             // fight against pirates
+	ExpeditionAliens                              // fight against aliens
+	ExpeditionDelay                               // fleet returns late
+	ExpeditionEarly                               // fleet returns early
+	ExpeditionLost                                // fleet lost (black hole)
+)
+
+func (o ExpeditionOutcome) String() string {
+	switch o {
+	case ExpeditionResources:
+		return "resources"
+	case ExpeditionDarkMatter:
+		return "dark matter"
+	case ExpeditionShips:
+		return "ships"
+	case ExpeditionItem:
+		return "item"
+	case ExpeditionTrader:
+		return "trader"
+	case ExpeditionPirates:
+		return "pirates"
+	case ExpeditionAliens:
+		return "aliens"
+	case ExpeditionDelay:
+		return "delay"
+	case ExpeditionEarly:
+		return "early"
+	case ExpeditionLost:
+		return "lost"
+	default:
+		return "nothing"
+	}
+}
+
+// ExpeditionResult outcome and gains of an expedition message
+type ExpeditionResult struct {
+	Outcome   ExpeditionOutcome
+	Resources Resources // metal, crystal, deuterium and dark matter found
+	Ships     ShipsInfos
+}
+
+var (
+	expeditionLineRgx     = regexp.MustCompile(`(?i)<br\s*/?>`)
+	expeditionResourceRgx = regexp.MustCompile(`^(Metal|Crystal|Deuterium|Dark Matter) ([\d.,]+)`)
+	expeditionShipRgx     = regexp.MustCompile(`^([^:]+): ([\d.,]+)$`)
+)
+
+// ClassifyExpeditionMessage returns the outcome of an expedition message.
+// Gains are parsed from the message structure, the other outcomes rely on the english texts.
+func ClassifyExpeditionMessage(content string) ExpeditionResult {
+	res := ExpeditionResult{}
+	for _, line := range expeditionLineRgx.Split(content, -1) {
+		line = strings.TrimSpace(line)
+		if m := expeditionResourceRgx.FindStringSubmatch(line); len(m) == 3 {
+			amount := utils.ParseInt(m[2])
+			switch m[1] {
+			case "Metal":
+				res.Resources.Metal += amount
+			case "Crystal":
+				res.Resources.Crystal += amount
+			case "Deuterium":
+				res.Resources.Deuterium += amount
+			case "Dark Matter":
+				res.Resources.Darkmatter += amount
+			}
+		} else if m := expeditionShipRgx.FindStringSubmatch(line); len(m) == 3 {
+			if shipID := ShipName2ID(m[1]); shipID.IsShip() {
+				res.Ships.AddShips(shipID, utils.ParseInt(m[2]))
+			}
+		}
+	}
+	lower := strings.ToLower(content)
+	switch {
+	case res.Ships.CountShips() > 0:
+		res.Outcome = ExpeditionShips
+	case res.Resources.Darkmatter > 0:
+		res.Outcome = ExpeditionDarkMatter
+	case res.Resources.Total() > 0:
+		res.Outcome = ExpeditionResources
+	case strings.Contains(lower, "black hole") || strings.Contains(lower, "only thing left"):
+		res.Outcome = ExpeditionLost
+	case strings.Contains(lower, "item"):
+		res.Outcome = ExpeditionItem
+	case strings.Contains(lower, "merchant") || strings.Contains(lower, "trader"):
+		res.Outcome = ExpeditionTrader
+	case strings.Contains(lower, "pirate") || strings.Contains(lower, "barbarian"):
+		res.Outcome = ExpeditionPirates
+	case strings.Contains(lower, "alien") || strings.Contains(lower, "unknown species") || strings.Contains(lower, "exotic"):
+		res.Outcome = ExpeditionAliens
+	case strings.Contains(lower, "sooner") || strings.Contains(lower, "earlier"):
+		res.Outcome = ExpeditionEarly
+	case strings.Contains(lower, "delay"):
+		res.Outcome = ExpeditionDelay
+	}
+	return res
+}
+
+// ExpeditionStats totals of the expedition messages
+type ExpeditionStats struct {
+	Since      time.Time
+	Count      int64
+	Outcomes   map[ExpeditionOutcome]int64
+	Resources  Resources // metal, crystal, deuterium and dark matter found
+	Ships      ShipsInfos
+	FleetsLost int64 // number of expeditions lost
+}
+
+// NewExpeditionStats creates empty stats
+func NewExpeditionStats(since time.Time) ExpeditionStats {
+	return ExpeditionStats{Since: since, Outcomes: make(map[ExpeditionOutcome]int64)}
+}
+
+// Add classifies a message and adds it to the totals
+func (s *ExpeditionStats) Add(msg ExpeditionMessage) {
+	res := ClassifyExpeditionMessage(msg.Content)
+	s.Count++
+	s.Outcomes[res.Outcome]++
+	darkmatter := s.Resources.Darkmatter + res.Resources.Darkmatter
+	s.Resources = s.Resources.Add(res.Resources) // Add does not sum the dark matter
+	s.Resources.Darkmatter = darkmatter
+	s.Ships.Add(res.Ships)
+	if res.Outcome == ExpeditionLost {
+		s.FleetsLost++
+	}
+}
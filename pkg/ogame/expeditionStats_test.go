@@ -0,0 +1,35 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyExpeditionMessage(t *testing.T) {
+	res := ClassifyExpeditionMessage("We found the remains of an armada.<br /><br />The following ships are now part of the fleet:<br />Espionage Probe: 578<br />Small Cargo: 1270<br />Light Fighter: 10")
+	assert.Equal(t, ExpeditionShips, res.Outcome)
+	assert.Equal(t, ShipsInfos{EspionageProbe: 578, SmallCargo: 1270, LightFighter: 10}, res.Ships)
+
+	res = ClassifyExpeditionMessage("Your expedition discovered a small asteroid from which some resources could be harvested.<br /><br />Metal 900.000 have been captured.")
+	assert.Equal(t, ExpeditionResources, res.Outcome)
+	assert.Equal(t, int64(900000), res.Resources.Metal)
+
+	res = ClassifyExpeditionMessage("In the asteroids core a small amount of Dark Matter was found.<br /><br />Dark Matter 371 have been captured.")
+	assert.Equal(t, ExpeditionDarkMatter, res.Outcome)
+	assert.Equal(t, int64(371), res.Resources.Darkmatter)
+
+	assert.Equal(t, ExpeditionPirates, ClassifyExpeditionMessage("Some really desperate space pirates tried to capture our expedition fleet.").Outcome)
+	assert.Equal(t, ExpeditionNothing, ClassifyExpeditionMessage("Your expedition nearly ran into a neutron stars gravitation field.").Outcome)
+}
+
+func TestExpeditionStats_Add(t *testing.T) {
+	stats := NewExpeditionStats(time.Time{})
+	stats.Add(ExpeditionMessage{Content: "<br /><br />Dark Matter 300 have been captured."})
+	stats.Add(ExpeditionMessage{Content: "<br /><br />Dark Matter 71 have been captured."})
+	stats.Add(ExpeditionMessage{Content: "<br /><br />Crystal 1.000 have been captured."})
+	assert.Equal(t, int64(3), stats.Count)
+	assert.Equal(t, int64(2), stats.Outcomes[ExpeditionDarkMatter])
+	assert.Equal(t, Resources{Crystal: 1000, Darkmatter: 371}, stats.Resources)
+}
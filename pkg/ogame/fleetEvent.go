@@ -0,0 +1,44 @@
+package ogame
+
+import "time"
+
+// EventRelation relation of a fleet event to the player, as colored in the event list
+type EventRelation int64
+
+// Event relations
+const (
+	NeutralEvent  EventRelation = iota // foreign fleet not targeting the player (eg: acs defend, transport from another player)
+	FriendlyEvent                      // own fleet
+	HostileEvent                       // enemy fleet
+)
+
+func (r EventRelation) String() string {
+	switch r {
+	case FriendlyEvent:
+		return "friendly"
+	case HostileEvent:
+		return "hostile"
+	default:
+		return "neutral"
+	}
+}
+
+// FleetEvent a fleet movement displayed in the event list
+type FleetEvent struct {
+	ID              int64
+	Relation        EventRelation
+	MissionType     MissionID
+	ReturnFlight    bool
+	Origin          Coordinate
+	OriginName      string
+	Destination     Coordinate
+	DestinationName string
+	ArrivalTime     time.Time
+	ArriveIn        int64
+	PlayerID        int64 // sender of a foreign fleet
+	PlayerName      string
+	UnionID         int64
+	Missiles        int64
+	Ships           *ShipsInfos // nil when the ships are not visible, -1 for a ship type with unknown quantity
+	Resources       *Resources  // shipment, only visible for own fleets
+}
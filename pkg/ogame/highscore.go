@@ -48,3 +48,39 @@ func (h HighscorePlayer) String() string {
 		"   Homeworld: " + h.Homeworld.String() + "\n" +
 		"       Ships: " + utils.FI64(h.Ships) + "\n"
 }
+
+// FindPlayer returns the position of a player in the highscore page
+func (h Highscore) FindPlayer(playerID int64) (HighscorePlayer, bool) {
+	return findHighscorePlayer(h.Players, playerID)
+}
+
+// FullHighscore all the positions of a highscore (every pages)
+type FullHighscore struct {
+	Category int64 // 1:Player, 2:Alliance
+	Type     int64 // 0:Total, 1:Economy, 2:Research, 3:Military, 4:Military Built, 5:Military Destroyed, 6:Military Lost, 7:Honor
+	Players  []HighscorePlayer
+}
+
+// FindPlayer returns the position of a player in the highscore
+func (h FullHighscore) FindPlayer(playerID int64) (HighscorePlayer, bool) {
+	return findHighscorePlayer(h.Players, playerID)
+}
+
+// FindPlayerByName returns the position of a player in the highscore using the player name
+func (h FullHighscore) FindPlayerByName(name string) (HighscorePlayer, bool) {
+	for _, p := range h.Players {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return HighscorePlayer{}, false
+}
+
+func findHighscorePlayer(players []HighscorePlayer, playerID int64) (HighscorePlayer, bool) {
+	for _, p := range players {
+		if p.ID == playerID {
+			return p, true
+		}
+	}
+	return HighscorePlayer{}, false
+}
@@ -0,0 +1,22 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullHighscore_FindPlayer(t *testing.T) {
+	h := FullHighscore{Players: []HighscorePlayer{
+		{Position: 1, ID: 100001, Name: "Bob"},
+		{Position: 2, ID: 100002, Name: "Alice"},
+	}}
+	p, found := h.FindPlayer(100002)
+	assert.True(t, found)
+	assert.Equal(t, int64(2), p.Position)
+	p, found = h.FindPlayerByName("Bob")
+	assert.True(t, found)
+	assert.Equal(t, int64(1), p.Position)
+	_, found = h.FindPlayer(123)
+	assert.False(t, found)
+}
@@ -1,5 +1,7 @@
 package ogame
 
+import "sort"
+
 type intergalacticResearchNetwork struct {
 	BaseTechnology
 }
@@ -13,3 +15,24 @@ func newIntergalacticResearchNetwork() *intergalacticResearchNetwork {
 	b.Requirements = map[ID]int64{ResearchLabID: 10, ComputerTechnologyID: 8, HyperspaceTechnologyID: 8}
 	return b
 }
+
+// EffectiveResearchLab returns the research lab level used for a research launched from the planet at labs[originIdx].
+// The Intergalactic Research Network links the origin lab with the irnLevel highest other labs
+// that are at least minLabLevel (the research lab requirement of the researched technology).
+func EffectiveResearchLab(labs []int64, originIdx int, irnLevel, minLabLevel int64) int64 {
+	if originIdx < 0 || originIdx >= len(labs) {
+		return 0
+	}
+	others := make([]int64, 0, len(labs))
+	for i, lvl := range labs {
+		if i != originIdx && lvl >= minLabLevel {
+			others = append(others, lvl)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] > others[j] })
+	total := labs[originIdx]
+	for i := 0; i < len(others) && int64(i) < irnLevel; i++ {
+		total += others[i]
+	}
+	return total
+}
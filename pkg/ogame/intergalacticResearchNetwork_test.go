@@ -0,0 +1,17 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveResearchLab(t *testing.T) {
+	labs := []int64{10, 12, 8, 3}
+	assert.Equal(t, int64(10), EffectiveResearchLab(labs, 0, 0, 1))
+	assert.Equal(t, int64(22), EffectiveResearchLab(labs, 0, 1, 1))
+	assert.Equal(t, int64(30), EffectiveResearchLab(labs, 0, 2, 1))
+	assert.Equal(t, int64(33), EffectiveResearchLab(labs, 1, 5, 1))
+	assert.Equal(t, int64(22), EffectiveResearchLab(labs, 0, 5, 10))
+	assert.Equal(t, int64(0), EffectiveResearchLab(labs, 4, 1, 1))
+}
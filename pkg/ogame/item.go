@@ -1,5 +1,11 @@
 package ogame
 
+import (
+	"encoding/json"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
 // Item Is an ogame item that can be activated
 type Item struct {
 	Ref            string
@@ -44,3 +50,139 @@ type ActiveItem struct {
 	TotalDuration int64
 	ImgSmall      string
 }
+
+// ShopItem an item as listed in the shop, Amount is the number of items in the inventory
+type ShopItem struct {
+	Ref                     string
+	Name                    string
+	Image                   string
+	ImageLarge              string
+	Title                   string
+	Effect                  string
+	Rarity                  string // common, uncommon, rare, epic
+	Amount                  int64
+	AmountFree              int64
+	AmountBought            int64
+	Category                []string
+	Currency                string // dm
+	Costs                   int64
+	IsReduced               bool
+	Buyable                 bool
+	CanBeActivated          bool
+	CanBeBoughtAndActivated bool
+	IsAnUpgrade             bool
+	IsCharacterClassItem    bool
+	HasEnoughCurrency       bool
+	Cooldown                int64 // seconds before the item can be activated again
+	Duration                int64 // seconds, 0 for permanent items
+	TotalTime               int64
+	TimeLeft                int64
+	Status                  string
+	FirstStatus             string
+	Extendable              bool
+	MoonOnlyItem            bool
+}
+
+// UnmarshalJSON parses the item json used by the game, in which costs is a string
+func (i *ShopItem) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Ref                     string   `json:"ref"`
+		Name                    string   `json:"name"`
+		Image                   string   `json:"image"`
+		ImageLarge              string   `json:"imageLarge"`
+		Title                   string   `json:"title"`
+		Effect                  string   `json:"effect"`
+		Rarity                  string   `json:"rarity"`
+		Amount                  int64    `json:"amount"`
+		AmountFree              int64    `json:"amount_free"`
+		AmountBought            int64    `json:"amount_bought"`
+		Category                []string `json:"category"`
+		Currency                string   `json:"currency"`
+		Costs                   any      `json:"costs"`
+		IsReduced               bool     `json:"isReduced"`
+		Buyable                 bool     `json:"buyable"`
+		CanBeActivated          bool     `json:"canBeActivated"`
+		CanBeBoughtAndActivated bool     `json:"canBeBoughtAndActivated"`
+		IsAnUpgrade             bool     `json:"isAnUpgrade"`
+		IsCharacterClassItem    bool     `json:"isCharacterClassItem"`
+		HasEnoughCurrency       bool     `json:"hasEnoughCurrency"`
+		Cooldown                any      `json:"cooldown"`
+		Duration                any      `json:"duration"`
+		TotalTime               any      `json:"totalTime"`
+		TimeLeft                any      `json:"timeLeft"`
+		Status                  any      `json:"status"`
+		FirstStatus             string   `json:"firstStatus"`
+		Extendable              bool     `json:"extendable"`
+		MoonOnlyItem            bool     `json:"moonOnlyItem"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	// Depending on the item, numeric fields can be numbers, strings, false or null
+	toI64 := func(v any) int64 {
+		if str, ok := v.(string); ok {
+			return utils.ParseInt(str)
+		}
+		return int64(utils.DoCastF64(v))
+	}
+	*i = ShopItem{
+		Ref:                     raw.Ref,
+		Name:                    raw.Name,
+		Image:                   raw.Image,
+		ImageLarge:              raw.ImageLarge,
+		Title:                   raw.Title,
+		Effect:                  raw.Effect,
+		Rarity:                  raw.Rarity,
+		Amount:                  raw.Amount,
+		AmountFree:              raw.AmountFree,
+		AmountBought:            raw.AmountBought,
+		Category:                raw.Category,
+		Currency:                raw.Currency,
+		Costs:                   toI64(raw.Costs),
+		IsReduced:               raw.IsReduced,
+		Buyable:                 raw.Buyable,
+		CanBeActivated:          raw.CanBeActivated,
+		CanBeBoughtAndActivated: raw.CanBeBoughtAndActivated,
+		IsAnUpgrade:             raw.IsAnUpgrade,
+		IsCharacterClassItem:    raw.IsCharacterClassItem,
+		HasEnoughCurrency:       raw.HasEnoughCurrency,
+		Cooldown:                toI64(raw.Cooldown),
+		Duration:                toI64(raw.Duration),
+		TotalTime:               toI64(raw.TotalTime),
+		TimeLeft:                toI64(raw.TimeLeft),
+		Status:                  utils.DoCastStr(raw.Status),
+		FirstStatus:             raw.FirstStatus,
+		Extendable:              raw.Extendable,
+		MoonOnlyItem:            raw.MoonOnlyItem,
+	}
+	return nil
+}
+
+// HasCategory either or not the item belongs to the given category
+func (i ShopItem) HasCategory(category string) bool {
+	for _, c := range i.Category {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOnCooldown either or not the item has been activated recently and cannot be activated again yet
+func (i ShopItem) IsOnCooldown() bool {
+	return i.Cooldown > 0
+}
+
+// ItemActivationResult response sent by the game when an item is activated
+type ItemActivationResult struct {
+	Buff          string   `json:"buff"`
+	Status        string   `json:"status"`
+	Duration      int64    `json:"duration"`
+	Extendable    bool     `json:"extendable"`
+	TotalDuration int64    `json:"totalDuration"`
+	Tooltip       string   `json:"tooltip"`
+	Reload        bool     `json:"reload"`
+	BuffID        string   `json:"buffId"`
+	Item          ShopItem `json:"item"`
+	Message       string   `json:"message"`
+}
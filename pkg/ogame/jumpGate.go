@@ -1,5 +1,10 @@
 package ogame
 
+import (
+	"math"
+	"time"
+)
+
 type jumpGate struct {
 	BaseBuilding
 }
@@ -13,3 +18,18 @@ func newJumpGate() *jumpGate {
 	b.Requirements = map[ID]int64{LunarBaseID: 1, HyperspaceTechnologyID: 7}
 	return b
 }
+
+// JumpGateRechargeTime returns the time a jump gate of the given level needs to recharge after a jump.
+// Level 1 takes one hour, each additional level reduces it by 30%.
+func JumpGateRechargeTime(level int64) time.Duration {
+	if level < 1 {
+		level = 1
+	}
+	return time.Duration(float64(time.Hour) * math.Pow(0.7, float64(level-1))).Round(time.Second)
+}
+
+// JumpGateReadyAt returns when a jump gate of the given level used at jumpedAt can jump again.
+// Both the origin and destination gates recharge after a jump, each one according to its own level.
+func JumpGateReadyAt(jumpedAt time.Time, level int64) time.Time {
+	return jumpedAt.Add(JumpGateRechargeTime(level))
+}
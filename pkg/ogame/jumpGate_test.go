@@ -0,0 +1,20 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJumpGateRechargeTime(t *testing.T) {
+	assert.Equal(t, time.Hour, JumpGateRechargeTime(1))
+	assert.Equal(t, 42*time.Minute, JumpGateRechargeTime(2))
+	assert.Equal(t, 29*time.Minute+24*time.Second, JumpGateRechargeTime(3))
+}
+
+func TestJumpGateReadyAt(t *testing.T) {
+	jumpedAt := time.Unix(1700000000, 0)
+	assert.Equal(t, jumpedAt.Add(time.Hour), JumpGateReadyAt(jumpedAt, 1))
+	assert.Equal(t, jumpedAt.Add(42*time.Minute), JumpGateReadyAt(jumpedAt, 2))
+}
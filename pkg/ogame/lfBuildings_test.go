@@ -3,6 +3,7 @@ package ogame
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestResidentialSectorCost(t *testing.T) {
@@ -29,3 +30,17 @@ func TestResearchCenterCost(t *testing.T) {
 //	rs := newResidentialSector()
 //	assert.Equal(t, (6*60*60+58*60+48)*time.Second, rs.ConstructionTime(35, 4, Facilities{}, false, false))
 //}
+
+func TestLfBuildingConstructionTime(t *testing.T) {
+	// level * 40s * 1.21^level / ((1 + robotics) * 2^nanite * speed)
+	rs := newResidentialSector()
+	assert.Equal(t, 48*time.Second, rs.ConstructionTime(1, 1, Facilities{}, false, false))
+	assert.Equal(t, 117*time.Second, rs.ConstructionTime(2, 1, Facilities{}, false, false))
+	assert.Equal(t, 64*time.Second, rs.ConstructionTime(5, 2, Facilities{RoboticsFactory: 1, NaniteFactory: 1}, false, false))
+	assert.Equal(t, time.Second, rs.ConstructionTime(1, 1, Facilities{RoboticsFactory: 10, NaniteFactory: 2}, false, false))
+}
+
+func TestBuildingTimeReduction(t *testing.T) {
+	assert.Equal(t, 0.05, LfBuildings{Megalith: 5}.BuildingTimeReduction())
+	assert.Equal(t, 0.99, LfBuildings{Megalith: 150}.BuildingTimeReduction())
+}
@@ -0,0 +1,74 @@
+package ogame
+
+// MarketplaceTab marketplace listing tab
+type MarketplaceTab string
+
+// Marketplace listing tabs
+const (
+	MarketplaceBuyingTab  MarketplaceTab = "buying"  // offers from players selling something, accepted with BuyMarketplace
+	MarketplaceSellingTab MarketplaceTab = "selling" // requests from players wanting to buy something
+)
+
+// Marketplace item types
+const (
+	MarketplaceShipsItemType     int64 = 1
+	MarketplaceResourcesItemType int64 = 2
+	MarketplaceItemItemType      int64 = 3
+)
+
+// MarketplaceOffer a listing on the marketplace
+type MarketplaceOffer struct {
+	ID         int64
+	Tab        MarketplaceTab
+	ItemType   int64  // MarketplaceShipsItemType, MarketplaceResourcesItemType or MarketplaceItemItemType
+	ItemID     int64  // ship ID, or 1: metal, 2: crystal, 3: deuterium
+	ItemRef    string // item hash when ItemType is MarketplaceItemItemType
+	Quantity   int64
+	PriceType  int64 // 1: metal, 2: crystal, 3: deuterium
+	Price      int64
+	PlayerName string
+}
+
+// Goods returns the value of the offered goods in resources, false for items which have no resource value
+func (o MarketplaceOffer) Goods() (Resources, bool) {
+	switch o.ItemType {
+	case MarketplaceResourcesItemType:
+		return marketplaceResources(o.ItemID, o.Quantity), true
+	case MarketplaceShipsItemType:
+		if obj := Objs.ByID(ID(o.ItemID)); obj != nil && ID(o.ItemID).IsShip() {
+			return obj.GetPrice(o.Quantity), true
+		}
+	}
+	return Resources{}, false
+}
+
+// Cost returns the price of the offer in resources
+func (o MarketplaceOffer) Cost() Resources {
+	return marketplaceResources(o.PriceType, o.Price)
+}
+
+// ExchangeRate returns the metal value of the goods divided by the metal value of the price.
+// A rate above 1 means the offer is better than trading at the given rates, 0 if the goods have no resource value.
+func (o MarketplaceOffer) ExchangeRate(rates Ratio) float64 {
+	goods, ok := o.Goods()
+	if !ok {
+		return 0
+	}
+	cost := rates.MetalValue(o.Cost())
+	if cost <= 0 {
+		return 0
+	}
+	return rates.MetalValue(goods) / cost
+}
+
+func marketplaceResources(resourceID, amount int64) Resources {
+	switch resourceID {
+	case 1:
+		return Resources{Metal: amount}
+	case 2:
+		return Resources{Crystal: amount}
+	case 3:
+		return Resources{Deuterium: amount}
+	}
+	return Resources{}
+}
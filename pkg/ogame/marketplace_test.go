@@ -0,0 +1,17 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketplaceOfferExchangeRate(t *testing.T) {
+	rates := Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+	offer := MarketplaceOffer{ItemType: MarketplaceResourcesItemType, ItemID: 3, Quantity: 1000, PriceType: 1, Price: 2000}
+	assert.Equal(t, 1.5, offer.ExchangeRate(rates))
+	offer = MarketplaceOffer{ItemType: MarketplaceShipsItemType, ItemID: int64(LargeCargoID), Quantity: 2, PriceType: 2, Price: 8000}
+	assert.Equal(t, 2.5, offer.ExchangeRate(rates))
+	offer = MarketplaceOffer{ItemType: MarketplaceItemItemType, ItemRef: "abc", Quantity: 1, PriceType: 1, Price: 1000}
+	assert.Equal(t, 0.0, offer.ExchangeRate(rates))
+}
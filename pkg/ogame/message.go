@@ -0,0 +1,16 @@
+package ogame
+
+import "time"
+
+// Message a message of a messages tab, only the field matching the tab is set
+type Message struct {
+	ID              int64
+	TabID           MessagesTabID
+	CreatedAt       time.Time // zero when the tab does not show the date
+	EspionageReport *EspionageReportSummary
+	CombatReport    *CombatReportSummary
+	Expedition      *ExpeditionMessage
+	Marketplace     *MarketplaceMessage
+	Other           *OtherMessage
+	UnionsTransport *UnionsTransportMessage
+}
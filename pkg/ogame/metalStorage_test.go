@@ -25,3 +25,7 @@ func TestMetalStorageCapacity(t *testing.T) {
 	assert.Equal(t, int64(140000), ms.Capacity(4))
 	assert.Equal(t, int64(255000), ms.Capacity(5))
 }
+
+func TestStorageCapacity(t *testing.T) {
+	assert.Equal(t, Resources{Metal: 20000, Crystal: 40000, Deuterium: 10000}, StorageCapacity(ResourcesBuildings{MetalStorage: 1, CrystalStorage: 2}))
+}
@@ -0,0 +1,29 @@
+package ogame
+
+// Missiles missiles stored in the missile silo of a planet
+type Missiles struct {
+	AntiBallisticMissiles  int64
+	InterplanetaryMissiles int64
+	SiloLevel              int64
+	Capacity               int64 // silo capacity in anti-ballistic missiles, an interplanetary missile uses the space of two
+}
+
+// MissileSiloCapacity returns how many anti-ballistic missiles a missile silo of the given level can hold
+func MissileSiloCapacity(level int64) int64 {
+	return level * 10
+}
+
+// Used returns the silo space used, in anti-ballistic missiles
+func (m Missiles) Used() int64 {
+	return m.AntiBallisticMissiles + 2*m.InterplanetaryMissiles
+}
+
+// FreeABM returns how many more anti-ballistic missiles can be stored
+func (m Missiles) FreeABM() int64 {
+	return max64(0, m.Capacity-m.Used())
+}
+
+// FreeIPM returns how many more interplanetary missiles can be stored
+func (m Missiles) FreeIPM() int64 {
+	return m.FreeABM() / 2
+}
@@ -0,0 +1,23 @@
+package ogame
+
+import "math"
+
+// MoonDestructionChance chances of a destroy mission, in percent
+type MoonDestructionChance struct {
+	Moon      float64 // chance to destroy the moon
+	Deathstar float64 // chance to lose all the deathstars
+}
+
+// NewMoonDestructionChance computes the chances of a destroy mission on a moon of the given diameter
+func NewMoonDestructionChance(moonDiameter, deathstars int64) MoonDestructionChance {
+	if moonDiameter < 0 {
+		moonDiameter = 0
+	}
+	if deathstars <= 0 {
+		return MoonDestructionChance{}
+	}
+	moonChance := (100 - math.Sqrt(float64(moonDiameter))) * math.Sqrt(float64(deathstars))
+	moonChance = math.Max(0, math.Min(100, moonChance))
+	deathstarChance := math.Min(100, math.Sqrt(float64(moonDiameter))/2)
+	return MoonDestructionChance{Moon: moonChance, Deathstar: deathstarChance}
+}
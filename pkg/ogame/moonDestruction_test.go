@@ -0,0 +1,19 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMoonDestructionChance(t *testing.T) {
+	c := NewMoonDestructionChance(8100, 1)
+	assert.Equal(t, 10.0, c.Moon)
+	assert.Equal(t, 45.0, c.Deathstar)
+	c = NewMoonDestructionChance(8100, 4)
+	assert.Equal(t, 20.0, c.Moon)
+	c = NewMoonDestructionChance(3600, 100)
+	assert.Equal(t, 100.0, c.Moon)
+	assert.Equal(t, 30.0, c.Deathstar)
+	assert.Equal(t, MoonDestructionChance{}, NewMoonDestructionChance(3600, 0))
+}
@@ -0,0 +1,42 @@
+package ogame
+
+import "math"
+
+// MaxMoonChance maximum chance (in percent) to create a moon after a fight
+const MaxMoonChance = 20
+
+// MoonChance returns the chance (in percent) for a debris field to create a moon, 1% per 100.000 resources
+func MoonChance(debris Resources) float64 {
+	return math.Min(MaxMoonChance, math.Floor(float64(debris.Total())/100000))
+}
+
+// ShipDebris returns the debris left by nbr destroyed ships, debrisFactor is the server "fleet to debris field" setting (eg: 0.3)
+func ShipDebris(id ID, nbr int64, debrisFactor float64) Resources {
+	obj := Objs.ByID(id)
+	if obj == nil || !id.IsShip() {
+		return Resources{}
+	}
+	price := obj.GetPrice(nbr)
+	return Resources{
+		Metal:   int64(float64(price.Metal) * debrisFactor),
+		Crystal: int64(float64(price.Crystal) * debrisFactor),
+	}
+}
+
+// MoonshotShips returns how many ships of the given type must be destroyed to reach the moon chance (in percent), 0 if it cannot be reached
+func MoonshotShips(id ID, chance, debrisFactor float64) int64 {
+	chance = math.Min(math.Ceil(chance), MaxMoonChance)
+	if chance <= 0 {
+		return 0
+	}
+	debrisPerShip := ShipDebris(id, 1, debrisFactor).Total()
+	if debrisPerShip <= 0 {
+		return 0
+	}
+	nbr := int64(math.Ceil(chance * 100000 / float64(debrisPerShip)))
+	// rounding on the debris can make the estimate fall short
+	for MoonChance(ShipDebris(id, nbr, debrisFactor)) < chance {
+		nbr++
+	}
+	return nbr
+}
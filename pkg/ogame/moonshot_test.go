@@ -0,0 +1,23 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoonChance(t *testing.T) {
+	assert.Equal(t, 0.0, MoonChance(Resources{Metal: 99999}))
+	assert.Equal(t, 5.0, MoonChance(Resources{Metal: 300000, Crystal: 250000}))
+	assert.Equal(t, 20.0, MoonChance(Resources{Metal: 5000000}))
+}
+
+func TestMoonshotShips(t *testing.T) {
+	// light fighter: 3000 metal 1000 crystal, 30% debris -> 1200 per ship
+	assert.Equal(t, Resources{Metal: 900, Crystal: 300}, ShipDebris(LightFighterID, 1, 0.3))
+	assert.Equal(t, int64(1667), MoonshotShips(LightFighterID, 20, 0.3))
+	assert.Equal(t, int64(1667), MoonshotShips(LightFighterID, 50, 0.3))
+	assert.Equal(t, int64(84), MoonshotShips(LightFighterID, 1, 0.3))
+	assert.Equal(t, int64(0), MoonshotShips(RocketLauncherID, 20, 0.3))
+	assert.Equal(t, int64(0), MoonshotShips(LightFighterID, 0, 0.3))
+}
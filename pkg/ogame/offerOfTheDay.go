@@ -0,0 +1,23 @@
+package ogame
+
+// OfferOfTheDay the daily container sold by the merchant (Merchant -> Import/Export)
+type OfferOfTheDay struct {
+	Price           int64  // price in metal, other resources are converted with Multiplier
+	Rarity          string // rarity of the container, eg: common, rare, precious
+	Description     string
+	BargainCost     int64 // dark matter cost to exchange the bought item for another random item of the same rarity
+	Token           string
+	PlanetResources PlanetResources
+	Multiplier      Multiplier
+}
+
+// OfferOfTheDayItem the item found in the container of the offer of the day
+type OfferOfTheDayItem struct {
+	UUID        string `json:"uuid"`
+	Text        string `json:"itemText"`
+	Image       string `json:"image"`
+	Amount      int64  `json:"amount"`
+	Rarity      string `json:"rarity"`
+	BargainText string `json:"bargainText"`
+	BargainCost int64  `json:"bargainCost"`
+}
@@ -0,0 +1,36 @@
+package ogame
+
+import "time"
+
+// Officer types, as used by the premium page
+const (
+	OfficerCommander  int64 = 2
+	OfficerAdmiral    int64 = 3
+	OfficerEngineer   int64 = 4
+	OfficerGeologist  int64 = 5
+	OfficerTechnocrat int64 = 6
+)
+
+// Officer hiring state of an officer
+type Officer struct {
+	Type     int64
+	Active   bool
+	ExpireAt time.Time // zero if the officer is not hired or the expiry is unknown, the officers bar only shows a rough remaining time
+}
+
+// OfficerCost returns the dark matter cost to hire an officer for the given number of days (7 or 90)
+func OfficerCost(typ, days int64) int64 {
+	if typ < OfficerCommander || typ > OfficerTechnocrat {
+		return 0
+	}
+	if typ == OfficerCommander {
+		if days == 90 {
+			return 100000
+		}
+		return 10000
+	}
+	if days == 90 {
+		return 125000
+	}
+	return 12500
+}
@@ -0,0 +1,15 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfficerCost(t *testing.T) {
+	assert.Equal(t, int64(10000), OfficerCost(OfficerCommander, 7))
+	assert.Equal(t, int64(100000), OfficerCost(OfficerCommander, 90))
+	assert.Equal(t, int64(12500), OfficerCost(OfficerGeologist, 7))
+	assert.Equal(t, int64(125000), OfficerCost(OfficerTechnocrat, 90))
+	assert.Equal(t, int64(0), OfficerCost(1, 7))
+}
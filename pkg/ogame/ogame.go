@@ -84,6 +84,35 @@ func (s Speed) String() string {
 	}
 }
 
+// AllowedSpeeds returns the fleet speeds available to a character class, from the slowest to the fastest.
+// General gets 5% steps, other classes 10% steps.
+func AllowedSpeeds(class CharacterClass) []Speed {
+	step := Speed(1)
+	if class == General {
+		step = 0.5
+	}
+	speeds := make([]Speed, 0)
+	for speed := step; speed <= HundredPercent; speed += step {
+		speeds = append(speeds, speed)
+	}
+	return speeds
+}
+
+// IsValid returns either or not the speed is available to the character class
+func (s Speed) IsValid(class CharacterClass) bool {
+	for _, speed := range AllowedSpeeds(class) {
+		if s == speed {
+			return true
+		}
+	}
+	return false
+}
+
+// FormValue returns the speed as sent by the fleet dispatch form, eg: "10" or "5.5"
+func (s Speed) FormValue() string {
+	return strconv.FormatFloat(float64(s), 'f', -1, 64)
+}
+
 type ResourcesResp struct {
 	Metal struct {
 		Resources struct {
@@ -194,6 +223,8 @@ type EspionageReportSummary struct {
 	From           string // Fleet Command | Space Monitoring
 	Target         Coordinate
 	LootPercentage float64
+	APIKey         string // sr-xx-... key, empty for espionage actions
+	CreatedAt      time.Time
 }
 
 // ExpeditionMessage ...
@@ -254,3 +285,22 @@ type ACSValues struct {
 	ACSValues string
 	Union     int64
 }
+
+// ConstructionETA construction started by Build, queued behind the constructions already in its queue
+type ConstructionETA struct {
+	ID        ID
+	Nbr       int64         // level being built for buildings and researches, units for ships and defenses
+	StartedAt time.Time     // server clock, in the server time zone, when the queue in front of it is done
+	Duration  time.Duration // 0 if it could not be computed (eg: lifeform researches)
+	FinishAt  time.Time
+	Err       string // set when the techs or the queue of the celestial could not be loaded, the construction was started anyway
+}
+
+// BuildProgress progress of a ships or defenses order split in chunks, reported after each chunk
+type BuildProgress struct {
+	ID      ID
+	Chunk   int64 // index of the chunk just ordered, starting at 1
+	Ordered int64 // units ordered so far
+	Total   int64 // units requested
+	Queued  int64 // units of ID in the production queue after the chunk
+}
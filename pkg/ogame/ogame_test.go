@@ -0,0 +1,34 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedSpeeds(t *testing.T) {
+	speeds := AllowedSpeeds(Collector)
+	assert.Equal(t, 10, len(speeds))
+	assert.Equal(t, TenPercent, speeds[0])
+	assert.Equal(t, HundredPercent, speeds[9])
+	speeds = AllowedSpeeds(General)
+	assert.Equal(t, 20, len(speeds))
+	assert.Equal(t, FivePercent, speeds[0])
+	assert.Equal(t, FifteenPercent, speeds[2])
+	assert.Equal(t, HundredPercent, speeds[19])
+}
+
+func TestSpeedIsValid(t *testing.T) {
+	assert.True(t, HundredPercent.IsValid(Collector))
+	assert.False(t, FiftyFivePercent.IsValid(Collector))
+	assert.False(t, FiftyFivePercent.IsValid(NoClass))
+	assert.True(t, FiftyFivePercent.IsValid(General))
+	assert.False(t, Speed(0).IsValid(General))
+	assert.False(t, Speed(10.5).IsValid(General))
+}
+
+func TestSpeedFormValue(t *testing.T) {
+	assert.Equal(t, "10", HundredPercent.FormValue())
+	assert.Equal(t, "5.5", FiftyFivePercent.FormValue())
+	assert.Equal(t, "0.5", FivePercent.FormValue())
+}
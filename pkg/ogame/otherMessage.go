@@ -0,0 +1,77 @@
+package ogame
+
+import (
+	"strconv"
+	"time"
+)
+
+// OtherMessageType kind of message of the "Other" messages tab (24)
+type OtherMessageType int64
+
+// Other tab message types
+const (
+	UnknownOtherMessage OtherMessageType = iota
+	MoonDestructionOtherMessage
+	FleetReturnOtherMessage
+	OfficerExpiryOtherMessage
+	TutorialRewardOtherMessage
+)
+
+func (t OtherMessageType) String() string {
+	switch t {
+	case UnknownOtherMessage:
+		return "unknown"
+	case MoonDestructionOtherMessage:
+		return "moon destruction"
+	case FleetReturnOtherMessage:
+		return "fleet return"
+	case OfficerExpiryOtherMessage:
+		return "officer expiry"
+	case TutorialRewardOtherMessage:
+		return "tutorial reward"
+	}
+	return "OtherMessageType(" + strconv.FormatInt(int64(t), 10) + ")"
+}
+
+// OtherMessage message of the "Other" tab. Title and Content are always set,
+// the field matching Type is set when the message could be parsed.
+type OtherMessage struct {
+	ID              int64
+	Type            OtherMessageType
+	Title           string
+	Sender          string
+	Coordinate      Coordinate // first coordinate of the title, or of the content
+	Content         string     // html content
+	CreatedAt       time.Time
+	MoonDestruction *MoonDestructionResult
+	FleetReturn     *FleetReturnNotice
+	OfficerExpiry   *OfficerExpiryNotice
+	TutorialReward  *TutorialRewardNotice
+}
+
+// MoonDestructionResult outcome of a moon destruction mission
+type MoonDestructionResult struct {
+	Moon                Coordinate
+	MoonChance          float64 // announced moon destruction chance, 0-1
+	DeathstarsChance    float64 // announced deathstars destruction chance, 0-1
+	MoonDestroyed       bool
+	DeathstarsDestroyed bool
+}
+
+// FleetReturnNotice fleet back on its origin celestial
+type FleetReturnNotice struct {
+	From      Coordinate // destination of the mission
+	To        Coordinate // celestial the fleet returned to
+	Resources Resources  // resources brought back
+}
+
+// OfficerExpiryNotice officer about to expire, or expired
+type OfficerExpiryNotice struct {
+	Officer string
+	Expired bool
+}
+
+// TutorialRewardNotice reward received for completing a tutorial task
+type TutorialRewardNotice struct {
+	Reward string // text of the reward, eg: "2 x Light Fighter"
+}
@@ -0,0 +1,106 @@
+package ogame
+
+import (
+	"math"
+	"sort"
+)
+
+// PhalanxMoon a moon and the level of its sensor phalanx
+type PhalanxMoon struct {
+	ID         MoonID
+	Coordinate Coordinate
+	Level      int64
+}
+
+// PhalanxCoverage systems in range of a set of sensor phalanx
+type PhalanxCoverage struct {
+	Moons        []PhalanxMoon
+	NbSystems    int64 // number of systems per galaxy
+	DonutSystem  bool
+	IsDiscoverer bool
+}
+
+// SystemDistance returns the distance between two systems of the same galaxy
+func SystemDistance(nbSystems, system1, system2 int64, donutSystem bool) int64 {
+	if !donutSystem {
+		return int64(math.Abs(float64(system2 - system1)))
+	}
+	if system1 > system2 {
+		system1, system2 = system2, system1
+	}
+	return int64(math.Min(float64(system2-system1), float64((system1+nbSystems)-system2)))
+}
+
+// InPhalanxRange returns true if target is in range of a sensor phalanx of the given level on moon
+func InPhalanxRange(moon, target Coordinate, level, nbSystems int64, donutSystem, isDiscoverer bool) bool {
+	if level <= 0 || moon.Galaxy != target.Galaxy {
+		return false
+	}
+	return SystemDistance(nbSystems, moon.System, target.System, donutSystem) <= PhalanxRange(level, isDiscoverer)
+}
+
+// PhalanxSystems returns the sorted systems of the galaxy of moon in range of a sensor phalanx of the given level.
+// With donutSystem the range wraps around the ends of the galaxy, nbSystems 0 does not bound the systems.
+func PhalanxSystems(moon Coordinate, level, nbSystems int64, donutSystem, isDiscoverer bool) []int64 {
+	out := make([]int64, 0)
+	if level <= 0 {
+		return out
+	}
+	seen := make(map[int64]struct{})
+	phalanxRange := PhalanxRange(level, isDiscoverer)
+	for offset := -phalanxRange; offset <= phalanxRange; offset++ {
+		system := moon.System + offset
+		if donutSystem && nbSystems > 0 {
+			system = ((system-1)%nbSystems+nbSystems)%nbSystems + 1
+		} else if system < 1 || (nbSystems > 0 && system > nbSystems) {
+			continue
+		}
+		if _, ok := seen[system]; ok {
+			continue
+		}
+		seen[system] = struct{}{}
+		out = append(out, system)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// IsCovered returns true if at least one of the moons can scan coord
+func (c PhalanxCoverage) IsCovered(coord Coordinate) bool {
+	return len(c.CoveringMoons(coord)) > 0
+}
+
+// CoveringMoons returns the moons that can scan coord
+func (c PhalanxCoverage) CoveringMoons(coord Coordinate) []MoonID {
+	out := make([]MoonID, 0)
+	for _, moon := range c.Moons {
+		if InPhalanxRange(moon.Coordinate, coord, moon.Level, c.NbSystems, c.DonutSystem, c.IsDiscoverer) {
+			out = append(out, moon.ID)
+		}
+	}
+	return out
+}
+
+// Systems returns the sorted list of covered systems for each galaxy
+func (c PhalanxCoverage) Systems() map[int64][]int64 {
+	covered := make(map[int64]map[int64]struct{})
+	for _, moon := range c.Moons {
+		if moon.Level <= 0 {
+			continue
+		}
+		if covered[moon.Coordinate.Galaxy] == nil {
+			covered[moon.Coordinate.Galaxy] = make(map[int64]struct{})
+		}
+		for _, system := range PhalanxSystems(moon.Coordinate, moon.Level, c.NbSystems, c.DonutSystem, c.IsDiscoverer) {
+			covered[moon.Coordinate.Galaxy][system] = struct{}{}
+		}
+	}
+	out := make(map[int64][]int64)
+	for galaxy, systems := range covered {
+		for system := range systems {
+			out[galaxy] = append(out[galaxy], system)
+		}
+		sort.Slice(out[galaxy], func(i, j int) bool { return out[galaxy][i] < out[galaxy][j] })
+	}
+	return out
+}
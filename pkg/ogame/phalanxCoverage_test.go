@@ -0,0 +1,40 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemDistance(t *testing.T) {
+	assert.Equal(t, int64(498), SystemDistance(499, 1, 499, false))
+	assert.Equal(t, int64(1), SystemDistance(499, 1, 499, true))
+	assert.Equal(t, int64(10), SystemDistance(499, 20, 10, true))
+}
+
+func TestPhalanxCoverage(t *testing.T) {
+	c := PhalanxCoverage{
+		Moons: []PhalanxMoon{
+			{ID: 1, Coordinate: Coordinate{1, 2, 8, MoonType}, Level: 2},
+			{ID: 2, Coordinate: Coordinate{2, 100, 8, MoonType}, Level: 1},
+			{ID: 3, Coordinate: Coordinate{3, 100, 8, MoonType}, Level: 0},
+		},
+		NbSystems:   499,
+		DonutSystem: true,
+	}
+	assert.True(t, c.IsCovered(Coordinate{1, 498, 3, PlanetType}))
+	assert.False(t, c.IsCovered(Coordinate{1, 497, 3, PlanetType}))
+	assert.Equal(t, []MoonID{2}, c.CoveringMoons(Coordinate{2, 101, 3, PlanetType}))
+	assert.False(t, c.IsCovered(Coordinate{3, 100, 3, PlanetType}))
+	assert.Equal(t, map[int64][]int64{1: {1, 2, 3, 4, 5, 498, 499}, 2: {99, 100, 101}}, c.Systems())
+	c.DonutSystem = false
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, c.Systems()[1])
+}
+
+func TestPhalanxSystems(t *testing.T) {
+	moon := Coordinate{Galaxy: 1, System: 2, Position: 8, Type: MoonType}
+	assert.Equal(t, []int64{}, PhalanxSystems(moon, 0, 499, true, false))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, PhalanxSystems(moon, 2, 499, false, false))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 498, 499}, PhalanxSystems(moon, 2, 499, true, false))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, PhalanxSystems(moon, 10, 5, true, false))
+}
@@ -1,5 +1,19 @@
 package ogame
 
+import (
+	"path"
+	"strings"
+)
+
+// ImgID returns the id of a celestial image, the file name of its url without the extension
+func ImgID(img string) string {
+	if img == "" {
+		return ""
+	}
+	name := path.Base(img)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
 // Planet ogame planet object
 type Planet struct {
 	Img         string
@@ -14,6 +28,7 @@ type Planet struct {
 
 func (p Planet) GetID() CelestialID          { return p.ID.Celestial() }
 func (p Planet) GetImg() string              { return p.Img }
+func (p Planet) GetImgID() string            { return ImgID(p.Img) }
 func (p Planet) GetName() string             { return p.Name }
 func (p Planet) GetDiameter() int64          { return p.Diameter }
 func (p Planet) GetCoordinate() Coordinate   { return p.Coordinate }
@@ -23,18 +38,21 @@ func (p Planet) GetMoon() *Moon              { return p.Moon }
 func (p Planet) GetType() CelestialType      { return PlanetType }
 
 type Moon struct {
-	ID         MoonID
-	Img        string
-	Name       string
-	Diameter   int64
-	Coordinate Coordinate
-	Fields     Fields
+	ID          MoonID
+	Img         string
+	Name        string
+	Diameter    int64
+	Coordinate  Coordinate
+	Fields      Fields
+	Temperature Temperature // same as the planet of the moon
 }
 
-func (m Moon) GetID() CelestialID        { return m.ID.Celestial() }
-func (m Moon) GetImg() string            { return m.Img }
-func (m Moon) GetName() string           { return m.Name }
-func (m Moon) GetDiameter() int64        { return m.Diameter }
-func (m Moon) GetCoordinate() Coordinate { return m.Coordinate }
-func (m Moon) GetFields() Fields         { return m.Fields }
-func (p Moon) GetType() CelestialType    { return MoonType }
+func (m Moon) GetID() CelestialID          { return m.ID.Celestial() }
+func (m Moon) GetImg() string              { return m.Img }
+func (m Moon) GetImgID() string            { return ImgID(m.Img) }
+func (m Moon) GetName() string             { return m.Name }
+func (m Moon) GetDiameter() int64          { return m.Diameter }
+func (m Moon) GetCoordinate() Coordinate   { return m.Coordinate }
+func (m Moon) GetFields() Fields           { return m.Fields }
+func (m Moon) GetTemperature() Temperature { return m.Temperature }
+func (p Moon) GetType() CelestialType      { return MoonType }
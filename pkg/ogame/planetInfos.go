@@ -13,6 +13,7 @@ type SystemInfos struct {
 	ExpeditionDebris struct {
 		Metal             int64
 		Crystal           int64
+		Deuterium         int64
 		PathfindersNeeded int64
 	}
 	Events struct {
@@ -40,6 +41,16 @@ func (s SystemInfos) Position(idx int64) *PlanetInfos {
 	return s.Tmpplanets[idx-1]
 }
 
+// ExpeditionDebrisCoordinate returns the coordinate of the expedition debris field (position 16) of the system
+func (s SystemInfos) ExpeditionDebrisCoordinate() Coordinate {
+	return Coordinate{Galaxy: s.Tmpgalaxy, System: s.Tmpsystem, Position: 16, Type: DebrisType}
+}
+
+// HasExpeditionDebris returns true if there are resources in the expedition debris field
+func (s SystemInfos) HasExpeditionDebris() bool {
+	return s.ExpeditionDebris.Metal+s.ExpeditionDebris.Crystal+s.ExpeditionDebris.Deuterium > 0
+}
+
 // Each will execute provided callback for every positions in the system
 func (s SystemInfos) Each(clb func(planetInfo *PlanetInfos)) {
 	var i int64
@@ -57,6 +68,7 @@ func (s SystemInfos) MarshalJSON() ([]byte, error) {
 		ExpeditionDebris struct {
 			Metal             int64
 			Crystal           int64
+			Deuterium         int64
 			PathfindersNeeded int64
 		}
 	}
@@ -65,10 +77,35 @@ func (s SystemInfos) MarshalJSON() ([]byte, error) {
 	tmp.Planets = s.Tmpplanets
 	tmp.ExpeditionDebris.Metal = s.ExpeditionDebris.Metal
 	tmp.ExpeditionDebris.Crystal = s.ExpeditionDebris.Crystal
+	tmp.ExpeditionDebris.Deuterium = s.ExpeditionDebris.Deuterium
 	tmp.ExpeditionDebris.PathfindersNeeded = s.ExpeditionDebris.PathfindersNeeded
 	return json.Marshal(tmp)
 }
 
+// UnmarshalJSON reads the json of MarshalJSON, the galaxy events are not part of it
+func (s *SystemInfos) UnmarshalJSON(data []byte) error {
+	var tmp struct {
+		Galaxy           int64
+		System           int64
+		Planets          [15]*PlanetInfos
+		ExpeditionDebris struct {
+			Metal             int64
+			Crystal           int64
+			Deuterium         int64
+			PathfindersNeeded int64
+		}
+	}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	*s = SystemInfos{Tmpgalaxy: tmp.Galaxy, Tmpsystem: tmp.System, Tmpplanets: tmp.Planets}
+	s.ExpeditionDebris.Metal = tmp.ExpeditionDebris.Metal
+	s.ExpeditionDebris.Crystal = tmp.ExpeditionDebris.Crystal
+	s.ExpeditionDebris.Deuterium = tmp.ExpeditionDebris.Deuterium
+	s.ExpeditionDebris.PathfindersNeeded = tmp.ExpeditionDebris.PathfindersNeeded
+	return nil
+}
+
 // MoonInfos public information of a moon in the galaxy page
 type MoonInfos struct {
 	ID       int64
@@ -50,6 +50,18 @@ func TestSystemInfos_MarshalJSON(t *testing.T) {
 		`"Administrator":false,"Destroyed":false,"Inactive":false,"Vacation":false,"StrongPlayer":false,"Newbie":false,` +
 		`"HonorableTarget":false,"Banned":false,"Debris":{"Metal":1,"Crystal":2,"RecyclersNeeded":3},"Moon":null,` +
 		`"Player":{"ID":1,"Name":"player name","Rank":2,"IsBandit":false,"IsStarlord":false},"Alliance":null,"Date":"0001-01-01T00:00:00Z"},` +
-		`null,null,null,null,null,null,null,null,null,null,null,null,null],"ExpeditionDebris":{"Metal":0,"Crystal":0,"PathfindersNeeded":0}}`
+		`null,null,null,null,null,null,null,null,null,null,null,null,null],"ExpeditionDebris":{"Metal":0,"Crystal":0,"Deuterium":0,"PathfindersNeeded":0}}`
 	assert.Equal(t, expected, string(by))
+
+	var decoded SystemInfos
+	assert.NoError(t, json.Unmarshal(by, &decoded))
+	assert.Equal(t, si, decoded)
+}
+
+func TestSystemInfos_ExpeditionDebris(t *testing.T) {
+	si := SystemInfos{Tmpgalaxy: 4, Tmpsystem: 148}
+	assert.Equal(t, Coordinate{Galaxy: 4, System: 148, Position: 16, Type: DebrisType}, si.ExpeditionDebrisCoordinate())
+	assert.False(t, si.HasExpeditionDebris())
+	si.ExpeditionDebris.Crystal = 2300
+	assert.True(t, si.HasExpeditionDebris())
 }
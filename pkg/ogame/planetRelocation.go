@@ -0,0 +1,15 @@
+package ogame
+
+import "time"
+
+// PlanetRelocationDuration time between the relocation request and the actual planet move
+const PlanetRelocationDuration = 24 * time.Hour
+
+// PlanetRelocationCost dark matter cost of a planet relocation
+const PlanetRelocationCost int64 = 240000
+
+// PlanetRelocation relocation state of a planet, from the planetMoveInProgress and planetMoveCooldown page variables
+type PlanetRelocation struct {
+	InProgress bool
+	Cooldown   int64 // seconds before a new relocation can be started, 0 if none
+}
@@ -0,0 +1,68 @@
+package ogame
+
+import "math"
+
+// ProductionBonuses planet production bonuses that do not come from the mines and power plants levels
+type ProductionBonuses struct {
+	Crawlers        int64 // crawlers stationed on the planet
+	CharacterClass  CharacterClass
+	Geologist       bool
+	Engineer        bool
+	CommandingStaff bool // all five officers are hired
+}
+
+// ActiveCrawlers returns the crawlers giving a bonus, at most 8 per mine level, 10% more with a geologist
+func (p ProductionBonuses) ActiveCrawlers(resBuildings IResourcesBuildings) int64 {
+	maxCrawlers := float64(resBuildings.GetMetalMine()+resBuildings.GetCrystalMine()+resBuildings.GetDeuteriumSynthesizer()) * 8
+	if p.Geologist {
+		maxCrawlers *= 1.1
+	}
+	return min64(p.Crawlers, int64(maxCrawlers))
+}
+
+// CrawlersBonus returns the mines production bonus given by the crawlers, 0.02% per crawler (0.03% for collectors),
+// scaled by the crawler production setting (percent) and capped at 50%
+func (p ProductionBonuses) CrawlersBonus(resBuildings IResourcesBuildings, crawlerSetting int64) float64 {
+	perCrawler := 0.0002
+	if p.CharacterClass == Collector {
+		perCrawler = 0.0003
+	}
+	bonus := float64(p.ActiveCrawlers(resBuildings)) * perCrawler * float64(crawlerSetting) / 100
+	return math.Min(bonus, 0.5)
+}
+
+// CrawlersEnergyConsumption returns the energy consumed by the active crawlers, 50 per crawler at 100%
+func (p ProductionBonuses) CrawlersEnergyConsumption(resBuildings IResourcesBuildings, crawlerSetting int64) int64 {
+	return int64(math.Ceil(float64(p.ActiveCrawlers(resBuildings)) * 50 * float64(crawlerSetting) / 100))
+}
+
+// MinesBonus returns the bonus applied to the base mines production (without plasma technology),
+// eg: 0.35 for a collector with a geologist
+func (p ProductionBonuses) MinesBonus(resBuildings IResourcesBuildings, crawlerSetting int64) float64 {
+	bonus := p.CrawlersBonus(resBuildings, crawlerSetting)
+	if p.Geologist {
+		bonus += 0.1
+	}
+	if p.CommandingStaff {
+		bonus += 0.02
+	}
+	if p.CharacterClass == Collector {
+		bonus += 0.25
+	}
+	return bonus
+}
+
+// EnergyBonus returns the bonus applied to the energy production
+func (p ProductionBonuses) EnergyBonus() float64 {
+	var bonus float64
+	if p.Engineer {
+		bonus += 0.1
+	}
+	if p.CommandingStaff {
+		bonus += 0.02
+	}
+	if p.CharacterClass == Collector {
+		bonus += 0.1
+	}
+	return bonus
+}
@@ -0,0 +1,28 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductionBonuses_ActiveCrawlers(t *testing.T) {
+	resBuildings := ResourcesBuildings{MetalMine: 10, CrystalMine: 10, DeuteriumSynthesizer: 5}
+	assert.Equal(t, int64(100), ProductionBonuses{Crawlers: 100}.ActiveCrawlers(resBuildings))
+	assert.Equal(t, int64(200), ProductionBonuses{Crawlers: 500}.ActiveCrawlers(resBuildings))
+	assert.Equal(t, int64(220), ProductionBonuses{Crawlers: 500, Geologist: true}.ActiveCrawlers(resBuildings))
+}
+
+func TestProductionBonuses_MinesBonus(t *testing.T) {
+	resBuildings := ResourcesBuildings{MetalMine: 10, CrystalMine: 10, DeuteriumSynthesizer: 5}
+	assert.Equal(t, 0.0, ProductionBonuses{}.MinesBonus(resBuildings, 100))
+	assert.InDelta(t, 0.02, ProductionBonuses{Crawlers: 100}.MinesBonus(resBuildings, 100), 0.00001)
+	assert.InDelta(t, 0.0, ProductionBonuses{Crawlers: 100}.MinesBonus(resBuildings, 0), 0.00001)
+	assert.InDelta(t, 0.25+0.1+0.02+0.045, ProductionBonuses{Crawlers: 100, CharacterClass: Collector, Geologist: true, CommandingStaff: true}.MinesBonus(resBuildings, 150), 0.00001)
+	assert.Equal(t, int64(5000), ProductionBonuses{Crawlers: 100}.CrawlersEnergyConsumption(resBuildings, 100))
+}
+
+func TestProductionBonuses_EnergyBonus(t *testing.T) {
+	assert.Equal(t, 0.0, ProductionBonuses{}.EnergyBonus())
+	assert.InDelta(t, 0.22, ProductionBonuses{CharacterClass: Collector, Engineer: true, CommandingStaff: true}.EnergyBonus(), 0.00001)
+}
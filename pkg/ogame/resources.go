@@ -3,6 +3,7 @@ package ogame
 import (
 	"fmt"
 	stdmath "math"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/google/gxui/math"
@@ -70,6 +71,53 @@ func (r ResourcesDetails) Available() Resources {
 	}
 }
 
+// forecastResource returns the amount of a resource after d, given its hourly production.
+// Production stops at the storage capacity, an amount already above it is kept.
+func forecastResource(available, storageCapacity, hourlyProduction int64, d time.Duration) int64 {
+	if d <= 0 || hourlyProduction <= 0 || (storageCapacity > 0 && available >= storageCapacity) {
+		return available
+	}
+	amount := available + int64(float64(hourlyProduction)*d.Hours())
+	if storageCapacity > 0 && amount > storageCapacity {
+		amount = storageCapacity
+	}
+	return amount
+}
+
+// Forecast returns the metal, crystal and deuterium the celestial will have after d,
+// assuming the current production and storage capacity do not change
+func (r ResourcesDetails) Forecast(d time.Duration) Resources {
+	return Resources{
+		Metal:     forecastResource(r.Metal.Available, r.Metal.StorageCapacity, r.Metal.CurrentProduction, d),
+		Crystal:   forecastResource(r.Crystal.Available, r.Crystal.StorageCapacity, r.Crystal.CurrentProduction, d),
+		Deuterium: forecastResource(r.Deuterium.Available, r.Deuterium.StorageCapacity, r.Deuterium.CurrentProduction, d),
+	}
+}
+
+// TimeToAfford returns how long until the celestial can afford cost with its current production.
+// false is returned if cost will never be affordable, because of a missing production or a too small storage.
+func (r ResourcesDetails) TimeToAfford(cost Resources) (time.Duration, bool) {
+	var longest time.Duration
+	for _, res := range []struct{ available, storageCapacity, production, cost int64 }{
+		{r.Metal.Available, r.Metal.StorageCapacity, r.Metal.CurrentProduction, cost.Metal},
+		{r.Crystal.Available, r.Crystal.StorageCapacity, r.Crystal.CurrentProduction, cost.Crystal},
+		{r.Deuterium.Available, r.Deuterium.StorageCapacity, r.Deuterium.CurrentProduction, cost.Deuterium},
+	} {
+		missing := res.cost - res.available
+		if missing <= 0 {
+			continue
+		}
+		if res.production <= 0 || (res.storageCapacity > 0 && res.cost > res.storageCapacity) {
+			return 0, false
+		}
+		d := time.Duration(stdmath.Ceil(float64(missing) / float64(res.production) * float64(time.Hour)))
+		if d > longest {
+			longest = d
+		}
+	}
+	return longest, true
+}
+
 // Resources represent ogame resources
 type Resources struct {
 	Metal      int64
@@ -2,6 +2,7 @@ package ogame
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/gxui/math"
 	"github.com/stretchr/testify/assert"
@@ -100,3 +101,28 @@ func TestResources_FitsIn(t *testing.T) {
 	assert.Equal(t, int64(0), Resources{Metal: 100, Crystal: 200, Deuterium: 300}.FitsIn(EspionageProbe, Researches{}, false, false, false))
 	assert.Equal(t, int64(120), Resources{Metal: 100, Crystal: 200, Deuterium: 300}.FitsIn(EspionageProbe, Researches{}, true, false, false))
 }
+
+func TestResourcesDetails_Forecast(t *testing.T) {
+	d := ResourcesDetails{}
+	d.Metal.Available, d.Metal.StorageCapacity, d.Metal.CurrentProduction = 1000, 10000, 3600
+	d.Crystal.Available, d.Crystal.StorageCapacity, d.Crystal.CurrentProduction = 9000, 10000, 3600
+	d.Deuterium.Available, d.Deuterium.StorageCapacity, d.Deuterium.CurrentProduction = 12000, 10000, 3600
+	assert.Equal(t, Resources{Metal: 4600, Crystal: 10000, Deuterium: 12000}, d.Forecast(time.Hour))
+	assert.Equal(t, Resources{Metal: 1000, Crystal: 9000, Deuterium: 12000}, d.Forecast(-time.Hour))
+}
+
+func TestResourcesDetails_TimeToAfford(t *testing.T) {
+	d := ResourcesDetails{}
+	d.Metal.Available, d.Metal.StorageCapacity, d.Metal.CurrentProduction = 1000, 10000, 3600
+	d.Crystal.Available, d.Crystal.StorageCapacity, d.Crystal.CurrentProduction = 1000, 10000, 1800
+	dur, ok := d.TimeToAfford(Resources{Metal: 4600, Crystal: 2800})
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, dur)
+	dur, ok = d.TimeToAfford(Resources{Metal: 500})
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), dur)
+	_, ok = d.TimeToAfford(Resources{Metal: 20000})
+	assert.False(t, ok)
+	_, ok = d.TimeToAfford(Resources{Deuterium: 1})
+	assert.False(t, ok)
+}
@@ -27,13 +27,19 @@ func (p sensorPhalanx) ScanConsumption() int64 {
 
 // GetRange gets sensor range
 func (p sensorPhalanx) GetRange(lvl int64, isDiscoverer bool) int64 {
+	return PhalanxRange(lvl, isDiscoverer)
+}
+
+// PhalanxRange returns the number of systems a sensor phalanx of the given level can scan on each side of its moon.
+// The range is level²-1 (1 at level 1), increased by 20% for the Discoverer class.
+func PhalanxRange(level int64, isDiscoverer bool) int64 {
 	var phalanxRange int64
-	if lvl == 0 {
+	if level <= 0 {
 		phalanxRange = 0
-	} else if lvl == 1 {
+	} else if level == 1 {
 		phalanxRange = 1
 	} else {
-		phalanxRange = int64(math.Pow(float64(lvl), 2)) - 1
+		phalanxRange = int64(math.Pow(float64(level), 2)) - 1
 	}
 	if isDiscoverer {
 		phalanxRange += int64(math.Round(0.2 * float64(phalanxRange)))
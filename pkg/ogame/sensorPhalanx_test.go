@@ -35,3 +35,11 @@ func TestSensorPhalanx_ScanConsumption(t *testing.T) {
 	sp := newSensorPhalanx()
 	assert.Equal(t, int64(5000), sp.ScanConsumption())
 }
+
+func TestPhalanxRange(t *testing.T) {
+	assert.Equal(t, int64(0), PhalanxRange(0, false))
+	assert.Equal(t, int64(0), PhalanxRange(-2, false))
+	assert.Equal(t, int64(1), PhalanxRange(1, false))
+	assert.Equal(t, int64(24), PhalanxRange(5, false))
+	assert.Equal(t, int64(29), PhalanxRange(5, true))
+}
@@ -115,6 +115,20 @@ func (s ShipsInfos) Cargo(techs Researches, probeRaids, isCollector, isPioneers
 	return
 }
 
+// CargoShipsNeeded returns how many ships of the given type are needed to carry amount resources, 0 if the ship has no cargo
+func CargoShipsNeeded(id ID, amount int64, techs Researches, probeRaids, isCollector, isPioneers bool) int64 {
+	var capacity int64
+	for _, ship := range Ships {
+		if ship.GetID() == id {
+			capacity = ship.GetCargoCapacity(techs, probeRaids, isCollector, isPioneers)
+		}
+	}
+	if capacity <= 0 || amount <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(float64(amount) / float64(capacity)))
+}
+
 // Has returns true if v is contained by s
 func (s ShipsInfos) Has(v ShipsInfos) bool {
 	for _, ship := range Ships {
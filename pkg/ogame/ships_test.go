@@ -19,6 +19,14 @@ func TestShipsInfos_Cargo(t *testing.T) {
 	assert.Equal(t, int64(60000), ships.Cargo(techs, false, false, false))
 }
 
+func TestCargoShipsNeeded(t *testing.T) {
+	techs := Researches{}
+	assert.Equal(t, int64(4), CargoShipsNeeded(LargeCargoID, 100000, techs, false, false, false))
+	assert.Equal(t, int64(20), CargoShipsNeeded(SmallCargoID, 100000, techs, false, false, false))
+	assert.Equal(t, int64(0), CargoShipsNeeded(SolarSatelliteID, 100000, techs, false, false, false))
+	assert.Equal(t, int64(0), CargoShipsNeeded(LargeCargoID, 0, techs, false, false, false))
+}
+
 func TestShipsInfos_FleetValue(t *testing.T) {
 	ships := ShipsInfos{
 		SmallCargo: 2,
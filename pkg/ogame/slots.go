@@ -1,5 +1,7 @@
 package ogame
 
+import "math"
+
 // Slots ...
 type Slots struct {
 	InUse    int64
@@ -7,3 +9,35 @@ type Slots struct {
 	ExpInUse int64
 	ExpTotal int64
 }
+
+// SlotsBonus additional fleet and expedition slots, eg: given by active items or lifeform technologies
+type SlotsBonus struct {
+	Fleet      int64
+	Expedition int64
+}
+
+// MaxSlots returns the fleet and expedition slots the account should have (only Total and ExpTotal are set).
+// Fleet slots: 1 + computer technology, +1 with the Admiral, +2 for the General class.
+// Expedition slots: square root of astrophysics, +1 with the Admiral, +2 for the Discoverer class.
+func MaxSlots(techs Researches, class CharacterClass, hasAdmiral bool, items SlotsBonus) Slots {
+	out := Slots{
+		Total:    1 + techs.ComputerTechnology + items.Fleet,
+		ExpTotal: int64(math.Sqrt(float64(techs.Astrophysics))) + items.Expedition,
+	}
+	if hasAdmiral {
+		out.Total++
+		out.ExpTotal++
+	}
+	if class == General {
+		out.Total += 2
+	} else if class == Discoverer {
+		out.ExpTotal += 2
+	}
+	return out
+}
+
+// Discrepancy returns how many fleet and expedition slots s has more than expected (negative if less),
+// eg: the extracted slots compared to MaxSlots. Non zero values point to a bonus MaxSlots does not know about.
+func (s Slots) Discrepancy(expected Slots) (fleet, expedition int64) {
+	return s.Total - expected.Total, s.ExpTotal - expected.ExpTotal
+}
@@ -0,0 +1,18 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxSlots(t *testing.T) {
+	techs := Researches{ComputerTechnology: 10, Astrophysics: 9}
+	assert.Equal(t, Slots{Total: 11, ExpTotal: 3}, MaxSlots(techs, NoClass, false, SlotsBonus{}))
+	assert.Equal(t, Slots{Total: 14, ExpTotal: 4}, MaxSlots(techs, General, true, SlotsBonus{}))
+	assert.Equal(t, Slots{Total: 12, ExpTotal: 7}, MaxSlots(techs, Discoverer, true, SlotsBonus{Expedition: 1}))
+
+	fleet, expedition := Slots{Total: 13, ExpTotal: 3}.Discrepancy(MaxSlots(techs, NoClass, false, SlotsBonus{}))
+	assert.Equal(t, int64(2), fleet)
+	assert.Equal(t, int64(0), expedition)
+}
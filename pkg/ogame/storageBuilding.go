@@ -12,3 +12,12 @@ type storageBuilding struct {
 func (s storageBuilding) Capacity(lvl int64) int64 {
 	return 5000 * int64(2.5*math.Pow(math.E, (20*float64(lvl))/33))
 }
+
+// StorageCapacity returns the storage capacity of a planet given its resources buildings
+func StorageCapacity(buildings IResourcesBuildings) Resources {
+	return Resources{
+		Metal:     MetalStorage.Capacity(buildings.GetMetalStorage()),
+		Crystal:   CrystalStorage.Capacity(buildings.GetCrystalStorage()),
+		Deuterium: DeuteriumTank.Capacity(buildings.GetDeuteriumTank()),
+	}
+}
@@ -0,0 +1,68 @@
+package ogame
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// TearDownStep one level of a building torn down
+type TearDownStep struct {
+	ID       ID
+	Level    int64         // level torn down, the building is at Level-1 afterward
+	Cost     Resources     // price of the tear down, reduced by the ion technology
+	Refund   Resources     // part of the construction price of the level given back
+	Duration time.Duration // the tear down takes as long as the construction of the level
+}
+
+// Net returns the resources won by the tear down, negative when it costs more than it gives back
+func (s TearDownStep) Net() int64 {
+	return s.Refund.Total() - s.Cost.Total()
+}
+
+// TearDownRefund returns the part of the construction price of level given back when it is torn down
+func TearDownRefund(building Building, level int64, refundRatio float64) Resources {
+	if level <= 0 || refundRatio <= 0 {
+		return Resources{}
+	}
+	price := building.GetPrice(level)
+	return Resources{
+		Metal:     int64(math.Floor(float64(price.Metal) * refundRatio)),
+		Crystal:   int64(math.Floor(float64(price.Crystal) * refundRatio)),
+		Deuterium: int64(math.Floor(float64(price.Deuterium) * refundRatio)),
+	}
+}
+
+// TearDownCost returns the cost, refund and duration of tearing down level of building
+func TearDownCost(building Building, level int64, techs IResearches, refundRatio float64, universeSpeed int64, facilities BuildAccelerators) TearDownStep {
+	return TearDownStep{
+		ID:       building.GetID(),
+		Level:    level,
+		Cost:     building.DeconstructionPrice(level, techs),
+		Refund:   TearDownRefund(building, level, refundRatio),
+		Duration: building.ConstructionTime(level, universeSpeed, facilities, false, false),
+	}
+}
+
+// TearDownPlan returns the levels of resources buildings and facilities worth tearing down, the ones refunding
+// more than they cost, most profitable first. Every level down to 0 is listed, a building is torn down from its
+// current level. The buildings the game does not demolish (terraformer, lunar base) are skipped.
+// The plan is empty without refund (refundRatio 0), a tear down then only costs resources.
+func TearDownPlan(resourcesBuildings IResourcesBuildings, facilities Facilities, techs IResearches, refundRatio float64, universeSpeed int64) []TearDownStep {
+	out := make([]TearDownStep, 0)
+	for _, building := range Buildings {
+		id := building.GetID()
+		if id.IsShip() || id == TerraformerID || id == LunarBaseID {
+			continue
+		}
+		level := building.GetLevel(resourcesBuildings, facilities, techs)
+		for lvl := level; lvl > 0; lvl-- {
+			step := TearDownCost(building, lvl, techs, refundRatio, universeSpeed, facilities)
+			if step.Net() > 0 {
+				out = append(out, step)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Net() > out[j].Net() })
+	return out
+}
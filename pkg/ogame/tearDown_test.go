@@ -0,0 +1,37 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTearDownCost(t *testing.T) {
+	step := TearDownCost(MetalMine, 31, Researches{IonTechnology: 17}, 0.5, 1, Facilities{})
+	assert.Equal(t, MetalMineID, step.ID)
+	assert.Equal(t, int64(31), step.Level)
+	assert.Equal(t, Resources{Metal: 3681620, Crystal: 920404}, step.Cost)
+	assert.Equal(t, Resources{Metal: 5752531, Crystal: 1438132}, step.Refund)
+	assert.Equal(t, int64(2588639), step.Net())
+	assert.Equal(t, MetalMine.ConstructionTime(31, 1, Facilities{}, false, false), step.Duration)
+
+	assert.Equal(t, Resources{}, TearDownRefund(MetalMine, 31, 0))
+	assert.True(t, TearDownCost(MetalMine, 31, Researches{}, 0, 1, Facilities{}).Net() < 0)
+}
+
+func TestTearDownPlan(t *testing.T) {
+	buildings := ResourcesBuildings{MetalMine: 2, CrystalMine: 1}
+	facilities := Facilities{Terraformer: 3}
+	assert.Equal(t, 0, len(TearDownPlan(buildings, facilities, Researches{IonTechnology: 10}, 0, 1)))
+
+	plan := TearDownPlan(buildings, facilities, Researches{IonTechnology: 10}, 0.9, 1)
+	assert.Equal(t, 3, len(plan))
+	assert.Equal(t, MetalMineID, plan[0].ID)
+	assert.Equal(t, int64(2), plan[0].Level)
+	for i := 1; i < len(plan); i++ {
+		assert.True(t, plan[i-1].Net() >= plan[i].Net())
+	}
+	for _, step := range plan {
+		assert.NotEqual(t, TerraformerID, step.ID)
+	}
+}
@@ -0,0 +1,47 @@
+package ogame
+
+import "sort"
+
+// TechTreeNode a technology and the tree of its requirements
+type TechTreeNode struct {
+	ID            ID
+	RequiredLevel int64 // level required by the parent node, 0 for the root
+	CurrentLevel  int64
+	Met           bool // CurrentLevel >= RequiredLevel, and all requirements are met
+	Requirements  []TechTreeNode
+}
+
+// NewTechTree builds the requirements tree of id, levels returns the current level of a technology
+func NewTechTree(id ID, levels func(ID) int64) TechTreeNode {
+	return newTechTreeNode(id, 0, levels)
+}
+
+func newTechTreeNode(id ID, requiredLevel int64, levels func(ID) int64) TechTreeNode {
+	node := TechTreeNode{ID: id, RequiredLevel: requiredLevel, CurrentLevel: levels(id)}
+	node.Met = node.CurrentLevel >= requiredLevel
+	if obj := Objs.ByID(id); obj != nil {
+		reqIDs := make([]ID, 0, len(obj.GetRequirements()))
+		for reqID := range obj.GetRequirements() {
+			reqIDs = append(reqIDs, reqID)
+		}
+		sort.Slice(reqIDs, func(i, j int) bool { return reqIDs[i] < reqIDs[j] })
+		for _, reqID := range reqIDs {
+			child := newTechTreeNode(reqID, obj.GetRequirements()[reqID], levels)
+			node.Met = node.Met && child.Met
+			node.Requirements = append(node.Requirements, child)
+		}
+	}
+	return node
+}
+
+// Blocking returns the requirements whose level is too low, deepest first
+func (n TechTreeNode) Blocking() []TechTreeNode {
+	out := make([]TechTreeNode, 0)
+	for _, req := range n.Requirements {
+		out = append(out, req.Blocking()...)
+		if req.CurrentLevel < req.RequiredLevel {
+			out = append(out, req)
+		}
+	}
+	return out
+}
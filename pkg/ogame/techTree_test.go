@@ -0,0 +1,24 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTechTree(t *testing.T) {
+	levels := map[ID]int64{ResearchLabID: 1, EnergyTechnologyID: 1}
+	tree := NewTechTree(ImpulseDriveID, func(id ID) int64 { return levels[id] })
+	assert.Equal(t, ImpulseDriveID, tree.ID)
+	assert.False(t, tree.Met)
+	assert.Len(t, tree.Requirements, 2)
+	blocking := tree.Blocking()
+	assert.Len(t, blocking, 1)
+	assert.Equal(t, ResearchLabID, blocking[0].ID)
+	assert.Equal(t, int64(2), blocking[0].RequiredLevel)
+
+	levels[ResearchLabID] = 2
+	tree = NewTechTree(ImpulseDriveID, func(id ID) int64 { return levels[id] })
+	assert.True(t, tree.Met)
+	assert.Empty(t, tree.Blocking())
+}
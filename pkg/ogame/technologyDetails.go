@@ -8,4 +8,9 @@ type TechnologyDetails struct {
 	Price              Resources
 	Level              int64
 	TearDownEnabled    bool
+	TearDownPrice      Resources     // resources needed to tear down one level
+	TearDownDuration   time.Duration // duration of the tear down
+	EnergyConsumption  int64         // additional energy needed by the next level
+	EnergyProduction   int64         // additional energy produced by the next level
+	RequirementsMet    bool          // either or not the requirements to build the next level are met
 }
@@ -0,0 +1,41 @@
+package ogame
+
+import "math"
+
+// TraderResources infos of the merchant resource exchange (Merchant -> Resources)
+type TraderResources struct {
+	Rates Ratio  // exchange rates offered by the merchant
+	Fee   int64  // dark matter fee charged to trade
+	Token string // token to submit the trade
+}
+
+// TraderResourcesCost returns the amount of the given resource the merchant asks in exchange for want.
+// give must have exactly one resource type, which is not part of want.
+func TraderResourcesCost(give, want Resources, rates Ratio) (int64, error) {
+	if rates.Metal <= 0 || rates.Crystal <= 0 || rates.Deuterium <= 0 {
+		return 0, ErrInvalidTrade
+	}
+	var giveRate float64
+	var giveAmount int64
+	switch {
+	case give.Metal > 0 && give.Crystal == 0 && give.Deuterium == 0 && want.Metal == 0:
+		giveRate, giveAmount = rates.Metal, give.Metal
+	case give.Crystal > 0 && give.Metal == 0 && give.Deuterium == 0 && want.Crystal == 0:
+		giveRate, giveAmount = rates.Crystal, give.Crystal
+	case give.Deuterium > 0 && give.Metal == 0 && give.Crystal == 0 && want.Deuterium == 0:
+		giveRate, giveAmount = rates.Deuterium, give.Deuterium
+	default:
+		return 0, ErrInvalidTrade
+	}
+	if want.Metal < 0 || want.Crystal < 0 || want.Deuterium < 0 || want.Total() == 0 {
+		return 0, ErrInvalidTrade
+	}
+	cost := float64(want.Metal)*giveRate/rates.Metal +
+		float64(want.Crystal)*giveRate/rates.Crystal +
+		float64(want.Deuterium)*giveRate/rates.Deuterium
+	needed := int64(math.Ceil(cost))
+	if needed > giveAmount {
+		return needed, ErrInvalidTrade
+	}
+	return needed, nil
+}
@@ -0,0 +1,23 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraderResourcesCost(t *testing.T) {
+	rates := Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+	cost, err := TraderResourcesCost(Resources{Metal: 6000}, Resources{Crystal: 1000, Deuterium: 1000}, rates)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4500), cost)
+	cost, err = TraderResourcesCost(Resources{Deuterium: 1000}, Resources{Metal: 3000}, rates)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), cost)
+	_, err = TraderResourcesCost(Resources{Deuterium: 999}, Resources{Metal: 3000}, rates)
+	assert.ErrorIs(t, err, ErrInvalidTrade)
+	_, err = TraderResourcesCost(Resources{Metal: 1000, Crystal: 1000}, Resources{Deuterium: 100}, rates)
+	assert.ErrorIs(t, err, ErrInvalidTrade)
+	_, err = TraderResourcesCost(Resources{Metal: 1000}, Resources{Metal: 100}, rates)
+	assert.ErrorIs(t, err, ErrInvalidTrade)
+}
@@ -0,0 +1,55 @@
+package ogame
+
+import (
+	"strconv"
+	"time"
+)
+
+// UnionsTransportMessageType kind of message of the "Unions/Transport" messages tab (23)
+type UnionsTransportMessageType int64
+
+// Unions/Transport tab message types
+const (
+	UnknownUnionsTransportMessage UnionsTransportMessageType = iota
+	TransportArrivalMessage
+	ACSInvitationMessage
+)
+
+func (t UnionsTransportMessageType) String() string {
+	switch t {
+	case UnknownUnionsTransportMessage:
+		return "unknown"
+	case TransportArrivalMessage:
+		return "transport arrival"
+	case ACSInvitationMessage:
+		return "acs invitation"
+	}
+	return "UnionsTransportMessageType(" + strconv.FormatInt(int64(t), 10) + ")"
+}
+
+// UnionsTransportMessage message of the "Unions/Transport" tab. Title and Content are always set,
+// the field matching Type is set when the message could be parsed.
+type UnionsTransportMessage struct {
+	ID            int64
+	Type          UnionsTransportMessageType
+	Title         string
+	Sender        string
+	Content       string // html content
+	CreatedAt     time.Time
+	Transport     *TransportArrival
+	ACSInvitation *ACSInvitation
+}
+
+// TransportArrival fleet that delivered resources
+type TransportArrival struct {
+	Origin      Coordinate
+	Destination Coordinate
+	Resources   Resources
+}
+
+// ACSInvitation invitation to join an alliance combat (union), accept it with JoinACS
+type ACSInvitation struct {
+	UnionID     int64
+	Target      Coordinate
+	ArrivalTime time.Time // zero if the message does not show it
+}
@@ -0,0 +1,7 @@
+package parser
+
+import "github.com/alaingilbert/ogame/pkg/ogame"
+
+func (p ChatPage) ExtractChatContacts() ([]ogame.ChatContact, error) {
+	return p.e.ExtractChatContactsFromDoc(p.GetDoc())
+}
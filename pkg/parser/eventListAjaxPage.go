@@ -5,3 +5,7 @@ import "github.com/alaingilbert/ogame/pkg/ogame"
 func (p EventListAjaxPage) ExtractAttacks(ownCoords []ogame.Coordinate) ([]ogame.AttackEvent, error) {
 	return p.e.ExtractAttacksFromDoc(p.GetDoc(), ownCoords)
 }
+
+func (p EventListAjaxPage) ExtractEventList() ([]ogame.FleetEvent, error) {
+	return p.e.ExtractEventListFromDoc(p.GetDoc())
+}
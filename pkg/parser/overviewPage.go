@@ -31,3 +31,7 @@ func (p OverviewPage) ExtractCancelBuildingInfos() (token string, techID, listID
 func (p OverviewPage) ExtractCancelLfBuildingInfos() (token string, id, listID int64, err error) {
 	return p.e.ExtractCancelLfBuildingInfos(p.content)
 }
+
+func (p OverviewPage) ExtractPlanetRelocation() (ogame.PlanetRelocation, error) {
+	return p.e.ExtractPlanetRelocation(p.content)
+}
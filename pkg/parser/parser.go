@@ -47,6 +47,8 @@ type DefensesPage struct{ FullPage }
 type MovementPage struct{ FullPage }
 type LfBuildingsPage struct{ FullPage }
 type LfResearchPage struct{ FullPage }
+type PremiumPage struct{ FullPage }
+type ChatPage struct{ FullPage }
 
 type FullPagePages interface {
 	OverviewPage |
@@ -62,13 +64,13 @@ type FullPagePages interface {
 		ShipyardPage |
 		DefensesPage |
 		//FleetDispatchPageContent |
-		MovementPage
+		MovementPage |
+		PremiumPage |
+		ChatPage
 	//GalaxyPageContent |
 	//AlliancePageContent |
-	//PremiumPageContent |
 	//ShopPageContent |
 	//MessagesPageContent |
-	//ChatPageContent |
 	//CharacterClassSelectionPageContent |
 	//BuddiesPageContent |
 	//HighScorePageContent
@@ -144,6 +146,14 @@ func ParsePage[T FullPagePages](e extractor.Extractor, pageHTML []byte) (T, erro
 		return T(PreferencesPage{fullPage}), nil
 	case MovementPage:
 		return T(MovementPage{fullPage}), nil
+	case PremiumPage:
+		if bytes.Contains(pageHTML, []byte(`currentPage = "premium";`)) {
+			return T(PremiumPage{fullPage}), nil
+		}
+	case ChatPage:
+		if bytes.Contains(pageHTML, []byte(`currentPage = "chat";`)) {
+			return T(ChatPage{fullPage}), nil
+		}
 	default:
 		return zero, errors.New("page type not implemented")
 	}
@@ -0,0 +1,7 @@
+package parser
+
+import "github.com/alaingilbert/ogame/pkg/ogame"
+
+func (p PremiumPage) ExtractOfficers() ([]ogame.Officer, error) {
+	return p.e.ExtractOfficersFromDoc(p.GetDoc())
+}
@@ -1,5 +1,13 @@
 package parser
 
+import (
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
 func (p RocketlayerAjaxPage) ExtractDestroyRockets() (int64, int64, string, error) {
 	return p.e.ExtractDestroyRockets(p.content)
 }
+
+func (p RocketlayerAjaxPage) ExtractMissiles() (ogame.Missiles, error) {
+	return p.e.ExtractMissiles(p.content)
+}
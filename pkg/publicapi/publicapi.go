@@ -0,0 +1,136 @@
+// Package publicapi is a client for the OGame public xml api (https://s<N>-<lang>.ogame.gameforge.com/api/).
+// It does not require to be logged in, and does not consume any session requests.
+package publicapi
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// Update intervals of the api files
+const (
+	PlayersUpdateInterval    = 24 * time.Hour
+	UniverseUpdateInterval   = 7 * 24 * time.Hour
+	HighscoreUpdateInterval  = time.Hour
+	AlliancesUpdateInterval  = 24 * time.Hour
+	ServerDataUpdateInterval = 24 * time.Hour
+)
+
+// minCacheDuration prevents re-fetching a file too often when gameforge is late updating it
+const minCacheDuration = 5 * time.Minute
+
+type cacheEntry struct {
+	value    any
+	expireAt time.Time
+}
+
+// Client for the public api of one universe. It is safe for concurrent use.
+type Client struct {
+	client  httpclient.IHttpClient
+	clock   clockwork.Clock
+	baseURL string
+	mu      sync.Mutex
+	cache   map[string]cacheEntry
+}
+
+// New creates a new public api client for the universe s<serverNumber>-<lang>
+func New(client httpclient.IHttpClient, serverNumber int64, lang string) *Client {
+	if client == nil {
+		client = httpclient.NewClient()
+	}
+	return &Client{
+		client:  client,
+		clock:   clockwork.NewRealClock(),
+		baseURL: "https://s" + utils.FI64(serverNumber) + "-" + lang + ".ogame.gameforge.com/api/",
+		cache:   make(map[string]cacheEntry),
+	}
+}
+
+// ClearCache removes all cached api results
+func (c *Client) ClearCache() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = make(map[string]cacheEntry)
+}
+
+// GetPlayers gets all players of the universe
+func (c *Client) GetPlayers(ctx context.Context) (Players, error) {
+	return fetch(c, ctx, "players.xml", PlayersUpdateInterval, func(v Players) int64 { return v.Timestamp })
+}
+
+// GetUniverse gets all planets and moons of the universe
+func (c *Client) GetUniverse(ctx context.Context) (Universe, error) {
+	return fetch(c, ctx, "universe.xml", UniverseUpdateInterval, func(v Universe) int64 { return v.Timestamp })
+}
+
+// GetHighscore gets the highscore for a category (1: player, 2: alliance) and type (0: total, 1: economy, 2: research, 3: military...)
+func (c *Client) GetHighscore(ctx context.Context, category, typ int64) (Highscore, error) {
+	path := "highscore.xml?category=" + utils.FI64(category) + "&type=" + utils.FI64(typ)
+	return fetch(c, ctx, path, HighscoreUpdateInterval, func(v Highscore) int64 { return v.Timestamp })
+}
+
+// GetAlliances gets all alliances of the universe
+func (c *Client) GetAlliances(ctx context.Context) (Alliances, error) {
+	return fetch(c, ctx, "alliances.xml", AlliancesUpdateInterval, func(v Alliances) int64 { return v.Timestamp })
+}
+
+// GetServerData gets the server data
+func (c *Client) GetServerData(ctx context.Context) (ServerData, error) {
+	return fetch(c, ctx, "serverData.xml", ServerDataUpdateInterval, func(v ServerData) int64 { return 0 })
+}
+
+// fetch gets an api file, and caches it until the next expected update of the file
+func fetch[T any](c *Client, ctx context.Context, path string, interval time.Duration, timestampFn func(T) int64) (T, error) {
+	c.mu.Lock()
+	entry, found := c.cache[path]
+	c.mu.Unlock()
+	now := c.clock.Now()
+	if found && now.Before(entry.expireAt) {
+		return entry.value.(T), nil
+	}
+
+	var res T
+	fileURL := c.baseURL + path
+	req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+	if err != nil {
+		return res, err
+	}
+	req.Header.Add("Accept-Encoding", "gzip, deflate, br")
+	req = req.WithContext(ctx)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return res, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return res, fmt.Errorf("failed to get %s : %s", fileURL, resp.Status)
+	}
+	by, err := utils.ReadBody(resp)
+	if err != nil {
+		return res, err
+	}
+	if err := xml.Unmarshal(by, &res); err != nil {
+		return res, fmt.Errorf("failed to xml unmarshal %s : %w", fileURL, err)
+	}
+
+	// The file is updated "interval" after it was generated
+	expireAt := now.Add(interval)
+	if ts := timestampFn(res); ts > 0 {
+		expireAt = time.Unix(ts, 0).Add(interval)
+	}
+	if expireAt.Before(now.Add(minCacheDuration)) {
+		expireAt = now.Add(minCacheDuration)
+	}
+	c.mu.Lock()
+	c.cache[path] = cacheEntry{value: res, expireAt: expireAt}
+	c.mu.Unlock()
+	return res, nil
+}
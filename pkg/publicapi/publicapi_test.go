@@ -0,0 +1,96 @@
+package publicapi
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func newTestClient(clock clockwork.Clock, files map[string]string, calls *int) *Client {
+	httpClient := &http.Client{Transport: roundTripFunc(func(req *http.Request) *http.Response {
+		*calls++
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(files[req.URL.Path])),
+			Header:     make(http.Header),
+		}
+	})}
+	c := New(httpClient, 157, "en")
+	c.clock = clock
+	return c
+}
+
+func TestClient_GetPlayers(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Unix(1660000000, 0))
+	calls := 0
+	c := newTestClient(clock, map[string]string{
+		"/api/players.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<players timestamp="1660000000" serverId="en157">
+<player id="100001" name="Bob" status="vI" alliance="500001"/>
+<player id="100002" name="Alice"/>
+</players>`,
+	}, &calls)
+	players, err := c.GetPlayers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(players.Players))
+	assert.Equal(t, int64(500001), players.Players[0].Alliance)
+	assert.True(t, players.Players[0].IsInactive())
+	assert.True(t, players.Players[0].IsVacation())
+	assert.False(t, players.Players[1].IsInactive())
+
+	_, _ = c.GetPlayers(context.Background())
+	assert.Equal(t, 1, calls) // cached
+	clock.Advance(PlayersUpdateInterval)
+	_, _ = c.GetPlayers(context.Background())
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_GetUniverse(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Unix(1660000000, 0))
+	calls := 0
+	c := newTestClient(clock, map[string]string{
+		"/api/universe.xml": `<universe timestamp="1660000000" serverId="en157">
+<planet id="33620000" player="100001" name="Homeworld" coords="1:2:3"><moon id="33630000" name="Moon" size="8888"/></planet>
+<planet id="33620001" player="100001" name="Colony" coords="4:5:6"/>
+</universe>`,
+	}, &calls)
+	universe, err := c.GetUniverse(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(universe.Planets))
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}, universe.Planets[0].Coordinate())
+	assert.Equal(t, int64(8888), universe.Planets[0].Moon.Size)
+	assert.Nil(t, universe.Planets[1].Moon)
+}
+
+func TestClient_GetHighscore(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Unix(1660000000, 0))
+	calls := 0
+	c := newTestClient(clock, map[string]string{
+		"/api/highscore.xml": `<highscore category="1" type="3" timestamp="1660000000" serverId="en157">
+<player position="1" id="100001" score="123456" ships="4321"/>
+</highscore>`,
+	}, &calls)
+	highscore, err := c.GetHighscore(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), highscore.Type)
+	assert.Equal(t, HighscoreEntry{Position: 1, ID: 100001, Score: 123456, Ships: 4321}, highscore.Players[0])
+
+	// Late update from gameforge should not make us hammer the api
+	clock.Advance(2 * HighscoreUpdateInterval)
+	_, _ = c.GetHighscore(context.Background(), 1, 3)
+	_, _ = c.GetHighscore(context.Background(), 1, 3)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,185 @@
+package publicapi
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// ServerData represent api result from https://s157-ru.ogame.gameforge.com/api/serverData.xml
+type ServerData struct {
+	Name                          string  `xml:"name"`                          // Europa
+	Number                        int64   `xml:"number"`                        // 157
+	Language                      string  `xml:"language"`                      // ru
+	Timezone                      string  `xml:"timezone"`                      // Europe/Moscow
+	TimezoneOffset                string  `xml:"timezoneOffset"`                // +03:00
+	Domain                        string  `xml:"domain"`                        // s157-ru.ogame.gameforge.com
+	Version                       string  `xml:"version"`                       // 6.8.8-pl2
+	Speed                         int64   `xml:"speed"`                         // 6
+	SpeedFleetPeaceful            int64   `xml:"speedFleetPeaceful"`            // 1
+	SpeedFleetWar                 int64   `xml:"speedFleetWar"`                 // 1
+	SpeedFleetHolding             int64   `xml:"speedFleetHolding"`             // 1
+	Galaxies                      int64   `xml:"galaxies"`                      // 4
+	Systems                       int64   `xml:"systems"`                       // 499
+	ACS                           bool    `xml:"acs"`                           // 1
+	RapidFire                     bool    `xml:"rapidFire"`                     // 1
+	DefToTF                       bool    `xml:"defToTF"`                       // 0
+	DebrisFactor                  float64 `xml:"debrisFactor"`                  // 0.5
+	DebrisFactorDef               float64 `xml:"debrisFactorDef"`               // 0
+	RepairFactor                  float64 `xml:"repairFactor"`                  // 0.7
+	NewbieProtectionLimit         int64   `xml:"newbieProtectionLimit"`         // 500000
+	NewbieProtectionHigh          int64   `xml:"newbieProtectionHigh"`          // 50000
+	TopScore                      float64 `xml:"topScore"`                      // 60259362 / 1.0363090034999E+17
+	BonusFields                   int64   `xml:"bonusFields"`                   // 30
+	DonutGalaxy                   bool    `xml:"donutGalaxy"`                   // 1
+	DonutSystem                   bool    `xml:"donutSystem"`                   // 1
+	WfEnabled                     bool    `xml:"wfEnabled"`                     // 1 (WreckField)
+	WfMinimumRessLost             int64   `xml:"wfMinimumRessLost"`             // 150000
+	WfMinimumLossPercentage       int64   `xml:"wfMinimumLossPercentage"`       // 5
+	WfBasicPercentageRepairable   int64   `xml:"wfBasicPercentageRepairable"`   // 45
+	GlobalDeuteriumSaveFactor     float64 `xml:"globalDeuteriumSaveFactor"`     // 0.5
+	Bashlimit                     int64   `xml:"bashlimit"`                     // 0
+	ProbeCargo                    int64   `xml:"probeCargo"`                    // 5
+	ResearchDurationDivisor       int64   `xml:"researchDurationDivisor"`       // 2
+	DarkMatterNewAcount           int64   `xml:"darkMatterNewAcount"`           // 8000
+	CargoHyperspaceTechMultiplier int64   `xml:"cargoHyperspaceTechMultiplier"` // 5
+	SpeedFleet                    int64   `xml:"speedFleet"`                    // 6 // Deprecated in 8.1.0
+
+	MarketplaceEnabled                       *bool   `xml:"marketplaceEnabled"`                       // 1, nil if the server data does not have the setting
+	MarketplaceBasicTradeRatioMetal          float64 `xml:"marketplaceBasicTradeRatioMetal"`          // 2.5
+	MarketplaceBasicTradeRatioCrystal        float64 `xml:"marketplaceBasicTradeRatioCrystal"`        // 1.5
+	MarketplaceBasicTradeRatioDeuterium      float64 `xml:"marketplaceBasicTradeRatioDeuterium"`      // 1
+	MarketplacePriceRangeLower               float64 `xml:"marketplacePriceRangeLower"`               // 0.3
+	MarketplacePriceRangeUpper               float64 `xml:"marketplacePriceRangeUpper"`               // 2
+	MarketplaceTaxNormalUser                 float64 `xml:"marketplaceTaxNormalUser"`                 // 0.1
+	MarketplaceTaxAdmiral                    float64 `xml:"marketplaceTaxAdmiral"`                    // 0.05
+	MarketplaceTaxCancelOffer                float64 `xml:"marketplaceTaxCancelOffer"`                // 0.3
+	MarketplaceTaxNotSold                    float64 `xml:"marketplaceTaxNotSold"`                    // 0.3
+	MarketplaceOfferTimeout                  int64   `xml:"marketplaceOfferTimeout"`                  // 3
+	CharacterClassesEnabled                  bool    `xml:"characterClassesEnabled"`                  // 1
+	CombatDebrisFieldLimit                   float64 `xml:"combatDebrisFieldLimit"`                   // 0.25
+	ResourceProductionIncreaseCrystalDefault int64   `xml:"resourceProductionIncreaseCrystalDefault"` // 10
+	ResourceProductionIncreaseCrystalPos1    int64   `xml:"resourceProductionIncreaseCrystalPos1"`    // 40
+	ResourceProductionIncreaseCrystalPos2    int64   `xml:"resourceProductionIncreaseCrystalPos2"`    // 30
+	ResourceProductionIncreaseCrystalPos3    int64   `xml:"resourceProductionIncreaseCrystalPos3"`    // 20
+	FleetIgnoreEmptySystems                  bool    `xml:"fleetIgnoreEmptySystems"`                  // 1
+	FleetIgnoreInactiveSystems               bool    `xml:"fleetIgnoreInactiveSystems"`               // 1
+}
+
+// IsMarketplaceEnabled returns either or not the marketplace is available, servers data without the setting are
+// assumed to have it
+func (d ServerData) IsMarketplaceEnabled() bool {
+	return d.MarketplaceEnabled == nil || *d.MarketplaceEnabled
+}
+
+// Players represent api result from https://s157-ru.ogame.gameforge.com/api/players.xml
+type Players struct {
+	XMLName   xml.Name `xml:"players"`
+	Timestamp int64    `xml:"timestamp,attr"`
+	ServerID  string   `xml:"serverId,attr"`
+	Players   []Player `xml:"player"`
+}
+
+// Player a player from players.xml
+type Player struct {
+	ID       int64  `xml:"id,attr"`
+	Name     string `xml:"name,attr"`
+	Status   string `xml:"status,attr"` // a: admin, v: vacation, I: inactive 28d, i: inactive 7d, b: banned, o: outlaw
+	Alliance int64  `xml:"alliance,attr"`
+}
+
+// IsInactive returns either or not the player is inactive (7 or 28 days)
+func (p Player) IsInactive() bool {
+	return strings.ContainsAny(p.Status, "iI")
+}
+
+// IsVacation returns either or not the player is in vacation mode
+func (p Player) IsVacation() bool {
+	return strings.Contains(p.Status, "v")
+}
+
+// Universe represent api result from https://s157-ru.ogame.gameforge.com/api/universe.xml
+type Universe struct {
+	XMLName   xml.Name `xml:"universe"`
+	Timestamp int64    `xml:"timestamp,attr"`
+	ServerID  string   `xml:"serverId,attr"`
+	Planets   []Planet `xml:"planet"`
+}
+
+// Planet a planet from universe.xml
+type Planet struct {
+	ID     int64  `xml:"id,attr"`
+	Player int64  `xml:"player,attr"`
+	Name   string `xml:"name,attr"`
+	Coords string `xml:"coords,attr"` // 1:2:3
+	Moon   *Moon  `xml:"moon"`
+}
+
+// Coordinate returns the planet coordinate
+func (p Planet) Coordinate() ogame.Coordinate {
+	return parseCoords(p.Coords, ogame.PlanetType)
+}
+
+// Moon a moon from universe.xml
+type Moon struct {
+	ID   int64  `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+	Size int64  `xml:"size,attr"`
+}
+
+// Highscore represent api result from https://s157-ru.ogame.gameforge.com/api/highscore.xml?category=1&type=0
+type Highscore struct {
+	XMLName   xml.Name         `xml:"highscore"`
+	Category  int64            `xml:"category,attr"` // 1: player, 2: alliance
+	Type      int64            `xml:"type,attr"`     // 0: total, 1: economy, 2: research, 3: military, ...
+	Timestamp int64            `xml:"timestamp,attr"`
+	ServerID  string           `xml:"serverId,attr"`
+	Players   []HighscoreEntry `xml:"player"`
+	Alliances []HighscoreEntry `xml:"alliance"`
+}
+
+// HighscoreEntry a player or alliance position from highscore.xml
+type HighscoreEntry struct {
+	Position int64 `xml:"position,attr"`
+	ID       int64 `xml:"id,attr"`
+	Score    int64 `xml:"score,attr"`
+	Ships    int64 `xml:"ships,attr"` // only for players military highscore
+}
+
+// Alliances represent api result from https://s157-ru.ogame.gameforge.com/api/alliances.xml
+type Alliances struct {
+	XMLName   xml.Name   `xml:"alliances"`
+	Timestamp int64      `xml:"timestamp,attr"`
+	ServerID  string     `xml:"serverId,attr"`
+	Alliances []Alliance `xml:"alliance"`
+}
+
+// Alliance an alliance from alliances.xml
+type Alliance struct {
+	ID        int64  `xml:"id,attr"`
+	Name      string `xml:"name,attr"`
+	Tag       string `xml:"tag,attr"`
+	Founder   int64  `xml:"founder,attr"`
+	FoundDate int64  `xml:"foundDate,attr"`
+	Logo      string `xml:"logo,attr"`
+	Homepage  string `xml:"homepage,attr"`
+	Open      bool   `xml:"open,attr"`
+	Members   []struct {
+		ID int64 `xml:"id,attr"`
+	} `xml:"player"`
+}
+
+func parseCoords(coords string, celestialType ogame.CelestialType) ogame.Coordinate {
+	parts := strings.Split(coords, ":")
+	if len(parts) != 3 {
+		return ogame.Coordinate{}
+	}
+	return ogame.Coordinate{
+		Galaxy:   utils.DoParseI64(parts[0]),
+		System:   utils.DoParseI64(parts[1]),
+		Position: utils.DoParseI64(parts[2]),
+		Type:     celestialType,
+	}
+}
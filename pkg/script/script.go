@@ -0,0 +1,604 @@
+// Package script runs Lua scripts controlling the bot.
+//
+// Every method of the Prioritizable API is exposed in the global "ogame" table.
+// Methods returning an error return it as a last string value (nil on success).
+//
+//	local planets = ogame.GetCachedPlanets()
+//	for _, planet in ipairs(planets) do
+//		local res, err = ogame.GetResources(planet.ID)
+//		if err == nil then print(planet.Name, res.Metal) end
+//	end
+//	on("chat", function(msg) print(msg.SenderName, msg.Content) end)
+//
+// Scripts are sandboxed, only the base (without file access), table, string and math libraries are available.
+// Quotas (time, call depth, memory) can be set with NewWithLimits, and a script can be reloaded from disk without losing its globals with Watch.
+package script
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/wrapper"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ErrMemoryLimitExceeded returned when a script is interrupted because it allocated more than Limits.MaxMemory
+var ErrMemoryLimitExceeded = errors.New("script memory limit exceeded")
+
+// memoryCheckInterval how often the heap is checked while a script runs with a memory limit
+const memoryCheckInterval = 10 * time.Millisecond
+
+// Limits quotas applied to the scripts of an engine, zero values mean no limit
+type Limits struct {
+	Timeout       time.Duration // maximum duration of a script run or event handler call
+	CallStackSize int           // maximum depth of nested calls
+	MaxRegistry   int           // maximum number of slots of the Lua value stack, does not bound tables and strings
+	// MaxMemory maximum growth of the heap in bytes during a script run or event handler call. The heap is shared
+	// with the rest of the process and sampled periodically, so the limit is approximate
+	MaxMemory uint64
+}
+
+// Engine a sandboxed Lua state bound to a bot
+type Engine struct {
+	b              wrapper.Wrapper
+	L              *lua.LState
+	limits         Limits
+	ctx            context.Context
+	cancel         context.CancelFunc
+	mu             sync.Mutex
+	handlers       map[string][]*lua.LFunction
+	errorCallbacks []func(error)
+	chatSub        *wrapper.Subscription
+}
+
+// New creates an engine exposing the bot to the scripts
+func New(b wrapper.Wrapper) *Engine {
+	return NewWithLimits(b, Limits{})
+}
+
+// NewWithLimits creates an engine exposing the bot to the scripts, with quotas
+func NewWithLimits(b wrapper.Wrapper, limits Limits) *Engine {
+	e := &Engine{b: b, limits: limits, handlers: make(map[string][]*lua.LFunction)}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	e.L = newSandbox(limits)
+	e.L.SetGlobal("ogame", e.apiTable())
+	e.L.SetGlobal("on", e.L.NewFunction(e.luaOn))
+	e.L.SetGlobal("coord", e.L.NewFunction(luaCoord))
+	e.L.SetGlobal("celestials", e.L.NewFunction(e.luaCelestials))
+	e.chatSub = b.RegisterChatCallback(func(msg ogame.ChatMsg) {
+		if err := e.Emit("chat", msg); err != nil {
+			e.emitError(err)
+		}
+	})
+	return e
+}
+
+// OnError registers a callback executed when an event handler or a reload fails
+func (e *Engine) OnError(clb func(error)) *Engine {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errorCallbacks = append(e.errorCallbacks, clb)
+	return e
+}
+
+// DoString runs a script
+func (e *Engine) DoString(src string) error {
+	return e.DoStringContext(e.ctx, src)
+}
+
+// DoStringContext runs a script, the script is interrupted when ctx is done
+func (e *Engine) DoStringContext(ctx context.Context, src string) error {
+	return e.run(ctx, func() error { return e.L.DoString(src) })
+}
+
+// DoFile runs a script file
+func (e *Engine) DoFile(path string) error {
+	return e.run(e.ctx, func() error { return e.L.DoFile(path) })
+}
+
+// Emit calls the handlers registered by the scripts for the event with the payload converted to a Lua value
+func (e *Engine) Emit(event string, payload any) error {
+	return e.run(e.ctx, func() error {
+		for _, fn := range e.handlers[event] {
+			if err := e.L.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, toLua(e.L, reflect.ValueOf(payload))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Reload runs the script file again in the same Lua state. Globals are preserved,
+// the event handlers are replaced by the ones registered by the new version of the script.
+// The "reload" event is emitted once the script ran.
+func (e *Engine) Reload(path string) error {
+	err := e.run(e.ctx, func() error {
+		handlers := e.handlers
+		e.handlers = make(map[string][]*lua.LFunction)
+		if err := e.L.DoFile(path); err != nil {
+			e.handlers = handlers // keep the previous version running
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return e.Emit("reload", path)
+}
+
+// Watch reloads the script file every time it is modified on disk, until the engine is closed
+func (e *Engine) Watch(path string, interval time.Duration) {
+	stat, _ := os.Stat(path)
+	var modTime time.Time
+	if stat != nil {
+		modTime = stat.ModTime()
+	}
+	go func() {
+		for {
+			select {
+			case <-time.After(interval):
+			case <-e.ctx.Done():
+				return
+			}
+			stat, err := os.Stat(path)
+			if err != nil || !stat.ModTime().After(modTime) {
+				continue
+			}
+			modTime = stat.ModTime()
+			if err := e.Reload(path); err != nil {
+				e.emitError(err)
+			}
+		}
+	}()
+}
+
+// Close interrupts the running scripts and closes the Lua state
+func (e *Engine) Close() {
+	e.cancel()
+	e.chatSub.Close()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.L.Close()
+}
+
+// run executes fn with the Lua state bound to ctx and the timeout quota
+func (e *Engine) run(ctx context.Context, fn func() error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ctx.Err() != nil {
+		return errors.New("engine closed")
+	}
+	if e.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.limits.Timeout)
+		defer cancel()
+	}
+	var memoryExceeded int32
+	if e.limits.MaxMemory > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		go watchMemory(ctx, e.limits.MaxMemory, func() {
+			atomic.StoreInt32(&memoryExceeded, 1)
+			cancel()
+		})
+	}
+	e.L.SetContext(ctx)
+	defer e.L.RemoveContext()
+	err := fn()
+	if err != nil && atomic.LoadInt32(&memoryExceeded) == 1 {
+		return ErrMemoryLimitExceeded
+	}
+	return err
+}
+
+// watchMemory calls exceeded once the heap grew by more than limit bytes, until ctx is done
+func watchMemory(ctx context.Context, limit uint64, exceeded func()) {
+	baseline := heapObjectsBytes()
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if heap := heapObjectsBytes(); heap > baseline && heap-baseline > limit {
+			exceeded()
+			return
+		}
+	}
+}
+
+// heapObjectsBytes returns the memory used by the heap objects, live and not yet collected
+func heapObjectsBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+func (e *Engine) emitError(err error) {
+	e.mu.Lock()
+	callbacks := e.errorCallbacks
+	e.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(err)
+	}
+}
+
+// newSandbox creates a Lua state without the libraries giving access to the file system or the os
+func newSandbox(limits Limits) *lua.LState {
+	opts := lua.Options{SkipOpenLibs: true, CallStackSize: limits.CallStackSize}
+	if limits.MaxRegistry > 0 {
+		opts.RegistrySize = limits.MaxRegistry // fixed size, gopher-lua uses its default size below 128
+		opts.RegistryMaxSize = limits.MaxRegistry
+	}
+	L := lua.NewState(opts)
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "loadfile", "load", "loadstring", "module", "require"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+	return L
+}
+
+var (
+	prioritizableType = reflect.TypeOf((*wrapper.Prioritizable)(nil)).Elem()
+	optionType        = reflect.TypeOf(wrapper.Option(nil))
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	coordinateType    = reflect.TypeOf(ogame.Coordinate{})
+)
+
+// apiTable returns a table with a function for every method of the Prioritizable interface that can be called from Lua
+func (e *Engine) apiTable() *lua.LTable {
+	tbl := e.L.NewTable()
+	bot := reflect.ValueOf(e.b)
+	for i := 0; i < prioritizableType.NumMethod(); i++ {
+		method := prioritizableType.Method(i)
+		if !isScriptable(method.Type) {
+			continue
+		}
+		fn := bot.MethodByName(method.Name)
+		tbl.RawSetString(method.Name, e.L.NewFunction(func(L *lua.LState) int {
+			return callMethod(L, fn)
+		}))
+	}
+	return tbl
+}
+
+// isScriptable returns either or not the arguments and return values of a method can be converted from/to Lua
+func isScriptable(t reflect.Type) bool {
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if t.IsVariadic() && i == t.NumIn()-1 && in.Elem() == optionType {
+			continue
+		}
+		if !isConvertible(in) {
+			return false
+		}
+	}
+	for i := 0; i < t.NumOut(); i++ {
+		if out := t.Out(i); out != errorType && !isConvertible(out) {
+			return false
+		}
+	}
+	return true
+}
+
+func isConvertible(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	case reflect.Interface:
+		return t.NumMethod() == 0 || t.Implements(reflect.TypeOf((*wrapper.Celestial)(nil)).Elem())
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return isConvertible(t.Elem())
+	case reflect.Map:
+		return isConvertible(t.Key()) && isConvertible(t.Elem())
+	}
+	return true
+}
+
+// callMethod calls a bot method with the Lua arguments, options are never passed
+func callMethod(L *lua.LState, fn reflect.Value) int {
+	t := fn.Type()
+	nbIn := t.NumIn()
+	if t.IsVariadic() {
+		nbIn--
+	}
+	args := make([]reflect.Value, 0, nbIn)
+	for i := 0; i < nbIn; i++ {
+		arg, err := fromLua(L.Get(i+1), t.In(i))
+		if err != nil {
+			L.ArgError(i+1, err.Error())
+			return 0
+		}
+		args = append(args, arg)
+	}
+	var results []reflect.Value
+	if t.IsVariadic() {
+		results = fn.CallSlice(append(args, reflect.MakeSlice(t.In(t.NumIn()-1), 0, 0)))
+	} else {
+		results = fn.Call(args)
+	}
+	for i, res := range results {
+		if t.Out(i) == errorType {
+			if res.IsNil() {
+				L.Push(lua.LNil)
+			} else {
+				L.Push(lua.LString(res.Interface().(error).Error()))
+			}
+			continue
+		}
+		L.Push(toLua(L, res))
+	}
+	return len(results)
+}
+
+// toLua converts a go value to a Lua value. Structs become tables keyed by field name (embedded structs are flattened),
+// times become unix timestamps and durations become seconds.
+func toLua(L *lua.LState, v reflect.Value) lua.LValue {
+	if !v.IsValid() {
+		return lua.LNil
+	}
+	if v.Type() == timeType {
+		return lua.LNumber(v.Interface().(time.Time).Unix())
+	}
+	if v.Type() == durationType {
+		return lua.LNumber(v.Interface().(time.Duration).Seconds())
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return lua.LBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return lua.LNumber(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return lua.LNumber(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return lua.LNumber(v.Float())
+	case reflect.String:
+		return lua.LString(v.String())
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return lua.LNil
+		}
+		return toLua(L, v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return lua.LNil
+		}
+		tbl := L.NewTable()
+		for i := 0; i < v.Len(); i++ {
+			tbl.Append(toLua(L, v.Index(i)))
+		}
+		return tbl
+	case reflect.Map:
+		if v.IsNil() {
+			return lua.LNil
+		}
+		tbl := L.NewTable()
+		for _, k := range v.MapKeys() {
+			tbl.RawSet(toLua(L, k), toLua(L, v.MapIndex(k)))
+		}
+		return tbl
+	case reflect.Struct:
+		tbl := L.NewTable()
+		setStructFields(L, tbl, v)
+		return tbl
+	}
+	return lua.LNil
+}
+
+func setStructFields(L *lua.LState, tbl *lua.LTable, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			setStructFields(L, tbl, v.Field(i))
+			continue
+		}
+		if field.PkgPath != "" || !isConvertible(field.Type) {
+			continue
+		}
+		tbl.RawSetString(field.Name, toLua(L, v.Field(i)))
+	}
+}
+
+// fromLua converts a Lua value to a go value of type t
+func fromLua(lv lua.LValue, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	if lv == lua.LNil {
+		return out, nil
+	}
+	switch {
+	case t == timeType:
+		n, ok := lv.(lua.LNumber)
+		if !ok {
+			return out, errors.New("number expected")
+		}
+		return reflect.ValueOf(time.Unix(int64(n), 0)), nil
+	case t == durationType:
+		n, ok := lv.(lua.LNumber)
+		if !ok {
+			return out, errors.New("number expected")
+		}
+		return reflect.ValueOf(time.Duration(float64(n) * float64(time.Second))), nil
+	case t == coordinateType:
+		if s, ok := lv.(lua.LString); ok {
+			coord, err := ogame.ParseCoord(string(s))
+			return reflect.ValueOf(coord), err
+		}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		out.SetBool(lua.LVAsBool(lv))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := lv.(lua.LNumber)
+		if !ok {
+			return out, errors.New("number expected")
+		}
+		out.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := lv.(lua.LNumber)
+		if !ok {
+			return out, errors.New("number expected")
+		}
+		out.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, ok := lv.(lua.LNumber)
+		if !ok {
+			return out, errors.New("number expected")
+		}
+		out.SetFloat(float64(n))
+	case reflect.String:
+		out.SetString(lv.String())
+	case reflect.Ptr:
+		elem, err := fromLua(lv, t.Elem())
+		if err != nil {
+			return out, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return out, errors.New("unsupported type " + t.String())
+		}
+		switch v := lv.(type) {
+		case lua.LNumber:
+			out.Set(reflect.ValueOf(int64(v)))
+		case lua.LString:
+			out.Set(reflect.ValueOf(string(v)))
+		case lua.LBool:
+			out.Set(reflect.ValueOf(bool(v)))
+		default:
+			return out, errors.New("number, string or boolean expected")
+		}
+	case reflect.Slice:
+		tbl, ok := lv.(*lua.LTable)
+		if !ok {
+			return out, errors.New("table expected")
+		}
+		out = reflect.MakeSlice(t, 0, tbl.Len())
+		for i := 1; i <= tbl.Len(); i++ {
+			elem, err := fromLua(tbl.RawGetInt(i), t.Elem())
+			if err != nil {
+				return out, err
+			}
+			out = reflect.Append(out, elem)
+		}
+	case reflect.Map:
+		tbl, ok := lv.(*lua.LTable)
+		if !ok {
+			return out, errors.New("table expected")
+		}
+		out = reflect.MakeMap(t)
+		var err error
+		tbl.ForEach(func(k, v lua.LValue) {
+			key, kErr := fromLua(k, t.Key())
+			val, vErr := fromLua(v, t.Elem())
+			if kErr != nil || vErr != nil {
+				err = errors.New("invalid map entry")
+				return
+			}
+			out.SetMapIndex(key, val)
+		})
+		return out, err
+	case reflect.Struct:
+		tbl, ok := lv.(*lua.LTable)
+		if !ok {
+			return out, errors.New("table expected")
+		}
+		if err := getStructFields(tbl, out); err != nil {
+			return out, err
+		}
+	default:
+		return out, errors.New("unsupported type " + t.String())
+	}
+	return out, nil
+}
+
+func getStructFields(tbl *lua.LTable, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := getStructFields(tbl, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		lv := tbl.RawGetString(field.Name)
+		if lv == lua.LNil {
+			continue
+		}
+		val, err := fromLua(lv, field.Type)
+		if err != nil {
+			return errors.New(field.Name + ": " + err.Error())
+		}
+		v.Field(i).Set(val)
+	}
+	return nil
+}
+
+// luaOn registers a handler for an event, eg: on("chat", function(msg) end)
+func (e *Engine) luaOn(L *lua.LState) int {
+	event := L.CheckString(1)
+	fn := L.CheckFunction(2)
+	e.handlers[event] = append(e.handlers[event], fn)
+	return 0
+}
+
+// luaCoord creates a coordinate table, eg: coord(1, 2, 3) or coord("1:2:3"), type defaults to planet
+func luaCoord(L *lua.LState) int {
+	var coord ogame.Coordinate
+	if s, ok := L.Get(1).(lua.LString); ok {
+		var err error
+		if coord, err = ogame.ParseCoord(string(s)); err != nil {
+			L.ArgError(1, err.Error())
+			return 0
+		}
+	} else {
+		celestialType := ogame.CelestialType(L.OptInt64(4, int64(ogame.PlanetType)))
+		coord = ogame.Coordinate{Galaxy: L.CheckInt64(1), System: L.CheckInt64(2), Position: L.CheckInt64(3), Type: celestialType}
+	}
+	L.Push(toLua(L, reflect.ValueOf(coord)))
+	return 1
+}
+
+// luaCelestials returns the cached planets and moons
+func (e *Engine) luaCelestials(L *lua.LState) int {
+	tbl := L.NewTable()
+	for _, celestial := range e.b.GetCachedCelestials() {
+		tbl.Append(toLua(L, reflect.ValueOf(celestial)))
+	}
+	L.Push(tbl)
+	return 1
+}
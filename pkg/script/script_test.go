@@ -0,0 +1,89 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/wrapper"
+	"github.com/stretchr/testify/assert"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestSandbox(t *testing.T) {
+	e := New(&wrapper.OGame{})
+	defer e.Close()
+	assert.NoError(t, e.DoString(`assert(os == nil and io == nil and dofile == nil and require == nil)`))
+	assert.NoError(t, e.DoString(`assert(type(ogame.GetResources) == "function")`))
+	assert.NoError(t, e.DoString(`assert(ogame.Begin == nil)`))
+}
+
+func TestCoordAndEvents(t *testing.T) {
+	e := New(&wrapper.OGame{})
+	defer e.Close()
+	assert.NoError(t, e.DoString(`
+		received = nil
+		on("test", function(payload) received = payload end)
+		local c = coord("1:2:3")
+		assert(c.Galaxy == 1 and c.System == 2 and c.Position == 3 and c.Type == 1)
+	`))
+	assert.NoError(t, e.Emit("test", ogame.ChatMsg{SenderName: "Bob"}))
+	assert.NoError(t, e.DoString(`assert(received.SenderName == "Bob")`))
+}
+
+func TestLuaConversions(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+	ships := ogame.ShipsInfos{LightFighter: 3, SmallCargo: 5}
+	lv := toLua(L, reflect.ValueOf(ships))
+	back, err := fromLua(lv, reflect.TypeOf(ogame.ShipsInfos{}))
+	assert.NoError(t, err)
+	assert.Equal(t, ships, back.Interface())
+
+	coord, err := fromLua(lua.LString("4:5:6"), reflect.TypeOf(ogame.Coordinate{}))
+	assert.NoError(t, err)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 5, Position: 6, Type: ogame.PlanetType}, coord.Interface())
+
+	ids, err := fromLua(toLua(L, reflect.ValueOf([]ogame.CelestialID{1, 2})), reflect.TypeOf([]ogame.CelestialID{}))
+	assert.NoError(t, err)
+	assert.Equal(t, []ogame.CelestialID{1, 2}, ids.Interface())
+
+	_, err = fromLua(lua.LString("abc"), reflect.TypeOf(int64(0)))
+	assert.Error(t, err)
+}
+
+func TestTimeoutLimit(t *testing.T) {
+	e := NewWithLimits(&wrapper.OGame{}, Limits{Timeout: 50 * time.Millisecond})
+	defer e.Close()
+	err := e.DoString(`while true do end`)
+	assert.Error(t, err)
+	assert.NoError(t, e.DoString(`x = 1`))
+}
+
+func TestMemoryLimit(t *testing.T) {
+	e := NewWithLimits(&wrapper.OGame{}, Limits{Timeout: 30 * time.Second, MaxMemory: 64 << 20})
+	defer e.Close()
+	start := time.Now()
+	err := e.DoString(`local t = {} while true do t[#t+1] = string.rep("x", 1024) .. #t end`)
+	assert.Equal(t, ErrMemoryLimitExceeded, err)
+	assert.Less(t, time.Since(start), 30*time.Second) // stopped by the memory limit, not the timeout
+	assert.NoError(t, e.DoString(`x = 1`))
+}
+
+func TestReloadKeepsGlobals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.lua")
+	e := New(&wrapper.OGame{})
+	defer e.Close()
+	assert.NoError(t, os.WriteFile(path, []byte(`counter = (counter or 0) + 1; on("tick", function() version = 1 end)`), 0644))
+	assert.NoError(t, e.DoFile(path))
+	assert.NoError(t, os.WriteFile(path, []byte(`counter = (counter or 0) + 1; on("tick", function() version = 2 end)`), 0644))
+	assert.NoError(t, e.Reload(path))
+	assert.NoError(t, e.Emit("tick", nil))
+	assert.NoError(t, e.DoString(`assert(counter == 2 and version == 2)`))
+	assert.NoError(t, os.WriteFile(path, []byte(`syntax error`), 0644))
+	assert.Error(t, e.Reload(path))
+	assert.NoError(t, e.Emit("tick", nil))
+}
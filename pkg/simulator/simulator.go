@@ -1279,6 +1279,28 @@ type Defender struct {
 	ogame.DefensesInfos
 }
 
+// DefenderFromReport returns the Defender described by an espionage report.
+// The sections the report does not include are left empty, ok is false when the fleet, defenses or researches are unknown.
+func DefenderFromReport(report ogame.EspionageReport) (defender Defender, ok bool) {
+	infos := report.Infos()
+	defender.Metal = int(infos.Resources.Metal)
+	defender.Crystal = int(infos.Resources.Crystal)
+	defender.Deuterium = int(infos.Resources.Deuterium)
+	if infos.Researches != nil {
+		defender.Weapon = int(infos.Researches.WeaponsTechnology)
+		defender.Shield = int(infos.Researches.ShieldingTechnology)
+		defender.Armour = int(infos.Researches.ArmourTechnology)
+	}
+	if infos.ShipsInfos != nil {
+		defender.ShipsInfos = *infos.ShipsInfos
+	}
+	if infos.DefensesInfos != nil {
+		defender.DefensesInfos = *infos.DefensesInfos
+	}
+	ok = infos.Researches != nil && infos.ShipsInfos != nil && infos.DefensesInfos != nil
+	return defender, ok
+}
+
 // SimulatorParams ...
 type SimulatorParams struct {
 	Simulations   int
@@ -5,7 +5,7 @@ import "container/heap"
 type IPQItem interface {
 	GetIndex() int
 	SetIndex(idx int)
-	GetPriority() int
+	GetPriority() float64
 }
 
 type PriorityQueue[T IPQItem] struct {
@@ -23,6 +23,10 @@ func (p *PriorityQueue[T]) Push(item T) { heap.Push(&p.items, item) }
 func (p *PriorityQueue[T]) Pop() T      { return heap.Pop(&p.items).(T) }
 func (p *PriorityQueue[T]) Len() int    { return p.items.Len() }
 func (p *PriorityQueue[T]) Items() []T  { return p.items }
+func (p *PriorityQueue[T]) Init()       { heap.Init(&p.items) }
+func (p *PriorityQueue[T]) Remove(i int) T {
+	return heap.Remove(&p.items, i).(T)
+}
 
 // A priorityQueue implements heap.Interface and holds Items.
 type priorityQueue[T IPQItem] []T
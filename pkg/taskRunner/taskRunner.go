@@ -2,9 +2,16 @@ package taskRunner
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 )
 
+// ErrTaskNotFound returned when cancelling a task that is neither queued nor running
+var ErrTaskNotFound = errors.New("task not found")
+
 type Priority int64
 
 // Priorities
@@ -15,17 +22,47 @@ const (
 	Critical
 )
 
+var (
+	priorityNamesLock sync.RWMutex
+	priorityNames     = map[Priority]string{Low: "Low", Normal: "Normal", Important: "Important", Critical: "Critical"}
+)
+
+// NewPriority defines a custom priority level, eg: NewPriority("Scanner", Low-1) or NewPriority("Urgent", Normal+10).
+// Tasks with a higher value are processed first.
+func NewPriority(name string, value int64) Priority {
+	priorityNamesLock.Lock()
+	defer priorityNamesLock.Unlock()
+	priorityNames[Priority(value)] = name
+	return Priority(value)
+}
+
+func (p Priority) String() string {
+	priorityNamesLock.RLock()
+	defer priorityNamesLock.RUnlock()
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return strconv.FormatInt(int64(p), 10)
+}
+
 // item ...
 type item struct {
 	canBeProcessedCh chan struct{}
 	isDoneCh         chan struct{}
 	priority         Priority
 	index            int // The index of the item in the heap.
+	id               int64
+	score            float64 // priority, plus the aging bonus when aging is enabled
+	name             string
+	enqueuedAt       time.Time
+	startedAt        time.Time
+	ctx              context.Context
+	cancel           context.CancelFunc
 }
 
-func (i *item) GetPriority() int { return int(i.priority) }
-func (i *item) GetIndex() int    { return i.index }
-func (i *item) SetIndex(idx int) { i.index = idx }
+func (i *item) GetPriority() float64 { return i.score }
+func (i *item) GetIndex() int        { return i.index }
+func (i *item) SetIndex(idx int)     { i.index = idx }
 
 // TaskRunner ...
 //
@@ -48,12 +85,52 @@ type TaskRunner[T ITask] struct {
 	tasksPopCh  chan struct{}
 	factory     func() T
 	ctx         context.Context
+	lastID      int64
+	queued      map[int64]*item // tasks pushed and not popped yet, including the ones still in tasksPushCh
+	current     *item
+	idleChs     []chan struct{}
+	aging       time.Duration
 }
 
 type ITask interface {
 	SetTaskDoneCh(ch chan struct{})
 }
 
+// ITaskWithHandle tasks implementing it receive a handle to name themselves and watch for cancellation
+type ITaskWithHandle interface {
+	SetTaskHandle(h *TaskHandle)
+}
+
+// TaskHandle gives a task access to its own information
+type TaskHandle struct {
+	item *item
+	lock *sync.Mutex
+}
+
+// ID returns the id of the task
+func (h *TaskHandle) ID() int64 { return h.item.id }
+
+// Context returns a context cancelled when the task is cancelled
+func (h *TaskHandle) Context() context.Context { return h.item.ctx }
+
+// SetName sets the name reported by Tasks
+func (h *TaskHandle) SetName(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.item.name = name
+}
+
+// TaskInfo information about a queued or running task
+type TaskInfo struct {
+	ID         int64
+	Name       string // empty until the task starts
+	Priority   Priority
+	EnqueuedAt time.Time
+	StartedAt  time.Time     // zero if the task is still queued
+	Runtime    time.Duration // for how long the task has been running, 0 if the task is still queued
+	Running    bool
+}
+
 func NewTaskRunner[T ITask](ctx context.Context, factory func() T) *TaskRunner[T] {
 	chanLen := 100
 	r := &TaskRunner[T]{}
@@ -61,6 +138,7 @@ func NewTaskRunner[T ITask](ctx context.Context, factory func() T) *TaskRunner[T
 	r.tasks = NewPriorityQueue[*item]()
 	r.tasksPushCh = make(chan *item, chanLen)
 	r.tasksPopCh = make(chan struct{}, chanLen)
+	r.queued = make(map[int64]*item)
 	r.ctx = ctx
 	r.start()
 	return r
@@ -70,6 +148,12 @@ func (r *TaskRunner[T]) start() {
 	go func() {
 		for t := range r.tasksPushCh {
 			r.tasksLock.Lock()
+			if t.ctx.Err() != nil { // task was cancelled before reaching the queue
+				r.tasksLock.Unlock()
+				close(t.canBeProcessedCh)
+				continue
+			}
+			t.score = r.score(t)
 			r.tasks.Push(t)
 			r.tasksLock.Unlock()
 			select {
@@ -82,7 +166,14 @@ func (r *TaskRunner[T]) start() {
 	go func() {
 		for range r.tasksPopCh {
 			r.tasksLock.Lock()
+			if r.tasks.Len() == 0 { // task was cancelled
+				r.tasksLock.Unlock()
+				continue
+			}
 			task := r.tasks.Pop()
+			delete(r.queued, task.id)
+			task.startedAt = time.Now()
+			r.current = task
 			r.tasksLock.Unlock()
 			close(task.canBeProcessedCh)
 			select {
@@ -90,6 +181,11 @@ func (r *TaskRunner[T]) start() {
 			case <-r.ctx.Done():
 				return
 			}
+			r.tasksLock.Lock()
+			r.current = nil
+			task.cancel()
+			r.notifyIdle()
+			r.tasksLock.Unlock()
 		}
 	}()
 }
@@ -101,19 +197,140 @@ func (r *TaskRunner[T]) WithPriority(priority Priority) T {
 	task.priority = priority
 	task.canBeProcessedCh = canBeProcessedCh
 	task.isDoneCh = taskIsDoneCh
+	task.enqueuedAt = time.Now()
+	task.ctx, task.cancel = context.WithCancel(r.ctx)
+	r.tasksLock.Lock()
+	r.lastID++
+	task.id = r.lastID
+	r.queued[task.id] = task
+	r.tasksLock.Unlock()
 	r.tasksPushCh <- task
 	<-canBeProcessedCh
 	t := r.factory()
+	if task.ctx.Err() != nil && task.startedAt.IsZero() {
+		// Cancelled while queued, the runner is not waiting for this task
+		taskIsDoneCh = make(chan struct{})
+	}
 	t.SetTaskDoneCh(taskIsDoneCh)
+	if th, ok := any(t).(ITaskWithHandle); ok {
+		th.SetTaskHandle(&TaskHandle{item: task, lock: &r.tasksLock})
+	}
 	return t
 }
 
+// SetAging enables the fairness policy: a queued task gains one priority level for every d it waits,
+// so low priority tasks are never blocked forever by a flow of higher priority ones. 0 disables it.
+func (r *TaskRunner[T]) SetAging(d time.Duration) {
+	r.tasksLock.Lock()
+	defer r.tasksLock.Unlock()
+	r.aging = d
+	for _, t := range r.tasks.Items() {
+		t.score = r.score(t)
+	}
+	r.tasks.Init()
+}
+
+// score returns the key ordering the queue, must be called with the lock held.
+// With aging, the effective priority of a task is priority + waited/aging. Since every task ages at the same
+// rate, ordering on priority - enqueuedAt/aging gives the same order at any time, and the heap stays valid.
+func (r *TaskRunner[T]) score(t *item) float64 {
+	if r.aging <= 0 {
+		return float64(t.priority)
+	}
+	return float64(t.priority) - float64(t.enqueuedAt.UnixNano())/float64(r.aging.Nanoseconds())
+}
+
+// Tasks returns the running task followed by the queued ones, in processing order
+func (r *TaskRunner[T]) Tasks() []TaskInfo {
+	r.tasksLock.Lock()
+	defer r.tasksLock.Unlock()
+	now := time.Now()
+	out := make([]TaskInfo, 0, len(r.queued)+1)
+	if r.current != nil {
+		out = append(out, TaskInfo{
+			ID:         r.current.id,
+			Name:       r.current.name,
+			Priority:   r.current.priority,
+			EnqueuedAt: r.current.enqueuedAt,
+			StartedAt:  r.current.startedAt,
+			Runtime:    now.Sub(r.current.startedAt),
+			Running:    true,
+		})
+	}
+	queued := make([]*item, 0, len(r.queued))
+	for _, task := range r.queued {
+		queued = append(queued, task)
+	}
+	sort.Slice(queued, func(i, j int) bool {
+		if si, sj := r.score(queued[i]), r.score(queued[j]); si != sj {
+			return si > sj
+		}
+		return queued[i].id < queued[j].id
+	})
+	for _, task := range queued {
+		out = append(out, TaskInfo{ID: task.id, Name: task.name, Priority: task.priority, EnqueuedAt: task.enqueuedAt})
+	}
+	return out
+}
+
+// CancelTask cancels a task. A queued task is removed from the queue and released right away with a
+// cancelled context, a running task only has its context cancelled.
+func (r *TaskRunner[T]) CancelTask(id int64) error {
+	r.tasksLock.Lock()
+	defer r.tasksLock.Unlock()
+	if r.current != nil && r.current.id == id {
+		r.current.cancel()
+		return nil
+	}
+	task, ok := r.queued[id]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	delete(r.queued, id)
+	task.cancel()
+	// A task still in tasksPushCh is released by the push thread
+	if task.index >= 0 && task.index < r.tasks.Len() && r.tasks.Items()[task.index] == task {
+		r.tasks.Remove(task.index)
+		close(task.canBeProcessedCh)
+	}
+	r.notifyIdle()
+	return nil
+}
+
+// Drain blocks until there is no more queued or running task
+func (r *TaskRunner[T]) Drain() {
+	r.tasksLock.Lock()
+	if r.current == nil && len(r.queued) == 0 {
+		r.tasksLock.Unlock()
+		return
+	}
+	idleCh := make(chan struct{})
+	r.idleChs = append(r.idleChs, idleCh)
+	r.tasksLock.Unlock()
+	select {
+	case <-idleCh:
+	case <-r.ctx.Done():
+	}
+}
+
+// notifyIdle releases the Drain callers if there is no more task, must be called with the lock held
+func (r *TaskRunner[T]) notifyIdle() {
+	if r.current != nil || len(r.queued) > 0 {
+		return
+	}
+	for _, ch := range r.idleChs {
+		close(ch)
+	}
+	r.idleChs = nil
+}
+
 // TasksOverview overview of tasks in heap
 type TasksOverview struct {
 	Low       Priority
 	Normal    Priority
 	Important Priority
 	Critical  Priority
+	Custom    Priority // tasks with a custom priority level
 	Total     int64
 }
 
@@ -130,6 +347,8 @@ func (r *TaskRunner[T]) GetTasks() (out TasksOverview) {
 			out.Important++
 		case Critical:
 			out.Critical++
+		default:
+			out.Custom++
 		}
 	}
 	r.tasksLock.Unlock()
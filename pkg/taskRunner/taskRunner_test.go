@@ -1,18 +1,27 @@
 package taskRunner
 
 import (
+	"context"
 	"fmt"
+	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type testItem struct {
 	taskDoneCh chan struct{}
+	handle     *TaskHandle
 }
 
 func (i *testItem) SetTaskDoneCh(ch chan struct{}) {
 	i.taskDoneCh = ch
 }
 
+func (i *testItem) SetTaskHandle(h *TaskHandle) {
+	i.handle = h
+}
+
 func (i *testItem) DoSomething(name string) {
 	defer close(i.taskDoneCh)
 	time.Sleep(250 * time.Millisecond)
@@ -39,3 +48,57 @@ func (i *testItem) DoSomethingElse(name string) {
 //	go func() { time.Sleep(470 * time.Millisecond); tr.WithPriority(Important).DoSomething("F"); wg.Done() }()
 //	wg.Wait()
 //}
+
+func TestCancelTaskAndDrain(t *testing.T) {
+	factory := func() *testItem { return &testItem{} }
+	tr := NewTaskRunner[*testItem](context.Background(), factory)
+	running := tr.WithPriority(Low)
+	running.handle.SetName("running")
+	cancelledCh := make(chan *testItem)
+	go func() { cancelledCh <- tr.WithPriority(Important) }()
+	assert.Eventually(t, func() bool { return len(tr.Tasks()) == 2 }, time.Second, time.Millisecond)
+
+	tasks := tr.Tasks()
+	assert.Equal(t, int64(1), tasks[0].ID)
+	assert.Equal(t, "running", tasks[0].Name)
+	assert.True(t, tasks[0].Running)
+	assert.Equal(t, int64(2), tasks[1].ID)
+	assert.Equal(t, Important, tasks[1].Priority)
+	assert.False(t, tasks[1].Running)
+
+	assert.ErrorIs(t, tr.CancelTask(3), ErrTaskNotFound)
+	assert.NoError(t, tr.CancelTask(2))
+	cancelled := <-cancelledCh
+	assert.ErrorIs(t, cancelled.handle.Context().Err(), context.Canceled)
+	assert.Equal(t, 1, len(tr.Tasks()))
+
+	drainedCh := make(chan struct{})
+	go func() { tr.Drain(); close(drainedCh) }()
+	close(running.taskDoneCh)
+	select {
+	case <-drainedCh:
+	case <-time.After(time.Second):
+		t.Fatal("drain did not return")
+	}
+	assert.Equal(t, 0, len(tr.Tasks()))
+}
+
+func TestAging(t *testing.T) {
+	tr := NewTaskRunner[*testItem](context.Background(), func() *testItem { return &testItem{} })
+	now := time.Now()
+	low := &item{priority: Low, enqueuedAt: now.Add(-10 * time.Second)}
+	normal := &item{priority: Normal, enqueuedAt: now}
+	assert.Greater(t, tr.score(normal), tr.score(low))
+	tr.SetAging(time.Second) // low waited 10s, it is now ahead of normal
+	assert.Greater(t, tr.score(low), tr.score(normal))
+	tr.SetAging(20 * time.Second)
+	assert.Greater(t, tr.score(normal), tr.score(low))
+}
+
+func TestPriorityString(t *testing.T) {
+	assert.Equal(t, "Normal", Normal.String())
+	scanner := NewPriority("Scanner", 0)
+	assert.Equal(t, Priority(0), scanner)
+	assert.Equal(t, "Scanner", scanner.String())
+	assert.Equal(t, "42", Priority(42).String())
+}
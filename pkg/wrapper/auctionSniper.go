@@ -0,0 +1,239 @@
+package wrapper
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// ErrAuctionBidNotConfirmed is returned when a websocket bid was sent but the auctioneer did not broadcast it in time
+var ErrAuctionBidNotConfirmed = errors.New("auction bid not confirmed")
+
+// AuctionSniper watches the auctioneer websocket events and bids in the last moments of an auction,
+// within a resource budget and for whitelisted items only.
+//
+//	sniper := wrapper.NewAuctionSniper(bot, planetID).
+//		SetBudget(500000).
+//		SetWhitelist("gold", "platinum")
+//	sniper.Start()
+type AuctionSniper struct {
+	b              Wrapper
+	celestialID    ogame.CelestialID
+	budget         int64
+	window         time.Duration
+	whitelist      []string
+	useWebsocket   bool
+	mu             sync.Mutex
+	running        bool
+	sub            *Subscription
+	bidding        bool
+	timer          *time.Timer
+	bidCallbacks   []func(ogame.Auction, int64)
+	errorCallbacks []func(error)
+}
+
+// NewAuctionSniper creates a sniper that bids with the resources of celestialID
+func NewAuctionSniper(b Wrapper, celestialID ogame.CelestialID) *AuctionSniper {
+	return &AuctionSniper{b: b, celestialID: celestialID, window: 2 * time.Minute}
+}
+
+// SetBudget sets the maximum total bid (in auction points) for a single auction, 0 for no limit
+func (s *AuctionSniper) SetBudget(budget int64) *AuctionSniper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.budget = budget
+	return s
+}
+
+// SetWindow sets how long before the end of the auction the sniper starts bidding
+func (s *AuctionSniper) SetWindow(d time.Duration) *AuctionSniper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.window = d
+	return s
+}
+
+// SetWhitelist only bid on items whose name contains one of the given strings (case insensitive), all items if empty
+func (s *AuctionSniper) SetWhitelist(items ...string) *AuctionSniper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.whitelist = s.whitelist[:0]
+	for _, item := range items {
+		s.whitelist = append(s.whitelist, strings.ToLower(item))
+	}
+	return s
+}
+
+// SetUseWebsocket sends bids on the auctioneer websocket for lower latency, falls back to http when not connected
+func (s *AuctionSniper) SetUseWebsocket(useWebsocket bool) *AuctionSniper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.useWebsocket = useWebsocket
+	return s
+}
+
+// OnBid registers a callback executed when a bid is placed, with the auction state before the bid and the amount bid
+func (s *AuctionSniper) OnBid(clb func(auction ogame.Auction, amount int64)) *AuctionSniper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bidCallbacks = append(s.bidCallbacks, clb)
+	return s
+}
+
+// OnError registers a callback executed when a bid attempt fails
+func (s *AuctionSniper) OnError(clb func(error)) *AuctionSniper {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorCallbacks = append(s.errorCallbacks, clb)
+	return s
+}
+
+// Start starts listening to auctioneer events
+func (s *AuctionSniper) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = true
+	if s.sub == nil {
+		s.sub = s.b.RegisterAuctioneerCallback(s.handlePacket)
+	}
+}
+
+// Stop stops bidding, the sniper can be started again
+func (s *AuctionSniper) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	s.sub.Close()
+	s.sub = nil
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+func (s *AuctionSniper) handlePacket(packet any) {
+	s.mu.Lock()
+	running, window := s.running, s.window
+	s.mu.Unlock()
+	if !running {
+		return
+	}
+	switch pck := packet.(type) {
+	case ogame.AuctioneerTimeRemaining:
+		remaining := time.Duration(pck.Approx) * time.Second
+		if remaining <= window {
+			go s.tryBid()
+		} else {
+			s.schedule(remaining - window)
+		}
+	case ogame.AuctioneerNewBid:
+		// Someone else might have outbid us, tryBid checks if we are in the bidding window
+		go s.tryBid()
+	case ogame.AuctioneerAuctionFinished, ogame.AuctioneerNewAuction:
+		s.schedule(0)
+	}
+}
+
+// schedule plans a bid attempt after d, 0 cancels the planned attempt
+func (s *AuctionSniper) schedule(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if d > 0 {
+		s.timer = time.AfterFunc(d, s.tryBid)
+	}
+}
+
+func (s *AuctionSniper) tryBid() {
+	s.mu.Lock()
+	if s.bidding || !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.bidding = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.bidding = false
+		s.mu.Unlock()
+	}()
+	if err := s.bid(); err != nil {
+		s.mu.Lock()
+		callbacks := s.errorCallbacks
+		s.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(err)
+		}
+	}
+}
+
+func (s *AuctionSniper) bid() error {
+	auction, err := s.b.GetAuction()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	budget, window, whitelist, useWebsocket := s.budget, s.window, s.whitelist, s.useWebsocket
+	s.mu.Unlock()
+	if auction.HasFinished || time.Duration(auction.Endtime)*time.Second > window {
+		return nil
+	}
+	if !auctionItemWhitelisted(auction, whitelist) {
+		return nil
+	}
+	if auction.HighestBidderUserID != 0 && auction.HighestBidderUserID == s.b.GetCachedPlayer().PlayerID {
+		return nil
+	}
+	amount := auctionBidAmount(auction)
+	if budget > 0 && auction.AlreadyBid+amount > budget {
+		return nil
+	}
+	if auction.ResourceMultiplier.Metal <= 0 {
+		return errors.New("invalid auction resource multiplier")
+	}
+	metal := int64(math.Ceil(float64(amount) / auction.ResourceMultiplier.Metal))
+	bid := map[ogame.CelestialID]ogame.Resources{s.celestialID: {Metal: metal}}
+	if useWebsocket {
+		err = s.b.DoAuctionWS(auction, bid)
+	} else {
+		err = s.b.DoAuction(bid)
+	}
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	callbacks := s.bidCallbacks
+	s.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(auction, amount)
+	}
+	return nil
+}
+
+// auctionBidAmount the amount (in auction points) needed to become the highest bidder
+func auctionBidAmount(auction ogame.Auction) int64 {
+	amount := auction.MinimumBid - auction.AlreadyBid
+	if auction.DeficitBid > amount {
+		amount = auction.DeficitBid
+	}
+	return amount
+}
+
+func auctionItemWhitelisted(auction ogame.Auction, whitelist []string) bool {
+	if len(whitelist) == 0 {
+		return true
+	}
+	for _, item := range whitelist {
+		if strings.Contains(auction.CurrentItem, item) || strings.Contains(auction.CurrentItemLong, item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package wrapper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// Delays waiting for the report of the auto spy probes
+const (
+	autoSpyReportDelay    = 5 * time.Second
+	autoSpyReportAttempts = 3
+)
+
+// spyAndWaitReport sends probes on coord and waits for the new report, outside any task so the bot is not locked
+// while the probes fly
+func (b *OGame) spyAndWaitReport(coord ogame.Coordinate, cfg Options) (ogame.EspionageReport, error) {
+	probes := cfg.AutoSpyProbes
+	if probes <= 0 {
+		probes = 1
+	}
+	start := time.Now()
+	ships := []ogame.Quantifiable{{ID: ogame.EspionageProbeID, Nbr: probes}}
+	fleet, err := b.SendFleet(cfg.AutoSpyFrom, ships, ogame.HundredPercent, coord, ogame.Spy, ogame.Resources{}, 0, 0)
+	if err != nil {
+		return ogame.EspionageReport{}, err
+	}
+	wait := time.Until(fleet.ArrivalTime) + autoSpyReportDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(wait):
+		case <-b.ctx.Done():
+			return ogame.EspionageReport{}, b.ctx.Err()
+		}
+		// Reports dated before the probes were sent are not the ones we are waiting for, with some slack for clock drift
+		maxAge := MaxAge(time.Since(start) + time.Minute)
+		report, err := b.WithPriority(taskRunner.Normal).GetEspionageReportFor(coord, maxAge)
+		if err == nil || attempt >= autoSpyReportAttempts ||
+			!(errors.Is(err, ogame.ErrReportTooOld) || errors.Is(err, ogame.ErrReportNotFound)) {
+			return report, err
+		}
+		wait = autoSpyReportDelay
+	}
+}
@@ -0,0 +1,23 @@
+package wrapper
+
+// BlackboxProvider returns the blackbox fingerprint token ("tra:...") sent with the gameforge login.
+// No blackbox is sent unless a provider is set.
+type BlackboxProvider func() (string, error)
+
+// SetBlackboxProvider sets the provider of the blackbox sent with the gameforge login, nil (default) to not send any
+func (b *OGame) SetBlackboxProvider(provider BlackboxProvider) {
+	b.blackboxProviderMu.Lock()
+	defer b.blackboxProviderMu.Unlock()
+	b.blackboxProvider = provider
+}
+
+// getBlackbox returns the blackbox of the provider, empty if no provider is set
+func (b *OGame) getBlackbox() (string, error) {
+	b.blackboxProviderMu.Lock()
+	provider := b.blackboxProvider
+	b.blackboxProviderMu.Unlock()
+	if provider == nil {
+		return "", nil
+	}
+	return provider()
+}
@@ -0,0 +1,349 @@
+package wrapper
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/parser"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// CacheKind kind of data stored in a Cache
+type CacheKind string
+
+// Cache kinds
+const (
+	PlanetsCache    CacheKind = "planets"    // planets and moons of the player
+	ResearchesCache CacheKind = "researches" // researches of the player
+	TechsCache      CacheKind = "techs"      // techs per celestial, keyed by celestial id
+	GalaxyCache     CacheKind = "galaxy"     // galaxy systems, keyed by "galaxy:system", served by GalaxyInfos while younger than the TTL
+	ReportsCache    CacheKind = "reports"    // espionage reports, keyed by message id
+)
+
+// DefaultTechsCacheTTL how long the techs of a celestial are trusted when no TTL is set for TechsCache,
+// ships and defenses change with every fleet sent and every construction finished
+const DefaultTechsCacheTTL = 5 * time.Minute
+
+// defaultCacheTTLs TTL of the kinds without TTL set with SetCacheTTL, the other kinds never expire by default
+var defaultCacheTTLs = map[CacheKind]time.Duration{
+	TechsCache: DefaultTechsCacheTTL,
+}
+
+// Cache persistent storage of the bot caches, values are json encoded.
+// Set it on the bot with SetCache.
+type Cache interface {
+	Get(kind CacheKind, key string) (value []byte, updatedAt time.Time, found bool, err error)
+	Set(kind CacheKind, key string, value []byte) error
+	Delete(kind CacheKind, key string) error
+}
+
+// errCacheMiss returned when a value is not in the cache
+var errCacheMiss = errors.New("cache miss")
+
+type memoryCacheEntry struct {
+	value     []byte
+	updatedAt time.Time
+}
+
+// MemoryCache in memory Cache, mostly useful for tests
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[CacheKind]map[string]memoryCacheEntry
+}
+
+// NewMemoryCache creates an empty in memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[CacheKind]map[string]memoryCacheEntry)}
+}
+
+// Get ...
+func (c *MemoryCache) Get(kind CacheKind, key string) ([]byte, time.Time, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, found := c.entries[kind][key]
+	return entry.value, entry.updatedAt, found, nil
+}
+
+// Set ...
+func (c *MemoryCache) Set(kind CacheKind, key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[kind]; !ok {
+		c.entries[kind] = make(map[string]memoryCacheEntry)
+	}
+	c.entries[kind][key] = memoryCacheEntry{value: value, updatedAt: time.Now()}
+	return nil
+}
+
+// Delete ...
+func (c *MemoryCache) Delete(kind CacheKind, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries[kind], key)
+	return nil
+}
+
+// SQLiteCache Cache stored in an SQLite database.
+// The driver is not imported by this package, open the database with the driver of your choice:
+//
+//	import _ "github.com/mattn/go-sqlite3"
+//	db, _ := sql.Open("sqlite3", "ogame.db")
+//	cache, _ := wrapper.NewSQLiteCache(db)
+//	bot.SetCache(cache)
+type SQLiteCache struct {
+	db *sql.DB
+}
+
+// NewSQLiteCache creates the cache table if needed
+func NewSQLiteCache(db *sql.DB) (*SQLiteCache, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ogame_cache (
+		kind TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value BLOB NOT NULL,
+		updated_at INTEGER NOT NULL,
+		PRIMARY KEY (kind, key)
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteCache{db: db}, nil
+}
+
+// Get ...
+func (c *SQLiteCache) Get(kind CacheKind, key string) ([]byte, time.Time, bool, error) {
+	var value []byte
+	var updatedAt int64
+	err := c.db.QueryRow(`SELECT value, updated_at FROM ogame_cache WHERE kind = ? AND key = ?`, string(kind), key).Scan(&value, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, false, nil
+	} else if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	return value, time.Unix(updatedAt, 0), true, nil
+}
+
+// Set ...
+func (c *SQLiteCache) Set(kind CacheKind, key string, value []byte) error {
+	_, err := c.db.Exec(`INSERT OR REPLACE INTO ogame_cache (kind, key, value, updated_at) VALUES (?, ?, ?, ?)`,
+		string(kind), key, value, time.Now().Unix())
+	return err
+}
+
+// Delete ...
+func (c *SQLiteCache) Delete(kind CacheKind, key string) error {
+	_, err := c.db.Exec(`DELETE FROM ogame_cache WHERE kind = ? AND key = ?`, string(kind), key)
+	return err
+}
+
+// cacheKey prefixes the key with the universe and the player, so several bots can share a cache
+func (b *OGame) cacheKey(key string) string {
+	return b.Universe + ":" + b.language + ":" + b.Username + ":" + key
+}
+
+func cacheGet[T any](b *OGame, kind CacheKind, key string) (out T, err error) {
+	out, _, err = cacheGetWithTime[T](b, kind, key)
+	return out, err
+}
+
+// cacheGetWithTime same as cacheGet, also returns when the value was stored
+func cacheGetWithTime[T any](b *OGame, kind CacheKind, key string) (out T, updatedAt time.Time, err error) {
+	if b.cache == nil {
+		return out, updatedAt, errCacheMiss
+	}
+	value, updatedAt, found, err := b.cache.Get(kind, b.cacheKey(key))
+	if err != nil {
+		return out, updatedAt, err
+	}
+	if !found || cacheExpired(b.getCacheTTL(kind), updatedAt, time.Now()) {
+		return out, updatedAt, errCacheMiss
+	}
+	err = json.Unmarshal(value, &out)
+	return out, updatedAt, err
+}
+
+func cacheSet(b *OGame, kind CacheKind, key string, v any) {
+	if b.cache == nil {
+		return
+	}
+	if value, err := json.Marshal(v); err == nil {
+		if err := b.cache.Set(kind, b.cacheKey(key), value); err != nil {
+			b.error("failed to write cache:", err)
+		}
+	}
+}
+
+func galaxyCacheKey(galaxy, system int64) string {
+	return utils.FI64(galaxy) + ":" + utils.FI64(system)
+}
+
+// planetsToOgame returns the planets without the bot reference, with their moons
+func planetsToOgame(planets []Planet) []ogame.Planet {
+	out := make([]ogame.Planet, 0, len(planets))
+	for _, planet := range planets {
+		p := planet.Planet
+		if planet.Moon != nil {
+			moon := planet.Moon.Moon
+			p.Moon = &moon
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// cachedTechs techs of a celestial as stored in the cache
+type cachedTechs struct {
+	ResourcesBuildings ogame.ResourcesBuildings
+	Facilities         ogame.Facilities
+	Ships              ogame.ShipsInfos
+	Defenses           ogame.DefensesInfos
+	Researches         ogame.Researches
+	LfBuildings        ogame.LfBuildings
+}
+
+type techsCacheEntry struct {
+	techs     cachedTechs
+	updatedAt time.Time
+}
+
+// cachedTechs returns the techs of a celestial from the in memory cache, then from the persistent cache.
+// Entries older than the TechsCache TTL are ignored.
+func (b *OGame) cachedTechs(celestialID ogame.CelestialID) (cachedTechs, bool) {
+	b.techsCacheMu.Lock()
+	entry, ok := b.techsCache[celestialID]
+	b.techsCacheMu.Unlock()
+	if ok && !cacheExpired(b.getCacheTTL(TechsCache), entry.updatedAt, time.Now()) {
+		return entry.techs, true
+	}
+	techs, updatedAt, err := cacheGetWithTime[cachedTechs](b, TechsCache, utils.FI64(celestialID))
+	if err != nil {
+		return cachedTechs{}, false
+	}
+	b.storeCachedTechs(celestialID, techs, updatedAt)
+	return techs, true
+}
+
+func (b *OGame) setCachedTechs(celestialID ogame.CelestialID, techs cachedTechs) {
+	b.storeCachedTechs(celestialID, techs, time.Now())
+	cacheSet(b, TechsCache, utils.FI64(celestialID), techs)
+}
+
+// storeCachedTechs keeps the techs of a celestial in the in memory cache
+func (b *OGame) storeCachedTechs(celestialID ogame.CelestialID, techs cachedTechs, updatedAt time.Time) {
+	b.techsCacheMu.Lock()
+	defer b.techsCacheMu.Unlock()
+	if b.techsCache == nil {
+		b.techsCache = make(map[ogame.CelestialID]techsCacheEntry)
+	}
+	b.techsCache[celestialID] = techsCacheEntry{techs: techs, updatedAt: updatedAt}
+}
+
+// TechsUpdatedAt returns when the cached techs of a celestial were fetched, zero time if they are not cached
+func (b *OGame) TechsUpdatedAt(celestialID ogame.CelestialID) time.Time {
+	b.techsCacheMu.Lock()
+	defer b.techsCacheMu.Unlock()
+	return b.techsCache[celestialID].updatedAt
+}
+
+// InvalidateTechs drops the cached techs of a celestial, so GetCachedTechs fetches them again on next use.
+// It is called automatically after a successful build or tear down on the celestial.
+func (b *OGame) InvalidateTechs(celestialID ogame.CelestialID) {
+	b.techsCacheMu.Lock()
+	delete(b.techsCache, celestialID)
+	b.techsCacheMu.Unlock()
+	if b.cache != nil {
+		if err := b.cache.Delete(TechsCache, b.cacheKey(utils.FI64(celestialID))); err != nil {
+			b.error("failed to write cache:", err)
+		}
+	}
+}
+
+// SetCacheTTL sets how long the values of a cache kind are trusted, 0 for no expiry.
+// TechsCache expires after DefaultTechsCacheTTL unless set, the other kinds never expire by default.
+// Expired planets are refreshed in the background, expired researches are fetched again on next use,
+// and expired persistent cache entries are ignored.
+func (b *OGame) SetCacheTTL(kind CacheKind, ttl time.Duration) {
+	b.cacheTTLMu.Lock()
+	defer b.cacheTTLMu.Unlock()
+	if b.cacheTTLs == nil {
+		b.cacheTTLs = make(map[CacheKind]time.Duration)
+	}
+	b.cacheTTLs[kind] = ttl
+}
+
+// InvalidateCache marks the in memory cache of a kind as expired, so it is fetched again on next use.
+// Persistent cache entries are left untouched, they expire with the TTL of their kind.
+func (b *OGame) InvalidateCache(kind CacheKind) {
+	b.cacheTTLMu.Lock()
+	delete(b.cacheUpdatedAt, kind)
+	b.cacheTTLMu.Unlock()
+	if kind == ResearchesCache {
+		b.researches = nil
+	}
+}
+
+func (b *OGame) getCacheTTL(kind CacheKind) time.Duration {
+	b.cacheTTLMu.Lock()
+	defer b.cacheTTLMu.Unlock()
+	return b.cacheTTLLocked(kind)
+}
+
+// cacheTTLLocked returns the TTL of a cache kind, must be called with cacheTTLMu held
+func (b *OGame) cacheTTLLocked(kind CacheKind) time.Duration {
+	if ttl, ok := b.cacheTTLs[kind]; ok {
+		return ttl
+	}
+	return defaultCacheTTLs[kind]
+}
+
+// touchCache records that the in memory cache of a kind was just updated
+func (b *OGame) touchCache(kind CacheKind) {
+	b.cacheTTLMu.Lock()
+	defer b.cacheTTLMu.Unlock()
+	if b.cacheUpdatedAt == nil {
+		b.cacheUpdatedAt = make(map[CacheKind]time.Time)
+	}
+	b.cacheUpdatedAt[kind] = time.Now()
+}
+
+// isCacheExpired returns true if the in memory cache of a kind was invalidated or is older than its TTL
+func (b *OGame) isCacheExpired(kind CacheKind) bool {
+	b.cacheTTLMu.Lock()
+	defer b.cacheTTLMu.Unlock()
+	updatedAt, ok := b.cacheUpdatedAt[kind]
+	if !ok {
+		return true
+	}
+	return cacheExpired(b.cacheTTLLocked(kind), updatedAt, time.Now())
+}
+
+func cacheExpired(ttl time.Duration, updatedAt, now time.Time) bool {
+	return ttl > 0 && now.Sub(updatedAt) > ttl
+}
+
+func (b *OGame) refreshCaches() error {
+	if _, err := getPage[parser.OverviewPage](b); err != nil { // Will update planets cached values
+		return err
+	}
+	if _, err := getPage[parser.ResearchPage](b); err != nil { // Will update researches cached values
+		return err
+	}
+	return nil
+}
+
+// refreshCachesInBackground refreshes the caches without waiting, at most one refresh runs at a time
+func (b *OGame) refreshCachesInBackground() {
+	if !b.IsLoggedIn() || !atomic.CompareAndSwapInt32(&b.refreshingCachesAtom, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&b.refreshingCachesAtom, 0)
+		if err := b.RefreshCaches(); err != nil {
+			b.error("failed to refresh caches:", err)
+		}
+	}()
+}
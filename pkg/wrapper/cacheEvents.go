@@ -0,0 +1,117 @@
+package wrapper
+
+import (
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// CacheEventType kind of change detected in the bot caches
+type CacheEventType int
+
+// Cache events
+const (
+	PlanetAddedEvent         CacheEventType = iota // a new planet appeared in the planets list (colonized)
+	PlanetLostEvent                                // a planet disappeared from the planets list (abandoned or destroyed)
+	MoonAddedEvent                                 // a planet got a moon
+	MoonLostEvent                                  // a moon was destroyed
+	VacationModeChangedEvent                       // vacation mode was enabled or disabled
+	ResearchesChangedEvent                         // at least one research level changed
+)
+
+// String ...
+func (t CacheEventType) String() string {
+	switch t {
+	case PlanetAddedEvent:
+		return "PlanetAdded"
+	case PlanetLostEvent:
+		return "PlanetLost"
+	case MoonAddedEvent:
+		return "MoonAdded"
+	case MoonLostEvent:
+		return "MoonLost"
+	case VacationModeChangedEvent:
+		return "VacationModeChanged"
+	case ResearchesChangedEvent:
+		return "ResearchesChanged"
+	default:
+		return "Unknown"
+	}
+}
+
+// CacheEvent change detected when the bot updates its caches from a page
+type CacheEvent struct {
+	Type           CacheEventType
+	Planet         ogame.Planet      // planet added/lost, or the planet of the moon added/lost
+	Moon           *ogame.Moon       // moon added/lost
+	VacationMode   bool              // new vacation mode state
+	Researches     ogame.Researches  // new researches
+	PrevResearches *ogame.Researches // researches before the change
+}
+
+// OnCacheChange registers a callback executed when the cached planets, moons, vacation mode or researches change.
+// Callbacks are executed in their own goroutine, so they can call the bot.
+func (b *OGame) OnCacheChange(clb func(CacheEvent)) {
+	b.cacheEventsMu.Lock()
+	defer b.cacheEventsMu.Unlock()
+	b.cacheEventCallbacks = append(b.cacheEventCallbacks, clb)
+}
+
+func (b *OGame) emitCacheEvents(events []CacheEvent) {
+	if len(events) == 0 {
+		return
+	}
+	b.cacheEventsMu.Lock()
+	callbacks := b.cacheEventCallbacks
+	b.cacheEventsMu.Unlock()
+	if len(callbacks) == 0 {
+		return
+	}
+	go func() {
+		for _, event := range events {
+			for _, clb := range callbacks {
+				clb(event)
+			}
+		}
+	}()
+}
+
+// setResearches updates the cached researches and emits an event if a level changed
+func (b *OGame) setResearches(researches ogame.Researches) {
+	prev := b.researches
+	b.researches = &researches
+	cacheSet(b, ResearchesCache, "", researches)
+	b.touchCache(ResearchesCache)
+	if prev != nil && *prev != researches {
+		b.emitCacheEvents([]CacheEvent{{Type: ResearchesChangedEvent, Researches: researches, PrevResearches: prev}})
+	}
+}
+
+// diffPlanets returns the planets and moons events between two planets lists
+func diffPlanets(prev, curr []ogame.Planet) (events []CacheEvent) {
+	prevByID := make(map[ogame.PlanetID]ogame.Planet, len(prev))
+	for _, planet := range prev {
+		prevByID[planet.ID] = planet
+	}
+	currByID := make(map[ogame.PlanetID]struct{}, len(curr))
+	for _, planet := range curr {
+		currByID[planet.ID] = struct{}{}
+		old, found := prevByID[planet.ID]
+		if !found {
+			events = append(events, CacheEvent{Type: PlanetAddedEvent, Planet: planet})
+			if planet.Moon != nil {
+				events = append(events, CacheEvent{Type: MoonAddedEvent, Planet: planet, Moon: planet.Moon})
+			}
+			continue
+		}
+		if old.Moon == nil && planet.Moon != nil {
+			events = append(events, CacheEvent{Type: MoonAddedEvent, Planet: planet, Moon: planet.Moon})
+		} else if old.Moon != nil && planet.Moon == nil {
+			events = append(events, CacheEvent{Type: MoonLostEvent, Planet: planet, Moon: old.Moon})
+		}
+	}
+	for _, planet := range prev {
+		if _, found := currByID[planet.ID]; !found {
+			events = append(events, CacheEvent{Type: PlanetLostEvent, Planet: planet})
+		}
+	}
+	return
+}
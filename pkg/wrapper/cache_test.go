@@ -0,0 +1,125 @@
+package wrapper
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memSQLDriver in memory database/sql driver understanding the statements of SQLiteCache.
+// The module does not depend on an SQLite driver, so the statements are not run against SQLite here,
+// keep them in the SQL subset SQLite supports (INSERT OR REPLACE relies on the (kind, key) primary key).
+type memSQLDriver struct {
+	mu     sync.Mutex
+	tables map[string]bool
+	rows   map[[2]string][]driver.Value // kind, key -> value, updated_at
+}
+
+func (d *memSQLDriver) Open(string) (driver.Conn, error) { return &memSQLConn{d: d}, nil }
+
+type memSQLConn struct{ d *memSQLDriver }
+
+func (c *memSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &memSQLStmt{d: c.d, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+func (c *memSQLConn) Close() error              { return nil }
+func (c *memSQLConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported") }
+
+type memSQLStmt struct {
+	d     *memSQLDriver
+	query string
+}
+
+func (s *memSQLStmt) Close() error  { return nil }
+func (s *memSQLStmt) NumInput() int { return -1 }
+
+func (s *memSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS ogame_cache "):
+		s.d.tables["ogame_cache"] = true
+	case strings.HasPrefix(s.query, "INSERT OR REPLACE INTO ogame_cache (kind, key, value, updated_at) VALUES (?, ?, ?, ?)"):
+		if !s.d.tables["ogame_cache"] {
+			return nil, errors.New("no such table: ogame_cache")
+		}
+		s.d.rows[[2]string{args[0].(string), args[1].(string)}] = []driver.Value{args[2], args[3]}
+	case strings.HasPrefix(s.query, "DELETE FROM ogame_cache WHERE kind = ? AND key = ?"):
+		delete(s.d.rows, [2]string{args[0].(string), args[1].(string)})
+	default:
+		return nil, errors.New("unexpected statement: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *memSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if !strings.HasPrefix(s.query, "SELECT value, updated_at FROM ogame_cache WHERE kind = ? AND key = ?") {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	rows := &memSQLRows{}
+	if row, ok := s.d.rows[[2]string{args[0].(string), args[1].(string)}]; ok {
+		rows.values = [][]driver.Value{row}
+	}
+	return rows, nil
+}
+
+type memSQLRows struct {
+	values [][]driver.Value
+}
+
+func (r *memSQLRows) Columns() []string { return []string{"value", "updated_at"} }
+func (r *memSQLRows) Close() error      { return nil }
+func (r *memSQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("ogame_memsql", &memSQLDriver{tables: make(map[string]bool), rows: make(map[[2]string][]driver.Value)})
+}
+
+func TestSQLiteCache(t *testing.T) {
+	db, err := sql.Open("ogame_memsql", "")
+	assert.NoError(t, err)
+	defer db.Close()
+	cache, err := NewSQLiteCache(db)
+	assert.NoError(t, err)
+
+	_, _, found, err := cache.Get(GalaxyCache, "1:2")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	before := time.Now().Add(-time.Second)
+	assert.NoError(t, cache.Set(GalaxyCache, "1:2", []byte(`{"Galaxy":1}`)))
+	value, updatedAt, found, err := cache.Get(GalaxyCache, "1:2")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, `{"Galaxy":1}`, string(value))
+	assert.True(t, updatedAt.After(before))
+	_, _, found, _ = cache.Get(TechsCache, "1:2")
+	assert.False(t, found)
+
+	assert.NoError(t, cache.Delete(GalaxyCache, "1:2"))
+	_, _, found, err = cache.Get(GalaxyCache, "1:2")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.SetCache(cache)
+	cacheSet(b, TechsCache, "123", cachedTechs{})
+	_, err = cacheGet[cachedTechs](b, TechsCache, "123")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,184 @@
+package wrapper
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// ChatRule reply sent when an incoming chat message matches Pattern
+type ChatRule struct {
+	Pattern       *regexp.Regexp
+	Reply         string
+	SenderID      int64 // only messages from this player, 0 for any player
+	AssociationID int64 // only messages of this alliance chat, 0 for private messages
+}
+
+func (r ChatRule) matches(msg ogame.ChatMsg) bool {
+	if r.SenderID != 0 && r.SenderID != msg.SenderID {
+		return false
+	}
+	if r.AssociationID != msg.AssociationID {
+		return false
+	}
+	return r.Pattern.MatchString(msg.Text)
+}
+
+// ChatResponder replies to the incoming chat messages matching its rules, the first matching rule wins.
+// Replies are rate limited per conversation and globally, so the bot cannot be baited into spamming.
+//
+//	responder := wrapper.NewChatResponder(bot).
+//		On(`(?i)\b(bot|script)\b`, "lol no, just fast fingers").
+//		OnFrom(friendID, `(?i)^ping$`, "pong")
+//	responder.Start()
+type ChatResponder struct {
+	b              Wrapper
+	mu             sync.Mutex
+	rules          []ChatRule
+	cooldown       time.Duration // minimum delay between two replies in the same conversation
+	maxPerHour     int64
+	lastReply      map[int64]time.Time // conversation id -> last reply
+	replies        []time.Time         // replies sent during the last hour
+	sub            *Subscription
+	running        bool
+	errorCallbacks []func(ogame.ChatMsg, error)
+}
+
+// NewChatResponder creates a responder without any rule, at most one reply every 10 minutes per conversation
+// and 10 replies per hour
+func NewChatResponder(b Wrapper) *ChatResponder {
+	return &ChatResponder{
+		b:          b,
+		cooldown:   10 * time.Minute,
+		maxPerHour: 10,
+		lastReply:  make(map[int64]time.Time),
+	}
+}
+
+// On replies to the private messages matching pattern
+func (r *ChatResponder) On(pattern, reply string) *ChatResponder {
+	return r.AddRule(ChatRule{Pattern: regexp.MustCompile(pattern), Reply: reply})
+}
+
+// OnFrom replies to the private messages of playerID matching pattern
+func (r *ChatResponder) OnFrom(playerID int64, pattern, reply string) *ChatResponder {
+	return r.AddRule(ChatRule{Pattern: regexp.MustCompile(pattern), Reply: reply, SenderID: playerID})
+}
+
+// OnAlliance replies in the alliance chat associationID to the messages matching pattern
+func (r *ChatResponder) OnAlliance(associationID int64, pattern, reply string) *ChatResponder {
+	return r.AddRule(ChatRule{Pattern: regexp.MustCompile(pattern), Reply: reply, AssociationID: associationID})
+}
+
+// AddRule adds a rule, rules are evaluated in the order they are added
+func (r *ChatResponder) AddRule(rule ChatRule) *ChatResponder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, rule)
+	return r
+}
+
+// SetCooldown sets the minimum delay between two replies in the same conversation
+func (r *ChatResponder) SetCooldown(d time.Duration) *ChatResponder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cooldown = d
+	return r
+}
+
+// SetMaxPerHour sets the maximum number of replies sent per hour, 0 for unlimited
+func (r *ChatResponder) SetMaxPerHour(n int64) *ChatResponder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxPerHour = n
+	return r
+}
+
+// OnError registers a callback executed when a reply cannot be sent
+func (r *ChatResponder) OnError(clb func(ogame.ChatMsg, error)) *ChatResponder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errorCallbacks = append(r.errorCallbacks, clb)
+	return r
+}
+
+// Start starts replying to the incoming messages, until Stop is called
+func (r *ChatResponder) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running = true
+	if r.sub == nil {
+		r.sub = r.b.RegisterChatCallback(r.handle)
+	}
+}
+
+// Stop stops replying, the responder can be started again
+func (r *ChatResponder) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running = false
+	r.sub.Close()
+	r.sub = nil
+}
+
+func (r *ChatResponder) handle(msg ogame.ChatMsg) {
+	if msg.SenderID == r.b.GetCachedPlayer().PlayerID {
+		return // our own messages
+	}
+	rule, ok := r.match(msg, time.Now())
+	if !ok {
+		return
+	}
+	go func() {
+		var err error
+		if msg.AssociationID != 0 {
+			err = r.b.SendMessageAlliance(msg.AssociationID, rule.Reply)
+		} else {
+			err = r.b.SendMessage(msg.SenderID, rule.Reply)
+		}
+		if err != nil {
+			r.mu.Lock()
+			callbacks := r.errorCallbacks
+			r.mu.Unlock()
+			for _, clb := range callbacks {
+				clb(msg, err)
+			}
+		}
+	}()
+}
+
+// match returns the rule replying to msg if the rate limits allow a reply at now, and records the reply
+func (r *ChatResponder) match(msg ogame.ChatMsg, now time.Time) (ChatRule, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.running {
+		return ChatRule{}, false
+	}
+	conversation := msg.SenderID
+	if msg.AssociationID != 0 {
+		conversation = -msg.AssociationID
+	}
+	if last, ok := r.lastReply[conversation]; ok && now.Sub(last) < r.cooldown {
+		return ChatRule{}, false
+	}
+	replies := r.replies[:0]
+	for _, t := range r.replies {
+		if now.Sub(t) < time.Hour {
+			replies = append(replies, t)
+		}
+	}
+	r.replies = replies
+	if r.maxPerHour > 0 && int64(len(r.replies)) >= r.maxPerHour {
+		return ChatRule{}, false
+	}
+	for _, rule := range r.rules {
+		if rule.matches(msg) {
+			r.lastReply[conversation] = now
+			r.replies = append(r.replies, now)
+			return rule, true
+		}
+	}
+	return ChatRule{}, false
+}
@@ -0,0 +1,179 @@
+package wrapper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+const (
+	constructionWatchInterval   = 5 * time.Minute // how often a celestial is checked when nothing is being built
+	constructionFinishTolerance = 2 * time.Second // finish times of the same construction differ by the rounding of the countdowns
+)
+
+// constructionWatcher tracks the construction queues of a celestial and fires callbacks when an item completes
+type constructionWatcher struct {
+	sync.Mutex
+	b           *OGame
+	celestialID ogame.CelestialID
+	callbacks   callbackList[func(id ogame.ID)]
+	inProgress  map[int]construction // queue kind -> construction in progress
+	shipyardID  ogame.ID             // first item of the shipyard queue
+	shipyardLen int                  // number of items in the shipyard queue
+	stopCh      chan struct{}        // closed when the watcher is removed
+	cancelRun   context.CancelFunc   // stops the current run, protected by OGame.constructionWatchMu
+	runDoneCh   chan struct{}        // closed when the current run returns, protected by OGame.constructionWatchMu
+}
+
+// construction being built in a queue
+type construction struct {
+	ID       ogame.ID
+	FinishAt time.Time
+}
+
+// constructionsSnapshot constructions currently in progress in every queue of a celestial
+type constructionsSnapshot struct {
+	queues      map[int]construction
+	shipyardID  ogame.ID
+	shipyardLen int
+	countdown   int64 // smallest countdown among the queues, 0 if nothing is being built
+}
+
+func (b *OGame) onConstructionFinished(celestialID ogame.CelestialID, clb func(id ogame.ID)) *Subscription {
+	b.constructionWatchMu.Lock()
+	defer b.constructionWatchMu.Unlock()
+	if b.constructionWatchers == nil {
+		b.constructionWatchers = make(map[ogame.CelestialID]*constructionWatcher)
+	}
+	w, ok := b.constructionWatchers[celestialID]
+	if !ok {
+		w = &constructionWatcher{b: b, celestialID: celestialID, stopCh: make(chan struct{})}
+		b.constructionWatchers[celestialID] = w
+		b.startConstructionWatcher(w)
+	}
+	sub := w.callbacks.add(clb)
+	return &Subscription{close: func() {
+		sub.Close()
+		b.removeConstructionWatcher(w, true)
+	}}
+}
+
+// removeConstructionWatcher stops the watcher, if onlyUnused is true the watcher is kept while it has callbacks
+func (b *OGame) removeConstructionWatcher(w *constructionWatcher, onlyUnused bool) {
+	b.constructionWatchMu.Lock()
+	defer b.constructionWatchMu.Unlock()
+	if b.constructionWatchers[w.celestialID] != w || (onlyUnused && w.callbacks.len() > 0) {
+		return
+	}
+	delete(b.constructionWatchers, w.celestialID)
+	close(w.stopCh)
+	w.cancelRun()
+}
+
+// startConstructionWatcher starts polling the celestial until the bot is disabled, stops the previous run if any.
+// Must be called with constructionWatchMu held
+func (b *OGame) startConstructionWatcher(w *constructionWatcher) {
+	if w.cancelRun != nil {
+		w.cancelRun()
+	}
+	ctx, cancel := context.WithCancel(b.ctx)
+	runDoneCh := make(chan struct{})
+	w.cancelRun, w.runDoneCh = cancel, runDoneCh
+	go func() {
+		defer close(runDoneCh)
+		w.run(ctx)
+	}()
+}
+
+// restartConstructionWatchers starts again the watchers stopped when the bot was disabled, their callbacks are kept
+func (b *OGame) restartConstructionWatchers() {
+	b.constructionWatchMu.Lock()
+	defer b.constructionWatchMu.Unlock()
+	for _, w := range b.constructionWatchers {
+		b.startConstructionWatcher(w)
+	}
+}
+
+// run polls the celestial until the watcher is removed, or ctx is done (the bot is disabled or closed)
+func (w *constructionWatcher) run(ctx context.Context) {
+	for {
+		wait := constructionWatchInterval
+		if w.b.IsLoggedIn() && w.b.isEnabled() {
+			if snapshot, err := w.snapshot(); err == nil {
+				w.update(snapshot)
+				if snapshot.countdown > 0 {
+					wait = time.Duration(snapshot.countdown+1) * time.Second
+				}
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-w.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// snapshot fetches the construction queues using the task runner, with a low priority
+func (w *constructionWatcher) snapshot() (out constructionsSnapshot, err error) {
+	err = w.b.WithPriority(taskRunner.Low).Tx(func(tx Prioritizable) error {
+		buildingID, buildingCountdown, researchID, researchCountdown, lfBuildingID, lfBuildingCountdown, lfResearchID, lfResearchCountdown := tx.ConstructionsBeingBuilt(w.celestialID)
+		queue, shipyardCountdown, err := tx.GetProduction(w.celestialID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		finishAt := func(countdown int64) time.Time { return now.Add(time.Duration(countdown) * time.Second) }
+		out.queues = map[int]construction{
+			queueKindBuilding:   {buildingID, finishAt(buildingCountdown)},
+			queueKindResearch:   {researchID, finishAt(researchCountdown)},
+			queueKindLfBuilding: {lfBuildingID, finishAt(lfBuildingCountdown)},
+			queueKindLfResearch: {lfResearchID, finishAt(lfResearchCountdown)},
+		}
+		if len(queue) > 0 {
+			out.shipyardID = queue[0].ID
+		}
+		out.shipyardLen = len(queue)
+		for _, countdown := range []int64{buildingCountdown, researchCountdown, lfBuildingCountdown, lfResearchCountdown, shipyardCountdown} {
+			if countdown > 0 && (out.countdown == 0 || countdown < out.countdown) {
+				out.countdown = countdown
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// update compares the snapshot with the previous state, and fires callbacks for the ids that are done.
+// The same id finishing later than expected is the next level of that id, the previous one is done.
+func (w *constructionWatcher) update(snapshot constructionsSnapshot) {
+	w.Lock()
+	var finished []ogame.ID
+	for kind, prev := range w.inProgress {
+		curr := snapshot.queues[kind]
+		if prev.ID != 0 && (curr.ID != prev.ID || curr.FinishAt.After(prev.FinishAt.Add(constructionFinishTolerance))) {
+			finished = append(finished, prev.ID)
+		}
+	}
+	if w.shipyardID != 0 && (snapshot.shipyardID != w.shipyardID || snapshot.shipyardLen < w.shipyardLen) {
+		finished = append(finished, w.shipyardID)
+	}
+	w.inProgress = snapshot.queues
+	w.shipyardID = snapshot.shipyardID
+	w.shipyardLen = snapshot.shipyardLen
+	w.Unlock()
+	callbacks := w.callbacks.list()
+	for _, id := range finished {
+		for _, clb := range callbacks {
+			clb(id)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package wrapper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// DarkMatterSpend dark matter spending action performed by the bot
+type DarkMatterSpend struct {
+	Time        time.Time
+	Action      string            // UseDM, RecruitOfficer, BuyItem, RelocatePlanet or TradeResources
+	CelestialID ogame.CelestialID // celestial the action was performed on, 0 if account wide
+	Details     string            // what was bought, eg: "research" or an item ref
+	Amount      int64             // dark matter spent, 0 if the game did not tell
+}
+
+// darkMatterLedger in memory log of the dark matter spending actions
+type darkMatterLedger struct {
+	mu      sync.Mutex
+	entries []DarkMatterSpend
+}
+
+func (l *darkMatterLedger) record(action string, celestialID ogame.CelestialID, details string, amount int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, DarkMatterSpend{
+		Time:        time.Now(),
+		Action:      action,
+		CelestialID: celestialID,
+		Details:     details,
+		Amount:      amount,
+	})
+}
+
+// since returns a copy of the entries recorded at or after t
+func (l *darkMatterLedger) since(t time.Time) []DarkMatterSpend {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]DarkMatterSpend, 0)
+	for _, entry := range l.entries {
+		if !entry.Time.Before(t) {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// GetDarkMatterLedger returns the dark matter spending actions performed by the bot since t, oldest first
+func (b *OGame) GetDarkMatterLedger(since time.Time) []DarkMatterSpend {
+	return b.dmLedger.since(since)
+}
+
+func (b *OGame) getDarkMatter() (int64, error) {
+	res, err := b.fetchResources(0)
+	if err != nil {
+		return 0, err
+	}
+	return res.Darkmatter.Available, nil
+}
@@ -0,0 +1,420 @@
+package wrapper
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// FarmSessionStats statistics of a farming session
+type FarmSessionStats struct {
+	Start         time.Time
+	End           time.Time
+	Targets       int64           // targets of the session
+	ProbesSent    int64           // espionage missions sent
+	Reports       int64           // fresh espionage reports scored
+	RaidsSent     int64           // attack missions sent
+	CargosSent    int64           // cargo ships sent with the raids
+	RecyclesSent  int64           // recycle missions sent on the targets debris fields
+	ExpectedLoot  ogame.Resources // loot expected from the raids sent
+	SkippedNoSlot int64           // targets not spied or raided because no fleet slot was available
+	Errors        int64
+}
+
+// FarmRaid raid planned from a scored espionage report
+type FarmRaid struct {
+	Target ogame.Coordinate
+	Score  float64
+	Loot   ogame.Resources
+	Cargos int64
+	Combat bool // the target has ships or defenses, or the report does not tell, the raid can leave a debris field
+}
+
+// Farmer spies a list of targets (eg: inactive planets), scores the reports, raids the best ones with
+// just enough cargo ships within the free fleet slots, and recycles the debris fields left on the targets
+// it raided.
+//
+//	farmer := wrapper.NewFarmer(bot, planetID, targets)
+//	farmer.SetMinLoot(100000).KeepFreeSlots(2)
+//	stats, _ := farmer.RunSession()
+//	fmt.Println(stats.RaidsSent, stats.ExpectedLoot)
+type Farmer struct {
+	b              Wrapper
+	origin         ogame.CelestialID
+	targets        []ogame.Coordinate
+	cargoID        ogame.ID
+	probes         int64
+	speed          ogame.Speed
+	minLoot        int64
+	keepFreeSlots  int64
+	recycle        bool
+	scorer         TargetScorer
+	interval       time.Duration
+	raided         map[ogame.Coordinate]struct{}
+	sessions       []FarmSessionStats
+	mu             sync.Mutex
+	cancel         context.CancelFunc
+	raidCallbacks  []func(FarmRaid, ogame.Fleet)
+	errorCallbacks []func(ogame.Coordinate, error)
+}
+
+// NewFarmer creates a farmer sending its fleets from origin to the targets
+func NewFarmer(b Wrapper, origin ogame.CelestialID, targets []ogame.Coordinate) *Farmer {
+	return &Farmer{
+		b:        b,
+		origin:   origin,
+		targets:  targets,
+		cargoID:  ogame.SmallCargoID,
+		probes:   1,
+		speed:    ogame.HundredPercent,
+		recycle:  true,
+		scorer:   DefaultTargetScorer{},
+		interval: time.Hour,
+		raided:   make(map[ogame.Coordinate]struct{}),
+	}
+}
+
+// SetTargets replaces the list of targets
+func (f *Farmer) SetTargets(targets []ogame.Coordinate) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets = targets
+	return f
+}
+
+// SetCargo sets the ship used to raid (default SmallCargo)
+func (f *Farmer) SetCargo(id ogame.ID) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cargoID = id
+	return f
+}
+
+// SetProbes sets how many probes are sent to spy a target (default 1)
+func (f *Farmer) SetProbes(nbr int64) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.probes = nbr
+	return f
+}
+
+// SetSpeed sets the speed of the missions (default 100%)
+func (f *Farmer) SetSpeed(speed ogame.Speed) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.speed = speed
+	return f
+}
+
+// SetMinLoot sets the minimum total loot for a target to be raided
+func (f *Farmer) SetMinLoot(minLoot int64) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.minLoot = minLoot
+	return f
+}
+
+// KeepFreeSlots sets how many fleet slots the farmer must leave free
+func (f *Farmer) KeepFreeSlots(nbr int64) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keepFreeSlots = nbr
+	return f
+}
+
+// SetRecycle sets either or not the debris fields of the raided targets are recycled (default true)
+func (f *Farmer) SetRecycle(recycle bool) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recycle = recycle
+	return f
+}
+
+// SetScorer sets the scorer of the targets, targets scored 0 or less are not raided.
+// Default is DefaultTargetScorer, scoring defenceless targets by their loot per hour of flight.
+func (f *Farmer) SetScorer(scorer TargetScorer) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scorer = scorer
+	return f
+}
+
+// SetInterval sets the delay between two sessions when running in the background (default 1h)
+func (f *Farmer) SetInterval(d time.Duration) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interval = d
+	return f
+}
+
+// OnRaid registers a callback executed when a raid is sent
+func (f *Farmer) OnRaid(clb func(FarmRaid, ogame.Fleet)) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.raidCallbacks = append(f.raidCallbacks, clb)
+	return f
+}
+
+// OnError registers a callback executed when a mission cannot be sent or a report cannot be fetched
+func (f *Farmer) OnError(clb func(ogame.Coordinate, error)) *Farmer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errorCallbacks = append(f.errorCallbacks, clb)
+	return f
+}
+
+// Sessions returns the statistics of the sessions run so far, oldest first
+func (f *Farmer) Sessions() []FarmSessionStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FarmSessionStats{}, f.sessions...)
+}
+
+// Start runs a session every interval in the background, until Stop is called
+func (f *Farmer) Start() {
+	f.mu.Lock()
+	if f.cancel != nil {
+		f.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	f.mu.Unlock()
+	go func() {
+		for {
+			_, _ = f.runSession(ctx)
+			f.mu.Lock()
+			interval := f.interval
+			f.mu.Unlock()
+			select {
+			case <-time.After(f.b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background sessions
+func (f *Farmer) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cancel != nil {
+		f.cancel()
+		f.cancel = nil
+	}
+}
+
+// RunSession runs one session: recycles the debris fields of the previously raided targets,
+// spies the targets, waits for the probes, scores the reports and raids the best targets.
+func (f *Farmer) RunSession() (FarmSessionStats, error) {
+	return f.runSession(context.Background())
+}
+
+func (f *Farmer) runSession(ctx context.Context) (FarmSessionStats, error) {
+	f.mu.Lock()
+	targets := append([]ogame.Coordinate{}, f.targets...)
+	origin, cargoID, probes, speed := f.origin, f.cargoID, f.probes, f.speed
+	minLoot, keepFreeSlots, recycle, scorer := f.minLoot, f.keepFreeSlots, f.recycle, f.scorer
+	raided := make([]ogame.Coordinate, 0, len(f.raided))
+	for coord := range f.raided {
+		raided = append(raided, coord)
+	}
+	f.mu.Unlock()
+
+	stats := FarmSessionStats{Start: time.Now(), Targets: int64(len(targets))}
+	defer func() {
+		stats.End = time.Now()
+		f.mu.Lock()
+		f.sessions = append(f.sessions, stats)
+		f.mu.Unlock()
+	}()
+
+	if !f.b.IsLoggedIn() {
+		return stats, ogame.ErrNotLogged
+	}
+	slots := f.b.GetSlots()
+	freeSlots := slots.Total - slots.InUse - keepFreeSlots
+
+	// Recycle the debris left by the previous raids
+	if recycle {
+		for _, coord := range raided {
+			if freeSlots <= 0 {
+				break
+			}
+			sent, empty, err := f.recycleDebris(origin, coord, speed)
+			if err != nil {
+				stats.Errors++
+				f.emitError(coord, err)
+				continue
+			}
+			if sent {
+				stats.RecyclesSent++
+				freeSlots--
+			}
+			// Kept for the next session only if the debris is still waiting for recyclers
+			if sent || empty {
+				f.mu.Lock()
+				delete(f.raided, coord)
+				f.mu.Unlock()
+			}
+		}
+	}
+
+	// Spy the targets
+	var lastArrival time.Time
+	spied := make([]ogame.Coordinate, 0, len(targets))
+	for _, coord := range targets {
+		if freeSlots <= 0 {
+			stats.SkippedNoSlot++
+			continue
+		}
+		fleet, err := f.b.SendFleet(origin, []ogame.Quantifiable{{ID: ogame.EspionageProbeID, Nbr: probes}}, speed, coord, ogame.Spy, ogame.Resources{}, 0, 0)
+		if err != nil {
+			stats.Errors++
+			f.emitError(coord, err)
+			continue
+		}
+		stats.ProbesSent++
+		freeSlots--
+		spied = append(spied, coord)
+		if fleet.ArrivalTime.After(lastArrival) {
+			lastArrival = fleet.ArrivalTime
+		}
+	}
+	if len(spied) == 0 {
+		return stats, nil
+	}
+
+	// Wait for the probes to reach the targets
+	select {
+	case <-time.After(time.Until(lastArrival) + 5*time.Second):
+	case <-ctx.Done():
+		return stats, ctx.Err()
+	}
+
+	// Score the fresh reports
+	characterClass := f.b.CharacterClass()
+	reports := make([]ogame.EspionageReport, 0, len(spied))
+	for _, coord := range spied {
+		report, err := f.b.GetEspionageReportFor(coord)
+		if err != nil {
+			stats.Errors++
+			f.emitError(coord, err)
+			continue
+		}
+		if report.Date.Before(stats.Start.Add(-time.Minute)) {
+			continue
+		}
+		stats.Reports++
+		reports = append(reports, report)
+	}
+	techs := f.b.GetCachedResearch()
+	isCollector := characterClass == ogame.Collector
+	isPioneers := f.b.IsPioneers()
+	originCelestial := f.b.GetCachedCelestial(origin)
+	raids := planFarmRaids(reports, scorer, minLoot, func(report ogame.EspionageReport) TargetInfo {
+		target := NewTargetInfo(report, characterClass)
+		target.Cargos = ogame.CargoShipsNeeded(cargoID, target.Loot.Total(), techs, false, isCollector, isPioneers)
+		if target.Cargos > 0 && originCelestial != nil {
+			var fleet ogame.ShipsInfos
+			fleet.Set(cargoID, target.Cargos)
+			target.FlightTime, target.Fuel = f.b.FlightTime(originCelestial.GetCoordinate(), report.Coordinate, speed, fleet, ogame.Attack)
+		}
+		return target
+	})
+
+	// Raid the best targets with the available cargos
+	ships, err := f.b.GetShips(origin)
+	if err != nil {
+		return stats, err
+	}
+	availableCargos := ships.ByID(cargoID)
+	for _, raid := range raids {
+		if freeSlots <= 0 {
+			stats.SkippedNoSlot++
+			continue
+		}
+		if raid.Cargos > availableCargos {
+			continue
+		}
+		fleet, err := f.b.SendFleet(origin, []ogame.Quantifiable{{ID: cargoID, Nbr: raid.Cargos}}, speed, raid.Target, ogame.Attack, ogame.Resources{}, 0, 0)
+		if err != nil {
+			stats.Errors++
+			f.emitError(raid.Target, err)
+			continue
+		}
+		availableCargos -= raid.Cargos
+		freeSlots--
+		stats.RaidsSent++
+		stats.CargosSent += raid.Cargos
+		stats.ExpectedLoot = stats.ExpectedLoot.Add(raid.Loot)
+		f.mu.Lock()
+		if raid.Combat {
+			f.raided[raid.Target] = struct{}{}
+		}
+		callbacks := f.raidCallbacks
+		f.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(raid, fleet)
+		}
+	}
+	return stats, nil
+}
+
+// recycleDebris sends recyclers to the debris field of coord.
+// sent is false if there is nothing to recycle (empty) or no recycler is available.
+func (f *Farmer) recycleDebris(origin ogame.CelestialID, coord ogame.Coordinate, speed ogame.Speed) (sent, empty bool, err error) {
+	systemInfos, err := f.b.GalaxyInfos(coord.Galaxy, coord.System, SkipCache)
+	if err != nil {
+		return false, false, err
+	}
+	planetInfos := systemInfos.Position(coord.Position)
+	if planetInfos == nil || planetInfos.Debris.RecyclersNeeded <= 0 {
+		return false, true, nil
+	}
+	ships, err := f.b.GetShips(origin)
+	if err != nil {
+		return false, false, err
+	}
+	nbr := utils.MinInt(planetInfos.Debris.RecyclersNeeded, ships.Recycler)
+	if nbr <= 0 {
+		return false, false, nil
+	}
+	where := ogame.Coordinate{Galaxy: coord.Galaxy, System: coord.System, Position: coord.Position, Type: ogame.DebrisType}
+	if _, err := f.b.SendFleet(origin, []ogame.Quantifiable{{ID: ogame.RecyclerID, Nbr: nbr}}, speed, where, ogame.RecycleDebrisField, ogame.Resources{}, 0, 0); err != nil {
+		return false, false, err
+	}
+	return true, false, nil
+}
+
+func (f *Farmer) emitError(coord ogame.Coordinate, err error) {
+	f.mu.Lock()
+	callbacks := f.errorCallbacks
+	f.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(coord, err)
+	}
+}
+
+// planFarmRaids scores the reports and returns the raids worth sending, best score first
+func planFarmRaids(reports []ogame.EspionageReport, scorer TargetScorer, minLoot int64, targetInfo func(ogame.EspionageReport) TargetInfo) []FarmRaid {
+	raids := make([]FarmRaid, 0)
+	for _, report := range reports {
+		target := targetInfo(report)
+		if target.Loot.Total() < minLoot || target.Cargos <= 0 {
+			continue
+		}
+		score := scorer.Score(target)
+		if score <= 0 {
+			continue
+		}
+		raids = append(raids, FarmRaid{Target: report.Coordinate, Score: score, Loot: target.Loot, Cargos: target.Cargos, Combat: target.DefenseValue != 0})
+	}
+	sort.SliceStable(raids, func(i, j int) bool { return raids[i].Score > raids[j].Score })
+	return raids
+}
@@ -98,6 +98,10 @@ func getPage[T parser.FullPagePages](b *OGame, opts ...Option) (T, error) {
 		pageName = MovementPageName
 	case parser.PreferencesPage:
 		pageName = PreferencesPageName
+	case parser.ChatPage:
+		pageName = ChatPageName
+	case parser.PremiumPage:
+		pageName = PremiumPageName
 	default:
 		panic("not implemented")
 	}
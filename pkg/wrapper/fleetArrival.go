@@ -0,0 +1,132 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+const (
+	fleetArrivalRetryDelay    = 2 * time.Second // delay between two verifications when the server is late
+	fleetArrivalMaxRetries    = 10
+	fleetArrivalHoldPollDelay = time.Minute // delay between two verifications of a holding fleet without known back time
+)
+
+func (b *OGame) onFleetArrival(fleetID ogame.FleetID, clb func(ogame.Fleet)) {
+	go b.watchFleetArrival(fleetID, clb)
+}
+
+// watchFleetArrival sleeps until the known arrival time of the fleet, then verifies that the fleet did land
+// (it is now flying back, holding at the destination, or it is no longer listed) before calling clb.
+// Stops once the fleet is back home.
+func (b *OGame) watchFleetArrival(fleetID ogame.FleetID, clb func(ogame.Fleet)) {
+	var fleet ogame.Fleet
+	for {
+		var found bool
+		var err error
+		if fleet, found, err = b.fetchFleetByID(fleetID); err == nil {
+			if !found {
+				return
+			}
+			break
+		}
+		select {
+		case <-time.After(fleetArrivalRetryDelay):
+		case <-b.ctx.Done():
+			return
+		}
+	}
+	retries := 0
+	holding := false
+	fetchFailed := false
+	for {
+		landAt := fleet.ArrivalTime
+		if (holding || fleet.ReturnFlight) && !fleet.BackTime.IsZero() {
+			landAt = fleet.BackTime
+		}
+		wait := time.Until(landAt) + time.Second
+		if holding && fleet.BackTime.IsZero() {
+			wait = fleetArrivalHoldPollDelay
+		}
+		if retries > 0 || fetchFailed {
+			wait = fleetArrivalRetryDelay
+		}
+		select {
+		case <-time.After(wait):
+		case <-b.ctx.Done():
+			return
+		}
+		curr, found, err := b.fetchFleetByID(fleetID)
+		if fetchFailed = err != nil; fetchFailed {
+			continue // skip the tick, the fleet is verified again after the retry delay
+		}
+		var currPtr *ogame.Fleet
+		if found {
+			currPtr = &curr
+		}
+		arrived, nowHolding := fleetArrived(fleet, holding, currPtr, time.Now())
+		if !arrived {
+			if holding && !nowHolding { // hold is over, the fleet flies back
+				fleet, holding, retries = curr, false, 0
+				continue
+			}
+			if holding && fleet.BackTime.IsZero() {
+				continue
+			}
+			if retries++; retries > fleetArrivalMaxRetries {
+				return
+			}
+			continue
+		}
+		retries = 0
+		if !found {
+			clb(fleet)
+			return
+		}
+		clb(curr)
+		fleet, holding = curr, nowHolding
+	}
+}
+
+// fetchFleetByID fetches the fleets with a low priority and returns the one with the given id
+func (b *OGame) fetchFleetByID(fleetID ogame.FleetID) (ogame.Fleet, bool, error) {
+	var fleets []ogame.Fleet
+	err := b.WithPriority(taskRunner.Low).Tx(func(tx Prioritizable) (err error) {
+		fleets, _, err = b.getFleetsErr()
+		return err
+	})
+	if err != nil {
+		return ogame.Fleet{}, false, err
+	}
+	for _, fleet := range fleets {
+		if fleet.ID == fleetID {
+			return fleet, true, nil
+		}
+	}
+	return ogame.Fleet{}, false, nil
+}
+
+// isHoldMission returns true for the missions that stay at the destination before flying back
+func isHoldMission(mission ogame.MissionID) bool {
+	return mission == ogame.Expedition || mission == ogame.ParkInThatAlly
+}
+
+// fleetArrived returns true if the fleet landed between the two fetches, and either or not it is now holding
+// at its destination (expedition, acs defend). holding is the state of prev.
+// curr is nil if the fleet is no longer listed (deployed or back home)
+func fleetArrived(prev ogame.Fleet, holding bool, curr *ogame.Fleet, now time.Time) (arrived, nowHolding bool) {
+	if curr == nil {
+		return true, false
+	}
+	if holding {
+		return false, !curr.ReturnFlight
+	}
+	if curr.ReturnFlight {
+		return !prev.ReturnFlight, false
+	}
+	if isHoldMission(curr.Mission) && !curr.ArrivalTime.IsZero() && !now.Before(curr.ArrivalTime) {
+		return true, true
+	}
+	return false, false
+}
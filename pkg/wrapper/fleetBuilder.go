@@ -238,7 +238,7 @@ func (f *FleetBuilder) sendNow(tx Prioritizable) error {
 	if f.resources.Metal == -1 || f.resources.Crystal == -1 || f.resources.Deuterium == -1 {
 		// Calculate cargo
 		techs := tx.GetResearch()
-		cargoCapacity := f.ships.Cargo(techs, f.b.GetServer().Settings.EspionageProbeRaids == 1, f.b.CharacterClass() == ogame.Collector, f.b.IsPioneers())
+		cargoCapacity := f.ships.Cargo(techs, f.b.ProbeRaids(), f.b.CharacterClass() == ogame.Collector, f.b.IsPioneers())
 		if f.minimumDeuterium <= 0 {
 			planetResources, _ = tx.GetResources(f.origin.GetID())
 		}
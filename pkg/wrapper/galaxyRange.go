@@ -0,0 +1,90 @@
+package wrapper
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// Default GalaxyRange options
+const (
+	defaultGalaxyRangeConcurrency = 3
+	defaultGalaxyRangePacing      = 300 * time.Millisecond
+)
+
+// GalaxyRangeError returned by GalaxyRange when some systems could not be fetched
+type GalaxyRangeError struct {
+	Errors map[int64]error // error of each system that failed
+}
+
+func (e *GalaxyRangeError) Error() string {
+	systems := make([]int64, 0, len(e.Errors))
+	for system := range e.Errors {
+		systems = append(systems, system)
+	}
+	sort.Slice(systems, func(i, j int) bool { return systems[i] < systems[j] })
+	msgs := make([]string, 0, len(systems))
+	for _, system := range systems {
+		msgs = append(msgs, utils.FI64(system)+": "+e.Errors[system].Error())
+	}
+	return "failed to fetch systems " + strings.Join(msgs, ", ")
+}
+
+// galaxyRange fetches the systems [fromSystem, toSystem] of a galaxy, using at most Concurrency parallel requests
+// spaced by at least Pacing. The systems that were fetched are returned sorted, even if some failed.
+func (b *OGame) galaxyRange(galaxy, fromSystem, toSystem int64, opts ...Option) ([]ogame.SystemInfos, error) {
+	cfg := getOptions(opts...)
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultGalaxyRangeConcurrency
+	}
+	pacing := cfg.Pacing
+	if pacing <= 0 {
+		pacing = defaultGalaxyRangePacing
+	}
+	if fromSystem > toSystem {
+		fromSystem, toSystem = toSystem, fromSystem
+	}
+
+	systems := make(chan int64)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	out := make([]ogame.SystemInfos, 0, toSystem-fromSystem+1)
+	errs := make(map[int64]error)
+	ticker := time.NewTicker(pacing)
+	defer ticker.Stop()
+	for i := int64(0); i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for system := range systems {
+				res, err := b.galaxyInfos(galaxy, system, opts...)
+				mu.Lock()
+				if err != nil {
+					errs[system] = err
+				} else {
+					out = append(out, res)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for system := fromSystem; system <= toSystem; system++ {
+		if system > fromSystem {
+			<-ticker.C
+		}
+		systems <- system
+	}
+	close(systems)
+	wg.Wait()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].System() < out[j].System() })
+	if len(errs) > 0 {
+		return out, &GalaxyRangeError{Errors: errs}
+	}
+	return out, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/alaingilbert/ogame/pkg/httpclient"
 	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/publicapi"
 	"github.com/alaingilbert/ogame/pkg/utils"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
@@ -121,12 +122,17 @@ func ValidateAccount(client httpclient.IHttpClient, ctx context.Context, lobby,
 	if err != nil {
 		return err
 	}
+	req.Header.Add("Content-Type", "application/json")
 	req.WithContext(ctx)
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		by, _ := utils.ReadBody(resp)
+		return fmt.Errorf("failed to validate account (%d) : %s", resp.StatusCode, string(by))
+	}
 	return nil
 }
 
@@ -263,13 +269,19 @@ type GFLoginRes struct {
 
 func (r GFLoginRes) GetBearerToken() string { return r.Token }
 
+// GFLogin logs in gameforge without blackbox, see GFLoginWithBlackbox
 func GFLogin(client httpclient.IHttpClient, ctx context.Context, lobby, username, password, otpSecret, challengeID string) (out *GFLoginRes, err error) {
+	return GFLoginWithBlackbox(client, ctx, lobby, username, password, otpSecret, challengeID, "")
+}
+
+// GFLoginWithBlackbox logs in gameforge sending the given blackbox fingerprint token, empty to not send any
+func GFLoginWithBlackbox(client httpclient.IHttpClient, ctx context.Context, lobby, username, password, otpSecret, challengeID, blackbox string) (out *GFLoginRes, err error) {
 	gameEnvironmentID, platformGameID, err := getConfiguration(client, ctx, lobby)
 	if err != nil {
 		return out, err
 	}
 
-	req, err := postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID)
+	req, err := postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID, blackbox)
 	if err != nil {
 		return out, err
 	}
@@ -281,7 +293,11 @@ func GFLogin(client httpclient.IHttpClient, ctx context.Context, lobby, username
 		return out, err
 	}
 	defer resp.Body.Close()
+	return parseGFLoginResp(resp)
+}
 
+// parseGFLoginResp parses the response of a gameforge sessions endpoint
+func parseGFLoginResp(resp *http.Response) (out *GFLoginRes, err error) {
 	by, err := utils.ReadBody(resp)
 	if err != nil {
 		return out, err
@@ -355,7 +371,7 @@ func getConfiguration(client httpclient.IHttpClient, ctx context.Context, lobby
 	return string(gameEnvironmentID), string(platformGameID), nil
 }
 
-func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID string) (*http.Request, error) {
+func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID, blackbox string) (*http.Request, error) {
 	payload := url.Values{
 		"autoGameAccountCreation": {"false"},
 		"gameEnvironmentId":       {gameEnvironmentID},
@@ -365,6 +381,9 @@ func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpS
 		"identity":                {username},
 		"password":                {password},
 	}
+	if blackbox != "" {
+		payload.Set("blackbox", blackbox)
+	}
 	req, err := http.NewRequest(http.MethodPost, "https://gameforge.com/api/v1/auth/thin/sessions", strings.NewReader(payload.Encode()))
 	if err != nil {
 		return nil, err
@@ -375,12 +394,7 @@ func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpS
 	}
 
 	if otpSecret != "" {
-		passcode, err := totp.GenerateCodeCustom(otpSecret, time.Now(), totp.ValidateOpts{
-			Period:    30,
-			Skew:      1,
-			Digits:    otp.DigitsSix,
-			Algorithm: otp.AlgorithmSHA1,
-		})
+		passcode, err := generateOTPCode(otpSecret)
 		if err != nil {
 			return nil, err
 		}
@@ -393,6 +407,16 @@ func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpS
 	return req, nil
 }
 
+// generateOTPCode generates the current two-factor authentication code of otpSecret
+func generateOTPCode(otpSecret string) (string, error) {
+	return totp.GenerateCodeCustom(otpSecret, time.Now(), totp.ValidateOpts{
+		Period:    30,
+		Skew:      1,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+}
+
 func StartCaptchaChallenge(client httpclient.IHttpClient, ctx context.Context, challengeID string) (questionRaw, iconsRaw []byte, err error) {
 	req, err := http.NewRequest(http.MethodGet, "https://challenge.gameforge.com/challenge/"+challengeID, nil)
 	if err != nil {
@@ -494,6 +518,54 @@ type Server struct {
 	}
 }
 
+// GetEconomySpeed returns the economy speed of the server as a number
+func (s Server) GetEconomySpeed() int64 {
+	switch v := s.Settings.EconomySpeed.(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case string:
+		return utils.DoParseI64(strings.TrimPrefix(v, "x"))
+	}
+	return 0
+}
+
+// ServerFilter filter used to select servers from the lobby
+type ServerFilter func(Server) bool
+
+// ServerOpenForRegistration keeps servers where new players can sign up
+func ServerOpenForRegistration() ServerFilter {
+	return func(s Server) bool { return s.SignupClosed == 0 && s.ServerClosed == 0 }
+}
+
+// ServerLanguage keeps servers of the given language
+func ServerLanguage(lang string) ServerFilter {
+	return func(s Server) bool { return s.Language == lang }
+}
+
+// ServerSpeed keeps servers with the given economy speed
+func ServerSpeed(speed int64) ServerFilter {
+	return func(s Server) bool { return s.GetEconomySpeed() == speed }
+}
+
+// FilterServers returns the servers that match all the filters
+func FilterServers(servers []Server, filters ...ServerFilter) []Server {
+	out := make([]Server, 0)
+LOOP:
+	for _, s := range servers {
+		for _, filter := range filters {
+			if !filter(s) {
+				continue LOOP
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
 func GetServers(lobby string, client httpclient.IHttpClient, ctx context.Context) ([]Server, error) {
 	var servers []Server
 	req, err := http.NewRequest(http.MethodGet, "https://"+lobby+".ogame.gameforge.com/api/servers", nil)
@@ -518,44 +590,7 @@ func GetServers(lobby string, client httpclient.IHttpClient, ctx context.Context
 }
 
 // ServerData represent api result from https://s157-ru.ogame.gameforge.com/api/serverData.xml
-type ServerData struct {
-	Name                          string  `xml:"name"`                          // Europa
-	Number                        int64   `xml:"number"`                        // 157
-	Language                      string  `xml:"language"`                      // ru
-	Timezone                      string  `xml:"timezone"`                      // Europe/Moscow
-	TimezoneOffset                string  `xml:"timezoneOffset"`                // +03:00
-	Domain                        string  `xml:"domain"`                        // s157-ru.ogame.gameforge.com
-	Version                       string  `xml:"version"`                       // 6.8.8-pl2
-	Speed                         int64   `xml:"speed"`                         // 6
-	SpeedFleetPeaceful            int64   `xml:"speedFleetPeaceful"`            // 1
-	SpeedFleetWar                 int64   `xml:"speedFleetWar"`                 // 1
-	SpeedFleetHolding             int64   `xml:"speedFleetHolding"`             // 1
-	Galaxies                      int64   `xml:"galaxies"`                      // 4
-	Systems                       int64   `xml:"systems"`                       // 499
-	ACS                           bool    `xml:"acs"`                           // 1
-	RapidFire                     bool    `xml:"rapidFire"`                     // 1
-	DefToTF                       bool    `xml:"defToTF"`                       // 0
-	DebrisFactor                  float64 `xml:"debrisFactor"`                  // 0.5
-	DebrisFactorDef               float64 `xml:"debrisFactorDef"`               // 0
-	RepairFactor                  float64 `xml:"repairFactor"`                  // 0.7
-	NewbieProtectionLimit         int64   `xml:"newbieProtectionLimit"`         // 500000
-	NewbieProtectionHigh          int64   `xml:"newbieProtectionHigh"`          // 50000
-	TopScore                      float64 `xml:"topScore"`                      // 60259362 / 1.0363090034999E+17
-	BonusFields                   int64   `xml:"bonusFields"`                   // 30
-	DonutGalaxy                   bool    `xml:"donutGalaxy"`                   // 1
-	DonutSystem                   bool    `xml:"donutSystem"`                   // 1
-	WfEnabled                     bool    `xml:"wfEnabled"`                     // 1 (WreckField)
-	WfMinimumRessLost             int64   `xml:"wfMinimumRessLost"`             // 150000
-	WfMinimumLossPercentage       int64   `xml:"wfMinimumLossPercentage"`       // 5
-	WfBasicPercentageRepairable   int64   `xml:"wfBasicPercentageRepairable"`   // 45
-	GlobalDeuteriumSaveFactor     float64 `xml:"globalDeuteriumSaveFactor"`     // 0.5
-	Bashlimit                     int64   `xml:"bashlimit"`                     // 0
-	ProbeCargo                    int64   `xml:"probeCargo"`                    // 5
-	ResearchDurationDivisor       int64   `xml:"researchDurationDivisor"`       // 2
-	DarkMatterNewAcount           int64   `xml:"darkMatterNewAcount"`           // 8000
-	CargoHyperspaceTechMultiplier int64   `xml:"cargoHyperspaceTechMultiplier"` // 5
-	SpeedFleet                    int64   `xml:"speedFleet"`                    // 6 // Deprecated in 8.1.0
-}
+type ServerData = publicapi.ServerData
 
 // GetServerData gets the server data from xml api
 func GetServerData(client httpclient.IHttpClient, ctx context.Context, serverNumber int64, serverLang string) (ServerData, error) {
@@ -617,6 +652,9 @@ func GetUserAccounts(client httpclient.IHttpClient, ctx context.Context, lobby,
 		return userAccounts, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return userAccounts, ogame.ErrBearerTokenExpired
+	}
 	by, err := utils.ReadBody(resp)
 	if err != nil {
 		return userAccounts, err
@@ -642,6 +680,9 @@ func GetLoginLink(client httpclient.IHttpClient, ctx context.Context, lobby stri
 		return "", err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", ogame.ErrBearerTokenExpired
+	}
 	by, err := utils.ReadBody(resp)
 	if err != nil {
 		return "", err
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/alaingilbert/ogame/pkg/ogame"
@@ -637,10 +638,11 @@ func BuildHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr"))
 	}
-	if err := bot.Build(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
+	eta, err := bot.Build(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(nil))
+	return c.JSON(http.StatusOK, SuccessResp(eta))
 }
 
 // BuildCancelableHandler ...
@@ -692,10 +694,11 @@ func BuildBuildingHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogame id"))
 	}
-	if err := bot.BuildBuilding(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
+	eta, err := bot.BuildBuilding(ogame.CelestialID(planetID), ogame.ID(ogameID))
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(nil))
+	return c.JSON(http.StatusOK, SuccessResp(eta))
 }
 
 // BuildTechnologyHandler ...
@@ -709,10 +712,11 @@ func BuildTechnologyHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogame id"))
 	}
-	if err := bot.BuildTechnology(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
+	eta, err := bot.BuildTechnology(ogame.CelestialID(planetID), ogame.ID(ogameID))
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(nil))
+	return c.JSON(http.StatusOK, SuccessResp(eta))
 }
 
 // BuildDefenseHandler ...
@@ -910,11 +914,11 @@ func SendFleetHandler(c echo.Context) error {
 				ships = append(ships, ogame.Quantifiable{ID: ogame.ID(shipID), Nbr: nbr})
 			}
 		case "speed":
-			speedInt, err := utils.ParseI64(values[0])
-			if err != nil || speedInt < 0 || speedInt > 10 {
+			speedFloat, err := strconv.ParseFloat(values[0], 64)
+			if err != nil || speedFloat < 0 || speedFloat > 10 {
 				return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid speed"))
 			}
-			speed = ogame.Speed(speedInt)
+			speed = ogame.Speed(speedFloat)
 		case "galaxy":
 			galaxy, err := utils.ParseI64(values[0])
 			if err != nil {
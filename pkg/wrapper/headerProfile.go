@@ -0,0 +1,149 @@
+package wrapper
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"os"
+)
+
+// HeaderProfile user agent and headers of a browser, sent with every game request
+type HeaderProfile struct {
+	Name      string            `json:"name"`
+	UserAgent string            `json:"userAgent"`
+	Headers   map[string]string `json:"headers"`
+}
+
+// HeaderProfiles realistic browser profiles, one of them is picked per account
+var HeaderProfiles = []HeaderProfile{
+	{
+		Name:      "chrome-windows",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+		Headers: map[string]string{
+			"Accept-Language":    "en-US,en;q=0.9",
+			"Sec-Ch-Ua":          `"Google Chrome";v="119", "Chromium";v="119", "Not?A_Brand";v="24"`,
+			"Sec-Ch-Ua-Mobile":   "?0",
+			"Sec-Ch-Ua-Platform": `"Windows"`,
+		},
+	},
+	{
+		Name:      "chrome-macos",
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+		Headers: map[string]string{
+			"Accept-Language":    "en-US,en;q=0.9",
+			"Sec-Ch-Ua":          `"Google Chrome";v="119", "Chromium";v="119", "Not?A_Brand";v="24"`,
+			"Sec-Ch-Ua-Mobile":   "?0",
+			"Sec-Ch-Ua-Platform": `"macOS"`,
+		},
+	},
+	{
+		Name:      "edge-windows",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36 Edg/119.0.0.0",
+		Headers: map[string]string{
+			"Accept-Language":    "en-US,en;q=0.9",
+			"Sec-Ch-Ua":          `"Microsoft Edge";v="119", "Chromium";v="119", "Not?A_Brand";v="24"`,
+			"Sec-Ch-Ua-Mobile":   "?0",
+			"Sec-Ch-Ua-Platform": `"Windows"`,
+		},
+	},
+	{
+		Name:      "firefox-windows",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:120.0) Gecko/20100101 Firefox/120.0",
+		Headers: map[string]string{
+			"Accept-Language": "en-US,en;q=0.5",
+			"DNT":             "1",
+		},
+	},
+	{
+		Name:      "firefox-linux",
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0",
+		Headers: map[string]string{
+			"Accept-Language": "en-US,en;q=0.5",
+		},
+	},
+	{
+		Name:      "safari-macos",
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+		Headers: map[string]string{
+			"Accept-Language": "en-US,en;q=0.9",
+		},
+	},
+}
+
+// HeaderProfileFor returns the profile of account, always the same one for a given account
+func HeaderProfileFor(account string) HeaderProfile {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(account))
+	return HeaderProfiles[h.Sum32()%uint32(len(HeaderProfiles))]
+}
+
+// headerProfileFilename file where the profile is persisted, next to the cookies
+func headerProfileFilename(cookiesFilename string) string {
+	if cookiesFilename == "" {
+		return ""
+	}
+	return cookiesFilename + ".profile.json"
+}
+
+func loadHeaderProfile(filename string) (HeaderProfile, error) {
+	var profile HeaderProfile
+	by, err := os.ReadFile(filename)
+	if err != nil {
+		return profile, err
+	}
+	err = json.Unmarshal(by, &profile)
+	return profile, err
+}
+
+func saveHeaderProfile(filename string, profile HeaderProfile) error {
+	by, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, by, 0600)
+}
+
+// EnableHeaderProfile sends the headers of a browser profile instead of the default user agent.
+// The profile persisted next to the cookies is reused, otherwise the profile of the account is picked and persisted.
+func (b *OGame) EnableHeaderProfile() error {
+	if b.headerProfileFilename != "" {
+		if profile, err := loadHeaderProfile(b.headerProfileFilename); err == nil && profile.UserAgent != "" {
+			b.applyHeaderProfile(profile)
+			return nil
+		}
+	}
+	return b.SetHeaderProfile(HeaderProfileFor(b.Username + "|" + b.Universe + "|" + b.language))
+}
+
+// SetHeaderProfile sets the user agent and headers sent by the bot, the profile is persisted next to the cookies
+func (b *OGame) SetHeaderProfile(profile HeaderProfile) error {
+	b.applyHeaderProfile(profile)
+	if b.headerProfileFilename == "" {
+		return nil
+	}
+	return saveHeaderProfile(b.headerProfileFilename, profile)
+}
+
+// GetHeaderProfile returns the user agent and headers sent by the bot, empty if header profiles are not enabled
+func (b *OGame) GetHeaderProfile() HeaderProfile {
+	b.headerProfileMu.Lock()
+	defer b.headerProfileMu.Unlock()
+	return b.headerProfile
+}
+
+func (b *OGame) applyHeaderProfile(profile HeaderProfile) {
+	b.headerProfileMu.Lock()
+	b.headerProfile = profile
+	b.headerProfileMu.Unlock()
+	if profile.UserAgent != "" {
+		b.client.SetUserAgent(profile.UserAgent)
+	}
+}
+
+// setProfileHeaders adds the headers of the profile to req
+func (b *OGame) setProfileHeaders(req *http.Request) {
+	profile := b.GetHeaderProfile()
+	for k, v := range profile.Headers {
+		req.Header.Set(k, v)
+	}
+}
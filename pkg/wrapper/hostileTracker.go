@@ -0,0 +1,215 @@
+package wrapper
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// HostileEventType kind of event emitted by HostileTracker
+type HostileEventType int
+
+// Hostile events
+const (
+	HostileDetected HostileEventType = iota // a new attack is on its way
+	HostileDelayed                          // the arrival time of an attack changed (eg: a fleet joined the union)
+	HostileRecalled                         // an attack disappeared before its arrival time
+	HostileImpact                           // an attack disappeared after its arrival time
+)
+
+func (t HostileEventType) String() string {
+	switch t {
+	case HostileDetected:
+		return "detected"
+	case HostileDelayed:
+		return "delayed"
+	case HostileRecalled:
+		return "recalled"
+	case HostileImpact:
+		return "impact"
+	}
+	return "unknown"
+}
+
+// hostileArrivalTolerance arrival time changes smaller than this are polling jitter, not a delay
+const hostileArrivalTolerance = 2 * time.Second
+
+// TrackedHostile attack followed by a HostileTracker
+type TrackedHostile struct {
+	Attack      ogame.AttackEvent
+	ArrivalTime time.Time // corrected with the countdown of the last poll
+	FirstSeen   time.Time
+	LastSeen    time.Time
+}
+
+// ArriveIn returns the time left before impact
+func (h TrackedHostile) ArriveIn() time.Duration {
+	return time.Until(h.ArrivalTime)
+}
+
+// HostileEvent change detected between two polls of the attacks
+type HostileEvent struct {
+	Type    HostileEventType
+	Hostile TrackedHostile
+}
+
+// HostileTracker polls the incoming attacks, follows them by id and reports the new ones, the ones recalled
+// before impact and the ones whose arrival time changed. The arrival times are corrected on each poll with the
+// countdown sent by the server, so they do not drift with the local clock.
+//
+//	tracker := wrapper.NewHostileTracker(bot, time.Minute)
+//	tracker.OnEvent(func(e wrapper.HostileEvent) { fmt.Println(e.Type, e.Hostile.Attack.Origin, e.Hostile.ArriveIn()) })
+//	tracker.Start()
+//	defer tracker.Stop()
+type HostileTracker struct {
+	b              Wrapper
+	interval       time.Duration
+	hostiles       map[int64]TrackedHostile
+	mu             sync.Mutex
+	cancel         context.CancelFunc
+	callbacks      []func(HostileEvent)
+	errorCallbacks []func(error)
+}
+
+// NewHostileTracker creates a tracker polling the attacks every interval
+func NewHostileTracker(b Wrapper, interval time.Duration) *HostileTracker {
+	return &HostileTracker{
+		b:        b,
+		interval: interval,
+		hostiles: make(map[int64]TrackedHostile),
+	}
+}
+
+// OnEvent registers a callback executed when an attack is detected, delayed, recalled or hits
+func (t *HostileTracker) OnEvent(clb func(HostileEvent)) *HostileTracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.callbacks = append(t.callbacks, clb)
+	return t
+}
+
+// OnError registers a callback executed when the attacks cannot be fetched
+func (t *HostileTracker) OnError(clb func(error)) *HostileTracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errorCallbacks = append(t.errorCallbacks, clb)
+	return t
+}
+
+// Hostiles returns the attacks on their way, soonest first
+func (t *HostileTracker) Hostiles() []TrackedHostile {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]TrackedHostile, 0, len(t.hostiles))
+	for _, hostile := range t.hostiles {
+		out = append(out, hostile)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ArrivalTime.Before(out[j].ArrivalTime) })
+	return out
+}
+
+// Start starts polling in the background, until Stop is called
+func (t *HostileTracker) Start() {
+	t.mu.Lock()
+	if t.cancel != nil {
+		t.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.mu.Unlock()
+	go func() {
+		for {
+			t.Poll()
+			select {
+			case <-time.After(t.b.HumanizeInterval(t.interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background polling
+func (t *HostileTracker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
+	}
+}
+
+// Poll fetches the attacks once and emits the changes since the previous poll
+func (t *HostileTracker) Poll() {
+	if !t.b.IsLoggedIn() {
+		return
+	}
+	attacks, err := t.b.GetAttacks()
+	if err != nil {
+		t.mu.Lock()
+		callbacks := t.errorCallbacks
+		t.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(err)
+		}
+		return
+	}
+	t.mu.Lock()
+	hostiles, events := trackHostiles(t.hostiles, attacks, time.Now())
+	t.hostiles = hostiles
+	callbacks := t.callbacks
+	t.mu.Unlock()
+	for _, event := range events {
+		for _, clb := range callbacks {
+			clb(event)
+		}
+	}
+}
+
+// trackHostiles returns the attacks tracked after a poll, and the events since the previous poll
+func trackHostiles(prev map[int64]TrackedHostile, attacks []ogame.AttackEvent, now time.Time) (map[int64]TrackedHostile, []HostileEvent) {
+	events := make([]HostileEvent, 0)
+	curr := make(map[int64]TrackedHostile, len(attacks))
+	for _, attack := range attacks {
+		arrivalTime := attack.ArrivalTime
+		if attack.ArriveIn > 0 {
+			arrivalTime = now.Add(time.Duration(attack.ArriveIn) * time.Second)
+		}
+		hostile, known := prev[attack.ID]
+		if !known {
+			hostile.FirstSeen = now
+		}
+		delayed := known && absDuration(arrivalTime.Sub(hostile.ArrivalTime)) > hostileArrivalTolerance
+		hostile.Attack = attack
+		hostile.ArrivalTime = arrivalTime
+		hostile.LastSeen = now
+		curr[attack.ID] = hostile
+		if !known {
+			events = append(events, HostileEvent{Type: HostileDetected, Hostile: hostile})
+		} else if delayed {
+			events = append(events, HostileEvent{Type: HostileDelayed, Hostile: hostile})
+		}
+	}
+	for id, hostile := range prev {
+		if _, ok := curr[id]; ok {
+			continue
+		}
+		typ := HostileImpact
+		if hostile.ArrivalTime.After(now) {
+			typ = HostileRecalled
+		}
+		events = append(events, HostileEvent{Type: typ, Hostile: hostile})
+	}
+	return curr, events
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,149 @@
+package wrapper
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// HumanizeProfile makes the traffic of the bot look less automated.
+// Page requests are spaced by a random delay, polling intervals of the helpers (watchers, farmer...) are
+// randomized, and harmless pages are viewed from time to time between requests.
+//
+//	bot, err := wrapper.NewWithParams(wrapper.Params{..., Humanize: wrapper.DefaultHumanizeProfile()})
+type HumanizeProfile struct {
+	MinDelay       time.Duration // minimum delay between two page requests
+	MaxDelay       time.Duration // maximum delay between two page requests
+	Jitter         float64       // polling intervals are randomized by +/- Jitter (0.2 = 20%)
+	PageViewChance float64       // probability (0-1) to view a harmless page before a request
+	PageViews      []string      // pages viewed, overview/resources/facilities/research/galaxy if empty
+}
+
+// DefaultHumanizeProfile returns a profile with reasonable values
+func DefaultHumanizeProfile() *HumanizeProfile {
+	return &HumanizeProfile{
+		MinDelay:       500 * time.Millisecond,
+		MaxDelay:       3 * time.Second,
+		Jitter:         0.2,
+		PageViewChance: 0.05,
+	}
+}
+
+var defaultHumanizePageViews = []string{OverviewPageName, SuppliesPageName, FacilitiesPageName, ResearchPageName, GalaxyPageName}
+
+// humanizer applies a HumanizeProfile, safe for concurrent use, a nil humanizer does nothing
+type humanizer struct {
+	mu          sync.Mutex
+	profile     *HumanizeProfile
+	rnd         *rand.Rand
+	lastRequest time.Time
+}
+
+func newHumanizer() *humanizer {
+	return &humanizer{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (h *humanizer) setProfile(profile *HumanizeProfile) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.profile = profile
+}
+
+// delay returns how long to wait before the next request, a random delay between MinDelay and MaxDelay
+// minus the time elapsed since the previous request
+func (h *humanizer) delay(now time.Time) time.Duration {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.profile == nil {
+		return 0
+	}
+	d := h.profile.MinDelay
+	if spread := h.profile.MaxDelay - h.profile.MinDelay; spread > 0 {
+		d += time.Duration(h.rnd.Int63n(int64(spread)))
+	}
+	wait := d - now.Sub(h.lastRequest)
+	if wait < 0 {
+		wait = 0
+	}
+	h.lastRequest = now.Add(wait)
+	return wait
+}
+
+// interval randomizes d by +/- Jitter
+func (h *humanizer) interval(d time.Duration) time.Duration {
+	if h == nil {
+		return d
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.profile == nil || h.profile.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + h.profile.Jitter*(2*h.rnd.Float64()-1)))
+}
+
+// pageView returns a page to view before the next request, empty if none
+func (h *humanizer) pageView() string {
+	if h == nil {
+		return ""
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.profile == nil || h.rnd.Float64() >= h.profile.PageViewChance {
+		return ""
+	}
+	pages := h.profile.PageViews
+	if len(pages) == 0 {
+		pages = defaultHumanizePageViews
+	}
+	return pages[h.rnd.Intn(len(pages))]
+}
+
+// humanize waits the humanized delay before a request and views a harmless page from time to time
+func (b *OGame) humanize() error {
+	if page := b.humanizer.pageView(); page != "" {
+		if err := b.humanizeWait(); err != nil {
+			return err
+		}
+		_, _ = b.getPage(page, SkipHumanize, SkipInterceptor)
+	}
+	return b.humanizeWait()
+}
+
+func (b *OGame) humanizeWait() error {
+	wait := b.humanizer.delay(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+	ctx := b.ctx
+	if holder, ok := b.taskCtx.Load().(taskCtxHolder); ok && holder.ctx != nil {
+		ctx = holder.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetHumanizeProfile sets the profile used to humanize the requests, nil to disable
+func (b *OGame) SetHumanizeProfile(profile *HumanizeProfile) {
+	b.humanizer.setProfile(profile)
+}
+
+// HumanizeInterval returns d randomized by the jitter of the humanize profile, d if there is no profile.
+// Used by the helpers to avoid perfectly periodic polling.
+func (b *OGame) HumanizeInterval(d time.Duration) time.Duration {
+	return b.humanizer.interval(d)
+}
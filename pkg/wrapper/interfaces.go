@@ -1,6 +1,7 @@
 package wrapper
 
 import (
+	"context"
 	"crypto/tls"
 	"net/http"
 	"net/url"
@@ -9,6 +10,7 @@ import (
 	"github.com/alaingilbert/ogame/pkg/extractor"
 	"github.com/alaingilbert/ogame/pkg/httpclient"
 	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/publicapi"
 	"github.com/alaingilbert/ogame/pkg/taskRunner"
 )
 
@@ -17,10 +19,10 @@ import (
 type Celestial interface {
 	ogame.Celestial
 	ActivateItem(string) error
-	Build(id ogame.ID, nbr int64) error
-	BuildBuilding(buildingID ogame.ID) error
+	Build(id ogame.ID, nbr int64) (ogame.ConstructionETA, error)
+	BuildBuilding(buildingID ogame.ID) (ogame.ConstructionETA, error)
 	BuildDefense(defenseID ogame.ID, nbr int64) error
-	BuildTechnology(technologyID ogame.ID) error
+	BuildTechnology(technologyID ogame.ID) (ogame.ConstructionETA, error)
 	CancelBuilding() error
 	CancelLfBuilding() error
 	CancelResearch() error
@@ -37,6 +39,7 @@ type Celestial interface {
 	GetResourcesDetails() (ogame.ResourcesDetails, error)
 	GetShips(...Option) (ogame.ShipsInfos, error)
 	GetTechs() (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error)
+	Rename(newName string) error
 	SendFleet([]ogame.Quantifiable, ogame.Speed, ogame.Coordinate, ogame.MissionID, ogame.Resources, int64, int64) (ogame.Fleet, error)
 	TearDown(buildingID ogame.ID) error
 }
@@ -44,76 +47,117 @@ type Celestial interface {
 // Prioritizable list of all actions that needs to communicate with ogame server.
 // These actions can also be prioritized.
 type Prioritizable interface {
-	Abandon(any) error
+	Abandon(v any, opts ...Option) error
 	ActivateItem(string, ogame.CelestialID) error
+	AmortizationPlan(celestialIDs []ogame.CelestialID, rates ogame.Ratio) ([]ogame.Investment, error)
 	Begin() Prioritizable
 	BeginNamed(name string) Prioritizable
+	BeginWithTimeout(d time.Duration) Prioritizable
+	BuyItem(ref string, celestialID ogame.CelestialID) error
 	BuyMarketplace(itemID int64, celestialID ogame.CelestialID) error
 	BuyOfferOfTheDay() error
 	CancelFleet(ogame.FleetID) error
+	ChangeNickname(newNick string) error
 	CollectAllMarketplaceMessages() error
 	CollectMarketplaceMessage(ogame.MarketplaceMessage) error
 	CreateUnion(fleet ogame.Fleet, unionUsers []string) (int64, error)
 	DeleteAllMessagesFromTab(tabID ogame.MessagesTabID) error
 	DeleteMessage(msgID int64) error
+	DeleteMessages(tabID ogame.MessagesTabID, filter func(ogame.Message) bool) (int64, error)
+	DestroyMoon(celestialID ogame.CelestialID, target ogame.Coordinate, deathstars int64) (ogame.Fleet, ogame.MoonDestructionChance, error)
 	DoAuction(bid map[ogame.CelestialID]ogame.Resources) error
+	DoAuctionWS(auction ogame.Auction, bid map[ogame.CelestialID]ogame.Resources) error
 	Done()
+	EmpireSnapshot() (ogame.EmpireSnapshot, error)
 	FlightTime(origin, destination ogame.Coordinate, speed ogame.Speed, ships ogame.ShipsInfos, mission ogame.MissionID) (secs, fuel int64)
+	ForecastResources(celestialID ogame.CelestialID, at time.Time) (ogame.Resources, error)
 	GalaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemInfos, error)
+	GalaxyRange(galaxy, fromSystem, toSystem int64, opts ...Option) ([]ogame.SystemInfos, error)
 	GetActiveItems(ogame.CelestialID) ([]ogame.ActiveItem, error)
 	GetAllResources() (map[ogame.CelestialID]ogame.Resources, error)
 	GetAttacks(...Option) ([]ogame.AttackEvent, error)
 	GetAuction() (ogame.Auction, error)
 	GetCachedResearch() ogame.Researches
+	GetCachedTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error)
 	GetCelestial(any) (Celestial, error)
 	GetCelestials() ([]Celestial, error)
+	GetChatContacts() ([]ogame.ChatContact, error)
+	GetChatHistory(playerID, beforeID int64) ([]ogame.ChatMsg, error)
 	GetCombatReportSummaryFor(ogame.Coordinate) (ogame.CombatReportSummary, error)
+	GetDarkMatter() (int64, error)
 	GetDMCosts(ogame.CelestialID) (ogame.DMCosts, error)
 	GetEmpire(ogame.CelestialType) ([]ogame.EmpireCelestial, error)
 	GetEmpireJSON(nbr int64) (any, error)
 	GetEspionageReport(msgID int64) (ogame.EspionageReport, error)
-	GetEspionageReportFor(ogame.Coordinate) (ogame.EspionageReport, error)
-	GetEspionageReportMessages() ([]ogame.EspionageReportSummary, error)
+	GetEspionageReportFor(ogame.Coordinate, ...Option) (ogame.EspionageReport, error)
+	GetEspionageReportMessages(...Option) ([]ogame.EspionageReportSummary, error)
+	GetEventList(...Option) ([]ogame.FleetEvent, error)
+	GetExpeditionFleet(cargoID ogame.ID, withPathfinder bool) (ogame.ShipsInfos, error)
 	GetExpeditionMessageAt(time.Time) (ogame.ExpeditionMessage, error)
-	GetExpeditionMessages() ([]ogame.ExpeditionMessage, error)
+	GetExpeditionMessages(...Option) ([]ogame.ExpeditionMessage, error)
+	GetExpeditionStats(since time.Time) (ogame.ExpeditionStats, error)
 	GetFleets(...Option) ([]ogame.Fleet, ogame.Slots)
 	GetFleetsFromEventList() []ogame.Fleet
+	GetFullHighscore(category, typ int64) (ogame.FullHighscore, error)
+	GetHighscorePosition(category, typ, playerID int64) (ogame.HighscorePlayer, error)
+	GetInventory(celestialID ogame.CelestialID) ([]ogame.ShopItem, error)
 	GetItems(ogame.CelestialID) ([]ogame.Item, error)
+	GetMarketplaceOffers(tab ogame.MarketplaceTab, celestialID ogame.CelestialID) ([]ogame.MarketplaceOffer, error)
+	GetMessagesPage(tabID ogame.MessagesTabID, page int64) ([]ogame.Message, int64, error)
 	GetMoon(any) (Moon, error)
 	GetMoons() []Moon
+	GetOfferOfTheDay() (ogame.OfferOfTheDay, error)
+	GetOfficers() ([]ogame.Officer, error)
+	GetOtherMessages(...Option) ([]ogame.OtherMessage, error)
 	GetPageContent(url.Values) ([]byte, error)
+	GetPhalanxCoverage() (ogame.PhalanxCoverage, error)
 	GetPlanet(any) (Planet, error)
+	GetPlanetInfos(celestialID ogame.CelestialID) (Celestial, error)
 	GetPlanets() []Planet
 	GetResearch() ogame.Researches
+	GetShopItems(celestialID ogame.CelestialID) ([]ogame.ShopItem, error)
 	GetSlots() ogame.Slots
+	GetTransportMessages(...Option) ([]ogame.UnionsTransportMessage, error)
 	GetUserInfos() ogame.UserInfos
+	HarvestExpeditionDebris(celestialID ogame.CelestialID, galaxy, system int64, speed ogame.Speed) (ogame.Fleet, error)
 	HeadersForPage(url string) (http.Header, error)
 	Highscore(category, typ, page int64) (ogame.Highscore, error)
 	IsUnderAttack() (bool, error)
+	JoinACS(celestialID ogame.CelestialID, invitation ogame.ACSInvitation, ships []ogame.Quantifiable, speed ogame.Speed) (ogame.Fleet, error)
 	Login() error
 	LoginWithBearerToken(token string) (bool, error)
 	LoginWithExistingCookies() (bool, error)
 	Logout()
 	OfferBuyMarketplace(itemID any, quantity, priceType, price, priceRange int64, celestialID ogame.CelestialID) error
 	OfferSellMarketplace(itemID any, quantity, priceType, price, priceRange int64, celestialID ogame.CelestialID) error
+	PanicSave(celestialID ogame.CelestialID, policy PanicSavePolicy) (ogame.Fleet, error)
 	PostPageContent(url.Values, url.Values) ([]byte, error)
 	RecruitOfficer(typ, days int64) error
+	RefreshCaches() error
+	RefreshServerData() (ServerData, error)
+	RefreshToken() (string, error)
+	RerollOfferOfTheDay(keep func(ogame.OfferOfTheDayItem) bool) (ogame.OfferOfTheDayItem, error)
 	SendMessage(playerID int64, message string) error
 	SendMessageAlliance(associationID int64, message string) error
 	ServerTime() time.Time
 	SetInitiator(initiator string) Prioritizable
 	SetVacationMode() error
+	TearDownPlan(celestialID ogame.CelestialID, opts ...Option) ([]ogame.TearDownStep, error)
+	TradeResources(celestialID ogame.CelestialID, give, want ogame.Resources) error
 	Tx(clb func(tx Prioritizable) error) error
+	TxCtx(ctx context.Context, clb func(tx Prioritizable) error) error
+	UnsetVacationMode() error
 	UseDM(string, ogame.CelestialID) error
 
 	// Planet or Moon functions
-	Build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) error
-	BuildBuilding(celestialID ogame.CelestialID, buildingID ogame.ID) error
+	Build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) (ogame.ConstructionETA, error)
+	BuildBuilding(celestialID ogame.CelestialID, buildingID ogame.ID) (ogame.ConstructionETA, error)
 	BuildCancelable(ogame.CelestialID, ogame.ID) error
 	BuildDefense(celestialID ogame.CelestialID, defenseID ogame.ID, nbr int64) error
 	BuildProduction(celestialID ogame.CelestialID, id ogame.ID, nbr int64) error
+	BuildProductionWithProgress(ctx context.Context, celestialID ogame.CelestialID, id ogame.ID, nbr int64, clb func(ogame.BuildProgress)) error
 	BuildShips(celestialID ogame.CelestialID, shipID ogame.ID, nbr int64) error
-	BuildTechnology(celestialID ogame.CelestialID, technologyID ogame.ID) error
+	BuildTechnology(celestialID ogame.CelestialID, technologyID ogame.ID) (ogame.ConstructionETA, error)
 	CancelBuilding(ogame.CelestialID) error
 	CancelLfBuilding(ogame.CelestialID) error
 	CancelResearch(ogame.CelestialID) error
@@ -123,21 +167,29 @@ type Prioritizable interface {
 	GetFacilities(ogame.CelestialID, ...Option) (ogame.Facilities, error)
 	GetLfBuildings(ogame.CelestialID, ...Option) (ogame.LfBuildings, error)
 	GetLfResearch(ogame.CelestialID, ...Option) (ogame.LfResearches, error)
+	GetMissiles(celestialID ogame.CelestialID) (ogame.Missiles, error)
 	GetProduction(ogame.CelestialID) ([]ogame.Quantifiable, int64, error)
 	GetResources(ogame.CelestialID) (ogame.Resources, error)
 	GetResourcesBuildings(ogame.CelestialID, ...Option) (ogame.ResourcesBuildings, error)
 	GetResourcesDetails(ogame.CelestialID) (ogame.ResourcesDetails, error)
 	GetShips(ogame.CelestialID, ...Option) (ogame.ShipsInfos, error)
+	GetStorageCapacity(celestialID ogame.CelestialID, options ...Option) (ogame.Resources, error)
+	GetTechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechnologyDetails, error)
 	GetTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error)
+	GetTechTree(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechTreeNode, error)
+	RenamePlanet(celestialID ogame.CelestialID, newName string) error
 	SendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error)
 	TearDown(celestialID ogame.CelestialID, id ogame.ID) error
 	TechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechnologyDetails, error)
 
 	// Planet specific functions
+	CancelPlanetRelocation(planetID ogame.PlanetID) error
 	DestroyRockets(ogame.PlanetID, int64, int64) error
+	GetPlanetRelocation(planetID ogame.PlanetID) (ogame.PlanetRelocation, error)
 	GetResourceSettings(ogame.PlanetID, ...Option) (ogame.ResourceSettings, error)
 	GetResourcesProductions(ogame.PlanetID) (ogame.Resources, error)
-	GetResourcesProductionsLight(ogame.ResourcesBuildings, ogame.Researches, ogame.ResourceSettings, ogame.Temperature) ogame.Resources
+	GetResourcesProductionsLight(ogame.ResourcesBuildings, ogame.Researches, ogame.ResourceSettings, ogame.Temperature, ogame.ProductionBonuses) ogame.Resources
+	RelocatePlanet(planetID ogame.PlanetID, dest ogame.Coordinate) (ogame.PlanetRelocation, error)
 	SendIPM(ogame.PlanetID, ogame.Coordinate, int64, ogame.ID) (int64, error)
 	SetResourceSettings(ogame.PlanetID, ogame.ResourceSettings) error
 
@@ -152,13 +204,23 @@ type Prioritizable interface {
 type Wrapper interface {
 	Prioritizable
 	AddAccount(number int, lang string) (*AddAccountRes, error)
+	BestSpeedFor(origin, destination ogame.Coordinate, ships ogame.ShipsInfos, missionID ogame.MissionID, arriveBy time.Time) (speed ogame.Speed, secs, fuel int64, err error)
 	BytesDownloaded() int64
 	BytesUploaded() int64
+	CancelTask(id int64) error
+	CargoCapacity(ships ogame.ShipsInfos) int64
+	CargoShipsNeeded(id ogame.ID, amount int64) int64
 	CharacterClass() ogame.CharacterClass
+	ColonyScorer(weights ogame.ColonyScoreWeights) ogame.ColonyScorer
+	ConnectChat() error
 	ConstructionTime(id ogame.ID, nbr int64, facilities ogame.Facilities) time.Duration
 	Disable()
+	DisconnectChat()
 	Distance(origin, destination ogame.Coordinate) int64
+	Drain()
 	Enable()
+	EnableHeaderProfile() error
+	ExportState() ([]byte, error)
 	FleetDeutSaveFactor() float64
 	GetCachedCelestial(any) Celestial
 	GetCachedCelestials() []Celestial
@@ -166,10 +228,17 @@ type Wrapper interface {
 	GetCachedPlanets() []Planet
 	GetCachedPlayer() ogame.UserInfos
 	GetCachedPreferences() ogame.Preferences
+	GetBearerToken() string
+	GetBearerTokenExpiry() time.Time
 	GetClient() *httpclient.Client
+	GetDarkMatterLedger(since time.Time) []DarkMatterSpend
 	GetExtractor() extractor.Extractor
+	GetHeaderProfile() HeaderProfile
 	GetLanguage() string
+	GetLobbyAccounts() ([]Account, error)
+	GetLobbyServers(...ServerFilter) ([]Server, error)
 	GetNbSystems() int64
+	GetPublicAPI() *publicapi.Client
 	GetPublicIP() (string, error)
 	GetResearchSpeed() int64
 	GetServer() Server
@@ -177,10 +246,15 @@ type Wrapper interface {
 	GetSession() string
 	GetState() (bool, string)
 	GetTasks() taskRunner.TasksOverview
+	GetTasksDetails() []taskRunner.TaskInfo
 	GetUniverseName() string
 	GetUniverseSpeed() int64
 	GetUniverseSpeedFleet() int64
 	GetUsername() string
+	HumanizeInterval(d time.Duration) time.Duration
+	ImportState(data []byte) error
+	InvalidateCache(kind CacheKind)
+	InvalidateTechs(celestialID ogame.CelestialID)
 	IsConnected() bool
 	IsDonutGalaxy() bool
 	IsDonutSystem() bool
@@ -191,23 +265,58 @@ type Wrapper interface {
 	IsV7() bool
 	IsV9() bool
 	IsVacationModeEnabled() bool
+	JoinServer(number int, lang string) (*AddAccountRes, error)
 	Location() *time.Location
+	MaxSlots(items ogame.SlotsBonus) ogame.Slots
+	MoonshotShips(id ogame.ID, chance float64) int64
+	OnCacheChange(clb func(CacheEvent))
+	OnConstructionFinished(celestialID ogame.CelestialID, clb func(id ogame.ID)) *Subscription
+	OnFleetArrival(fleetID ogame.FleetID, clb func(ogame.Fleet))
+	OnChatDisconnected(clb func(err error))
+	OnLoginFailure(clb func(err error))
+	OnLoginSuccess(clb func())
+	OnLogout(clb func())
+	OnRelogin(clb func())
 	OnStateChange(clb func(locked bool, actor string))
+	OnTokenRefreshed(clb func(token string))
+	OnTxExpired(clb func(name string))
+	OnVersionChanged(clb func(oldVersion, newVersion string))
+	ProbeRaids() bool
 	Quiet(bool)
 	ReconnectChat() bool
-	RegisterAuctioneerCallback(func(any))
-	RegisterChatCallback(func(ogame.ChatMsg))
-	RegisterHTMLInterceptor(func(method, url string, params, payload url.Values, pageHTML []byte))
+	Register(email, password, lang string) error
+	RegisterAuctioneerCallback(func(any)) *Subscription
+	RegisterChatCallback(func(ogame.ChatMsg)) *Subscription
+	RegisterHTMLInterceptor(func(method, url string, params, payload url.Values, pageHTML []byte)) *Subscription
 	RegisterWSCallback(string, func([]byte))
 	RemoveWSCallback(string)
+	ResearchDuration(id ogame.ID, level, researchLab int64) time.Duration
+	RunStrategies(strategies ...Strategy) *StrategyRunner
 	ServerURL() string
 	ServerVersion() string
+	SetBlackboxProvider(provider BlackboxProvider)
+	SetCache(Cache)
+	SetCacheTTL(kind CacheKind, ttl time.Duration)
+	SetChatReconnectPolicy(policy ChatReconnectPolicy)
 	SetClient(*httpclient.Client)
 	SetGetServerDataWrapper(func(func() (ServerData, error)) (ServerData, error))
+	SetHeaderProfile(profile HeaderProfile) error
+	SetHumanizeProfile(profile *HumanizeProfile)
+	SetLoginPolicy(policy LoginPolicy)
+	SetLoginStrategy(strategy LoginStrategy)
 	SetLoginWrapper(func(func() (bool, error)) error)
 	SetOGameCredentials(username, password, otpSecret, bearerToken string)
 	SetProxy(proxyAddress, username, password, proxyType string, loginOnly bool, config *tls.Config) error
+	SetReportStore(*ReportStore)
+	SetSlotManager(*SlotManager)
+	SetTaskAging(d time.Duration)
 	SetUserAgent(newUserAgent string)
+	StartServerDataRefresher(interval time.Duration)
+	StopServerDataRefresher()
+	SubscribeAuctioneer() (<-chan ogame.AuctioneerEvent, *Subscription)
+	SubscribeMessages(tabs ...ogame.MessagesTabID) *MessageSubscription
+	TechsUpdatedAt(celestialID ogame.CelestialID) time.Time
+	Use(mw Middleware)
 	ValidateAccount(code string) error
 	WithPriority(priority taskRunner.Priority) Prioritizable
 }
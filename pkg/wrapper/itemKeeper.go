@@ -0,0 +1,195 @@
+package wrapper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// ItemKeeper keeps selected items (eg: boosters) permanently active on chosen celestials,
+// re-activating them from the inventory when they are about to expire.
+//
+//	keeper := wrapper.NewItemKeeper(bot).
+//		Keep(planetID, bronzeCrystalBoosterRef).
+//		OnOutOfStock(func(celestialID ogame.CelestialID, ref string) { fmt.Println("no more", ref) })
+//	keeper.Start()
+//	defer keeper.Stop()
+type ItemKeeper struct {
+	b                   Wrapper
+	targets             []itemKeeperTarget
+	renewBefore         time.Duration
+	checkInterval       time.Duration
+	mu                  sync.Mutex
+	cancel              context.CancelFunc
+	activatedCallbacks  []func(ogame.CelestialID, string)
+	outOfStockCallbacks []func(ogame.CelestialID, string)
+	errorCallbacks      []func(error)
+}
+
+type itemKeeperTarget struct {
+	celestialID ogame.CelestialID
+	ref         string
+}
+
+// NewItemKeeper creates a new item keeper
+func NewItemKeeper(b Wrapper) *ItemKeeper {
+	return &ItemKeeper{b: b, renewBefore: 10 * time.Minute, checkInterval: 10 * time.Minute}
+}
+
+// Keep keeps the item ref active on the celestial
+func (k *ItemKeeper) Keep(celestialID ogame.CelestialID, ref string) *ItemKeeper {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.targets = append(k.targets, itemKeeperTarget{celestialID, ref})
+	return k
+}
+
+// SetRenewBefore sets how long before expiry an item gets activated again
+func (k *ItemKeeper) SetRenewBefore(d time.Duration) *ItemKeeper {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.renewBefore = d
+	return k
+}
+
+// SetCheckInterval sets how often the active items are checked
+func (k *ItemKeeper) SetCheckInterval(d time.Duration) *ItemKeeper {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.checkInterval = d
+	return k
+}
+
+// OnActivated registers a callback executed when an item is activated
+func (k *ItemKeeper) OnActivated(clb func(celestialID ogame.CelestialID, ref string)) *ItemKeeper {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.activatedCallbacks = append(k.activatedCallbacks, clb)
+	return k
+}
+
+// OnOutOfStock registers a callback executed when an item needs to be activated but none is left in the inventory
+func (k *ItemKeeper) OnOutOfStock(clb func(celestialID ogame.CelestialID, ref string)) *ItemKeeper {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.outOfStockCallbacks = append(k.outOfStockCallbacks, clb)
+	return k
+}
+
+// OnError registers a callback executed when a check fails
+func (k *ItemKeeper) OnError(clb func(error)) *ItemKeeper {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.errorCallbacks = append(k.errorCallbacks, clb)
+	return k
+}
+
+// Start starts checking items in the background, until Stop is called
+func (k *ItemKeeper) Start() {
+	k.mu.Lock()
+	if k.cancel != nil {
+		k.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	k.cancel = cancel
+	k.mu.Unlock()
+	go func() {
+		for {
+			if err := k.Check(); err != nil {
+				k.mu.Lock()
+				callbacks := k.errorCallbacks
+				k.mu.Unlock()
+				for _, clb := range callbacks {
+					clb(err)
+				}
+			}
+			k.mu.Lock()
+			interval := k.checkInterval
+			k.mu.Unlock()
+			select {
+			case <-time.After(k.b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks
+func (k *ItemKeeper) Stop() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.cancel != nil {
+		k.cancel()
+		k.cancel = nil
+	}
+}
+
+// Check activates the kept items that are not active or about to expire
+func (k *ItemKeeper) Check() error {
+	if !k.b.IsLoggedIn() {
+		return nil
+	}
+	k.mu.Lock()
+	targets := append([]itemKeeperTarget{}, k.targets...)
+	renewBefore := k.renewBefore
+	k.mu.Unlock()
+	activeItems := make(map[ogame.CelestialID][]ogame.ActiveItem)
+	for _, target := range targets {
+		active, ok := activeItems[target.celestialID]
+		if !ok {
+			var err error
+			if active, err = k.b.GetActiveItems(target.celestialID); err != nil {
+				return err
+			}
+			activeItems[target.celestialID] = active
+		}
+		if !itemNeedsActivation(active, target.ref, renewBefore) {
+			continue
+		}
+		inventory, err := k.b.GetInventory(target.celestialID)
+		if err != nil {
+			return err
+		}
+		if !inventoryHas(inventory, target.ref) {
+			k.emit(k.outOfStockCallbacks, target)
+			continue
+		}
+		if err := k.b.ActivateItem(target.ref, target.celestialID); err != nil {
+			return err
+		}
+		k.emit(k.activatedCallbacks, target)
+	}
+	return nil
+}
+
+func (k *ItemKeeper) emit(callbacks []func(ogame.CelestialID, string), target itemKeeperTarget) {
+	k.mu.Lock()
+	callbacks = append([]func(ogame.CelestialID, string){}, callbacks...)
+	k.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(target.celestialID, target.ref)
+	}
+}
+
+// itemNeedsActivation either or not ref is not active, or will expire within renewBefore
+func itemNeedsActivation(active []ogame.ActiveItem, ref string, renewBefore time.Duration) bool {
+	for _, item := range active {
+		if item.Ref == ref && time.Duration(item.TimeRemaining)*time.Second > renewBefore {
+			return false
+		}
+	}
+	return true
+}
+
+func inventoryHas(inventory []ogame.ShopItem, ref string) bool {
+	for _, item := range inventory {
+		if item.Ref == ref && item.Amount > 0 {
+			return true
+		}
+	}
+	return false
+}
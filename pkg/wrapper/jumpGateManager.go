@@ -0,0 +1,162 @@
+package wrapper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// JumpGateJump a jump waiting in the JumpGateManager queue
+type JumpGateJump struct {
+	Origin      ogame.MoonID
+	Destination ogame.MoonID
+	Ships       ogame.ShipsInfos
+	ExecuteAt   time.Time
+}
+
+// JumpGateManager remembers when the jump gates were last used and can queue jumps
+// to be executed as soon as both gates are recharged.
+//
+//	manager := wrapper.NewJumpGateManager(bot)
+//	manager.OnJump(func(jump wrapper.JumpGateJump) { fmt.Println("jumped", jump.Origin, jump.Destination) })
+//	manager.Queue(moon1, moon2, ogame.ShipsInfos{LargeCargo: 100})
+type JumpGateManager struct {
+	b              Wrapper
+	mu             sync.Mutex
+	availableAt    map[ogame.MoonID]time.Time
+	timers         map[*time.Timer]struct{}
+	jumpCallbacks  []func(JumpGateJump)
+	errorCallbacks []func(JumpGateJump, error)
+}
+
+// NewJumpGateManager creates a jump gate manager
+func NewJumpGateManager(b Wrapper) *JumpGateManager {
+	return &JumpGateManager{
+		b:           b,
+		availableAt: make(map[ogame.MoonID]time.Time),
+		timers:      make(map[*time.Timer]struct{}),
+	}
+}
+
+// OnJump registers a callback executed when a queued jump is executed
+func (m *JumpGateManager) OnJump(clb func(JumpGateJump)) *JumpGateManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jumpCallbacks = append(m.jumpCallbacks, clb)
+	return m
+}
+
+// OnError registers a callback executed when a queued jump fails
+func (m *JumpGateManager) OnError(clb func(JumpGateJump, error)) *JumpGateManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errorCallbacks = append(m.errorCallbacks, clb)
+	return m
+}
+
+// NextAvailableAt returns when the jump gate of the moon is recharged, zero time if it is ready or never used
+func (m *JumpGateManager) NextAvailableAt(moonID ogame.MoonID) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	availableAt := m.availableAt[moonID]
+	if !availableAt.After(time.Now()) {
+		return time.Time{}
+	}
+	return availableAt
+}
+
+// Refresh updates the recharge countdown of a moon using the countdown displayed by the game
+func (m *JumpGateManager) Refresh(moonID ogame.MoonID) error {
+	_, wait, err := m.b.JumpGateDestinations(moonID)
+	m.setWait(moonID, wait)
+	if wait > 0 {
+		return nil
+	}
+	return err
+}
+
+// Jump executes a jump and remembers the recharge time of both gates
+func (m *JumpGateManager) Jump(origin, dest ogame.MoonID, ships ogame.ShipsInfos) (bool, int64, error) {
+	success, wait, err := m.b.JumpGate(origin, dest, ships)
+	if wait > 0 {
+		m.setWait(origin, wait)
+	}
+	if !success || err != nil {
+		return success, wait, err
+	}
+	now := time.Now()
+	for _, moonID := range []ogame.MoonID{origin, dest} {
+		recharge := ogame.JumpGateRechargeTime(1)
+		if facilities, err := m.b.GetFacilities(moonID.Celestial()); err == nil {
+			recharge = ogame.JumpGateRechargeTime(facilities.JumpGate)
+		}
+		m.mu.Lock()
+		m.availableAt[moonID] = now.Add(recharge)
+		m.mu.Unlock()
+	}
+	return success, wait, err
+}
+
+// Queue executes the jump as soon as both jump gates are recharged
+func (m *JumpGateManager) Queue(origin, dest ogame.MoonID, ships ogame.ShipsInfos) JumpGateJump {
+	executeAt := m.NextAvailableAt(origin)
+	if destAt := m.NextAvailableAt(dest); destAt.After(executeAt) {
+		executeAt = destAt
+	}
+	jump := JumpGateJump{Origin: origin, Destination: dest, Ships: ships, ExecuteAt: executeAt}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(executeAt), func() {
+		m.mu.Lock()
+		delete(m.timers, timer)
+		m.mu.Unlock()
+		m.execute(jump)
+	})
+	m.timers[timer] = struct{}{}
+	return jump
+}
+
+// Stop cancels all the queued jumps
+func (m *JumpGateManager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for timer := range m.timers {
+		timer.Stop()
+	}
+	m.timers = make(map[*time.Timer]struct{})
+}
+
+func (m *JumpGateManager) execute(jump JumpGateJump) {
+	if _, wait, err := m.Jump(jump.Origin, jump.Destination, jump.Ships); err != nil {
+		if wait > 0 {
+			// Gate was used by someone else in the meantime, try again once recharged
+			m.Queue(jump.Origin, jump.Destination, jump.Ships)
+			return
+		}
+		m.mu.Lock()
+		callbacks := m.errorCallbacks
+		m.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(jump, err)
+		}
+		return
+	}
+	m.mu.Lock()
+	callbacks := m.jumpCallbacks
+	m.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(jump)
+	}
+}
+
+func (m *JumpGateManager) setWait(moonID ogame.MoonID, wait int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if wait > 0 {
+		m.availableAt[moonID] = time.Now().Add(time.Duration(wait) * time.Second)
+	} else {
+		delete(m.availableAt, moonID)
+	}
+}
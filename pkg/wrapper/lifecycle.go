@@ -0,0 +1,45 @@
+package wrapper
+
+// OnLoginSuccess registers a callback called every time the bot logs in, including the automatic re-logins
+func (b *OGame) OnLoginSuccess(clb func()) {
+	b.loginSuccessCallbacks = append(b.loginSuccessCallbacks, clb)
+}
+
+// OnLoginFailure registers a callback called when a login fails (eg: ogame.ErrAccountBlocked, ogame.ErrBadCredentials)
+func (b *OGame) OnLoginFailure(clb func(err error)) {
+	b.loginFailureCallbacks = append(b.loginFailureCallbacks, clb)
+}
+
+// OnRelogin registers a callback called when the bot logs in again automatically after being logged out
+func (b *OGame) OnRelogin(clb func()) {
+	b.reloginCallbacks = append(b.reloginCallbacks, clb)
+}
+
+// OnLogout registers a callback called when the bot logs out
+func (b *OGame) OnLogout(clb func()) {
+	b.logoutCallbacks = append(b.logoutCallbacks, clb)
+}
+
+func (b *OGame) loginSucceeded() {
+	for _, clb := range b.loginSuccessCallbacks {
+		clb()
+	}
+}
+
+func (b *OGame) loginFailed(err error) {
+	for _, clb := range b.loginFailureCallbacks {
+		clb(err)
+	}
+}
+
+func (b *OGame) reloggedIn() {
+	for _, clb := range b.reloginCallbacks {
+		clb()
+	}
+}
+
+func (b *OGame) loggedOut() {
+	for _, clb := range b.logoutCallbacks {
+		clb()
+	}
+}
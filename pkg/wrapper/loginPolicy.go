@@ -0,0 +1,121 @@
+package wrapper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// LoginPolicy controls how requests are retried and how the bot re-logins automatically when it gets logged out.
+//
+//	bot.SetLoginPolicy(wrapper.LoginPolicy{
+//		MaxAttempts:        5,
+//		MaxCaptchaFailures: 3,
+//		OnBlocked:          func(err error) { notify("bot stopped: " + err.Error()) },
+//	})
+type LoginPolicy struct {
+	MaxAttempts        int64                             // attempts of a request before giving up, 10 if not set
+	Backoff            func(attempt int64) time.Duration // delay before retrying a request, exponential from 1s to 60s if nil
+	MaxCaptchaFailures int64                             // auto re-login stops after that many consecutive captcha failures, 0 to never stop
+	OnCaptcha          func(failures int64, err error)   // called when an auto re-login fails because of a captcha
+	OnBlocked          func(err error)                   // called when the auto re-login stops (account blocked, bad credentials, too many captcha failures...)
+}
+
+func (p LoginPolicy) maxAttempts() int64 {
+	if p.MaxAttempts <= 0 {
+		return 10
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the given retry attempt (starting at 1)
+func (p LoginPolicy) backoff(attempt int64) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(attempt)
+	}
+	d := time.Second
+	for i := int64(1); i < attempt && d < time.Minute; i++ {
+		d *= 2
+	}
+	if d > time.Minute {
+		d = time.Minute
+	}
+	return d
+}
+
+// isCaptchaFailure returns true if err is caused by a captcha that could not be solved
+func isCaptchaFailure(err error) bool {
+	var captchaErr *CaptchaRequiredError
+	return errors.As(err, &captchaErr) || errors.Is(err, ogame.ErrCaptchaFailed)
+}
+
+// isFatalLoginError returns true if retrying the login cannot succeed
+func isFatalLoginError(err error) bool {
+	return err == ogame.ErrAccountNotFound ||
+		err == ogame.ErrAccountBlocked ||
+		err == ogame.ErrBadCredentials ||
+		err == ogame.ErrOTPRequired ||
+		err == ogame.ErrOTPInvalid
+}
+
+// SetLoginPolicy sets the policy used to retry requests and auto re-login, also resets the captcha failures counter
+func (b *OGame) SetLoginPolicy(policy LoginPolicy) {
+	b.loginPolicyMu.Lock()
+	defer b.loginPolicyMu.Unlock()
+	b.loginPolicy = policy
+	b.captchaFailures = 0
+}
+
+func (b *OGame) getLoginPolicy() LoginPolicy {
+	b.loginPolicyMu.Lock()
+	defer b.loginPolicyMu.Unlock()
+	return b.loginPolicy
+}
+
+// loginAttempted resets the captcha failures counter once a login succeeds, and notifies the lifecycle callbacks
+func (b *OGame) loginAttempted(err error) {
+	if err != nil {
+		b.loginFailed(err)
+		return
+	}
+	b.loginPolicyMu.Lock()
+	b.captchaFailures = 0
+	b.loginPolicyMu.Unlock()
+	b.loginSucceeded()
+}
+
+// reloginStopped returns true if the auto re-login is stopped after too many captcha failures,
+// it resumes once a manual login succeeds
+func (b *OGame) reloginStopped() bool {
+	b.loginPolicyMu.Lock()
+	defer b.loginPolicyMu.Unlock()
+	return b.loginPolicy.MaxCaptchaFailures > 0 && b.captchaFailures >= b.loginPolicy.MaxCaptchaFailures
+}
+
+// handleReloginError notifies the policy callbacks of a failed auto re-login,
+// returns the error to stop retrying with, nil to keep retrying
+func (b *OGame) handleReloginError(err error) error {
+	b.loginPolicyMu.Lock()
+	policy := b.loginPolicy
+	stopErr := error(nil)
+	failures := int64(0)
+	captchaFailure := isCaptchaFailure(err)
+	if captchaFailure {
+		b.captchaFailures++
+		failures = b.captchaFailures
+		if policy.MaxCaptchaFailures > 0 && failures >= policy.MaxCaptchaFailures {
+			stopErr = ogame.ErrTooManyCaptchaFailures
+		}
+	} else if isFatalLoginError(err) {
+		stopErr = err
+	}
+	b.loginPolicyMu.Unlock()
+	if captchaFailure && policy.OnCaptcha != nil {
+		policy.OnCaptcha(failures, err)
+	}
+	if stopErr != nil && policy.OnBlocked != nil {
+		policy.OnBlocked(stopErr)
+	}
+	return stopErr
+}
@@ -0,0 +1,214 @@
+package wrapper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// MarketplaceTrader polls the marketplace listings and accepts the offers with a good exchange rate.
+//
+//	trader := wrapper.NewMarketplaceTrader(bot, planetID).
+//		SetRates(ogame.Ratio{Metal: 3, Crystal: 2, Deuterium: 1}).
+//		SetMinRate(1.2).
+//		SetDailyLimit(5_000_000)
+//	trader.Start()
+//	defer trader.Stop()
+type MarketplaceTrader struct {
+	b               Wrapper
+	celestialID     ogame.CelestialID
+	rates           ogame.Ratio
+	minRate         float64
+	dailyLimit      int64
+	spentToday      int64
+	day             string
+	checkInterval   time.Duration
+	mu              sync.Mutex
+	cancel          context.CancelFunc
+	offersCallbacks []func(ogame.MarketplaceTab, []ogame.MarketplaceOffer)
+	boughtCallbacks []func(ogame.MarketplaceOffer)
+	errorCallbacks  []func(error)
+}
+
+// NewMarketplaceTrader creates a trader that pays with the resources of celestialID
+func NewMarketplaceTrader(b Wrapper, celestialID ogame.CelestialID) *MarketplaceTrader {
+	return &MarketplaceTrader{
+		b:             b,
+		celestialID:   celestialID,
+		rates:         ogame.Ratio{Metal: 3, Crystal: 2, Deuterium: 1},
+		minRate:       1.1,
+		checkInterval: 5 * time.Minute,
+	}
+}
+
+// SetRates sets the trade rates used to compute the offers exchange rate
+func (t *MarketplaceTrader) SetRates(rates ogame.Ratio) *MarketplaceTrader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rates = rates
+	return t
+}
+
+// SetMinRate sets the minimum exchange rate for an offer to be accepted, eg: 1.2 to only accept offers 20% better than the trade rates
+func (t *MarketplaceTrader) SetMinRate(minRate float64) *MarketplaceTrader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.minRate = minRate
+	return t
+}
+
+// SetDailyLimit sets the maximum amount (in metal value) spent per server day, 0 for no limit
+func (t *MarketplaceTrader) SetDailyLimit(limit int64) *MarketplaceTrader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dailyLimit = limit
+	return t
+}
+
+// SetCheckInterval sets how often the listings are polled
+func (t *MarketplaceTrader) SetCheckInterval(d time.Duration) *MarketplaceTrader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.checkInterval = d
+	return t
+}
+
+// OnOffers registers a callback executed with the listings of each tab every time they are polled
+func (t *MarketplaceTrader) OnOffers(clb func(ogame.MarketplaceTab, []ogame.MarketplaceOffer)) *MarketplaceTrader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.offersCallbacks = append(t.offersCallbacks, clb)
+	return t
+}
+
+// OnBought registers a callback executed when an offer is accepted
+func (t *MarketplaceTrader) OnBought(clb func(ogame.MarketplaceOffer)) *MarketplaceTrader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.boughtCallbacks = append(t.boughtCallbacks, clb)
+	return t
+}
+
+// OnError registers a callback executed when a check or a purchase fails
+func (t *MarketplaceTrader) OnError(clb func(error)) *MarketplaceTrader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errorCallbacks = append(t.errorCallbacks, clb)
+	return t
+}
+
+// SpentToday returns the amount (in metal value) spent during the current server day
+func (t *MarketplaceTrader) SpentToday() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.day != t.b.ServerTime().Format("2006-01-02") {
+		return 0
+	}
+	return t.spentToday
+}
+
+// Start starts polling the marketplace in the background, until Stop is called
+func (t *MarketplaceTrader) Start() {
+	t.mu.Lock()
+	if t.cancel != nil {
+		t.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.mu.Unlock()
+	go func() {
+		for {
+			if err := t.Check(); err != nil {
+				t.emitError(err)
+			}
+			t.mu.Lock()
+			interval := t.checkInterval
+			t.mu.Unlock()
+			select {
+			case <-time.After(t.b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background polling
+func (t *MarketplaceTrader) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
+	}
+}
+
+// Check polls both tabs and accepts the good offers of the buying tab
+func (t *MarketplaceTrader) Check() error {
+	if !t.b.IsLoggedIn() {
+		return nil
+	}
+	var buying []ogame.MarketplaceOffer
+	for _, tab := range []ogame.MarketplaceTab{ogame.MarketplaceBuyingTab, ogame.MarketplaceSellingTab} {
+		offers, err := t.b.GetMarketplaceOffers(tab, t.celestialID)
+		if err != nil {
+			return err
+		}
+		t.mu.Lock()
+		callbacks := t.offersCallbacks
+		t.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(tab, offers)
+		}
+		if tab == ogame.MarketplaceBuyingTab {
+			buying = offers
+		}
+	}
+	for _, offer := range buying {
+		if !t.accept(offer) {
+			continue
+		}
+		if err := t.b.BuyMarketplace(offer.ID, t.celestialID); err != nil {
+			t.emitError(err)
+			continue
+		}
+		t.mu.Lock()
+		t.spentToday += int64(t.rates.MetalValue(offer.Cost()))
+		callbacks := t.boughtCallbacks
+		t.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(offer)
+		}
+	}
+	return nil
+}
+
+// accept returns whether the offer is a good deal that fits in what is left of the daily limit
+func (t *MarketplaceTrader) accept(offer ogame.MarketplaceOffer) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if day := t.b.ServerTime().Format("2006-01-02"); day != t.day {
+		t.day = day
+		t.spentToday = 0
+	}
+	return marketplaceOfferAcceptable(offer, t.rates, t.minRate, t.dailyLimit, t.spentToday)
+}
+
+func (t *MarketplaceTrader) emitError(err error) {
+	t.mu.Lock()
+	callbacks := t.errorCallbacks
+	t.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(err)
+	}
+}
+
+func marketplaceOfferAcceptable(offer ogame.MarketplaceOffer, rates ogame.Ratio, minRate float64, dailyLimit, spent int64) bool {
+	if offer.ExchangeRate(rates) < minRate {
+		return false
+	}
+	return dailyLimit <= 0 || spent+int64(rates.MetalValue(offer.Cost())) <= dailyLimit
+}
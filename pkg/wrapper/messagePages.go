@@ -0,0 +1,123 @@
+package wrapper
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// fetchMessagePages fetches every page of a messages tab and returns the messages in page order.
+// The first page is fetched alone to learn the number of pages, the others are fetched by at most Concurrency
+// workers (default 1, sequential) spaced by Pacing. The messages of the pages fetched before an error are returned
+// along with the error. Request errors are no longer ignored as they used to be when the pages were fetched one by one:
+// an error on the first page returns no message at all.
+// The workers do not re-login by themselves, if the session is lost the pool is stopped, the bot re-logs once
+// from the calling goroutine and the remaining pages are fetched again.
+func fetchMessagePages[T any](b *OGame, tabID ogame.MessagesTabID, extract func([]byte) ([]T, int64, error), opts ...Option) ([]T, error) {
+	cfg := getOptions(opts...)
+	pageHTML, err := b.getPageMessages(1, tabID)
+	if err != nil {
+		return make([]T, 0), err
+	}
+	first, nbPage, _ := extract(pageHTML)
+	if nbPage <= 1 {
+		return append(make([]T, 0, len(first)), first...), nil
+	}
+
+	pages := make([][]T, nbPage+1)
+	errs := make([]error, nbPage+1)
+	pages[1] = first
+	pending := make([]int64, 0, nbPage-1)
+	for page := int64(2); page <= nbPage; page++ {
+		pending = append(pending, page)
+	}
+	fetch := func(page int64, opts ...Option) error {
+		pageHTML, err := b.getPageMessages(page, tabID, opts...)
+		if err != nil {
+			return err
+		}
+		pages[page], _, errs[page] = extract(pageHTML)
+		return nil
+	}
+	for relogged := false; len(pending) > 0; relogged = true {
+		notLogged := fetchPagesPool(pending, cfg, func(page int64) error { return fetch(page, SkipRetry) }, errs)
+		if len(notLogged) == 0 {
+			break
+		}
+		if relogged {
+			errs[notLogged[0]] = ogame.ErrNotLogged
+			break
+		}
+		// The retry policy of a single request re-logs the bot
+		if err := fetch(notLogged[0]); err != nil {
+			errs[notLogged[0]] = err
+			break
+		}
+		pending = notLogged[1:]
+	}
+
+	out := make([]T, 0)
+	for page := int64(1); page <= nbPage; page++ {
+		if errs[page] != nil {
+			return out, errs[page]
+		}
+		out = append(out, pages[page]...)
+	}
+	return out, nil
+}
+
+// fetchPagesPool calls fetch for every page with at most cfg.Concurrency workers spaced by cfg.Pacing, and stores
+// the errors in errs. When a page fails with ErrNotLogged the pool stops, the pages left to fetch are returned in order.
+func fetchPagesPool(pages []int64, cfg Options, fetch func(page int64) error, errs []error) []int64 {
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > int64(len(pages)) {
+		concurrency = int64(len(pages))
+	}
+	var mu sync.Mutex
+	notLogged := make([]int64, 0)
+	var stopped int32
+	jobs := make(chan int64)
+	var wg sync.WaitGroup
+	for i := int64(0); i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				err := fetch(page)
+				if errors.Is(err, ogame.ErrNotLogged) {
+					atomic.StoreInt32(&stopped, 1)
+					mu.Lock()
+					notLogged = append(notLogged, page)
+					mu.Unlock()
+					continue
+				}
+				if err != nil {
+					errs[page] = err
+				}
+			}
+		}()
+	}
+	for i, page := range pages {
+		if atomic.LoadInt32(&stopped) == 1 {
+			mu.Lock()
+			notLogged = append(notLogged, pages[i:]...)
+			mu.Unlock()
+			break
+		}
+		if cfg.Pacing > 0 && i > 0 {
+			time.Sleep(cfg.Pacing)
+		}
+		jobs <- page
+	}
+	close(jobs)
+	wg.Wait()
+	sort.Slice(notLogged, func(i, j int) bool { return notLogged[i] < notLogged[j] })
+	return notLogged
+}
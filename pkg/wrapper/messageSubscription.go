@@ -0,0 +1,198 @@
+package wrapper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// MessageSubscription polls messages tabs and pushes the messages received since the previous poll.
+// The first poll of a tab only records the last message, messages already in the tab are not pushed.
+//
+//	sub := bot.SubscribeMessages(wrapper.EspionageMessagesTabID, wrapper.CombatReportsMessagesTabID)
+//	sub.OnMessage(func(msg ogame.Message) {
+//		if msg.EspionageReport != nil {
+//			fmt.Println("new espionage report", msg.EspionageReport.Target)
+//		}
+//	})
+//	sub.Start()
+//	defer sub.Stop()
+type MessageSubscription struct {
+	b                Wrapper
+	tabs             []ogame.MessagesTabID
+	interval         time.Duration
+	lastIDs          map[ogame.MessagesTabID]int64
+	mu               sync.Mutex
+	cancel           context.CancelFunc
+	messageCallbacks []func(ogame.Message)
+	errorCallbacks   []func(ogame.MessagesTabID, error)
+}
+
+// NewMessageSubscription creates a subscription to the given tabs
+func NewMessageSubscription(b Wrapper, tabs ...ogame.MessagesTabID) *MessageSubscription {
+	return &MessageSubscription{
+		b:        b,
+		tabs:     tabs,
+		interval: time.Minute,
+		lastIDs:  make(map[ogame.MessagesTabID]int64),
+	}
+}
+
+// SetInterval sets how often the tabs are polled
+func (s *MessageSubscription) SetInterval(d time.Duration) *MessageSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interval = d
+	return s
+}
+
+// Cursors returns the id of the last message seen per tab
+func (s *MessageSubscription) Cursors() map[ogame.MessagesTabID]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[ogame.MessagesTabID]int64, len(s.lastIDs))
+	for tabID, lastID := range s.lastIDs {
+		out[tabID] = lastID
+	}
+	return out
+}
+
+// SetCursors sets the id of the last message seen per tab, eg: to resume from an exported state.
+// Messages received since then are pushed by the next poll.
+func (s *MessageSubscription) SetCursors(cursors map[ogame.MessagesTabID]int64) *MessageSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tabID := range s.tabs {
+		if lastID, ok := cursors[tabID]; ok {
+			s.lastIDs[tabID] = lastID
+		}
+	}
+	return s
+}
+
+// OnMessage registers a callback executed for every new message, oldest first
+func (s *MessageSubscription) OnMessage(clb func(ogame.Message)) *MessageSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messageCallbacks = append(s.messageCallbacks, clb)
+	return s
+}
+
+// OnError registers a callback executed when a tab cannot be polled
+func (s *MessageSubscription) OnError(clb func(ogame.MessagesTabID, error)) *MessageSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorCallbacks = append(s.errorCallbacks, clb)
+	return s
+}
+
+// Start starts polling in the background, until Stop is called
+func (s *MessageSubscription) Start() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.mu.Unlock()
+	go func() {
+		for {
+			s.Poll()
+			s.mu.Lock()
+			interval := s.interval
+			s.mu.Unlock()
+			select {
+			case <-time.After(s.b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background polling
+func (s *MessageSubscription) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// Poll polls every tab once and pushes the new messages
+func (s *MessageSubscription) Poll() {
+	if !s.b.IsLoggedIn() {
+		return
+	}
+	for _, tabID := range s.tabs {
+		s.mu.Lock()
+		lastID, seen := s.lastIDs[tabID]
+		s.mu.Unlock()
+		msgs, err := s.newMessages(tabID, lastID, seen)
+		if err != nil {
+			s.emitError(tabID, err)
+			continue
+		}
+		if len(msgs) > 0 {
+			lastID = msgs[0].ID
+		}
+		s.mu.Lock()
+		s.lastIDs[tabID] = lastID
+		callbacks := s.messageCallbacks
+		s.mu.Unlock()
+		if !seen {
+			continue
+		}
+		for i := len(msgs) - 1; i >= 0; i-- {
+			for _, clb := range callbacks {
+				clb(msgs[i])
+			}
+		}
+	}
+}
+
+// newMessages returns the messages of the tab more recent than lastID, newest first.
+// Pages are walked until a message already seen is found.
+func (s *MessageSubscription) newMessages(tabID ogame.MessagesTabID, lastID int64, seen bool) ([]ogame.Message, error) {
+	out := make([]ogame.Message, 0)
+	var page int64 = 1
+	var nbPage int64 = 1
+	for page <= nbPage {
+		msgs, newNbPage, err := s.b.GetMessagesPage(tabID, page)
+		if err != nil {
+			return nil, err
+		}
+		var done bool
+		out, done = appendNewMessages(out, msgs, lastID)
+		if done || !seen {
+			break
+		}
+		nbPage = newNbPage
+		page++
+	}
+	return out, nil
+}
+
+// appendNewMessages appends the messages more recent than lastID, returns true once an older message is reached
+func appendNewMessages(out, msgs []ogame.Message, lastID int64) ([]ogame.Message, bool) {
+	for _, msg := range msgs {
+		if msg.ID <= lastID {
+			return out, true
+		}
+		out = append(out, msg)
+	}
+	return out, false
+}
+
+func (s *MessageSubscription) emitError(tabID ogame.MessagesTabID, err error) {
+	s.mu.Lock()
+	callbacks := s.errorCallbacks
+	s.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(tabID, err)
+	}
+}
@@ -0,0 +1,142 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+type noRedirectKey struct{}
+
+// setRedirectPolicy makes the client stop at the first redirect (301) of the game post requests
+// https://stackoverflow.com/a/38150816/4196220
+// The requests are flagged through their context, so parallel requests do not race on CheckRedirect.
+func setRedirectPolicy(client *httpclient.Client) {
+	if client == nil || client.Client == nil {
+		return
+	}
+	prev := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if noRedirect, _ := req.Context().Value(noRedirectKey{}).(bool); noRedirect {
+			return http.ErrUseLastResponse
+		}
+		if prev != nil {
+			return prev(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+}
+
+// Request game request going through the middlewares
+type Request struct {
+	Method  string
+	URL     string      // full url requested
+	Vals    url.Values  // query parameters the url was built from, changing them does not change the url
+	Payload url.Values  // post payload
+	Header  http.Header // headers added to the request, they override the default ones
+}
+
+// Response response of a game request
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Handler executes a game request
+type Handler func(req *Request) (*Response, error)
+
+// Middleware wraps the handler executing the game requests. A middleware can mutate the request before calling
+// next, add headers, return a response without calling next, or observe/alter the response returned by next.
+//
+//	bot.Use(func(next wrapper.Handler) wrapper.Handler {
+//		return func(req *wrapper.Request) (*wrapper.Response, error) {
+//			start := time.Now()
+//			resp, err := next(req)
+//			log.Println(req.Method, req.URL, time.Since(start))
+//			return resp, err
+//		}
+//	})
+type Middleware func(next Handler) Handler
+
+// Use adds a middleware, the first one added is the outermost.
+// Interceptors registered with RegisterHTMLInterceptor are read-only taps called once the response went through
+// all the middlewares and was processed by the bot.
+func (b *OGame) Use(mw Middleware) {
+	b.middlewaresMu.Lock()
+	defer b.middlewaresMu.Unlock()
+	b.middlewares = append(b.middlewares, mw)
+}
+
+// requestHandler returns the handler executing the requests through the middlewares
+func (b *OGame) requestHandler() Handler {
+	b.middlewaresMu.Lock()
+	defer b.middlewaresMu.Unlock()
+	handler := Handler(b.doRequest)
+	for i := len(b.middlewares) - 1; i >= 0; i-- {
+		handler = b.middlewares[i](handler)
+	}
+	return handler
+}
+
+// doRequest executes the http request, last handler of the chain
+func (b *OGame) doRequest(r *Request) (*Response, error) {
+	var body io.Reader
+	if r.Method == http.MethodPost {
+		body = strings.NewReader(r.Payload.Encode())
+	}
+
+	req, err := http.NewRequest(r.Method, r.URL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Method == http.MethodPost {
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Add("Accept-Encoding", "gzip, deflate, br")
+	b.setProfileHeaders(req)
+	if IsAjaxPage(r.Vals) {
+		req.Header.Add("X-Requested-With", "XMLHttpRequest")
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+
+	ctx, cancel := b.requestCtx()
+	defer cancel()
+	if r.Method == http.MethodPost {
+		ctx = context.WithValue(ctx, noRedirectKey{}, true)
+	}
+	req = req.WithContext(ctx)
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := &Response{StatusCode: resp.StatusCode, Header: resp.Header}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return out, nil
+	}
+	if out.Body, err = utils.ReadBody(resp); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// runInterceptors calls the read-only interceptors with a processed page
+func (b *OGame) runInterceptors(method, url string, params, payload url.Values, pageHTML []byte) {
+	for _, fn := range b.interceptorCallbacks.list() {
+		b.safeCall("interceptor", func() { fn(method, url, params, payload, pageHTML) })
+	}
+}
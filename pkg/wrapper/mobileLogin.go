@@ -0,0 +1,104 @@
+package wrapper
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+)
+
+// LoginStrategy how the bot gets its gameforge bearer token
+type LoginStrategy int
+
+// Login strategies
+const (
+	WebLogin    LoginStrategy = iota // login of the browser lobby, default
+	MobileLogin                      // login of the gameforge mobile app, less often challenged with captchas
+)
+
+func (s LoginStrategy) String() string {
+	switch s {
+	case WebLogin:
+		return "web"
+	case MobileLogin:
+		return "mobile"
+	}
+	return fmt.Sprintf("LoginStrategy(%d)", int(s))
+}
+
+const mobileUserAgent = "Dalvik/2.1.0 (Linux; U; Android 13; Pixel 7 Build/TQ3A.230805.001)"
+
+// GFMobileLogin logs in gameforge with the device identity of the mobile app: the mobile user agent and a
+// persistent tnt-installation-id instead of a blackbox. The sessions endpoint and payload are the ones of GFLogin,
+// the game itself is still played through the regular login link.
+// installationID identifies the device and should not change between logins (see NewMobileInstallationID)
+func GFMobileLogin(client httpclient.IHttpClient, ctx context.Context, lobby, username, password, otpSecret, challengeID, installationID string) (out *GFLoginRes, err error) {
+	gameEnvironmentID, platformGameID, err := getConfiguration(client, ctx, lobby)
+	if err != nil {
+		return out, err
+	}
+	req, err := mobileSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID, installationID)
+	if err != nil {
+		return out, err
+	}
+	req = req.WithContext(ctx)
+	resp, err := client.Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	return parseGFLoginResp(resp)
+}
+
+func mobileSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID, installationID string) (*http.Request, error) {
+	req, err := postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID, "")
+	if err != nil {
+		return nil, err
+	}
+	// The client keeps a user agent already set on the request
+	req.Header.Set("User-Agent", mobileUserAgent)
+	req.Header.Set("tnt-installation-id", installationID)
+	return req, nil
+}
+
+// NewMobileInstallationID generates a random device identifier for GFMobileLogin
+func NewMobileInstallationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40 // uuid v4
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// mobileInstallationIDFilename file where the installation id is persisted, next to the cookies
+func mobileInstallationIDFilename(cookiesFilename string) string {
+	if cookiesFilename == "" {
+		return ""
+	}
+	return cookiesFilename + ".installation"
+}
+
+// SetLoginStrategy sets how the bot gets its gameforge bearer token when it has to login with its credentials.
+// The installation id of MobileLogin is persisted next to the cookies, so the bot keeps the same device between restarts.
+func (b *OGame) SetLoginStrategy(strategy LoginStrategy) {
+	b.loginStrategy = strategy
+	if strategy != MobileLogin || b.mobileInstallationID != "" {
+		return
+	}
+	if b.mobileInstallationIDFile != "" {
+		if by, err := os.ReadFile(b.mobileInstallationIDFile); err == nil && len(bytes.TrimSpace(by)) > 0 {
+			b.mobileInstallationID = string(bytes.TrimSpace(by))
+			return
+		}
+	}
+	b.mobileInstallationID = NewMobileInstallationID()
+	if b.mobileInstallationIDFile != "" {
+		if err := os.WriteFile(b.mobileInstallationIDFile, []byte(b.mobileInstallationID), 0600); err != nil {
+			b.error("failed to persist installation id : " + err.Error())
+		}
+	}
+}
@@ -37,8 +37,8 @@ func (m Moon) GetShips(options ...Option) (ogame.ShipsInfos, error) {
 	return m.ogame.GetShips(m.ID.Celestial(), options...)
 }
 
-// Build builds any ogame objects (building, technology, ship, defence)
-func (m Moon) Build(id ogame.ID, nbr int64) error {
+// Build builds any ogame objects (building, technology, ship, defence), and returns when the construction will be done
+func (m Moon) Build(id ogame.ID, nbr int64) (ogame.ConstructionETA, error) {
 	return m.ogame.Build(ogame.CelestialID(m.ID), id, nbr)
 }
 
@@ -48,8 +48,8 @@ func (m Moon) TearDown(buildingID ogame.ID) error {
 }
 
 // BuildTechnology ensure that we're trying to build a technology
-func (m Moon) BuildTechnology(technologyID ogame.ID) error {
-	return errors.New("cannot build technology on a moon")
+func (m Moon) BuildTechnology(technologyID ogame.ID) (ogame.ConstructionETA, error) {
+	return ogame.ConstructionETA{}, errors.New("cannot build technology on a moon")
 }
 
 // BuildDefense builds a defense unit
@@ -58,7 +58,7 @@ func (m Moon) BuildDefense(defenseID ogame.ID, nbr int64) error {
 }
 
 // BuildBuilding ensure what is being built is a building
-func (m Moon) BuildBuilding(buildingID ogame.ID) error {
+func (m Moon) BuildBuilding(buildingID ogame.ID) (ogame.ConstructionETA, error) {
 	return m.ogame.BuildBuilding(ogame.CelestialID(m.ID), buildingID)
 }
 
@@ -152,3 +152,8 @@ func (m Moon) GetLfResearch(options ...Option) (ogame.LfResearches, error) {
 func (m Moon) GetTechs() (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
 	return m.ogame.GetTechs(m.ID.Celestial())
 }
+
+// Rename renames the moon
+func (m Moon) Rename(newName string) error {
+	return m.ogame.RenamePlanet(m.ID.Celestial(), newName)
+}
@@ -0,0 +1,233 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// ErrOfficerBudgetExceeded returned when renewing an officer would exceed the renewer dark matter budget
+var ErrOfficerBudgetExceeded = errors.New("officer dark matter budget exceeded")
+
+// ErrOfficerExpiryUnknown returned when a hired officer expiry could not be read, the renewer cannot tell when to renew it
+var ErrOfficerExpiryUnknown = errors.New("officer expiry is unknown")
+
+// OfficerRenewer periodically renews the selected officers before they expire.
+//
+//	renewer := wrapper.NewOfficerRenewer(bot, ogame.OfficerCommander, ogame.OfficerGeologist).
+//		SetRenewBefore(6 * time.Hour).
+//		SetBudget(50000)
+//	renewer.Start()
+//	defer renewer.Stop()
+type OfficerRenewer struct {
+	b                Wrapper
+	officers         []int64
+	days             int64
+	renewBefore      time.Duration
+	checkInterval    time.Duration
+	budget           int64
+	spent            int64
+	mu               sync.Mutex
+	cancel           context.CancelFunc
+	renewedCallbacks []func(ogame.Officer)
+	errorCallbacks   []func(error)
+}
+
+// NewOfficerRenewer creates a renewer for the given officer types
+func NewOfficerRenewer(b Wrapper, officers ...int64) *OfficerRenewer {
+	return &OfficerRenewer{
+		b:             b,
+		officers:      officers,
+		days:          7,
+		renewBefore:   12 * time.Hour,
+		checkInterval: time.Hour,
+	}
+}
+
+// SetDays sets the hiring duration used when renewing, 7 or 90 days
+func (r *OfficerRenewer) SetDays(days int64) *OfficerRenewer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.days = days
+	return r
+}
+
+// SetRenewBefore sets how long before expiry an officer gets renewed
+func (r *OfficerRenewer) SetRenewBefore(d time.Duration) *OfficerRenewer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renewBefore = d
+	return r
+}
+
+// SetCheckInterval sets how often the officers expiry is checked
+func (r *OfficerRenewer) SetCheckInterval(d time.Duration) *OfficerRenewer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkInterval = d
+	return r
+}
+
+// SetBudget sets the maximum amount of dark matter the renewer is allowed to spend, 0 for no limit
+func (r *OfficerRenewer) SetBudget(budget int64) *OfficerRenewer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.budget = budget
+	return r
+}
+
+// OnRenewed registers a callback executed when an officer is renewed
+func (r *OfficerRenewer) OnRenewed(clb func(ogame.Officer)) *OfficerRenewer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renewedCallbacks = append(r.renewedCallbacks, clb)
+	return r
+}
+
+// OnError registers a callback executed when a check or a renewal fails
+func (r *OfficerRenewer) OnError(clb func(error)) *OfficerRenewer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errorCallbacks = append(r.errorCallbacks, clb)
+	return r
+}
+
+// Spent returns the amount of dark matter spent by the renewer so far
+func (r *OfficerRenewer) Spent() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spent
+}
+
+// Start starts checking officers in the background, until Stop is called
+func (r *OfficerRenewer) Start() {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.mu.Unlock()
+	go func() {
+		for {
+			if err := r.Check(); err != nil {
+				r.emitError(err)
+			}
+			r.mu.Lock()
+			interval := r.checkInterval
+			r.mu.Unlock()
+			select {
+			case <-time.After(r.b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks
+func (r *OfficerRenewer) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// Check renews the selected officers that are not hired or are about to expire.
+// Returns ErrOfficerExpiryUnknown if a selected officer is hired but its expiry could not be read.
+func (r *OfficerRenewer) Check() error {
+	if !r.b.IsLoggedIn() {
+		return nil
+	}
+	officers, err := r.b.GetOfficers()
+	if err != nil {
+		return err
+	}
+	var checkErr error
+	for _, officer := range officers {
+		if !r.isSelected(officer.Type) {
+			continue
+		}
+		needsRenewal, err := r.needsRenewal(officer)
+		if err != nil {
+			checkErr = err
+			continue
+		}
+		if !needsRenewal {
+			continue
+		}
+		if err := r.renew(officer); err != nil {
+			return err
+		}
+	}
+	return checkErr
+}
+
+func (r *OfficerRenewer) isSelected(typ int64) bool {
+	for _, officer := range r.officers {
+		if officer == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *OfficerRenewer) needsRenewal(officer ogame.Officer) (bool, error) {
+	if !officer.Active {
+		return true, nil
+	}
+	if officer.ExpireAt.IsZero() {
+		return false, ErrOfficerExpiryUnknown
+	}
+	r.mu.Lock()
+	renewBefore := r.renewBefore
+	r.mu.Unlock()
+	return time.Until(officer.ExpireAt) <= renewBefore, nil
+}
+
+func (r *OfficerRenewer) renew(officer ogame.Officer) error {
+	r.mu.Lock()
+	days, budget, spent := r.days, r.budget, r.spent
+	r.mu.Unlock()
+	cost := ogame.OfficerCost(officer.Type, days)
+	if budget > 0 && spent+cost > budget {
+		return ErrOfficerBudgetExceeded
+	}
+	celestials := r.b.GetCachedCelestials()
+	if len(celestials) == 0 {
+		return errors.New("no celestial available")
+	}
+	resources, err := r.b.GetResourcesDetails(celestials[0].GetID())
+	if err != nil {
+		return err
+	}
+	if resources.Darkmatter.Available < cost {
+		return ogame.ErrNotEnoughDarkMatter
+	}
+	if err := r.b.RecruitOfficer(officer.Type, days); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.spent += cost
+	callbacks := r.renewedCallbacks
+	r.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(officer)
+	}
+	return nil
+}
+
+func (r *OfficerRenewer) emitError(err error) {
+	r.mu.Lock()
+	callbacks := r.errorCallbacks
+	r.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(err)
+	}
+}
@@ -28,8 +28,6 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/alaingilbert/clockwork"
-	"github.com/alaingilbert/ogame/pkg/exponentialBackoff"
 	"github.com/alaingilbert/ogame/pkg/extractor"
 	v6 "github.com/alaingilbert/ogame/pkg/extractor/v6"
 	v7 "github.com/alaingilbert/ogame/pkg/extractor/v7"
@@ -40,6 +38,7 @@ import (
 	"github.com/alaingilbert/ogame/pkg/httpclient"
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/alaingilbert/ogame/pkg/parser"
+	"github.com/alaingilbert/ogame/pkg/publicapi"
 	"github.com/alaingilbert/ogame/pkg/taskRunner"
 	"github.com/alaingilbert/ogame/pkg/utils"
 
@@ -57,59 +56,122 @@ import (
 // multiple goroutines (thread-safe)
 type OGame struct {
 	sync.Mutex
-	isEnabledAtom         int32  // atomic, prevent auto re login if we manually logged out
-	isLoggedInAtom        int32  // atomic, prevent auto re login if we manually logged out
-	isConnectedAtom       int32  // atomic, either or not communication between the bot and OGame is possible
-	lockedAtom            int32  // atomic, bot state locked/unlocked
-	chatConnectedAtom     int32  // atomic, either or not the chat is connected
-	state                 string // keep name of the function that currently lock the bot
-	ctx                   context.Context
-	cancelCtx             context.CancelFunc
-	stateChangeCallbacks  []func(locked bool, actor string)
-	quiet                 bool
-	Player                ogame.UserInfos
-	CachedPreferences     ogame.Preferences
-	isVacationModeEnabled bool
-	researches            *ogame.Researches
-	planets               []Planet
-	planetsMu             sync.RWMutex
-	ajaxChatToken         string
-	Universe              string
-	Username              string
-	password              string
-	otpSecret             string
-	bearerToken           string
-	language              string
-	playerID              int64
-	lobby                 string
-	ogameSession          string
-	sessionChatCounter    int64
-	server                Server
-	serverData            ServerData
-	location              *time.Location
-	serverURL             string
-	client                *httpclient.Client
-	logger                *log.Logger
-	chatCallbacks         []func(msg ogame.ChatMsg)
-	wsCallbacks           map[string]func(msg []byte)
-	auctioneerCallbacks   []func(any)
-	interceptorCallbacks  []func(method, url string, params, payload url.Values, pageHTML []byte)
-	closeChatCh           chan struct{}
-	ws                    *websocket.Conn
-	taskRunnerInst        *taskRunner.TaskRunner[*Prioritize]
-	loginWrapper          func(func() (bool, error)) error
-	getServerDataWrapper  func(func() (ServerData, error)) (ServerData, error)
-	loginProxyTransport   http.RoundTripper
-	extractor             extractor.Extractor
-	apiNewHostname        string
-	characterClass        ogame.CharacterClass
-	hasCommander          bool
-	hasAdmiral            bool
-	hasEngineer           bool
-	hasGeologist          bool
-	hasTechnocrat         bool
-	captchaCallback       CaptchaCallback
-}
+	serverClockOffsetAtom     int64  // atomic, server clock minus local clock in nanoseconds, first for 64-bit alignment
+	isEnabledAtom             int32  // atomic, prevent auto re login if we manually logged out
+	isLoggedInAtom            int32  // atomic, prevent auto re login if we manually logged out
+	isConnectedAtom           int32  // atomic, either or not communication between the bot and OGame is possible
+	lockedAtom                int32  // atomic, bot state locked/unlocked
+	chatConnectedAtom         int32  // atomic, either or not the chat is connected
+	chatDisabledAtom          int32  // atomic, either or not the chat websocket must not be connected at login
+	state                     string // keep name of the function that currently lock the bot
+	ctx                       context.Context
+	cancelCtx                 context.CancelFunc
+	stateChangeCallbacks      []func(locked bool, actor string)
+	txExpiredCallbacks        []func(name string)
+	quiet                     bool
+	Player                    ogame.UserInfos
+	CachedPreferences         ogame.Preferences
+	isVacationModeEnabled     bool
+	researches                *ogame.Researches
+	planets                   []Planet
+	planetsMu                 sync.RWMutex
+	ajaxChatToken             string
+	Universe                  string
+	Username                  string
+	password                  string
+	otpSecret                 string
+	bearerToken               string
+	bearerTokenExpiry         time.Time
+	language                  string
+	playerID                  int64
+	lobby                     string
+	ogameSession              string
+	sessionChatCounter        int64
+	server                    Server
+	serverData                ServerData
+	location                  *time.Location
+	serverURL                 string
+	client                    *httpclient.Client
+	logger                    *log.Logger
+	chatCallbacks             callbackList[func(msg ogame.ChatMsg)]
+	tokenCallbacks            callbackList[func(token string)]
+	bearerTokenRefreshCancel  context.CancelFunc
+	bearerTokenRefreshMu      sync.Mutex
+	loginSuccessCallbacks     []func()
+	loginFailureCallbacks     []func(err error)
+	reloginCallbacks          []func()
+	logoutCallbacks           []func()
+	wsCallbacks               map[string]func(msg []byte)
+	auctioneerCallbacks       callbackList[func(any)]
+	interceptorCallbacks      callbackList[func(method, url string, params, payload url.Values, pageHTML []byte)]
+	closeChatCh               chan struct{}
+	chatMu                    sync.Mutex // protects closeChatCh, chatHost and chatPort
+	chatHost                  string
+	chatPort                  string
+	chatReconnectPolicy       atomic.Value // ChatReconnectPolicy
+	chatDisconnectedCallbacks []func(err error)
+	ws                        *websocket.Conn
+	taskRunnerInst            *taskRunner.TaskRunner[*Prioritize]
+	loginWrapper              func(func() (bool, error)) error
+	getServerDataWrapper      func(func() (ServerData, error)) (ServerData, error)
+	loginProxyTransport       http.RoundTripper
+	extractor                 extractor.Extractor
+	publicAPIMu               sync.Mutex // protects publicAPI, not the bot mutex which is held by the login
+	publicAPI                 *publicapi.Client
+	apiNewHostname            string
+	characterClass            ogame.CharacterClass
+	hasCommander              bool
+	hasAdmiral                bool
+	hasEngineer               bool
+	hasGeologist              bool
+	hasTechnocrat             bool
+	captchaCallback           CaptchaCallback
+	constructionWatchers      map[ogame.CelestialID]*constructionWatcher
+	constructionWatchMu       sync.Mutex
+	reportStore               *ReportStore
+	slotManager               *SlotManager
+	humanizer                 *humanizer
+	blackboxProviderMu        sync.Mutex
+	blackboxProvider          BlackboxProvider
+	loginStrategy             LoginStrategy
+	middlewaresMu             sync.Mutex
+	middlewares               []Middleware
+	headerProfileMu           sync.Mutex
+	headerProfile             HeaderProfile
+	headerProfileFilename     string
+	mobileInstallationID      string
+	mobileInstallationIDFile  string
+	loginPolicyMu             sync.Mutex
+	loginPolicy               LoginPolicy
+	captchaFailures           int64
+	cache                     Cache
+	cacheTTLMu                sync.Mutex
+	cacheTTLs                 map[CacheKind]time.Duration
+	cacheUpdatedAt            map[CacheKind]time.Time
+	techsCacheMu              sync.Mutex
+	techsCache                map[ogame.CelestialID]techsCacheEntry
+	refreshingCachesAtom      int32        // atomic, a background refresh of the expired caches is running
+	taskCtx                   atomic.Value // context.Context of the running task, cancelled by CancelTask
+	cacheEventsMu             sync.Mutex
+	cacheEventCallbacks       []func(CacheEvent)
+	serverDataRefreshMu       sync.Mutex
+	serverDataRefreshStop     context.CancelFunc
+	versionCallbacks          []func(oldVersion, newVersion string)
+	dmLedger                  darkMatterLedger
+	resourcesDetailsMu        sync.Mutex
+	resourcesDetails          map[ogame.CelestialID]cachedResourcesDetails
+	stateMu                   sync.Mutex
+	importedState             BotState
+	messageSubscriptions      []*MessageSubscription
+	strategyRunners           []*StrategyRunner
+}
+
+// BearerTokenLifetime how long a gameforge bearer token obtained by the bot is considered valid,
+// when the token does not carry its own expiry. The sessions response has no expiry field.
+var BearerTokenLifetime = 24 * time.Hour
+
+// bearerTokenRefreshMargin how long before the expiry we proactively ask for a new token
+const bearerTokenRefreshMargin = 30 * time.Minute
 
 // CaptchaCallback ...
 type CaptchaCallback func(question, icons []byte) (int64, error)
@@ -141,6 +203,12 @@ type Params struct {
 	CookiesFilename string
 	Client          *httpclient.Client
 	CaptchaCallback CaptchaCallback
+	Humanize        *HumanizeProfile // randomized delays between requests, nil to disable
+	LoginPolicy     LoginPolicy      // auto re-login retries, backoff and notifications
+	Blackbox        BlackboxProvider // gameforge login fingerprint, none is sent if nil (default)
+	LoginStrategy   LoginStrategy    // WebLogin or MobileLogin
+	DisableChat     bool             // do not connect the chat/auctioneer websocket at login, see ConnectChat
+	HeaderProfile   bool             // send the headers of a browser profile instead of the default user agent, see EnableHeaderProfile
 }
 
 // Lobby constants
@@ -185,6 +253,20 @@ func NewWithParams(params Params) (*OGame, error) {
 		return nil, err
 	}
 	b.captchaCallback = params.CaptchaCallback
+	b.SetHumanizeProfile(params.Humanize)
+	b.SetLoginPolicy(params.LoginPolicy)
+	b.SetLoginStrategy(params.LoginStrategy)
+	if params.DisableChat {
+		atomic.StoreInt32(&b.chatDisabledAtom, 1)
+	}
+	if params.HeaderProfile {
+		if err := b.EnableHeaderProfile(); err != nil {
+			return nil, err
+		}
+	}
+	if params.Blackbox != nil {
+		b.SetBlackboxProvider(params.Blackbox)
+	}
 	b.setOGameLobby(params.Lobby)
 	b.apiNewHostname = params.APINewHostname
 	if params.Proxy != "" {
@@ -243,14 +325,18 @@ func NewNoLogin(username, password, otpSecret, bearerToken, universe, lang, cook
 		b.client = httpclient.NewClient()
 		b.client.Jar = jar
 		b.client.SetUserAgent(defaultUserAgent)
+		b.headerProfileFilename = headerProfileFilename(cookiesFilename)
+		b.mobileInstallationIDFile = mobileInstallationIDFilename(cookiesFilename)
 	} else {
 		b.client = client
 	}
+	setRedirectPolicy(b.client)
 
 	factory := func() *Prioritize { return &Prioritize{bot: b} }
 	b.taskRunnerInst = taskRunner.NewTaskRunner(context.Background(), factory)
 
 	b.wsCallbacks = make(map[string]func([]byte))
+	b.humanizer = newHumanizer()
 
 	return b, nil
 }
@@ -313,6 +399,11 @@ func (b *OGame) loginWithBearerToken(token string) (bool, error) {
 		err := b.login()
 		return false, err
 	}
+	if token == b.bearerToken && b.isBearerTokenExpired() {
+		b.debug("bearer token is about to expire")
+		err := b.login()
+		return false, err
+	}
 	b.bearerToken = token
 	server, userAccount, err := b.loginPart1(token)
 	if err2.Is(err, context.Canceled) {
@@ -356,9 +447,7 @@ func (b *OGame) loginWithBearerToken(token string) (bool, error) {
 			if err := b.client.Jar.(*cookiejar.Jar).Save(); err != nil {
 				return false, err
 			}
-			for _, fn := range b.interceptorCallbacks {
-				fn(http.MethodGet, loginLink, nil, nil, pageHTML)
-			}
+			b.runInterceptors(http.MethodGet, loginLink, nil, nil, pageHTML)
 			return true, nil
 		}
 		return false, err
@@ -470,12 +559,14 @@ func NinjaSolver(apiKey string) CaptchaCallback {
 	}
 }
 
-func postSessions(b *OGame, lobby, username, password, otpSecret string) (out *GFLoginRes, err error) {
-	if err := b.client.WithTransport(b.loginProxyTransport, func(client *httpclient.Client) error {
+// withCaptcha executes clb using the login transport. If gameforge requires a captcha, the bot's captcha callback
+// is used to solve the challenge, and clb is called again with the solved challenge id.
+func (b *OGame) withCaptcha(clb func(client *httpclient.Client, challengeID string) error) error {
+	return b.client.WithTransport(b.loginProxyTransport, func(client *httpclient.Client) error {
 		var challengeID string
 		tried := false
 		for {
-			out, err = GFLogin(client, b.ctx, lobby, username, password, otpSecret, challengeID)
+			err := clb(client, challengeID)
 			var captchaErr *CaptchaRequiredError
 			if errors.As(err, &captchaErr) {
 				if tried || b.captchaCallback == nil {
@@ -485,23 +576,35 @@ func postSessions(b *OGame, lobby, username, password, otpSecret string) (out *G
 
 				questionRaw, iconsRaw, err := StartCaptchaChallenge(client, b.ctx, captchaErr.ChallengeID)
 				if err != nil {
-					return errors.New("failed to start captcha challenge: " + err.Error())
+					return errors.Wrap(ogame.ErrCaptchaFailed, "failed to start captcha challenge: "+err.Error())
 				}
 				answer, err := b.captchaCallback(questionRaw, iconsRaw)
 				if err != nil {
-					return errors.New("failed to get answer for captcha challenge: " + err.Error())
+					return errors.Wrap(ogame.ErrCaptchaFailed, "failed to get answer for captcha challenge: "+err.Error())
 				}
 				if err := SolveChallenge(client, b.ctx, captchaErr.ChallengeID, answer); err != nil {
-					return errors.New("failed to solve captcha challenge: " + err.Error())
+					return errors.Wrap(ogame.ErrCaptchaFailed, "failed to solve captcha challenge: "+err.Error())
 				}
 				challengeID = captchaErr.ChallengeID
 				continue
-			} else if err != nil {
-				return err
 			}
-			break
+			return err
 		}
-		return nil
+	})
+}
+
+func postSessions(b *OGame, lobby, username, password, otpSecret string) (out *GFLoginRes, err error) {
+	if err := b.withCaptcha(func(client *httpclient.Client, challengeID string) (err error) {
+		if b.loginStrategy == MobileLogin {
+			out, err = GFMobileLogin(client, b.ctx, lobby, username, password, otpSecret, challengeID, b.mobileInstallationID)
+			return err
+		}
+		blackbox, err := b.getBlackbox()
+		if err != nil {
+			return errors.New("failed to generate blackbox: " + err.Error())
+		}
+		out, err = GFLoginWithBlackbox(client, b.ctx, lobby, username, password, otpSecret, challengeID, blackbox)
+		return err
 	}); err != nil {
 		return nil, err
 	}
@@ -518,9 +621,82 @@ func postSessions(b *OGame, lobby, username, password, otpSecret string) (out *G
 	cookies = append(cookies, cookie)
 	b.client.Jar.SetCookies(u, cookies)
 	b.bearerToken = out.Token
+	b.bearerTokenExpiry = bearerTokenExpiry(out.Token)
+	if b.bearerTokenExpiry.IsZero() {
+		b.bearerTokenExpiry = time.Now().Add(BearerTokenLifetime)
+	}
+	b.scheduleBearerTokenRefresh(b.bearerTokenExpiry)
+	for _, clb := range b.tokenCallbacks.list() {
+		clb(out.Token)
+	}
 	return out, nil
 }
 
+// bearerTokenExpiry returns the "exp" claim of a JWT bearer token, zero time if the token is not a JWT
+func bearerTokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	by, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(by, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// scheduleBearerTokenRefresh refreshes the bearer token bearerTokenRefreshMargin before it expires.
+// Replaces the previously scheduled refresh, and stops when the bot is disabled.
+func (b *OGame) scheduleBearerTokenRefresh(expiry time.Time) {
+	b.bearerTokenRefreshMu.Lock()
+	defer b.bearerTokenRefreshMu.Unlock()
+	if b.bearerTokenRefreshCancel != nil {
+		b.bearerTokenRefreshCancel()
+	}
+	wait := time.Until(expiry) - bearerTokenRefreshMargin
+	if wait <= 0 {
+		return // already within the margin, the next lobby call refreshes it
+	}
+	ctx, cancel := context.WithCancel(b.ctx)
+	b.bearerTokenRefreshCancel = cancel
+	go func() {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+		if _, err := b.WithPriority(taskRunner.Low).RefreshToken(); err != nil {
+			b.error("failed to refresh bearer token:", err)
+		}
+	}()
+}
+
+// Returns either or not the bearer token we own is expired or about to expire.
+// A token that was provided by the user has an unknown expiry and is never considered expired.
+func (b *OGame) isBearerTokenExpired() bool {
+	if b.bearerTokenExpiry.IsZero() {
+		return false
+	}
+	return time.Now().Add(bearerTokenRefreshMargin).After(b.bearerTokenExpiry)
+}
+
+func (b *OGame) refreshToken() (string, error) {
+	b.debug("refresh bearer token")
+	out, err := postSessions(b, b.lobby, b.Username, b.password, b.otpSecret)
+	if err != nil {
+		return "", err
+	}
+	return out.Token, nil
+}
+
 func (b *OGame) login() error {
 	b.debug("post sessions")
 	postSessionsRes, err := postSessions(b, b.lobby, b.Username, b.password, b.otpSecret)
@@ -557,9 +733,7 @@ func (b *OGame) login() error {
 	if err := b.client.Jar.(*cookiejar.Jar).Save(); err != nil {
 		return err
 	}
-	for _, fn := range b.interceptorCallbacks {
-		fn(http.MethodGet, loginLink, nil, nil, pageHTML)
-	}
+	b.runInterceptors(http.MethodGet, loginLink, nil, nil, pageHTML)
 	return nil
 }
 
@@ -592,12 +766,27 @@ func (b *OGame) loginPart2(server Server) error {
 	// Get server data
 	start := time.Now()
 	b.server = server
+	b.publicAPIMu.Lock()
+	b.publicAPI = nil
+	b.publicAPIMu.Unlock()
 	serverData, err := b.getServerDataWrapper(func() (ServerData, error) {
 		return GetServerData(b.client, b.ctx, b.server.Number, b.server.Language)
 	})
 	if err != nil {
 		return err
 	}
+	b.serverData = normalizeServerData(serverData)
+	lang := server.Language
+	if server.Language == "yu" {
+		lang = "ba"
+	}
+	b.language = lang
+	b.serverURL = "https://s" + utils.FI64(server.Number) + "-" + lang + ".ogame.gameforge.com"
+	b.debug("get server data", time.Since(start))
+	return nil
+}
+
+func normalizeServerData(serverData ServerData) ServerData {
 	if serverData.SpeedFleetWar == 0 {
 		serverData.SpeedFleetWar = 1
 	}
@@ -610,29 +799,29 @@ func (b *OGame) loginPart2(server Server) error {
 	if serverData.SpeedFleet == 0 {
 		serverData.SpeedFleet = serverData.SpeedFleetPeaceful
 	}
-	b.serverData = serverData
-	lang := server.Language
-	if server.Language == "yu" {
-		lang = "ba"
+	return serverData
+}
+
+// extractorForVersion returns the extractor to use with the given ogame version, nil if there is none more specific than v6
+func extractorForVersion(ogVersion *version.Version) extractor.Extractor {
+	if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("9.0.0"))) {
+		return v9.NewExtractor()
+	} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("8.7.4-pl3"))) {
+		return v874.NewExtractor()
+	} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("8.0.0"))) {
+		return v8.NewExtractor()
+	} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("7.1.0-rc0"))) {
+		return v71.NewExtractor()
+	} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("7.0.0-rc0"))) {
+		return v7.NewExtractor()
 	}
-	b.language = lang
-	b.serverURL = "https://s" + utils.FI64(server.Number) + "-" + lang + ".ogame.gameforge.com"
-	b.debug("get server data", time.Since(start))
 	return nil
 }
 
 func (b *OGame) loginPart3(userAccount Account, page parser.OverviewPage) error {
 	if ogVersion, err := version.NewVersion(b.serverData.Version); err == nil {
-		if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("9.0.0"))) {
-			b.extractor = v9.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("8.7.4-pl3"))) {
-			b.extractor = v874.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("8.0.0"))) {
-			b.extractor = v8.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("7.1.0-rc0"))) {
-			b.extractor = v71.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("7.0.0-rc0"))) {
-			b.extractor = v7.NewExtractor()
+		if e := extractorForVersion(ogVersion); e != nil {
+			b.extractor = e
 		}
 		b.extractor.SetLanguage(b.language)
 		b.extractor.SetLifeformEnabled(page.ExtractLifeformEnabled())
@@ -660,26 +849,13 @@ func (b *OGame) loginPart3(userAccount Account, page parser.OverviewPage) error
 
 	// Extract chat host and port
 	m := regexp.MustCompile(`var nodeUrl\s?=\s?"https:\\/\\/([^:]+):(\d+)\\/socket.io\\/socket.io.js"`).FindSubmatch(page.GetContent())
-	chatHost := string(m[1])
-	chatPort := string(m[2])
-
-	if atomic.CompareAndSwapInt32(&b.chatConnectedAtom, 0, 1) {
-		b.closeChatCh = make(chan struct{})
-		go func(b *OGame) {
-			defer atomic.StoreInt32(&b.chatConnectedAtom, 0)
-			chatRetry := exponentialBackoff.New(context.Background(), clockwork.NewRealClock(), 60)
-			chatRetry.LoopForever(func() bool {
-				select {
-				case <-b.closeChatCh:
-					return false
-				default:
-					b.connectChat(chatRetry, chatHost, chatPort)
-				}
-				return true
-			})
-		}(b)
-	} else {
-		b.ReconnectChat()
+	b.chatMu.Lock()
+	b.chatHost = string(m[1])
+	b.chatPort = string(m[2])
+	b.chatMu.Unlock()
+
+	if atomic.LoadInt32(&b.chatDisabledAtom) == 0 {
+		_ = b.startChat()
 	}
 
 	return nil
@@ -725,12 +901,13 @@ func convertMoon(b *OGame, moonIn ogame.Moon) *Moon {
 	return &Moon{
 		ogame: b,
 		Moon: ogame.Moon{
-			ID:         moonIn.ID,
-			Img:        moonIn.Img,
-			Name:       moonIn.Name,
-			Diameter:   moonIn.Diameter,
-			Coordinate: moonIn.Coordinate,
-			Fields:     moonIn.Fields,
+			ID:          moonIn.ID,
+			Img:         moonIn.Img,
+			Name:        moonIn.Name,
+			Diameter:    moonIn.Diameter,
+			Coordinate:  moonIn.Coordinate,
+			Fields:      moonIn.Fields,
+			Temperature: moonIn.Temperature,
 		},
 	}
 }
@@ -757,9 +934,22 @@ func convertCelestial(b *OGame, celestial ogame.Celestial) Celestial {
 
 func (b *OGame) cacheFullPageInfo(page parser.IFullPage) {
 	b.planetsMu.Lock()
+	prevPlanets := planetsToOgame(b.planets)
 	b.planets = convertPlanets(b, page.ExtractPlanets())
+	currPlanets := planetsToOgame(b.planets)
+	cacheSet(b, PlanetsCache, "", currPlanets)
 	b.planetsMu.Unlock()
+	b.touchCache(PlanetsCache)
+	prevVacationMode := b.isVacationModeEnabled
 	b.isVacationModeEnabled = page.ExtractIsInVacation()
+	// Nothing to compare to until the planets were cached once
+	if len(prevPlanets) > 0 {
+		events := diffPlanets(prevPlanets, currPlanets)
+		if prevVacationMode != b.isVacationModeEnabled {
+			events = append(events, CacheEvent{Type: VacationModeChangedEvent, VacationMode: b.isVacationModeEnabled})
+		}
+		b.emitCacheEvents(events)
+	}
 	b.ajaxChatToken, _ = page.ExtractAjaxChatToken()
 	b.characterClass, _ = page.ExtractCharacterClass()
 	b.hasCommander = page.ExtractCommander()
@@ -767,6 +957,9 @@ func (b *OGame) cacheFullPageInfo(page parser.IFullPage) {
 	b.hasEngineer = page.ExtractEngineer()
 	b.hasGeologist = page.ExtractGeologist()
 	b.hasTechnocrat = page.ExtractTechnocrat()
+	if serverTime, err := page.ExtractServerTime(); err == nil && !serverTime.IsZero() {
+		atomic.StoreInt64(&b.serverClockOffsetAtom, int64(serverTime.Sub(time.Now())))
+	}
 
 	switch castedPage := page.(type) {
 	case parser.OverviewPage:
@@ -774,8 +967,7 @@ func (b *OGame) cacheFullPageInfo(page parser.IFullPage) {
 	case parser.PreferencesPage:
 		b.CachedPreferences = castedPage.ExtractPreferences()
 	case parser.ResearchPage:
-		researches := castedPage.ExtractResearch()
-		b.researches = &researches
+		b.setResearches(castedPage.ExtractResearch())
 	}
 }
 
@@ -795,7 +987,9 @@ func (b *OGame) wrapLoginWithBearerToken(token string) (useToken bool, err error
 		useToken, err = b.loginWithBearerToken(token)
 		return useToken, err
 	}
-	return useToken, b.loginWrapper(fn)
+	err = b.loginWrapper(fn)
+	b.loginAttempted(err)
+	return useToken, err
 }
 
 func (b *OGame) wrapLoginWithExistingCookies() (useCookies bool, err error) {
@@ -803,11 +997,15 @@ func (b *OGame) wrapLoginWithExistingCookies() (useCookies bool, err error) {
 		useCookies, err = b.loginWithExistingCookies()
 		return useCookies, err
 	}
-	return useCookies, b.loginWrapper(fn)
+	err = b.loginWrapper(fn)
+	b.loginAttempted(err)
+	return useCookies, err
 }
 
 func (b *OGame) wrapLogin() error {
-	return b.loginWrapper(func() (bool, error) { return false, b.login() })
+	err := b.loginWrapper(func() (bool, error) { return false, b.login() })
+	b.loginAttempted(err)
+	return err
 }
 
 // GetExtractor gets extractor object
@@ -821,6 +1019,7 @@ func (b *OGame) SetOGameCredentials(username, password, otpSecret, bearerToken s
 	b.password = password
 	b.otpSecret = otpSecret
 	b.bearerToken = bearerToken
+	b.bearerTokenExpiry = bearerTokenExpiry(bearerToken)
 }
 
 func (b *OGame) setOGameLobby(lobby string) {
@@ -835,6 +1034,16 @@ func (b *OGame) SetGetServerDataWrapper(newWrapper func(func() (ServerData, erro
 	b.getServerDataWrapper = newWrapper
 }
 
+// SetCache sets the persistent cache the bot writes to and reads from when its caches are empty, nil to disable
+func (b *OGame) SetCache(cache Cache) {
+	b.cache = cache
+}
+
+// SetReportStore sets the store archiving the espionage reports fetched by the bot, nil to disable
+func (b *OGame) SetReportStore(store *ReportStore) {
+	b.reportStore = store
+}
+
 // SetLoginWrapper ...
 func (b *OGame) SetLoginWrapper(newWrapper func(func() (bool, error)) error) {
 	b.loginWrapper = newWrapper
@@ -921,14 +1130,6 @@ func (b *OGame) SetProxy(proxyAddress, username, password, proxyType string, log
 	return b.setProxy(proxyAddress, username, password, proxyType, loginOnly, config)
 }
 
-func (b *OGame) connectChat(chatRetry *exponentialBackoff.ExponentialBackoff, host, port string) {
-	if b.IsV8() || b.IsV9() {
-		b.connectChatV8(chatRetry, host, port)
-	} else {
-		b.connectChatV7(chatRetry, host, port)
-	}
-}
-
 // Socket IO v3 timestamp encoding
 // https://github.com/unshiftio/yeast/blob/28d15f72fc5a4273592bc209056c328a54e2b522/index.js#L17
 // fmt.Println(yeast(time.Now().UnixNano() / 1000000))
@@ -942,215 +1143,36 @@ func yeast(num int64) (encoded string) {
 	return
 }
 
-func (b *OGame) connectChatV8(chatRetry *exponentialBackoff.ExponentialBackoff, host, port string) {
-	token := yeast(time.Now().UnixNano() / 1000000)
-	req, err := http.NewRequest(http.MethodGet, "https://"+host+":"+port+"/socket.io/?EIO=4&transport=polling&t="+token, nil)
-	if err != nil {
-		b.error("failed to create request:", err)
-		return
-	}
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		b.error("failed to get socket.io token:", err)
-		return
-	}
-	defer resp.Body.Close()
-	chatRetry.Reset()
-	by, _ := ioutil.ReadAll(resp.Body)
-	m := regexp.MustCompile(`"sid":"([^"]+)"`).FindSubmatch(by)
-	if len(m) != 2 {
-		b.error("failed to get websocket sid:", err)
-		return
-	}
-	sid := string(m[1])
-
-	origin := "https://" + host + ":" + port + "/"
-	wssURL := "wss://" + host + ":" + port + "/socket.io/?EIO=4&transport=websocket&sid=" + sid
-	b.ws, err = websocket.Dial(wssURL, "", origin)
-	if err != nil {
-		b.error("failed to dial websocket:", err)
-		return
-	}
-	_ = websocket.Message.Send(b.ws, "2probe")
-
-	// Recv msgs
-LOOP:
-	for {
-		select {
-		case <-b.closeChatCh:
-			break LOOP
-		default:
-		}
-
-		var buf string
-		if err := b.ws.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
-			b.error("failed to set read deadline:", err)
-		}
-		err := websocket.Message.Receive(b.ws, &buf)
-		if err != nil {
-			if err == io.EOF {
-				b.error("chat eof:", err)
-				break
-			} else if strings.HasSuffix(err.Error(), "use of closed network connection") {
-				break
-			} else if strings.HasSuffix(err.Error(), "i/o timeout") {
-				continue
-			} else {
-				b.error("chat unexpected error", err)
-				// connection reset by peer
-				break
-			}
-		}
-		for _, clb := range b.wsCallbacks {
-			go clb([]byte(buf))
-		}
-		if buf == "3probe" {
-			_ = websocket.Message.Send(b.ws, "5")
-			_ = websocket.Message.Send(b.ws, "40/chat,")
-			_ = websocket.Message.Send(b.ws, "40/auctioneer,")
-		} else if buf == "2" {
-			_ = websocket.Message.Send(b.ws, "3")
-		} else if regexp.MustCompile(`40/auctioneer,{"sid":"[^"]+"}`).MatchString(buf) {
-			b.debug("got auctioneer sid")
-		} else if regexp.MustCompile(`40/chat,{"sid":"[^"]+"}`).MatchString(buf) {
-			b.debug("got chat sid")
-			_ = websocket.Message.Send(b.ws, `42/chat,`+utils.FI64(b.sessionChatCounter)+`["authorize","`+b.ogameSession+`"]`)
-			b.sessionChatCounter++
-		} else if regexp.MustCompile(`43/chat,\d+\[true]`).MatchString(buf) {
-			b.debug("chat connected")
-		} else if regexp.MustCompile(`43/chat,\d+\[false]`).MatchString(buf) {
-			b.error("Failed to connect to chat")
-		} else if strings.HasPrefix(buf, `42/chat,["chat",`) {
-			payload := strings.TrimPrefix(buf, `42/chat,["chat",`)
-			payload = strings.TrimSuffix(payload, `]`)
-			var chatMsg ogame.ChatMsg
-			if err := json.Unmarshal([]byte(payload), &chatMsg); err != nil {
-				b.error("Unable to unmarshal chat payload", err, payload)
-				continue
-			}
-			for _, clb := range b.chatCallbacks {
-				clb(chatMsg)
-			}
-		} else if regexp.MustCompile(`^\d+/auctioneer`).MatchString(buf) {
-			// 42/auctioneer,["timeLeft","<span style=\"color:#99CC00;\"><b>approx. 30m</b></span> remaining until the auction ends"] // every minute
-			// 42/auctioneer,["timeLeft","Next auction in:<br />\n<span class=\"nextAuction\" id=\"nextAuction\">117</span>"]
-			// 42/auctioneer,["new bid",{"player":{"id":219657,"name":"Payback","link":"https://s129-en.ogame.gameforge.com/game/index.php?page=ingame&component=galaxy&galaxy=2&system=146"},"sum":5000,"price":6000,"bids":5,"auctionId":"42894"}]
-			// 42/auctioneer,["new auction",{"info":"<span style=\"color:#99CC00;\"><b>approx. 35m</b></span> remaining until the auction ends","item":{"uuid":"0968999df2fe956aa4a07aea74921f860af7d97f","image":"55d4b1750985e4843023d7d0acd2b9bafb15f0b7","rarity":"rare"},"oldAuction":{"item":{"uuid":"3c9f85221807b8d593fa5276cdf7af9913c4a35d","imageSmall":"286f3eaf6072f55d8858514b159d1df5f16a5654","rarity":"common"},"time":"20.05.2021 08:42:07","bids":5,"sum":5000,"player":{"id":219657,"name":"Payback","link":"http://s129-en.ogame.gameforge.com/game/index.php?page=ingame&component=galaxy&galaxy=2&system=146"}},"auctionId":42895}]
-			// 42/auctioneer,["auction finished",{"sum":5000,"player":{"id":219657,"name":"Payback","link":"http://s129-en.ogame.gameforge.com/game/index.php?page=ingame&component=galaxy&galaxy=2&system=146"},"bids":5,"info":"Next auction in:<br />\n<span class=\"nextAuction\" id=\"nextAuction\">1072</span>","time":"08:42"}]
-			parts := strings.SplitN(buf, ",", 2)
-			msg := parts[1]
-			var pck any = msg
-			var out []any
-			_ = json.Unmarshal([]byte(msg), &out)
-			if len(out) == 0 {
-				b.error("unknown message received:", buf)
-				continue
-			}
-			if name, ok := out[0].(string); ok {
-				arg := out[1]
-				if name == "new bid" {
-					if firstArg, ok := arg.(map[string]any); ok {
-						auctionID := utils.DoParseI64(utils.DoCastStr(firstArg["auctionId"]))
-						pck1 := ogame.AuctioneerNewBid{
-							Sum:       int64(utils.DoCastF64(firstArg["sum"])),
-							Price:     int64(utils.DoCastF64(firstArg["price"])),
-							Bids:      int64(utils.DoCastF64(firstArg["bids"])),
-							AuctionID: auctionID,
-						}
-						if player, ok := firstArg["player"].(map[string]any); ok {
-							pck1.Player.ID = int64(utils.DoCastF64(player["id"]))
-							pck1.Player.Name = utils.DoCastStr(player["name"])
-							pck1.Player.Link = utils.DoCastStr(player["link"])
-						}
-						pck = pck1
-					}
-				} else if name == "timeLeft" {
-					if timeLeftMsg, ok := arg.(string); ok {
-						if strings.Contains(timeLeftMsg, "color:") {
-							doc, _ := goquery.NewDocumentFromReader(strings.NewReader(timeLeftMsg))
-							rgx := regexp.MustCompile(`\d+`)
-							txt := rgx.FindString(doc.Find("b").Text())
-							approx := utils.DoParseI64(txt)
-							pck = ogame.AuctioneerTimeRemaining{Approx: approx * 60}
-						} else if strings.Contains(timeLeftMsg, "nextAuction") {
-							doc, _ := goquery.NewDocumentFromReader(strings.NewReader(timeLeftMsg))
-							rgx := regexp.MustCompile(`\d+`)
-							txt := rgx.FindString(doc.Find("span").Text())
-							secs := utils.DoParseI64(txt)
-							pck = ogame.AuctioneerNextAuction{Secs: secs}
-						}
-					}
-				} else if name == "new auction" {
-					if firstArg, ok := arg.(map[string]any); ok {
-						pck1 := ogame.AuctioneerNewAuction{
-							AuctionID: int64(utils.DoCastF64(firstArg["auctionId"])),
-						}
-						if infoMsg, ok := firstArg["info"].(string); ok {
-							doc, _ := goquery.NewDocumentFromReader(strings.NewReader(infoMsg))
-							rgx := regexp.MustCompile(`\d+`)
-							txt := rgx.FindString(doc.Find("b").Text())
-							approx := utils.DoParseI64(txt)
-							pck1.Approx = approx * 60
-						}
-						pck = pck1
-					}
-				} else if name == "auction finished" {
-					if firstArg, ok := arg.(map[string]any); ok {
-						pck1 := ogame.AuctioneerAuctionFinished{
-							Sum:  int64(utils.DoCastF64(firstArg["sum"])),
-							Bids: int64(utils.DoCastF64(firstArg["bids"])),
-						}
-						if player, ok := firstArg["player"].(map[string]any); ok {
-							pck1.Player.ID = int64(utils.DoCastF64(player["id"]))
-							pck1.Player.Name = utils.DoCastStr(player["name"])
-							pck1.Player.Link = utils.DoCastStr(player["link"])
-						}
-						pck = pck1
-					}
-				}
-			}
-			for _, clb := range b.auctioneerCallbacks {
-				clb(pck)
-			}
-		} else {
-			b.error("unknown message received:", buf)
-			time.Sleep(time.Second)
-		}
-	}
-}
-
-func (b *OGame) connectChatV7(chatRetry *exponentialBackoff.ExponentialBackoff, host, port string) {
+func (b *OGame) connectChatV7(host, port string, closeCh chan struct{}) (bool, error) {
 	req, err := http.NewRequest(http.MethodGet, "https://"+host+":"+port+"/socket.io/1/?t="+utils.FI64(time.Now().UnixNano()/int64(time.Millisecond)), nil)
 	if err != nil {
-		b.error("failed to create request:", err)
-		return
+		return false, err
 	}
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		b.error("failed to get socket.io token:", err)
-		return
+		return false, errors.New("failed to get socket.io token: " + err.Error())
 	}
 	defer resp.Body.Close()
-	chatRetry.Reset()
 	by, _ := ioutil.ReadAll(resp.Body)
 	token := strings.Split(string(by), ":")[0]
+	if token == "" {
+		return false, errors.New("failed to get socket.io token: " + string(by))
+	}
 
 	origin := "https://" + host + ":" + port + "/"
 	wssURL := "wss://" + host + ":" + port + "/socket.io/1/websocket/" + token
 	b.ws, err = websocket.Dial(wssURL, "", origin)
 	if err != nil {
-		b.error("failed to dial websocket:", err)
-		return
+		return false, errors.New("failed to dial websocket: " + err.Error())
 	}
+	defer b.ws.Close()
 
 	// Recv msgs
-LOOP:
 	for {
 		select {
-		case <-b.closeChatCh:
-			break LOOP
+		case <-closeCh:
+			return true, nil
 		default:
 		}
 
@@ -1160,18 +1182,14 @@ LOOP:
 		}
 		n, err := b.ws.Read(buf)
 		if err != nil {
-			if err == io.EOF {
-				b.error("chat eof:", err)
-				break
-			} else if strings.HasSuffix(err.Error(), "use of closed network connection") {
-				break
-			} else if strings.HasSuffix(err.Error(), "i/o timeout") {
+			if strings.HasSuffix(err.Error(), "i/o timeout") {
 				continue
-			} else {
-				b.error("chat unexpected error", err)
-				// connection reset by peer
-				break
 			}
+			if err == io.EOF || strings.HasSuffix(err.Error(), "use of closed network connection") {
+				return true, err
+			}
+			b.error("chat unexpected error", err)
+			return true, err // connection reset by peer
 		}
 		for _, clb := range b.wsCallbacks {
 			go clb(buf[0:n])
@@ -1184,6 +1202,11 @@ LOOP:
 			authMsg := `5:` + utils.FI64(b.sessionChatCounter) + `+:/chat:{"name":"authorize","args":["` + b.ogameSession + `"]}`
 			_, _ = b.ws.Write([]byte(authMsg))
 			b.sessionChatCounter++
+		} else if bytes.Equal(msg, []byte("0::")) {
+			return true, errors.New("chat closed by the server")
+		} else if bytes.HasPrefix(msg, []byte("0::/")) {
+			b.debug("disconnected from", string(msg[3:]), ", subscribing again")
+			_, _ = b.ws.Write(append([]byte("1::"), msg[3:]...))
 		} else if bytes.Equal(msg, []byte("2::")) {
 			_, _ = b.ws.Write([]byte("2::"))
 		} else if regexp.MustCompile(`\d+::/auctioneer`).Match(msg) {
@@ -1194,75 +1217,16 @@ LOOP:
 			// 5::/auctioneer:{"name":"auction finished","args":[{"sum":2000,"player":{"id":106734,"name":"Someone","link":"http://s152-en.ogame.gameforge.com/game/index.php?page=ingame&component=galaxy&galaxy=4&system=116"},"bids":2,"info":"Next auction in:<br />\n<span class=\"nextAuction\" id=\"nextAuction\">1390</span>","time":"06:36"}]}
 			msg = bytes.TrimPrefix(msg, []byte("5::/auctioneer:"))
 			var pck any = string(msg)
-			var out map[string]any
-			_ = json.Unmarshal(msg, &out)
-			if args, ok := out["args"].([]any); ok {
-				if len(args) > 0 {
-					if name, ok := out["name"].(string); ok && name == "new bid" {
-						if firstArg, ok := args[0].(map[string]any); ok {
-							auctionID := utils.DoParseI64(utils.DoCastStr(firstArg["auctionId"]))
-							pck1 := ogame.AuctioneerNewBid{
-								Sum:       int64(utils.DoCastF64(firstArg["sum"])),
-								Price:     int64(utils.DoCastF64(firstArg["price"])),
-								Bids:      int64(utils.DoCastF64(firstArg["bids"])),
-								AuctionID: auctionID,
-							}
-							if player, ok := firstArg["player"].(map[string]any); ok {
-								pck1.Player.ID = int64(utils.DoCastF64(player["id"]))
-								pck1.Player.Name = utils.DoCastStr(player["name"])
-								pck1.Player.Link = utils.DoCastStr(player["link"])
-							}
-							pck = pck1
-						}
-					} else if name, ok := out["name"].(string); ok && name == "timeLeft" {
-						if timeLeftMsg, ok := args[0].(string); ok {
-							if strings.Contains(timeLeftMsg, "color:") {
-								doc, _ := goquery.NewDocumentFromReader(strings.NewReader(timeLeftMsg))
-								rgx := regexp.MustCompile(`\d+`)
-								txt := rgx.FindString(doc.Find("b").Text())
-								approx := utils.DoParseI64(txt)
-								pck = ogame.AuctioneerTimeRemaining{Approx: approx * 60}
-							} else if strings.Contains(timeLeftMsg, "nextAuction") {
-								doc, _ := goquery.NewDocumentFromReader(strings.NewReader(timeLeftMsg))
-								rgx := regexp.MustCompile(`\d+`)
-								txt := rgx.FindString(doc.Find("span").Text())
-								secs := utils.DoParseI64(txt)
-								pck = ogame.AuctioneerNextAuction{Secs: secs}
-							}
-						}
-					} else if name, ok := out["name"].(string); ok && name == "new auction" {
-						if firstArg, ok := args[0].(map[string]any); ok {
-							pck1 := ogame.AuctioneerNewAuction{
-								AuctionID: int64(utils.DoCastF64(firstArg["auctionId"])),
-							}
-							if infoMsg, ok := firstArg["info"].(string); ok {
-								doc, _ := goquery.NewDocumentFromReader(strings.NewReader(infoMsg))
-								rgx := regexp.MustCompile(`\d+`)
-								txt := rgx.FindString(doc.Find("b").Text())
-								approx := utils.DoParseI64(txt)
-								pck1.Approx = approx * 60
-							}
-							pck = pck1
-						}
-					} else if name, ok := out["name"].(string); ok && name == "auction finished" {
-						if firstArg, ok := args[0].(map[string]any); ok {
-							pck1 := ogame.AuctioneerAuctionFinished{
-								Sum:  int64(utils.DoCastF64(firstArg["sum"])),
-								Bids: int64(utils.DoCastF64(firstArg["bids"])),
-							}
-							if player, ok := firstArg["player"].(map[string]any); ok {
-								pck1.Player.ID = int64(utils.DoCastF64(player["id"]))
-								pck1.Player.Name = utils.DoCastStr(player["name"])
-								pck1.Player.Link = utils.DoCastStr(player["link"])
-							}
-							pck = pck1
-						}
-					}
-				}
+			var out struct {
+				Name string `json:"name"`
+				Args []any  `json:"args"`
 			}
-			for _, clb := range b.auctioneerCallbacks {
-				clb(pck)
+			if err := json.Unmarshal(msg, &out); err == nil && len(out.Args) > 0 {
+				if typed := parseAuctioneerEvent(out.Name, out.Args[0]); typed != nil {
+					pck = typed
+				}
 			}
+			b.deliverAuctioneerPacket(pck)
 		} else if regexp.MustCompile(`6::/chat:\d+\+\[true]`).Match(msg) {
 			b.debug("chat connected")
 		} else if regexp.MustCompile(`6::/chat:\d+\+\[false]`).Match(msg) {
@@ -1275,13 +1239,10 @@ LOOP:
 				continue
 			}
 			for _, chatMsg := range chatPayload.Args {
-				for _, clb := range b.chatCallbacks {
-					clb(chatMsg)
-				}
+				b.deliverChatMsg(chatMsg)
 			}
 		} else {
-			b.error("unknown message received:", string(buf))
-			time.Sleep(time.Second)
+			b.debug("unknown frame received:", string(msg))
 		}
 	}
 }
@@ -1291,22 +1252,90 @@ func (b *OGame) ReconnectChat() bool {
 	if b.ws == nil {
 		return false
 	}
+	if b.IsV8() || b.IsV9() {
+		_ = websocket.Message.Send(b.ws, encodeSocketIOPacket(sioConnect, "/chat", -1, ""))
+		return true
+	}
 	_ = websocket.Message.Send(b.ws, "1::/chat")
 	return true
 }
 
+// auctionBidWSPayload builds the auctioneer "bid" packet, with the same fields as the http bid form
+func auctionBidWSPayload(token string, bid map[ogame.CelestialID]ogame.Resources) ([]byte, error) {
+	type bidResources struct {
+		Metal     int64 `json:"metal"`
+		Crystal   int64 `json:"crystal"`
+		Deuterium int64 `json:"deuterium"`
+	}
+	planets := make(map[string]bidResources)
+	for celestialID, resources := range bid {
+		planets[utils.FI64(celestialID)] = bidResources{resources.Metal, resources.Crystal, resources.Deuterium}
+	}
+	return json.Marshal(map[string]any{"planets": planets, "honor": 0, "token": token})
+}
+
+// auctionBidWSTimeout is how long sendAuctionBidWS waits for the auctioneer to broadcast the bid
+var auctionBidWSTimeout = 5 * time.Second
+
+// sendAuctionBidWS sends a bid packet on the auctioneer namespace of the websocket,
+// and waits for the auctioneer to broadcast a new bid from the player
+func (b *OGame) sendAuctionBidWS(token string, bid map[ogame.CelestialID]ogame.Resources) (sent bool, err error) {
+	if !b.isChatConnected() {
+		return false, errors.New("websocket not connected")
+	}
+	payload, err := auctionBidWSPayload(token, bid)
+	if err != nil {
+		return false, err
+	}
+	playerID := b.Player.PlayerID
+	confirmed := make(chan struct{}, 1)
+	sub := b.auctioneerCallbacks.add(func(pck any) {
+		if newBid, ok := pck.(ogame.AuctioneerNewBid); ok && newBid.Player.ID == playerID {
+			select {
+			case confirmed <- struct{}{}:
+			default:
+			}
+		}
+	})
+	defer sub.Close()
+	if b.IsV8() || b.IsV9() {
+		err = websocket.Message.Send(b.ws, encodeSocketIOPacket(sioEvent, "/auctioneer", -1, `["bid",`+string(payload)+`]`))
+	} else {
+		_, err = b.ws.Write([]byte(`5::/auctioneer:{"name":"bid","args":[` + string(payload) + `]}`))
+	}
+	if err != nil {
+		return false, err
+	}
+	select {
+	case <-confirmed:
+		return true, nil
+	case <-time.After(auctionBidWSTimeout):
+		return true, ErrAuctionBidNotConfirmed
+	}
+}
+
+// doAuctionWS bids using the websocket with the token of an already fetched auction.
+// Falls back to http only if the bid could not be sent, a bid that was sent but not confirmed is not retried
+// since it could be accepted late and bid twice.
+func (b *OGame) doAuctionWS(auction ogame.Auction, bid map[ogame.CelestialID]ogame.Resources) error {
+	if auction.Token != "" && !auction.HasFinished {
+		sent, err := b.sendAuctionBidWS(auction.Token, bid)
+		if sent {
+			b.invalidateResourcesDetails()
+			return err
+		}
+	}
+	return b.doAuction(0, bid)
+}
+
 func (b *OGame) logout() {
 	_, _ = b.getPage(LogoutPageName)
 	_ = b.client.Jar.(*cookiejar.Jar).Save()
 	if atomic.CompareAndSwapInt32(&b.isLoggedInAtom, 1, 0) {
-		select {
-		case <-b.closeChatCh:
-		default:
-			close(b.closeChatCh)
-			if b.ws != nil {
-				_ = b.ws.Close()
-			}
-		}
+		b.chatMu.Lock()
+		b.stopChat()
+		b.chatMu.Unlock()
+		b.loggedOut()
 	}
 }
 
@@ -1395,39 +1424,19 @@ func (b *OGame) preRequestChecks() error {
 }
 
 func (b *OGame) execRequest(method, finalURL string, payload, vals url.Values) ([]byte, error) {
-	var body io.Reader
-	if method == http.MethodPost {
-		body = strings.NewReader(payload.Encode())
-	}
-
-	req, err := http.NewRequest(method, finalURL, body)
-	if err != nil {
+	if err := b.taskCtxErr(); err != nil {
 		return []byte{}, err
 	}
 
-	if method == http.MethodPost {
-		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	}
-	req.Header.Add("Accept-Encoding", "gzip, deflate, br")
-	if IsAjaxPage(vals) {
-		req.Header.Add("X-Requested-With", "XMLHttpRequest")
-	}
-
-	req = req.WithContext(b.ctx)
-	resp, err := b.client.Do(req)
+	req := &Request{Method: method, URL: finalURL, Vals: vals, Payload: payload, Header: make(http.Header)}
+	resp, err := b.requestHandler()(req)
 	if err != nil {
 		return []byte{}, err
 	}
-	defer resp.Body.Close()
-
 	if resp.StatusCode >= http.StatusInternalServerError {
-		return []byte{}, err
-	}
-	by, err := utils.ReadBody(resp)
-	if err != nil {
-		return []byte{}, err
+		return []byte{}, nil
 	}
-	return by, nil
+	return resp.Body, nil
 }
 
 func getPageName(vals url.Values) string {
@@ -1505,6 +1514,12 @@ func (b *OGame) pageContent(method string, vals, payload url.Values, opts ...Opt
 		return []byte{}, err
 	}
 
+	if !cfg.SkipHumanize {
+		if err := b.humanize(); err != nil {
+			return []byte{}, err
+		}
+	}
+
 	setCPParam(b, vals, cfg)
 
 	alterPayload(method, b, vals, payload)
@@ -1515,13 +1530,6 @@ func (b *OGame) pageContent(method string, vals, payload url.Values, opts ...Opt
 	var pageHTMLBytes []byte
 
 	clb := func() (err error) {
-		if method == http.MethodPost {
-			// Needs to be inside the withRetry, so if we need to re-login the redirect is back for the login call
-			// Prevent redirect (301) https://stackoverflow.com/a/38150816/4196220
-			b.client.CheckRedirect = func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }
-			defer func() { b.client.CheckRedirect = nil }()
-		}
-
 		pageHTMLBytes, err = b.execRequest(method, finalURL, payload, vals)
 		if err != nil {
 			return err
@@ -1546,12 +1554,13 @@ func (b *OGame) pageContent(method string, vals, payload url.Values, opts ...Opt
 		return []byte{}, err
 	}
 
+	// Actions (build, cancel, send fleet, trade...) are posted or carry a token, they change the resources
+	if method == http.MethodPost || vals.Get("token") != "" {
+		b.invalidateResourcesDetails()
+	}
+
 	if !cfg.SkipInterceptor {
-		go func() {
-			for _, fn := range b.interceptorCallbacks {
-				fn(method, finalURL, vals, payload, pageHTMLBytes)
-			}
-		}()
+		go b.runInterceptors(method, finalURL, vals, payload, pageHTMLBytes)
 	}
 
 	return pageHTMLBytes, nil
@@ -1606,19 +1615,16 @@ func (b *OGame) withoutRetry(fn func() error) error {
 }
 
 func (b *OGame) withRetry(fn func() error) error {
-	maxRetry := 10
-	retryInterval := 1
+	policy := b.getLoginPolicy()
+	maxRetry := policy.maxAttempts()
+	attempt := int64(0)
 	retry := func(err error) error {
 		b.error(err.Error())
 		select {
-		case <-time.After(time.Duration(retryInterval) * time.Second):
+		case <-time.After(policy.backoff(attempt)):
 		case <-b.ctx.Done():
 			return ogame.ErrBotInactive
 		}
-		retryInterval *= 2
-		if retryInterval > 60 {
-			retryInterval = 60
-		}
 		return nil
 	}
 
@@ -1634,8 +1640,8 @@ func (b *OGame) withRetry(fn func() error) error {
 		if !b.IsLoggedIn() {
 			return ogame.ErrBotLoggedOut
 		}
-		maxRetry--
-		if maxRetry <= 0 {
+		attempt++
+		if attempt >= maxRetry {
 			return errors.Wrap(err, ogame.ErrFailedExecuteCallback.Error())
 		}
 
@@ -1644,15 +1650,16 @@ func (b *OGame) withRetry(fn func() error) error {
 		}
 
 		if err == ogame.ErrNotLogged {
+			if b.reloginStopped() {
+				return ogame.ErrTooManyCaptchaFailures
+			}
 			if _, loginErr := b.wrapLoginWithExistingCookies(); loginErr != nil {
 				b.error(loginErr.Error()) // log error
-				if loginErr == ogame.ErrAccountNotFound ||
-					loginErr == ogame.ErrAccountBlocked ||
-					loginErr == ogame.ErrBadCredentials ||
-					loginErr == ogame.ErrOTPRequired ||
-					loginErr == ogame.ErrOTPInvalid {
-					return loginErr
+				if stopErr := b.handleReloginError(loginErr); stopErr != nil {
+					return stopErr
 				}
+			} else {
+				b.reloggedIn()
 			}
 		}
 	}
@@ -1678,9 +1685,20 @@ func (b *OGame) constructionTime(id ogame.ID, nbr int64, facilities ogame.Facili
 	return obj.ConstructionTime(nbr, b.getUniverseSpeed(), facilities, b.hasTechnocrat, b.isDiscoverer())
 }
 
+// lfConstructionTime same as constructionTime, with the time reductions of the lifeform buildings of the celestial
+func (b *OGame) lfConstructionTime(id ogame.ID, nbr int64, facilities ogame.Facilities, lfBuildings ogame.LfBuildings) time.Duration {
+	duration := b.constructionTime(id, nbr, facilities)
+	if id.IsResourceBuilding() || id.IsFacility() {
+		secs := math.Floor(duration.Seconds() * (1 - lfBuildings.BuildingTimeReduction()))
+		duration = time.Duration(math.Max(1, secs)) * time.Second
+	}
+	return duration
+}
+
 func (b *OGame) enable() {
 	b.ctx, b.cancelCtx = context.WithCancel(context.Background())
 	atomic.StoreInt32(&b.isEnabledAtom, 1)
+	b.restartConstructionWatchers()
 	b.stateChanged(false, "Enable")
 }
 
@@ -1706,6 +1724,15 @@ func (b *OGame) isDiscoverer() bool {
 	return b.characterClass == ogame.Discoverer
 }
 
+func (b *OGame) researchDuration(id ogame.ID, level, researchLab int64) time.Duration {
+	tech, ok := ogame.Objs.ByID(id).(ogame.Technology)
+	if !ok {
+		return 0
+	}
+	researchSpeed := b.getUniverseSpeed() * utils.MaxInt(b.serverData.ResearchDurationDivisor, 1)
+	return tech.TechnologyConstructionTime(level, researchSpeed, ogame.Facilities{ResearchLab: researchLab}, b.hasTechnocrat, b.isDiscoverer())
+}
+
 func (b *OGame) getUniverseSpeed() int64 {
 	return b.serverData.Speed
 }
@@ -1749,42 +1776,149 @@ func (b *OGame) setVacationMode() error {
 	return err
 }
 
-func (b *OGame) getPlanets() []Planet {
-	page, err := getPage[parser.OverviewPage](b)
-	if err != nil {
-		return []Planet{}
+// isValidNickname follows the rules of the preferences page validator, letters and digits separated
+// by at most 3 spaces, 3 underscores and 3 hyphens, never at the beginning or end nor next to each other
+func isValidNickname(nick string) bool {
+	if len([]rune(nick)) < 3 || len([]rune(nick)) > 20 || !planetNameRgx.MatchString(nick) {
+		return false
 	}
-	return convertPlanets(b, page.ExtractPlanets())
+	return strings.Count(nick, " ") <= 3 && strings.Count(nick, "_") <= 3 && strings.Count(nick, "-") <= 3
 }
 
-func (b *OGame) getPlanet(v any) (Planet, error) {
-	page, err := getPage[parser.OverviewPage](b)
-	if err != nil {
-		return Planet{}, err
+func (b *OGame) changeNickname(newNick string) error {
+	if !isValidNickname(newNick) {
+		return ogame.ErrInvalidNickname
 	}
-	planet, err := page.ExtractPlanet(v)
+	vals := url.Values{"page": {"ajax"}, "component": {ChangenickAjaxPageName}}
+	pageHTML, err := b.getPageContent(vals)
 	if err != nil {
-		return Planet{}, err
+		return err
 	}
-	return convertPlanet(b, planet), nil
-}
-
-func (b *OGame) getMoons() []Moon {
-	page, err := getPage[parser.OverviewPage](b)
-	if err != nil {
-		return []Moon{}
+	// The form is replaced by a notice while the name cannot be changed
+	if !bytes.Contains(pageHTML, []byte(`name="db_character"`)) {
+		return ogame.NewNicknameChangeCooldownError(extractNicknameCooldown(pageHTML, b.location))
 	}
-	return convertMoons(b, page.ExtractMoons())
-}
-
-func (b *OGame) getMoon(v any) (Moon, error) {
-	page, err := getPage[parser.OverviewPage](b)
-	if err != nil {
-		return Moon{}, err
+	m := regexp.MustCompile(`name=['"]token['"] value=['"](\w+)['"]`).FindSubmatch(pageHTML)
+	if len(m) < 2 {
+		return errors.New("unable to find token")
 	}
-	moon, err := page.ExtractMoon(v)
+	// The game asks for the account password as confirmation
+	payload := url.Values{"db_character": {newNick}, "db_character_password": {b.password}, "token": {string(m[1])}}
+	vals.Set("asJson", "1")
+	by, err := b.postPageContent(vals, payload)
 	if err != nil {
-		return Moon{}, err
+		return err
+	}
+	// {"status":"failure","errors":[{"message":"This name is already taken."}],"components":[],"newAjaxToken":"..."}
+	var res struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(by, &res); err != nil {
+		return errors.New("unexpected response : " + err.Error())
+	}
+	if res.Status != "success" {
+		msg := res.Message
+		if len(res.Errors) > 0 {
+			msg = res.Errors[0].Message
+		}
+		return fmt.Errorf("%w : %s", ogame.ErrInvalidNickname, msg)
+	}
+	b.Player.PlayerName = newNick
+	return nil
+}
+
+// extractNicknameCooldown returns the date found in the notice shown instead of the name change form, zero if none
+func extractNicknameCooldown(pageHTML []byte, loc *time.Location) (until time.Time) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	m := regexp.MustCompile(`(\d{2}\.\d{2}\.\d{4} \d{2}:\d{2}:\d{2})`).FindSubmatch(pageHTML)
+	if len(m) == 2 {
+		until, _ = time.ParseInLocation("02.01.2006 15:04:05", string(m[1]), loc)
+	}
+	return
+}
+
+func (b *OGame) unsetVacationMode() error {
+	vals := url.Values{"page": {"ingame"}, "component": {"preferences"}}
+	pageHTML, err := b.getPageContent(vals)
+	if err != nil {
+		return err
+	}
+	if !b.extractor.ExtractPreferences(pageHTML).UrlaubsModus {
+		return nil
+	}
+	if until, locked := extractVacationModeLock(pageHTML, b.location); locked {
+		return ogame.NewVacationModeLockedError(until)
+	}
+	rgx := regexp.MustCompile(`type='hidden' name='token' value='(\w+)'`)
+	m := rgx.FindSubmatch(pageHTML)
+	if len(m) < 2 {
+		return errors.New("unable to find token")
+	}
+	token := string(m[1])
+	payload := url.Values{"mode": {"save"}, "selectedTab": {"0"}, "token": {token}}
+	_, err = b.postPageContent(vals, payload)
+	return err
+}
+
+// extractVacationModeLock returns either or not the vacation mode button of the preferences page is disabled,
+// and the earliest disable time displayed in the advice bar
+func extractVacationModeLock(pageHTML []byte, loc *time.Location) (until time.Time, locked bool) {
+	if !regexp.MustCompile(`#vacation-mode-button'\)\.button\(\{\s*disabled:\s*true`).Match(pageHTML) {
+		return
+	}
+	locked = true
+	if loc == nil {
+		loc = time.UTC
+	}
+	m := regexp.MustCompile(`href="[^"]*page=preferences[^"]*"[^>]*title="[^"]*?(\d{2}\.\d{2}\.\d{4} \d{2}:\d{2}:\d{2})`).FindSubmatch(pageHTML)
+	if len(m) == 2 {
+		until, _ = time.ParseInLocation("02.01.2006 15:04:05", string(m[1]), loc)
+	}
+	return
+}
+
+func (b *OGame) getPlanets() []Planet {
+	page, err := getPage[parser.OverviewPage](b)
+	if err != nil {
+		return []Planet{}
+	}
+	return convertPlanets(b, page.ExtractPlanets())
+}
+
+func (b *OGame) getPlanet(v any) (Planet, error) {
+	page, err := getPage[parser.OverviewPage](b)
+	if err != nil {
+		return Planet{}, err
+	}
+	planet, err := page.ExtractPlanet(v)
+	if err != nil {
+		return Planet{}, err
+	}
+	return convertPlanet(b, planet), nil
+}
+
+func (b *OGame) getMoons() []Moon {
+	page, err := getPage[parser.OverviewPage](b)
+	if err != nil {
+		return []Moon{}
+	}
+	return convertMoons(b, page.ExtractMoons())
+}
+
+func (b *OGame) getMoon(v any) (Moon, error) {
+	page, err := getPage[parser.OverviewPage](b)
+	if err != nil {
+		return Moon{}, err
+	}
+	moon, err := page.ExtractMoon(v)
+	if err != nil {
+		return Moon{}, err
 	}
 	cMoon := convertMoon(b, moon)
 	return *cMoon, nil
@@ -1802,6 +1936,19 @@ func (b *OGame) getCelestials() ([]Celestial, error) {
 	return convertCelestials(b, celestials), nil
 }
 
+// getPlanetInfos loads the overview of a single celestial, which also refreshes the cached planets list
+func (b *OGame) getPlanetInfos(celestialID ogame.CelestialID) (Celestial, error) {
+	page, err := getPage[parser.OverviewPage](b, ChangePlanet(celestialID))
+	if err != nil {
+		return nil, err
+	}
+	celestial, err := page.ExtractCelestial(celestialID)
+	if err != nil {
+		return nil, err
+	}
+	return convertCelestial(b, celestial), nil
+}
+
 func (b *OGame) getCelestial(v any) (Celestial, error) {
 	page, err := getPage[parser.OverviewPage](b)
 	if err != nil {
@@ -1834,10 +1981,31 @@ func (b *OGame) recruitOfficer(typ, days int64) error {
 		"token": {token}}); err != nil {
 		return err
 	}
+	b.dmLedger.record("RecruitOfficer", 0, utils.FI64(typ)+":"+utils.FI64(days), 0)
 	return nil
 }
 
-func (b *OGame) abandon(v any) error {
+func (b *OGame) getOfficers() ([]ogame.Officer, error) {
+	page, err := getPage[parser.PremiumPage](b)
+	if err != nil {
+		return nil, err
+	}
+	return page.ExtractOfficers()
+}
+
+// DefaultAbandonMaxResources resources a planet can hold and still be abandoned, unless set with AbandonMaxResources
+const DefaultAbandonMaxResources = 10000
+
+// abandonMaxResources returns the resources a planet can hold and still be abandoned with the given options
+func abandonMaxResources(cfg Options) int64 {
+	if cfg.MaxResources != nil {
+		return *cfg.MaxResources
+	}
+	return DefaultAbandonMaxResources
+}
+
+func (b *OGame) abandon(v any, opts ...Option) error {
+	cfg := getOptions(opts...)
 	page, err := getPage[parser.OverviewPage](b)
 	if err != nil {
 		return err
@@ -1846,6 +2014,11 @@ func (b *OGame) abandon(v any) error {
 	if err != nil {
 		return errors.New("invalid parameter")
 	}
+	if !cfg.Force {
+		if err := b.checkAbandon(planet, len(page.ExtractPlanets()), abandonMaxResources(cfg)); err != nil {
+			return err
+		}
+	}
 	pageHTML, _ := b.getPage(PlanetlayerPageName, ChangePlanet(planet.GetID()))
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
 	abandonToken, token := b.extractor.ExtractAbandonInformation(doc)
@@ -1864,6 +2037,246 @@ func (b *OGame) abandon(v any) error {
 	return err
 }
 
+// checkAbandon returns a *ogame.AbandonNotSafeError if abandoning the planet would lose something
+func (b *OGame) checkAbandon(planet ogame.Planet, nbPlanets int, maxResources int64) error {
+	celestials := []ogame.CelestialID{planet.ID.Celestial()}
+	if planet.Moon != nil {
+		celestials = append(celestials, planet.Moon.ID.Celestial())
+	}
+	var ships ogame.ShipsInfos
+	var resources ogame.Resources
+	for _, celestialID := range celestials {
+		celestialShips, err := b.getShips(celestialID)
+		if err != nil {
+			return err
+		}
+		ships.Add(celestialShips)
+		celestialResources, err := b.getResources(celestialID)
+		if err != nil {
+			return err
+		}
+		resources = resources.Add(celestialResources)
+	}
+	events, err := b.getEventList()
+	if err != nil {
+		return err
+	}
+	if reasons := abandonReasons(planet.Coordinate, nbPlanets, ships, resources, events, maxResources); len(reasons) > 0 {
+		return ogame.NewAbandonNotSafeError(planet.Coordinate, reasons)
+	}
+	return nil
+}
+
+// abandonReasons returns why abandoning the planet at coord would lose something, ships and resources are the
+// ones of the planet and its moon
+func abandonReasons(coord ogame.Coordinate, nbPlanets int, ships ogame.ShipsInfos, resources ogame.Resources, events []ogame.FleetEvent, maxResources int64) []string {
+	reasons := make([]string, 0)
+	if nbPlanets <= 1 {
+		reasons = append(reasons, "last planet")
+	}
+	if ships.HasFlyableShips() {
+		reasons = append(reasons, utils.FI64(ships.CountShips())+" ships stationed")
+	}
+	if resources.Total() > maxResources {
+		reasons = append(reasons, utils.FI64(resources.Total())+" resources stored")
+	}
+	onPlanet := func(c ogame.Coordinate) bool {
+		return c.Galaxy == coord.Galaxy && c.System == coord.System && c.Position == coord.Position && !c.IsDebris()
+	}
+	for _, event := range events {
+		if event.Relation == ogame.HostileEvent {
+			continue
+		}
+		if onPlanet(event.Destination) || (event.ReturnFlight && onPlanet(event.Origin)) {
+			reasons = append(reasons, "fleet "+utils.FI64(event.ID)+" "+event.MissionType.String()+" coming")
+		}
+	}
+	return reasons
+}
+
+var planetNameRgx = regexp.MustCompile(`^[\p{L}\p{N}]+(?:[ _-][\p{L}\p{N}]+)*$`)
+
+func (b *OGame) renamePlanet(celestialID ogame.CelestialID, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if len([]rune(newName)) < 2 || len([]rune(newName)) > 20 || !planetNameRgx.MatchString(newName) {
+		return ogame.ErrInvalidPlanetName
+	}
+	if b.getCachedCelestial(celestialID) == nil {
+		return ogame.ErrInvalidPlanetID
+	}
+	pageHTML, err := b.getPage(PlanetlayerPageName, ChangePlanet(celestialID))
+	if err != nil {
+		return err
+	}
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	token := b.extractor.ExtractPlanetRenameToken(doc)
+	if token == "" {
+		return errors.New("failed to find rename token")
+	}
+	payload := url.Values{
+		"newPlanetName": {newName},
+		"token":         {token},
+	}
+	by, err := b.postPageContent(url.Values{"page": {PlanetRenameAjaxPageName}, "cp": {utils.FI64(celestialID)}}, payload)
+	if err != nil {
+		return err
+	}
+	var res struct {
+		Status   bool `json:"status"`
+		Errorbox struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"errorbox"`
+	}
+	if err := json.Unmarshal(by, &res); err != nil {
+		return errors.New("unexpected response : " + err.Error())
+	}
+	if !res.Status {
+		return errors.New("failed to rename planet : " + res.Errorbox.Text)
+	}
+	b.planetsMu.Lock()
+	for i := range b.planets {
+		if b.planets[i].GetID() == celestialID {
+			b.planets[i].Name = newName
+		} else if b.planets[i].Moon != nil && b.planets[i].Moon.GetID() == celestialID {
+			b.planets[i].Moon.Name = newName
+		}
+	}
+	b.planetsMu.Unlock()
+	return nil
+}
+
+// getPlanetRelocation the overview page only declares planetMoveCooldown,
+// planetMoveInProgress is declared by the shipyard and defenses pages.
+func (b *OGame) getPlanetRelocation(planetID ogame.PlanetID) (ogame.PlanetRelocation, error) {
+	page, err := getPage[parser.OverviewPage](b, ChangePlanet(planetID.Celestial()))
+	if err != nil {
+		return ogame.PlanetRelocation{}, err
+	}
+	relocation, err := page.ExtractPlanetRelocation()
+	if err != nil {
+		return ogame.PlanetRelocation{}, err
+	}
+	pageHTML, err := b.getPage(DefensesPageName, ChangePlanet(planetID.Celestial()))
+	if err != nil {
+		return ogame.PlanetRelocation{}, err
+	}
+	defensesRelocation, err := b.extractor.ExtractPlanetRelocation(pageHTML)
+	if err != nil {
+		return ogame.PlanetRelocation{}, err
+	}
+	relocation.InProgress = defensesRelocation.InProgress
+	return relocation, nil
+}
+
+func (b *OGame) relocatePlanet(planetID ogame.PlanetID, dest ogame.Coordinate) (ogame.PlanetRelocation, error) {
+	var res ogame.PlanetRelocation
+	if b.getCachedCelestial(planetID) == nil {
+		return res, ogame.ErrInvalidPlanetID
+	}
+	if dest.Position < 1 || dest.Position > 15 {
+		return res, errors.New("position must be within [1, 15]")
+	}
+	relocation, err := b.getPlanetRelocation(planetID)
+	if err != nil {
+		return res, err
+	}
+	if relocation.InProgress {
+		return relocation, ogame.ErrPlanetAlreadyReservedForRelocation
+	}
+	if relocation.Cooldown > 0 {
+		return relocation, fmt.Errorf("planet relocation is in cooldown for %s", time.Duration(relocation.Cooldown)*time.Second)
+	}
+	resources, err := b.getResourcesDetails(planetID.Celestial())
+	if err != nil {
+		return res, err
+	}
+	if resources.Darkmatter.Available < ogame.PlanetRelocationCost {
+		return res, ogame.ErrNotEnoughDarkMatter
+	}
+	systemInfos, err := b.galaxyInfos(dest.Galaxy, dest.System, SkipCache)
+	if err != nil {
+		return res, err
+	}
+	if systemInfos.Position(dest.Position) != nil {
+		return res, errors.New("destination is not empty")
+	}
+	pageHTML, err := b.getPage(GalaxyPageName, ChangePlanet(planetID.Celestial()))
+	if err != nil {
+		return res, err
+	}
+	token, err := b.extractor.ExtractPlanetMoveToken(pageHTML)
+	if err != nil {
+		return res, err
+	}
+	vals := url.Values{"page": {"ingame"}, "component": {"galaxy"}, "action": {"planetMove"}, "ajax": {"1"}, "asJson": {"1"}}
+	payload := url.Values{
+		"galaxy":   {utils.FI64(dest.Galaxy)},
+		"system":   {utils.FI64(dest.System)},
+		"position": {utils.FI64(dest.Position)},
+		"token":    {token},
+	}
+	by, err := b.postPageContent(vals, payload)
+	if err != nil {
+		return res, err
+	}
+	var resp struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(by, &resp); err != nil {
+		return res, errors.New("unexpected response : " + err.Error())
+	}
+	if resp.Status != "success" {
+		return res, errors.New("failed to relocate planet : " + resp.Message)
+	}
+	b.dmLedger.record("RelocatePlanet", planetID.Celestial(), dest.String(), ogame.PlanetRelocationCost)
+	return b.getPlanetRelocation(planetID)
+}
+
+func (b *OGame) cancelPlanetRelocation(planetID ogame.PlanetID) error {
+	relocation, err := b.getPlanetRelocation(planetID)
+	if err != nil {
+		return err
+	}
+	if !relocation.InProgress {
+		return errors.New("no planet relocation in progress")
+	}
+	pageHTML, err := b.getPage(OverviewPageName, ChangePlanet(planetID.Celestial()))
+	if err != nil {
+		return err
+	}
+	token, err := b.extractor.ExtractPlanetMoveToken(pageHTML)
+	if err != nil {
+		return err
+	}
+	vals := url.Values{"page": {"ingame"}, "component": {"overview"}, "action": {"cancelPlanetMove"}, "ajax": {"1"}, "asJson": {"1"}}
+	by, err := b.postPageContent(vals, url.Values{"token": {token}}, ChangePlanet(planetID.Celestial()))
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(by, &resp); err != nil {
+		return errors.New("unexpected response : " + err.Error())
+	}
+	if resp.Status != "success" {
+		return errors.New("failed to cancel planet relocation : " + resp.Message)
+	}
+	return nil
+}
+
+// serverNow returns the server clock, estimated from the server time of the last full page loaded
+func (b *OGame) serverNow() time.Time {
+	now := time.Now().Add(time.Duration(atomic.LoadInt64(&b.serverClockOffsetAtom)))
+	if b.location != nil {
+		now = now.In(b.location)
+	}
+	return now
+}
+
 func (b *OGame) serverTime() time.Time {
 	page, err := getPage[parser.OverviewPage](b)
 	serverTime, err := page.ExtractServerTime()
@@ -1925,19 +2338,52 @@ func (b *OGame) sendMessage(id int64, message string, isPlayer bool) error {
 	return nil
 }
 
+func (b *OGame) getChatContacts() ([]ogame.ChatContact, error) {
+	page, err := getPage[parser.ChatPage](b)
+	if err != nil {
+		return nil, err
+	}
+	return page.ExtractChatContacts()
+}
+
+func (b *OGame) getChatHistory(playerID, beforeID int64) ([]ogame.ChatMsg, error) {
+	payload := url.Values{
+		"mode":     {"2"},
+		"ajax":     {"1"},
+		"playerId": {utils.FI64(playerID)},
+	}
+	if beforeID != 0 {
+		payload.Set("lastMsgId", utils.FI64(beforeID))
+	}
+	bodyBytes, err := b.postPageContent(url.Values{"page": {AjaxChatAjaxPageName}}, payload)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(bodyBytes), "INVALID_PARAMETERS") {
+		return nil, errors.New("invalid parameters")
+	}
+	return b.extractor.ExtractChatHistory(bodyBytes)
+}
+
 func (b *OGame) getFleetsFromEventList() []ogame.Fleet {
 	pageHTML, _ := b.getPageContent(url.Values{"eventList": {"movement"}, "ajax": {"1"}})
 	return b.extractor.ExtractFleetsFromEventList(pageHTML)
 }
 
 func (b *OGame) getFleets(opts ...Option) ([]ogame.Fleet, ogame.Slots) {
+	fleets, slots, _ := b.getFleetsErr(opts...)
+	return fleets, slots
+}
+
+// getFleetsErr same as getFleets, but reports the failure to load the movement page
+func (b *OGame) getFleetsErr(opts ...Option) ([]ogame.Fleet, ogame.Slots, error) {
 	page, err := getPage[parser.MovementPage](b, opts...)
 	if err != nil {
-		return []ogame.Fleet{}, ogame.Slots{}
+		return []ogame.Fleet{}, ogame.Slots{}, err
 	}
 	fleets := page.ExtractFleets()
 	slots := page.ExtractSlots()
-	return fleets, slots
+	return fleets, slots, nil
 }
 
 func (b *OGame) cancelFleet(fleetID ogame.FleetID) error {
@@ -1973,13 +2419,7 @@ func galaxyDistance(galaxy1, galaxy2, universeSize int64, donutGalaxy bool) (dis
 }
 
 func systemDistance(nbSystems, system1, system2 int64, donutSystem bool) (distance int64) {
-	if !donutSystem {
-		return int64(math.Abs(float64(system2 - system1)))
-	}
-	if system1 > system2 {
-		system1, system2 = system2, system1
-	}
-	return int64(math.Min(float64(system2-system1), float64((system1+nbSystems)-system2)))
+	return ogame.SystemDistance(nbSystems, system1, system2, donutSystem)
 }
 
 // Returns the distance between two systems
@@ -2042,7 +2482,13 @@ func calcFuel(ships ogame.ShipsInfos, dist, duration int64, universeSpeedFleet,
 // CalcFlightTime ...
 func CalcFlightTime(origin, destination ogame.Coordinate, universeSize, nbSystems int64, donutGalaxy, donutSystem bool,
 	fleetDeutSaveFactor, speed float64, universeSpeedFleet int64, ships ogame.ShipsInfos, techs ogame.Researches, characterClass ogame.CharacterClass) (secs, fuel int64) {
-	if !ships.HasShips() {
+	distance := Distance(origin, destination, universeSize, nbSystems, donutGalaxy, donutSystem)
+	return calcFlightTimeForDistance(distance, fleetDeutSaveFactor, speed, universeSpeedFleet, ships, techs, characterClass)
+}
+
+func calcFlightTimeForDistance(distance int64, fleetDeutSaveFactor, speed float64, universeSpeedFleet int64, ships ogame.ShipsInfos,
+	techs ogame.Researches, characterClass ogame.CharacterClass) (secs, fuel int64) {
+	if !ships.HasShips() || speed <= 0 {
 		return
 	}
 	isCollector := characterClass == ogame.Collector
@@ -2050,17 +2496,84 @@ func CalcFlightTime(origin, destination ogame.Coordinate, universeSize, nbSystem
 	s := speed
 	v := float64(findSlowestSpeed(ships, techs, isCollector, isGeneral))
 	a := float64(universeSpeedFleet)
-	d := float64(Distance(origin, destination, universeSize, nbSystems, donutGalaxy, donutSystem))
+	d := float64(distance)
 	secs = int64(math.Round(((3500/s)*math.Sqrt(d*10/v) + 10) / a))
-	fuel = calcFuel(ships, int64(d), secs, float64(universeSpeedFleet), fleetDeutSaveFactor, techs, isCollector, isGeneral)
+	fuel = calcFuel(ships, distance, secs, float64(universeSpeedFleet), fleetDeutSaveFactor, techs, isCollector, isGeneral)
 	return
 }
 
 // CalcFlightTime calculates the flight time and the fuel consumption
 func (b *OGame) CalcFlightTime(origin, destination ogame.Coordinate, speed float64, ships ogame.ShipsInfos, missionID ogame.MissionID) (secs, fuel int64) {
-	return CalcFlightTime(origin, destination, b.serverData.Galaxies, b.serverData.Systems, b.serverData.DonutGalaxy,
-		b.serverData.DonutSystem, b.serverData.GlobalDeuteriumSaveFactor, speed, GetFleetSpeedForMission(b.serverData, missionID), ships,
-		b.GetCachedResearch(), b.characterClass)
+	return b.calcFlightTime(origin, destination, speed, ships, missionID, b.GetCachedResearch())
+}
+
+// calcFlightTime calculates the flight time and the fuel consumption with the server settings,
+// the systems ignored by the server are not counted in the distance (see distance)
+func (b *OGame) calcFlightTime(origin, destination ogame.Coordinate, speed float64, ships ogame.ShipsInfos, missionID ogame.MissionID, techs ogame.Researches) (secs, fuel int64) {
+	return calcFlightTimeForDistance(b.distance(origin, destination), b.serverData.GlobalDeuteriumSaveFactor, speed,
+		GetFleetSpeedForMission(b.serverData, missionID), ships, techs, b.characterClass)
+}
+
+// distance returns the distance between two coordinates with the server settings. When the server ignores the empty
+// (FleetIgnoreEmptySystems) or inactive (FleetIgnoreInactiveSystems) systems, the ones between the two systems are
+// not counted. A system is only known to be empty or inactive once it is in the galaxy cache.
+func (b *OGame) distance(origin, destination ogame.Coordinate) int64 {
+	if origin.Galaxy != destination.Galaxy || origin.System == destination.System {
+		return Distance(origin, destination, b.serverData.Galaxies, b.serverData.Systems, b.serverData.DonutGalaxy, b.serverData.DonutSystem)
+	}
+	nbSystems := b.serverData.Systems
+	systems := systemDistance(nbSystems, origin.System, destination.System, b.serverData.DonutSystem)
+	if !b.serverData.FleetIgnoreEmptySystems && !b.serverData.FleetIgnoreInactiveSystems {
+		return flightSystemDistance(nbSystems, origin.System, destination.System, b.serverData.DonutSystem)
+	}
+	step := int64(1)
+	if destination.System < origin.System {
+		step = -1
+	}
+	if b.serverData.DonutSystem && step*(destination.System-origin.System) > systems {
+		step = -step // shorter around the donut
+	}
+	var ignored int64
+	for i := int64(1); i < systems; i++ {
+		system := (origin.System-1+i*step+nbSystems)%nbSystems + 1
+		infos, err := cacheGet[ogame.SystemInfos](b, GalaxyCache, galaxyCacheKey(origin.Galaxy, system))
+		if err != nil {
+			continue
+		}
+		empty, inactive := systemActivity(infos)
+		if (empty && b.serverData.FleetIgnoreEmptySystems) || (inactive && b.serverData.FleetIgnoreInactiveSystems) {
+			ignored++
+		}
+	}
+	return 2700 + 95*utils.MaxInt(systems-ignored, 1)
+}
+
+// systemActivity returns either or not the system has no planet, and either or not all its players are inactive
+func systemActivity(infos ogame.SystemInfos) (empty, inactive bool) {
+	empty, inactive = true, true
+	infos.Each(func(planetInfo *ogame.PlanetInfos) {
+		if planetInfo == nil {
+			return
+		}
+		empty = false
+		if !planetInfo.Inactive {
+			inactive = false
+		}
+	})
+	return empty, inactive && !empty
+}
+
+// bestSpeedFor returns the slowest speed (which burns the least fuel) that still arrives before arriveBy
+func (b *OGame) bestSpeedFor(origin, destination ogame.Coordinate, ships ogame.ShipsInfos, missionID ogame.MissionID, arriveBy time.Time) (speed ogame.Speed, secs, fuel int64, err error) {
+	available := int64(time.Until(arriveBy) / time.Second)
+	speeds := ogame.AllowedSpeeds(b.characterClass)
+	for _, speed := range speeds {
+		secs, fuel = b.CalcFlightTime(origin, destination, float64(speed)/10, ships, missionID)
+		if secs <= available {
+			return speed, secs, fuel, nil
+		}
+	}
+	return ogame.HundredPercent, secs, fuel, ogame.ErrDeadlineUnreachable
 }
 
 // getPhalanx makes 3 calls to ogame server (2 validation, 1 scan)
@@ -2085,9 +2598,7 @@ func (b *OGame) getPhalanx(moonID ogame.MoonID, coord ogame.Coordinate) ([]ogame
 	}
 
 	// Verify that coordinate is in phalanx range
-	phalanxRange := ogame.SensorPhalanx.GetRange(phalanxLvl, b.isDiscoverer())
-	if moon.GetCoordinate().Galaxy != coord.Galaxy ||
-		systemDistance(b.serverData.Systems, moon.GetCoordinate().System, coord.System, b.serverData.DonutSystem) > phalanxRange {
+	if !ogame.InPhalanxRange(moon.GetCoordinate(), coord, phalanxLvl, b.serverData.Systems, b.serverData.DonutSystem, b.isDiscoverer()) {
 		return res, errors.New("coordinate not in phalanx range")
 	}
 
@@ -2098,7 +2609,7 @@ func (b *OGame) getPhalanx(moonID ogame.MoonID, coord ogame.Coordinate) ([]ogame
 // getUnsafePhalanx ...
 func (b *OGame) getUnsafePhalanx(moonID ogame.MoonID, coord ogame.Coordinate) ([]ogame.Fleet, error) {
 	// Get galaxy planets information, verify coordinate is valid planet (call to ogame server)
-	planetInfos, _ := b.galaxyInfos(coord.Galaxy, coord.System)
+	planetInfos, _ := b.galaxyInfos(coord.Galaxy, coord.System, SkipCache)
 	target := planetInfos.Position(coord.Position)
 	if target == nil {
 		return nil, errors.New("invalid planet coordinate")
@@ -2123,6 +2634,20 @@ func (b *OGame) getUnsafePhalanx(moonID ogame.MoonID, coord ogame.Coordinate) ([
 	return page.ExtractPhalanx()
 }
 
+func (b *OGame) getPhalanxCoverage() (ogame.PhalanxCoverage, error) {
+	coverage := ogame.PhalanxCoverage{NbSystems: b.serverData.Systems, DonutSystem: b.serverData.DonutSystem, IsDiscoverer: b.isDiscoverer()}
+	for _, moon := range b.getCachedMoons() {
+		facilities, err := b.getFacilities(moon.ID.Celestial())
+		if err != nil {
+			return coverage, err
+		}
+		if facilities.SensorPhalanx > 0 {
+			coverage.Moons = append(coverage.Moons, ogame.PhalanxMoon{ID: moon.ID, Coordinate: moon.Coordinate, Level: facilities.SensorPhalanx})
+		}
+	}
+	return coverage, nil
+}
+
 func moonIDInSlice(needle ogame.MoonID, haystack []ogame.MoonID) bool {
 	for _, element := range haystack {
 		if needle == element {
@@ -2227,6 +2752,23 @@ func (b *OGame) getEmpire(celestialType ogame.CelestialType) (out []ogame.Empire
 	return b.extractor.ExtractEmpire(pageHTMLBytes)
 }
 
+func (b *OGame) empireSnapshot() (ogame.EmpireSnapshot, error) {
+	snapshot := ogame.EmpireSnapshot{Time: time.Now()}
+	planets, err := b.getEmpire(ogame.PlanetType)
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.Celestials = append(snapshot.Celestials, planets...)
+	if len(b.getCachedMoons()) > 0 {
+		moons, err := b.getEmpire(ogame.MoonType)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.Celestials = append(snapshot.Celestials, moons...)
+	}
+	return snapshot, nil
+}
+
 func (b *OGame) getEmpireJSON(nbr int64) (any, error) {
 	// Valid URLs:
 	// /game/index.php?page=standalone&component=empire&planetType=0
@@ -2300,6 +2842,86 @@ func (b *OGame) highscore(category, typ, page int64) (out ogame.Highscore, err e
 	return b.extractor.ExtractHighscore(pageHTML)
 }
 
+// getTopScore returns the points of the top 1 player, from the server data or the first highscore page
+func (b *OGame) getTopScore() (int64, error) {
+	if topScore := int64(b.serverData.TopScore); topScore > 0 {
+		return topScore, nil
+	}
+	highscore, err := b.highscore(1, 0, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(highscore.Players) == 0 {
+		return 0, errors.New("empty highscore")
+	}
+	return highscore.Players[0].Score, nil
+}
+
+func (b *OGame) getExpeditionFleet(cargoID ogame.ID, withPathfinder bool) (ogame.ShipsInfos, error) {
+	topScore, err := b.getTopScore()
+	if err != nil {
+		return ogame.ShipsInfos{}, err
+	}
+	characterClass := b.characterClass
+	maxFind := ogame.ExpeditionMaxFind(topScore, b.serverData.Speed, characterClass == ogame.Discoverer, withPathfinder)
+	return ogame.ExpeditionFleet(maxFind, cargoID, withPathfinder, b.getCachedResearch(), characterClass == ogame.Collector, b.IsPioneers()), nil
+}
+
+// delay between two highscore pages when walking the full highscore
+const fullHighscorePageDelay = 500 * time.Millisecond
+
+func (b *OGame) getFullHighscore(category, typ int64) (out ogame.FullHighscore, err error) {
+	out.Category = category
+	out.Type = typ
+	for page := int64(1); ; page++ {
+		if page > 1 {
+			select {
+			case <-time.After(fullHighscorePageDelay):
+			case <-b.ctx.Done():
+				return out, ogame.ErrBotInactive
+			}
+		}
+		res, err := b.highscore(category, typ, page)
+		if err != nil {
+			return out, err
+		}
+		out.Players = append(out.Players, res.Players...)
+		if page >= res.NbPage || len(res.Players) == 0 {
+			break
+		}
+	}
+	return out, nil
+}
+
+// getHighscorePosition gets the highscore page that contains the player, and returns the player position
+func (b *OGame) getHighscorePosition(category, typ, playerID int64) (ogame.HighscorePlayer, error) {
+	if category < 1 || category > 2 {
+		return ogame.HighscorePlayer{}, errors.New("category must be in [1, 2] (1:player, 2:alliance)")
+	}
+	if typ < 0 || typ > 7 {
+		return ogame.HighscorePlayer{}, errors.New("typ must be in [0, 7] (0:Total, 1:Economy, 2:Research, 3:Military, 4:Military Built, 5:Military Destroyed, 6:Military Lost, 7:Honor)")
+	}
+	vals := url.Values{
+		"page":        {HighscoreContentAjaxPageName},
+		"category":    {utils.FI64(category)},
+		"type":        {utils.FI64(typ)},
+		"searchRelId": {utils.FI64(playerID)},
+	}
+	pageHTML, err := b.postPageContent(vals, url.Values{})
+	if err != nil {
+		return ogame.HighscorePlayer{}, err
+	}
+	res, err := b.extractor.ExtractHighscore(pageHTML)
+	if err != nil {
+		return ogame.HighscorePlayer{}, err
+	}
+	p, found := res.FindPlayer(playerID)
+	if !found {
+		return ogame.HighscorePlayer{}, errors.New("player not found in highscore")
+	}
+	return p, nil
+}
+
 func (b *OGame) getAllResources() (map[ogame.CelestialID]ogame.Resources, error) {
 	vals := url.Values{
 		"page":      {"ajax"},
@@ -2333,15 +2955,16 @@ func (b *OGame) useDM(typ string, celestialID ogame.CelestialID) error {
 	if err != nil {
 		return err
 	}
-	var buyAndActivate, token string
+	var cost ogame.DMCost
 	switch typ {
 	case "buildings":
-		buyAndActivate, token = costs.Buildings.BuyAndActivateToken, costs.Buildings.Token
+		cost = costs.Buildings
 	case "research":
-		buyAndActivate, token = costs.Research.BuyAndActivateToken, costs.Research.Token
+		cost = costs.Research
 	case "shipyard":
-		buyAndActivate, token = costs.Shipyard.BuyAndActivateToken, costs.Shipyard.Token
+		cost = costs.Shipyard
 	}
+	buyAndActivate, token := cost.BuyAndActivateToken, cost.Token
 	params := url.Values{
 		"page":           {"inventory"},
 		"buyAndActivate": {buyAndActivate},
@@ -2354,6 +2977,7 @@ func (b *OGame) useDM(typ string, celestialID ogame.CelestialID) error {
 	if _, err := b.postPageContent(params, payload); err != nil {
 		return err
 	}
+	b.dmLedger.record("UseDM", celestialID, typ, cost.Cost)
 	return nil
 }
 
@@ -2365,6 +2989,9 @@ func (b *OGame) useDM(typ string, celestialID ogame.CelestialID) error {
 // itemID 204 -> light fighter
 // itemID <HASH> -> item
 func (b *OGame) offerMarketplace(marketItemType int64, itemID any, quantity, priceType, price, priceRange int64, celestialID ogame.CelestialID) error {
+	if !b.serverData.IsMarketplaceEnabled() {
+		return ogame.ErrMarketplaceDisabled
+	}
 	params := url.Values{"page": {"ingame"}, "component": {"marketplace"}, "tab": {"create_offer"}, "action": {"submitOffer"}, "asJson": {"1"}}
 	const (
 		shipsItemType = iota + 1
@@ -2461,6 +3088,9 @@ func (b *OGame) offerMarketplace(marketItemType int64, itemID any, quantity, pri
 }
 
 func (b *OGame) buyMarketplace(itemID int64, celestialID ogame.CelestialID) (err error) {
+	if !b.serverData.IsMarketplaceEnabled() {
+		return ogame.ErrMarketplaceDisabled
+	}
 	params := url.Values{"page": {"ingame"}, "component": {"marketplace"}, "tab": {"buying"}, "action": {"acceptRequest"}, "asJson": {"1"}}
 	payload := url.Values{
 		"marketItemId": {utils.FI64(itemID)},
@@ -2486,6 +3116,60 @@ func (b *OGame) buyMarketplace(itemID int64, celestialID ogame.CelestialID) (err
 	return err
 }
 
+func (b *OGame) getMarketplaceOffers(tab ogame.MarketplaceTab, celestialID ogame.CelestialID) ([]ogame.MarketplaceOffer, error) {
+	if !b.serverData.IsMarketplaceEnabled() {
+		return nil, ogame.ErrMarketplaceDisabled
+	}
+	var action string
+	switch tab {
+	case ogame.MarketplaceBuyingTab:
+		action = "fetchBuyingItems"
+	case ogame.MarketplaceSellingTab:
+		action = "fetchSellingItems"
+	default:
+		return nil, errors.New("invalid marketplace tab")
+	}
+	var page int64 = 1
+	var nbPage int64 = 1
+	offers := make([]ogame.MarketplaceOffer, 0)
+	for page <= nbPage {
+		vals := url.Values{
+			"page":             {"ingame"},
+			"component":        {"marketplace"},
+			"tab":              {string(tab)},
+			"action":           {action},
+			"ajax":             {"1"},
+			"pagination[page]": {utils.FI64(page)},
+		}
+		by, err := b.getPageContent(vals, ChangePlanet(celestialID))
+		if err != nil {
+			return nil, err
+		}
+		var res struct {
+			Status  string            `json:"status"`
+			Content map[string]string `json:"content"`
+		}
+		if err := json.Unmarshal(by, &res); err != nil {
+			return nil, err
+		}
+		var pageHTML string
+		for _, content := range res.Content {
+			pageHTML += content
+		}
+		newOffers, newNbPage, err := b.extractor.ExtractMarketplaceOffers([]byte(pageHTML))
+		if err != nil {
+			return nil, err
+		}
+		for _, offer := range newOffers {
+			offer.Tab = tab
+			offers = append(offers, offer)
+		}
+		nbPage = newNbPage
+		page++
+	}
+	return offers, nil
+}
+
 func (b *OGame) getItems(celestialID ogame.CelestialID) (items []ogame.Item, err error) {
 	params := url.Values{"page": {"buffActivation"}, "ajax": {"1"}, "type": {"1"}}
 	pageHTML, _ := b.getPageContent(params, ChangePlanet(celestialID))
@@ -2501,6 +3185,7 @@ func (b *OGame) getActiveItems(celestialID ogame.CelestialID) (items []ogame.Act
 	return page.ExtractActiveItems()
 }
 
+// MessageSuccess response sent by the game when an item is activated
 type MessageSuccess struct {
 	Buff          string `json:"buff"`
 	Status        string `json:"status"`
@@ -2547,6 +3232,76 @@ type MessageSuccess struct {
 	Message string `json:"message"`
 }
 
+// ToItemActivationResult converts the raw game response to its typed ogame.ItemActivationResult
+func (m MessageSuccess) ToItemActivationResult() (ogame.ItemActivationResult, error) {
+	var res ogame.ItemActivationResult
+	by, err := json.Marshal(m)
+	if err != nil {
+		return res, err
+	}
+	err = json.Unmarshal(by, &res)
+	return res, err
+}
+
+func (b *OGame) getShopItems(celestialID ogame.CelestialID) ([]ogame.ShopItem, error) {
+	params := url.Values{"page": {"buffActivation"}, "ajax": {"1"}, "type": {"1"}}
+	pageHTML, err := b.getPageContent(params, ChangePlanet(celestialID))
+	if err != nil {
+		return nil, err
+	}
+	_, items, err := b.extractor.ExtractShopItems(pageHTML)
+	return items, err
+}
+
+func (b *OGame) getInventory(celestialID ogame.CelestialID) ([]ogame.ShopItem, error) {
+	items, err := b.getShopItems(celestialID)
+	if err != nil {
+		return nil, err
+	}
+	inventory := make([]ogame.ShopItem, 0)
+	for _, item := range items {
+		if item.Amount > 0 {
+			inventory = append(inventory, item)
+		}
+	}
+	return inventory, nil
+}
+
+// buyItem buys an item with dark matter and activates it, the same way the "Buy & Activate" button
+// of the buff activation popup does. The item must be part of the popup with canBeBoughtAndActivated.
+func (b *OGame) buyItem(ref string, celestialID ogame.CelestialID) error {
+	params := url.Values{"page": {"buffActivation"}, "ajax": {"1"}, "type": {"1"}}
+	pageHTML, err := b.getPageContent(params, ChangePlanet(celestialID))
+	if err != nil {
+		return err
+	}
+	token, items, err := b.extractor.ExtractShopItems(pageHTML)
+	if err != nil {
+		return err
+	}
+	var item *ogame.ShopItem
+	for i := range items {
+		if items[i].Ref == ref {
+			item = &items[i]
+			break
+		}
+	}
+	if item == nil {
+		return errors.New("item not found " + ref)
+	}
+	if !item.CanBeBoughtAndActivated {
+		return errors.New("item cannot be bought")
+	}
+	if !item.HasEnoughCurrency {
+		return ogame.ErrNotEnoughDarkMatter
+	}
+	if err := b.postInventoryItem(token, ref); err != nil {
+		return err
+	}
+	b.dmLedger.record("BuyItem", celestialID, ref, item.Costs)
+	return nil
+}
+
 func (b *OGame) activateItem(ref string, celestialID ogame.CelestialID) error {
 	params := url.Values{"page": {"buffActivation"}, "ajax": {"1"}, "type": {"1"}}
 	pageHTML, _ := b.getPageContent(params, ChangePlanet(celestialID))
@@ -2554,7 +3309,12 @@ func (b *OGame) activateItem(ref string, celestialID ogame.CelestialID) error {
 	if err != nil {
 		return err
 	}
-	params = url.Values{"page": {"inventory"}}
+	return b.postInventoryItem(token, ref)
+}
+
+// postInventoryItem activates an item of the inventory, the item is bought first if none is left
+func (b *OGame) postInventoryItem(token, ref string) error {
+	params := url.Values{"page": {"inventory"}}
 	payload := url.Values{
 		"ajax":         {"1"},
 		"token":        {token},
@@ -2579,7 +3339,7 @@ func (b *OGame) activateItem(ref string, celestialID ogame.CelestialID) error {
 		}
 		return errors.New("unknown error")
 	}
-	return err
+	return nil
 }
 
 func (b *OGame) getAuction(celestialID ogame.CelestialID) (ogame.Auction, error) {
@@ -2697,56 +3457,164 @@ func calcResources(price int64, planetResources ogame.PlanetResources, multiplie
 	return payload
 }
 
-func (b *OGame) buyOfferOfTheDay() error {
+func (b *OGame) getOfferOfTheDay() (ogame.OfferOfTheDay, error) {
 	pageHTML, err := b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderimportexport"}}, url.Values{"show": {"importexport"}, "ajax": {"1"}})
 	if err != nil {
-		return err
+		return ogame.OfferOfTheDay{}, err
+	}
+	return b.extractor.ExtractOfferOfTheDayDetails(pageHTML)
+}
+
+// maxOfferOfTheDayBargains number of times the bought item can be exchanged per daily offer
+const maxOfferOfTheDayBargains = 2
+
+type offerOfTheDayResponse struct {
+	Message      string
+	Error        bool
+	Item         ogame.OfferOfTheDayItem
+	NewAjaxToken string
+}
+
+func (b *OGame) postOfferOfTheDayAction(action string, payload url.Values) (offerOfTheDayResponse, error) {
+	var res offerOfTheDayResponse
+	pageHTML, err := b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderimportexport"}, "ajax": {"1"}, "action": {action}, "asJson": {"1"}}, payload)
+	if err != nil {
+		return res, err
+	}
+	if err := json.Unmarshal(pageHTML, &res); err != nil {
+		return res, err
+	}
+	if res.Error {
+		return res, errors.New(res.Message)
+	}
+	return res, nil
+}
+
+// purchaseOfferOfTheDay pays the offer of the day, the item still has to be taken or exchanged
+func (b *OGame) purchaseOfferOfTheDay() (offerOfTheDayResponse, error) {
+	pageHTML, err := b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderimportexport"}}, url.Values{"show": {"importexport"}, "ajax": {"1"}})
+	if err != nil {
+		return offerOfTheDayResponse{}, err
 	}
 
 	price, importToken, planetResources, multiplier, err := b.extractor.ExtractOfferOfTheDay(pageHTML)
 	if err != nil {
-		return err
+		return offerOfTheDayResponse{}, err
 	}
 	payload := calcResources(price, planetResources, multiplier)
 	payload.Add("action", "trade")
 	payload.Add("bid[honor]", "0")
 	payload.Add("token", importToken)
 	payload.Add("ajax", "1")
-	pageHTML1, err := b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderimportexport"}, "ajax": {"1"}, "action": {"trade"}, "asJson": {"1"}}, payload)
+	// {"message":"You have bought a container.","error":false,"item":{"uuid":"40f6c78e11be01ad3389b7dccd6ab8efa9347f3c","itemText":"You have purchased 1 KRAKEN Bronze.","bargainText":"The contents of the container not appeal to you? For 500 Dark Matter you can exchange the container for another random container of the same quality. You can only carry out this exchange 2 times per daily offer.","bargainCost":500,"bargainCostText":"Costs: 500 Dark Matter","tooltip":"KRAKEN Bronze|Reduces the building time of buildings currently under construction by <b>30m<\/b>.<br \/><br \/>\nDuration: now<br \/><br \/>\nPrice: --- <br \/>\nIn Inventory: 1","image":"98629d11293c9f2703592ed0314d99f320f45845","amount":1,"rarity":"common"},"newToken":"07eefc14105db0f30cb331a8b7af0bfe"}
+	return b.postOfferOfTheDayAction("trade", payload)
+}
+
+func (b *OGame) takeOfferOfTheDayItem(token string) error {
+	payload := url.Values{"action": {"takeItem"}, "token": {token}, "ajax": {"1"}}
+	_, err := b.postOfferOfTheDayAction("takeItem", payload)
+	// {"error":false,"message":"You have accepted the offer and put the item in your inventory.","item":{"name":"Bronze Deuterium Booster","image":"f0e514af79d0808e334e9b6b695bf864b861bdfa","imageLarge":"c7c2837a0b341d37383d6a9d8f8986f500db7bf9","title":"Bronze Deuterium Booster|+10% more Deuterium Synthesizer harvest on one planet<br \/><br \/>\nDuration: 1w<br \/><br \/>\nPrice: --- <br \/>\nIn Inventory: 134","effect":"+10% more Deuterium Synthesizer harvest on one planet","ref":"d9fa5f359e80ff4f4c97545d07c66dbadab1d1be","rarity":"common","amount":134,"amount_free":134,"amount_bought":0,"category":["d8d49c315fa620d9c7f1f19963970dea59a0e3be","e71139e15ee5b6f472e2c68a97aa4bae9c80e9da"],"currency":"dm","costs":"2500","isReduced":false,"buyable":false,"canBeActivated":true,"canBeBoughtAndActivated":false,"isAnUpgrade":false,"isCharacterClassItem":false,"hasEnoughCurrency":true,"cooldown":0,"duration":604800,"durationExtension":null,"totalTime":null,"timeLeft":null,"status":null,"extendable":false,"firstStatus":"effecting","toolTip":"Bronze Deuterium Booster|+10% more Deuterium Synthesizer harvest on one planet&lt;br \/&gt;&lt;br \/&gt;\nDuration: 1w&lt;br \/&gt;&lt;br \/&gt;\nPrice: --- &lt;br \/&gt;\nIn Inventory: 134","buyTitle":"This item is currently unavailable for purchase.","activationTitle":"Activate","moonOnlyItem":false,"newOffer":false,"noOfferMessage":"There are no further offers today. Please come again tomorrow."},"newToken":"dec779714b893be9b39c0bedf5738450","components":[],"newAjaxToken":"e20cf0a6ca0e9b43a81ccb8fe7e7e2e3"}
+	return err
+}
+
+func (b *OGame) buyOfferOfTheDay() error {
+	res, err := b.purchaseOfferOfTheDay()
+	if err != nil {
+		return err
+	}
+	return b.takeOfferOfTheDayItem(res.NewAjaxToken)
+}
+
+// rerollOfferOfTheDay buys the offer of the day and exchanges the item until keep returns true,
+// or the exchanges are exhausted. The last item is then taken.
+func (b *OGame) rerollOfferOfTheDay(keep func(ogame.OfferOfTheDayItem) bool) (ogame.OfferOfTheDayItem, error) {
+	res, err := b.purchaseOfferOfTheDay()
+	if err != nil {
+		return ogame.OfferOfTheDayItem{}, err
+	}
+	for i := 0; i < maxOfferOfTheDayBargains && (keep == nil || !keep(res.Item)); i++ {
+		payload := url.Values{"action": {"bargain"}, "token": {res.NewAjaxToken}, "ajax": {"1"}}
+		bargain, err := b.postOfferOfTheDayAction("bargain", payload)
+		if err != nil {
+			// the purchased item is still waiting, keep it
+			break
+		}
+		res = bargain
+	}
+	if err := b.takeOfferOfTheDayItem(res.NewAjaxToken); err != nil {
+		return res.Item, err
+	}
+	return res.Item, nil
+}
+
+func (b *OGame) tradeResources(celestialID ogame.CelestialID, give, want ogame.Resources) error {
+	pageHTML, err := b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderresources"}}, url.Values{"show": {"resources"}, "ajax": {"1"}}, ChangePlanet(celestialID))
+	if err != nil {
+		return err
+	}
+	trader, err := b.extractor.ExtractTraderResources(pageHTML)
+	if err != nil {
+		return err
+	}
+	needed, err := ogame.TraderResourcesCost(give, want, trader.Rates)
+	if err != nil {
+		return err
+	}
+	sell := traderSellResources(give, needed)
+	resources, err := b.getResourcesDetails(celestialID)
 	if err != nil {
 		return err
 	}
-	// {"message":"You have bought a container.","error":false,"item":{"uuid":"40f6c78e11be01ad3389b7dccd6ab8efa9347f3c","itemText":"You have purchased 1 KRAKEN Bronze.","bargainText":"The contents of the container not appeal to you? For 500 Dark Matter you can exchange the container for another random container of the same quality. You can only carry out this exchange 2 times per daily offer.","bargainCost":500,"bargainCostText":"Costs: 500 Dark Matter","tooltip":"KRAKEN Bronze|Reduces the building time of buildings currently under construction by <b>30m<\/b>.<br \/><br \/>\nDuration: now<br \/><br \/>\nPrice: --- <br \/>\nIn Inventory: 1","image":"98629d11293c9f2703592ed0314d99f320f45845","amount":1,"rarity":"common"},"newToken":"07eefc14105db0f30cb331a8b7af0bfe"}
-	var tmp struct {
-		Message      string
-		Error        bool
-		NewAjaxToken string
+	if !resources.Available().CanAfford(sell) {
+		return errors.New("not enough resources")
 	}
-	if err := json.Unmarshal(pageHTML1, &tmp); err != nil {
-		return err
+	if resources.Darkmatter.Available < trader.Fee {
+		return ogame.ErrNotEnoughDarkMatter
 	}
-	if tmp.Error {
-		return errors.New(tmp.Message)
+	payload := url.Values{
+		"token":           {trader.Token},
+		"ajax":            {"1"},
+		"sell[metal]":     {utils.FI64(sell.Metal)},
+		"sell[crystal]":   {utils.FI64(sell.Crystal)},
+		"sell[deuterium]": {utils.FI64(sell.Deuterium)},
+		"buy[metal]":      {utils.FI64(want.Metal)},
+		"buy[crystal]":    {utils.FI64(want.Crystal)},
+		"buy[deuterium]":  {utils.FI64(want.Deuterium)},
+	}
+	by, err := b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderresources"}, "ajax": {"1"}, "action": {"trade"}, "asJson": {"1"}}, payload, ChangePlanet(celestialID))
+	if err != nil {
+		return err
 	}
-
-	payload2 := url.Values{"action": {"takeItem"}, "token": {tmp.NewAjaxToken}, "ajax": {"1"}}
-	pageHTML2, _ := b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderimportexport"}, "ajax": {"1"}, "action": {"takeItem"}, "asJson": {"1"}}, payload2)
-	var tmp2 struct {
-		Message      string
-		Error        bool
-		NewAjaxToken string
+	var res struct {
+		Message string
+		Error   bool
 	}
-	if err := json.Unmarshal(pageHTML2, &tmp2); err != nil {
+	if err := json.Unmarshal(by, &res); err != nil {
 		return err
 	}
-	if tmp2.Error {
-		return errors.New(tmp2.Message)
+	if res.Error {
+		return errors.New(res.Message)
+	}
+	if trader.Fee > 0 {
+		b.dmLedger.record("TradeResources", celestialID, want.String(), trader.Fee)
 	}
-	// {"error":false,"message":"You have accepted the offer and put the item in your inventory.","item":{"name":"Bronze Deuterium Booster","image":"f0e514af79d0808e334e9b6b695bf864b861bdfa","imageLarge":"c7c2837a0b341d37383d6a9d8f8986f500db7bf9","title":"Bronze Deuterium Booster|+10% more Deuterium Synthesizer harvest on one planet<br \/><br \/>\nDuration: 1w<br \/><br \/>\nPrice: --- <br \/>\nIn Inventory: 134","effect":"+10% more Deuterium Synthesizer harvest on one planet","ref":"d9fa5f359e80ff4f4c97545d07c66dbadab1d1be","rarity":"common","amount":134,"amount_free":134,"amount_bought":0,"category":["d8d49c315fa620d9c7f1f19963970dea59a0e3be","e71139e15ee5b6f472e2c68a97aa4bae9c80e9da"],"currency":"dm","costs":"2500","isReduced":false,"buyable":false,"canBeActivated":true,"canBeBoughtAndActivated":false,"isAnUpgrade":false,"isCharacterClassItem":false,"hasEnoughCurrency":true,"cooldown":0,"duration":604800,"durationExtension":null,"totalTime":null,"timeLeft":null,"status":null,"extendable":false,"firstStatus":"effecting","toolTip":"Bronze Deuterium Booster|+10% more Deuterium Synthesizer harvest on one planet&lt;br \/&gt;&lt;br \/&gt;\nDuration: 1w&lt;br \/&gt;&lt;br \/&gt;\nPrice: --- &lt;br \/&gt;\nIn Inventory: 134","buyTitle":"This item is currently unavailable for purchase.","activationTitle":"Activate","moonOnlyItem":false,"newOffer":false,"noOfferMessage":"There are no further offers today. Please come again tomorrow."},"newToken":"dec779714b893be9b39c0bedf5738450","components":[],"newAjaxToken":"e20cf0a6ca0e9b43a81ccb8fe7e7e2e3"}
-
 	return nil
 }
 
+// traderSellResources returns the resources actually sold to the trader, the amount needed for the trade
+// taken from the resource type given, never more than what was given
+func traderSellResources(give ogame.Resources, needed int64) (sell ogame.Resources) {
+	switch {
+	case give.Metal > 0:
+		sell.Metal = utils.MinInt(needed, give.Metal)
+	case give.Crystal > 0:
+		sell.Crystal = utils.MinInt(needed, give.Crystal)
+	case give.Deuterium > 0:
+		sell.Deuterium = utils.MinInt(needed, give.Deuterium)
+	}
+	return
+}
+
 // Hack fix: When moon name is >12, the moon image disappear from the EventsBox
 // and attacks are detected on planet instead.
 func fixAttackEvents(attacks []ogame.AttackEvent, planets []Planet) {
@@ -2786,6 +3654,15 @@ func (b *OGame) getAttacks(opts ...Option) (out []ogame.AttackEvent, err error)
 	return
 }
 
+func (b *OGame) getEventList(opts ...Option) ([]ogame.FleetEvent, error) {
+	vals := url.Values{"page": {"componentOnly"}, "component": {EventListAjaxPageName}, "ajax": {"1"}}
+	page, err := getAjaxPage[parser.EventListAjaxPage](b, vals, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.ExtractEventList()
+}
+
 func (b *OGame) galaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemInfos, error) {
 	cfg := getOptions(opts...)
 	var res ogame.SystemInfos
@@ -2795,6 +3672,12 @@ func (b *OGame) galaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemI
 	if system < 1 || system > b.serverData.Systems {
 		return res, errors.New("system must be within [1, " + utils.FI64(b.serverData.Systems) + "]")
 	}
+	// Cached systems are only served when the GalaxyCache TTL is set, a galaxy changes too often to be kept forever
+	if !cfg.SkipCache && b.getCacheTTL(GalaxyCache) > 0 {
+		if cached, err := cacheGet[ogame.SystemInfos](b, GalaxyCache, galaxyCacheKey(galaxy, system)); err == nil {
+			return cached, nil
+		}
+	}
 	payload := url.Values{
 		"galaxy": {utils.FI64(galaxy)},
 		"system": {utils.FI64(system)},
@@ -2814,9 +3697,34 @@ func (b *OGame) galaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemI
 	if res.Tmpgalaxy != galaxy || res.Tmpsystem != system {
 		return ogame.SystemInfos{}, errors.New("not enough deuterium")
 	}
+	if err == nil {
+		cacheSet(b, GalaxyCache, galaxyCacheKey(galaxy, system), res)
+	}
 	return res, err
 }
 
+// harvestExpeditionDebris sends pathfinders from celestialID to the expedition debris field (position 16) of the system.
+// Sends as many pathfinders as needed, or all the available ones if there is not enough.
+func (b *OGame) harvestExpeditionDebris(celestialID ogame.CelestialID, galaxy, system int64, speed ogame.Speed) (ogame.Fleet, error) {
+	systemInfos, err := b.galaxyInfos(galaxy, system, SkipCache)
+	if err != nil {
+		return ogame.Fleet{}, err
+	}
+	if !systemInfos.HasExpeditionDebris() {
+		return ogame.Fleet{}, ogame.ErrNoExpeditionDebris
+	}
+	ships, err := b.getShips(celestialID)
+	if err != nil {
+		return ogame.Fleet{}, err
+	}
+	nbPathfinders := utils.MinInt(systemInfos.ExpeditionDebris.PathfindersNeeded, ships.Pathfinder)
+	if nbPathfinders <= 0 {
+		return ogame.Fleet{}, ogame.ErrNoShipSelected
+	}
+	fleet := []ogame.Quantifiable{{ID: ogame.PathfinderID, Nbr: nbPathfinders}}
+	return b.sendFleet(celestialID, fleet, speed, systemInfos.ExpeditionDebrisCoordinate(), ogame.RecycleDebrisField, ogame.Resources{}, 0, 0, false)
+}
+
 func (b *OGame) getResourceSettings(planetID ogame.PlanetID, options ...Option) (ogame.ResourceSettings, error) {
 	options = append(options, ChangePlanet(planetID.Celestial()))
 	page, err := getPage[parser.ResourcesSettingsPage](b, options...)
@@ -2855,6 +3763,14 @@ func (b *OGame) setResourceSettings(planetID ogame.PlanetID, settings ogame.Reso
 
 func (b *OGame) getCachedResearch() ogame.Researches {
 	if b.researches == nil {
+		if researches, err := cacheGet[ogame.Researches](b, ResearchesCache, ""); err == nil {
+			b.researches = &researches
+			b.touchCache(ResearchesCache)
+			return researches
+		}
+		return b.getResearch()
+	}
+	if b.isCacheExpired(ResearchesCache) {
 		return b.getResearch()
 	}
 	return *b.researches
@@ -2866,7 +3782,7 @@ func (b *OGame) getResearch() ogame.Researches {
 		return ogame.Researches{}
 	}
 	researches := page.ExtractResearch()
-	b.researches = &researches
+	b.setResearches(researches)
 	return researches
 }
 
@@ -2879,6 +3795,14 @@ func (b *OGame) getResourcesBuildings(celestialID ogame.CelestialID, options ...
 	return page.ExtractResourcesBuildings()
 }
 
+func (b *OGame) getStorageCapacity(celestialID ogame.CelestialID, options ...Option) (ogame.Resources, error) {
+	buildings, err := b.getResourcesBuildings(celestialID, options...)
+	if err != nil {
+		return ogame.Resources{}, err
+	}
+	return ogame.StorageCapacity(buildings), nil
+}
+
 func (b *OGame) getLfBuildings(celestialID ogame.CelestialID, options ...Option) (ogame.LfBuildings, error) {
 	options = append(options, ChangePlanet(celestialID))
 	page, err := getPage[parser.LfBuildingsPage](b, options...)
@@ -2930,7 +3854,20 @@ func (b *OGame) getTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuilding
 	if err != nil {
 		return ogame.ResourcesBuildings{}, ogame.Facilities{}, ogame.ShipsInfos{}, ogame.DefensesInfos{}, ogame.Researches{}, ogame.LfBuildings{}, err
 	}
-	return page.ExtractTechs()
+	resourcesBuildings, facilities, ships, defenses, researches, lfBuildings, err := page.ExtractTechs()
+	if err == nil {
+		b.setCachedTechs(celestialID, cachedTechs{resourcesBuildings, facilities, ships, defenses, researches, lfBuildings})
+	}
+	return resourcesBuildings, facilities, ships, defenses, researches, lfBuildings, err
+}
+
+// getCachedTechs returns the techs of a celestial from the cache, they are fetched only if they are missing,
+// older than the TechsCache TTL or were invalidated
+func (b *OGame) getCachedTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
+	if techs, ok := b.cachedTechs(celestialID); ok {
+		return techs.ResourcesBuildings, techs.Facilities, techs.Ships, techs.Defenses, techs.Researches, techs.LfBuildings, nil
+	}
+	return b.getTechs(celestialID)
 }
 
 func (b *OGame) getProduction(celestialID ogame.CelestialID) ([]ogame.Quantifiable, int64, error) {
@@ -2968,19 +3905,98 @@ func (b *OGame) technologyDetails(celestialID ogame.CelestialID, id ogame.ID) (o
 	return b.extractor.ExtractTechnologyDetails(pageHTML)
 }
 
+func (b *OGame) getTechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechnologyDetails, error) {
+	celestial := b.getCachedCelestial(celestialID)
+	if celestial == nil {
+		return ogame.TechnologyDetails{}, ogame.ErrInvalidPlanetID
+	}
+	details, err := b.technologyDetails(celestialID, id)
+	if err != nil {
+		return details, err
+	}
+	resBuildings, facilities, _, _, researches, _, err := b.getTechs(celestialID)
+	if err != nil {
+		return details, err
+	}
+	var energy int64
+	if id == ogame.GravitonTechnologyID {
+		resourcesDetails, err := b.getResourcesDetails(celestialID)
+		if err != nil {
+			return details, err
+		}
+		energy = resourcesDetails.Energy.Available
+	}
+	if obj := ogame.Objs.ByID(id); obj != nil {
+		details.RequirementsMet = obj.IsAvailable(celestial.GetType(), resBuildings, facilities, researches, energy, b.characterClass)
+	}
+	return details, nil
+}
+
+func (b *OGame) getTechTree(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechTreeNode, error) {
+	if ogame.Objs.ByID(id) == nil {
+		return ogame.TechTreeNode{}, errors.New("invalid id " + id.String())
+	}
+	resBuildings, facilities, ships, defenses, researches, lfBuildings, err := b.getTechs(celestialID)
+	if err != nil {
+		return ogame.TechTreeNode{}, err
+	}
+	var lfResearches *ogame.LfResearches
+	var lfErr error
+	tree := ogame.NewTechTree(id, func(id ogame.ID) int64 {
+		switch {
+		case id.IsResourceBuilding():
+			return resBuildings.ByID(id)
+		case id.IsFacility():
+			return facilities.ByID(id)
+		case id.IsTech():
+			return researches.ByID(id)
+		case id.IsShip():
+			return ships.ByID(id)
+		case id.IsDefense():
+			return defenses.ByID(id)
+		case id.IsLfBuilding():
+			return lfBuildings.ByID(id)
+		case id.IsLfTech():
+			if lfResearches == nil && lfErr == nil {
+				var res ogame.LfResearches
+				res, lfErr = b.getLfResearch(celestialID)
+				lfResearches = &res
+			}
+			return lfResearches.ByID(id)
+		}
+		return 0
+	})
+	return tree, lfErr
+}
+
 func getToken(b *OGame, page string, celestialID ogame.CelestialID) (string, error) {
 	pageHTML, _ := b.getPage(page, ChangePlanet(celestialID))
 	return b.extractor.ExtractUpgradeToken(pageHTML)
 }
 
-func (b *OGame) tearDown(celestialID ogame.CelestialID, id ogame.ID) error {
-	var page string
+// tearDownPage returns the page on which id can be torn down, or a *TearDownNotAllowedError
+// explaining why the game does not allow it
+func tearDownPage(id ogame.ID) (string, error) {
 	if id.IsResourceBuilding() {
-		page = "supplies"
+		return SuppliesPageName, nil
 	} else if id.IsFacility() {
-		page = "facilities"
-	} else {
-		return errors.New("invalid id " + id.String())
+		return FacilitiesPageName, nil
+	} else if id.IsLfBuilding() {
+		return LfBuildingsPageName, nil
+	} else if id == ogame.AntiBallisticMissilesID || id == ogame.InterplanetaryMissilesID {
+		return "", ogame.NewTearDownNotAllowedError(id, "missiles are destroyed from the missile silo, use DestroyRockets")
+	} else if id.IsDefense() || id.IsShip() {
+		return "", ogame.NewTearDownNotAllowedError(id, "ships and defenses cannot be demolished, they can only be sold to the scrap merchant")
+	} else if id.IsTech() || id.IsLfTech() {
+		return "", ogame.NewTearDownNotAllowedError(id, "researches cannot be downgraded")
+	}
+	return "", errors.New("invalid id " + id.String())
+}
+
+func (b *OGame) tearDown(celestialID ogame.CelestialID, id ogame.ID) error {
+	page, err := tearDownPage(id)
+	if err != nil {
+		return err
 	}
 
 	pageHTML, _ := b.getPage(page, ChangePlanet(celestialID))
@@ -3010,10 +4026,28 @@ func (b *OGame) tearDown(celestialID ogame.CelestialID, id ogame.ID) error {
 		"type":      {utils.FI64(id)},
 		"cp":        {utils.FI64(celestialID)},
 	}
-	_, err = b.getPageContent(params)
+	if _, err = b.getPageContent(params); err == nil {
+		b.InvalidateTechs(celestialID)
+	}
 	return err
 }
 
+func (b *OGame) tearDownPlan(celestialID ogame.CelestialID, opts ...Option) ([]ogame.TearDownStep, error) {
+	cfg := getOptions(opts...)
+	if cfg.RefundRatio <= 0 {
+		return nil, ogame.ErrRefundRatioRequired
+	}
+	resourcesBuildings, err := b.getResourcesBuildings(celestialID)
+	if err != nil {
+		return nil, err
+	}
+	facilities, err := b.getFacilities(celestialID)
+	if err != nil {
+		return nil, err
+	}
+	return ogame.TearDownPlan(resourcesBuildings, facilities, b.getCachedResearch(), cfg.RefundRatio, b.getUniverseSpeed()), nil
+}
+
 func (b *OGame) build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) error {
 	var page string
 	if id.IsDefense() {
@@ -3063,10 +4097,15 @@ func (b *OGame) build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) err
 			vals.Set("token", token)
 			nbr -= maximumNbr
 		}
+		if err == nil {
+			b.InvalidateTechs(celestialID)
+		}
 		return err
 	}
 
-	_, err = b.getPageContent(vals)
+	if _, err = b.getPageContent(vals); err == nil {
+		b.InvalidateTechs(celestialID)
+	}
 	return err
 }
 
@@ -3112,6 +4151,153 @@ func (b *OGame) buildShips(celestialID ogame.CelestialID, shipID ogame.ID, nbr i
 	return b.buildProduction(celestialID, shipID, nbr)
 }
 
+// maximum number of units the shipyard accepts in one order
+const buildChunkSize int64 = 99999
+
+// errChunkNotQueued returned when the production queue did not grow after ordering a chunk
+var errChunkNotQueued = errors.New("chunk not queued")
+
+func (b *OGame) buildProductionWithProgress(ctx context.Context, celestialID ogame.CelestialID, id ogame.ID, nbr int64, clb func(ogame.BuildProgress)) error {
+	var page string
+	if id.IsShip() {
+		page = ShipyardPageName
+	} else if id.IsDefense() {
+		page = DefensesPageName
+	} else {
+		return errors.New("invalid id " + id.String())
+	}
+	queuedCount := func() (int64, error) {
+		production, _, err := b.getProduction(celestialID)
+		if err != nil {
+			return 0, err
+		}
+		var out int64
+		for _, q := range production {
+			if q.ID == id {
+				out += q.Nbr
+			}
+		}
+		return out, nil
+	}
+	queued, err := queuedCount()
+	if err != nil {
+		return err
+	}
+	progress := ogame.BuildProgress{ID: id, Total: nbr, Queued: queued}
+	for progress.Ordered < nbr {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		token, err := getToken(b, page, celestialID)
+		if err != nil {
+			return err
+		}
+		chunk := utils.MinInt(nbr-progress.Ordered, buildChunkSize)
+		vals := url.Values{
+			"page":      {"ingame"},
+			"component": {page},
+			"modus":     {"1"},
+			"type":      {utils.FI64(id)},
+			"menge":     {utils.FI64(chunk)},
+			"token":     {token},
+			"cp":        {utils.FI64(celestialID)},
+		}
+		if _, err := b.getPageContent(vals); err != nil {
+			return err
+		}
+		prevQueued := progress.Queued
+		if progress.Queued, err = queuedCount(); err != nil {
+			return err
+		}
+		progress.Chunk++
+		progress.Ordered += chunk
+		if clb != nil {
+			clb(progress)
+		}
+		if progress.Queued <= prevQueued {
+			return fmt.Errorf("%w: chunk %d of %d %s", errChunkNotQueued, progress.Chunk, chunk, id)
+		}
+	}
+	return nil
+}
+
+// withConstructionETA runs build and returns when the construction it started will be done.
+// The levels and the queue are read before the build, which invalidates the cached techs of the celestial,
+// so only the page of the queue is fetched when the techs are cached.
+func (b *OGame) withConstructionETA(celestialID ogame.CelestialID, id ogame.ID, nbr int64, build func() error) (ogame.ConstructionETA, error) {
+	var techs cachedTechs
+	var techsErr error
+	techs.ResourcesBuildings, techs.Facilities, techs.Ships, techs.Defenses, techs.Researches, techs.LfBuildings, techsErr = b.getCachedTechs(celestialID)
+	queuedID, countdown, queueErr := b.constructionQueue(celestialID, id)
+	now := b.serverNow()
+	if err := build(); err != nil {
+		return ogame.ConstructionETA{}, err
+	}
+	startedAt := now.Add(time.Duration(countdown) * time.Second)
+	if techsErr != nil {
+		return ogame.ConstructionETA{ID: id, Nbr: nbr, StartedAt: startedAt, FinishAt: startedAt, Err: techsErr.Error()}, nil
+	}
+	eta := b.constructionETA(id, nbr, techs, queuedID, startedAt)
+	if queueErr != nil {
+		eta.Err = queueErr.Error()
+	}
+	return eta, nil
+}
+
+// constructionQueue returns the construction in the queue id goes in and the seconds before the queue is done.
+// Buildings and researches have a single construction in their queue, ships and defenses share the shipyard queue.
+func (b *OGame) constructionQueue(celestialID ogame.CelestialID, id ogame.ID) (ogame.ID, int64, error) {
+	if id.IsShip() || id.IsDefense() {
+		_, countdown, err := b.getProduction(celestialID)
+		return 0, utils.MaxInt(countdown, 0), err
+	}
+	page, err := getPage[parser.OverviewPage](b, ChangePlanet(celestialID))
+	if err != nil {
+		return 0, 0, err
+	}
+	buildingID, buildingCountdown, researchID, researchCountdown, lfBuildingID, lfBuildingCountdown, lfResearchID, lfResearchCountdown := page.ExtractConstructions()
+	queuedID, countdown := buildingID, buildingCountdown
+	switch {
+	case id.IsTech():
+		queuedID, countdown = researchID, researchCountdown
+	case id.IsLfBuilding():
+		queuedID, countdown = lfBuildingID, lfBuildingCountdown
+	case id.IsLfTech():
+		queuedID, countdown = lfResearchID, lfResearchCountdown
+	}
+	return queuedID, utils.MaxInt(countdown, 0), nil // the countdown of a construction just done can be negative
+}
+
+// constructionETA computes the duration of the construction of id, from the techs of the celestial before the build.
+// queuedID is the construction in front of it in its queue, the level is one more when it is the same.
+func (b *OGame) constructionETA(id ogame.ID, nbr int64, techs cachedTechs, queuedID ogame.ID, startedAt time.Time) ogame.ConstructionETA {
+	eta := ogame.ConstructionETA{ID: id, Nbr: nbr, StartedAt: startedAt}
+	var queued int64
+	if queuedID == id {
+		queued = 1
+	}
+	switch {
+	case id.IsTech():
+		eta.Nbr = techs.Researches.ByID(id) + 1 + queued
+		eta.Duration = b.researchDuration(id, eta.Nbr, techs.Facilities.ResearchLab)
+	case id.IsResourceBuilding():
+		eta.Nbr = techs.ResourcesBuildings.ByID(id) + 1 + queued
+		eta.Duration = b.lfConstructionTime(id, eta.Nbr, techs.Facilities, techs.LfBuildings)
+	case id.IsFacility():
+		eta.Nbr = techs.Facilities.ByID(id) + 1 + queued
+		eta.Duration = b.lfConstructionTime(id, eta.Nbr, techs.Facilities, techs.LfBuildings)
+	case id.IsLfBuilding():
+		eta.Nbr = techs.LfBuildings.ByID(id) + 1 + queued
+		eta.Duration = b.constructionTime(id, eta.Nbr, techs.Facilities)
+	case id.IsShip(), id.IsDefense():
+		eta.Duration = b.constructionTime(id, nbr, techs.Facilities)
+	}
+	eta.FinishAt = startedAt.Add(eta.Duration)
+	return eta
+}
+
 func (b *OGame) constructionsBeingBuilt(celestialID ogame.CelestialID) (ogame.ID, int64, ogame.ID, int64, ogame.ID, int64, ogame.ID, int64) {
 	page, err := getPage[parser.OverviewPage](b, ChangePlanet(celestialID))
 	if err != nil {
@@ -3158,7 +4344,48 @@ func (b *OGame) fetchResources(celestialID ogame.CelestialID) (ogame.ResourcesDe
 	if err != nil {
 		return ogame.ResourcesDetails{}, err
 	}
-	return b.extractor.ExtractResourcesDetails(pageJSON)
+	details, err := b.extractor.ExtractResourcesDetails(pageJSON)
+	if err == nil && celestialID != 0 {
+		b.resourcesDetailsMu.Lock()
+		if b.resourcesDetails == nil {
+			b.resourcesDetails = make(map[ogame.CelestialID]cachedResourcesDetails)
+		}
+		b.resourcesDetails[celestialID] = cachedResourcesDetails{details: details, fetchedAt: time.Now()}
+		b.resourcesDetailsMu.Unlock()
+	}
+	return details, err
+}
+
+// cachedResourcesDetails resources details of a celestial, and when they were fetched
+type cachedResourcesDetails struct {
+	details   ogame.ResourcesDetails
+	fetchedAt time.Time
+}
+
+// invalidateResourcesDetails forgets the fetched resources, they are fetched again by the next forecast
+func (b *OGame) invalidateResourcesDetails() {
+	b.resourcesDetailsMu.Lock()
+	b.resourcesDetails = nil
+	b.resourcesDetailsMu.Unlock()
+}
+
+// forecastResources extrapolates the last fetched resources of the celestial, they are fetched if never fetched
+// before, or if an action was made since they were fetched
+func (b *OGame) forecastResources(celestialID ogame.CelestialID, at time.Time) (ogame.Resources, error) {
+	b.resourcesDetailsMu.Lock()
+	cached, found := b.resourcesDetails[celestialID]
+	b.resourcesDetailsMu.Unlock()
+	if !found {
+		if b.getCachedCelestial(celestialID) == nil {
+			return ogame.Resources{}, ogame.ErrInvalidPlanetID
+		}
+		details, err := b.fetchResources(celestialID)
+		if err != nil {
+			return ogame.Resources{}, err
+		}
+		cached = cachedResourcesDetails{details: details, fetchedAt: time.Now()}
+	}
+	return cached.details.Forecast(at.Sub(cached.fetchedAt)), nil
 }
 
 func (b *OGame) getResources(celestialID ogame.CelestialID) (ogame.Resources, error) {
@@ -3181,6 +4408,19 @@ func (b *OGame) getResourcesDetails(celestialID ogame.CelestialID) (ogame.Resour
 	return b.fetchResources(celestialID)
 }
 
+func (b *OGame) getMissiles(celestialID ogame.CelestialID) (ogame.Missiles, error) {
+	vals := url.Values{
+		"page":      {"ajax"},
+		"component": {RocketlayerPageName},
+		"overlay":   {"1"},
+	}
+	page, err := getAjaxPage[parser.RocketlayerAjaxPage](b, vals, ChangePlanet(celestialID))
+	if err != nil {
+		return ogame.Missiles{}, err
+	}
+	return page.ExtractMissiles()
+}
+
 func (b *OGame) destroyRockets(planetID ogame.PlanetID, abm, ipm int64) error {
 	vals := url.Values{
 		"page":      {"ajax"},
@@ -3346,9 +4586,22 @@ type CheckTargetResponse struct {
 	NewAjaxToken string `json:"newAjaxToken"`
 }
 
+// cargoCapacity returns the cargo capacity of ships using the cached researches, the player class and the server settings
+func (b *OGame) cargoCapacity(ships ogame.ShipsInfos) int64 {
+	return ships.Cargo(b.getCachedResearch(), b.ProbeRaids(), b.isCollector(), b.IsPioneers())
+}
+
+func (b *OGame) cargoShipsNeeded(id ogame.ID, amount int64) int64 {
+	return ogame.CargoShipsNeeded(id, amount, b.getCachedResearch(), b.ProbeRaids(), b.isCollector(), b.IsPioneers())
+}
+
 func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate,
 	mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64, ensure bool) (ogame.Fleet, error) {
 
+	if !speed.IsValid(b.characterClass) {
+		return ogame.Fleet{}, ogame.ErrInvalidSpeed
+	}
+
 	// Get existing fleet, so we can ensure new fleet ID is greater
 	initialFleets, slots := b.getFleets()
 	maxInitialFleetID := ogame.FleetID(0)
@@ -3368,6 +4621,12 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 		}
 	}
 
+	if b.slotManager != nil {
+		if err := b.slotManager.Check(mission, slots); err != nil {
+			return ogame.Fleet{}, err
+		}
+	}
+
 	// Page 1 : get to fleet page
 	pageHTML, err := b.getPage(FleetdispatchPageName, ChangePlanet(celestialID))
 	if err != nil {
@@ -3493,7 +4752,7 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 		return ogame.Fleet{}, errors.New("target is not ok")
 	}
 
-	cargo := ogame.ShipsInfos{}.FromQuantifiables(ships).Cargo(b.getCachedResearch(), b.server.Settings.EspionageProbeRaids == 1, b.isCollector(), b.IsPioneers())
+	cargo := b.cargoCapacity(ogame.ShipsInfos{}.FromQuantifiables(ships))
 	newResources := ogame.Resources{}
 	if resources.Total() > cargo {
 		newResources.Deuterium = int64(math.Min(float64(resources.Deuterium), float64(cargo)))
@@ -3513,7 +4772,7 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	if b.IsV8() || b.IsV9() {
 		payload.Set("token", checkRes.NewAjaxToken)
 	}
-	payload.Set("speed", strconv.FormatInt(int64(speed), 10))
+	payload.Set("speed", speed.FormValue())
 	payload.Set("crystal", utils.FI64(newResources.Crystal))
 	payload.Set("deuterium", utils.FI64(newResources.Deuterium))
 	payload.Set("metal", utils.FI64(newResources.Metal))
@@ -3580,6 +4839,9 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 			}
 		}
 		if max.ID > maxInitialFleetID {
+			if b.slotManager != nil {
+				b.slotManager.track(max)
+			}
 			return max, nil
 		}
 	}
@@ -3600,7 +4862,30 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	return ogame.Fleet{}, errors.New("could not find new fleet ID")
 }
 
-func (b *OGame) getPageMessages(page int64, tabid ogame.MessagesTabID) ([]byte, error) {
+func (b *OGame) moonshotShips(id ogame.ID, chance float64) int64 {
+	return ogame.MoonshotShips(id, chance, b.serverData.DebrisFactor)
+}
+
+func (b *OGame) destroyMoon(celestialID ogame.CelestialID, target ogame.Coordinate, deathstars int64) (ogame.Fleet, ogame.MoonDestructionChance, error) {
+	if deathstars <= 0 {
+		return ogame.Fleet{}, ogame.MoonDestructionChance{}, ogame.ErrNoShipSelected
+	}
+	target.Type = ogame.MoonType
+	systemInfos, err := b.galaxyInfos(target.Galaxy, target.System, SkipCache)
+	if err != nil {
+		return ogame.Fleet{}, ogame.MoonDestructionChance{}, err
+	}
+	planetInfos := systemInfos.Position(target.Position)
+	if planetInfos == nil || planetInfos.Moon == nil {
+		return ogame.Fleet{}, ogame.MoonDestructionChance{}, ogame.ErrNoMoonAvailable
+	}
+	chance := ogame.NewMoonDestructionChance(planetInfos.Moon.Diameter, deathstars)
+	ships := []ogame.Quantifiable{{ID: ogame.DeathstarID, Nbr: deathstars}}
+	fleet, err := b.sendFleet(celestialID, ships, ogame.HundredPercent, target, ogame.Destroy, ogame.Resources{}, 0, 0, true)
+	return fleet, chance, err
+}
+
+func (b *OGame) getPageMessages(page int64, tabid ogame.MessagesTabID, opts ...Option) ([]byte, error) {
 	payload := url.Values{
 		"messageId":  {"-1"},
 		"tabid":      {utils.FI64(tabid)},
@@ -3608,49 +4893,92 @@ func (b *OGame) getPageMessages(page int64, tabid ogame.MessagesTabID) ([]byte,
 		"pagination": {utils.FI64(page)},
 		"ajax":       {"1"},
 	}
-	return b.postPageContent(url.Values{"page": {"messages"}}, payload)
+	return b.postPageContent(url.Values{"page": {"messages"}}, payload, opts...)
 }
 
-func (b *OGame) getEspionageReportMessages() ([]ogame.EspionageReportSummary, error) {
-	var page int64 = 1
-	var nbPage int64 = 1
-	msgs := make([]ogame.EspionageReportSummary, 0)
-	for page <= nbPage {
-		pageHTML, _ := b.getPageMessages(page, EspionageMessagesTabID)
-		newMessages, newNbPage := b.extractor.ExtractEspionageReportMessageIDs(pageHTML)
-		msgs = append(msgs, newMessages...)
-		nbPage = newNbPage
-		page++
+// getMessagesPage returns the parsed messages of a page of a tab, and the number of pages of the tab
+func (b *OGame) getMessagesPage(tabID ogame.MessagesTabID, page int64) ([]ogame.Message, int64, error) {
+	pageHTML, err := b.getPageMessages(page, tabID)
+	if err != nil {
+		return nil, 0, err
+	}
+	msgs := make([]ogame.Message, 0)
+	var nbPage int64
+	switch tabID {
+	case EspionageMessagesTabID:
+		var newMessages []ogame.EspionageReportSummary
+		newMessages, nbPage = b.extractor.ExtractEspionageReportMessageIDs(pageHTML)
+		for i := range newMessages {
+			msgs = append(msgs, ogame.Message{ID: newMessages[i].ID, TabID: tabID, CreatedAt: newMessages[i].CreatedAt, EspionageReport: &newMessages[i]})
+		}
+	case CombatReportsMessagesTabID:
+		var newMessages []ogame.CombatReportSummary
+		newMessages, nbPage = b.extractor.ExtractCombatReportMessagesSummary(pageHTML)
+		for i := range newMessages {
+			msgs = append(msgs, ogame.Message{ID: newMessages[i].ID, TabID: tabID, CreatedAt: newMessages[i].CreatedAt, CombatReport: &newMessages[i]})
+		}
+	case ExpeditionsMessagesTabID:
+		var newMessages []ogame.ExpeditionMessage
+		newMessages, nbPage, err = b.extractor.ExtractExpeditionMessages(pageHTML)
+		for i := range newMessages {
+			msgs = append(msgs, ogame.Message{ID: newMessages[i].ID, TabID: tabID, CreatedAt: newMessages[i].CreatedAt, Expedition: &newMessages[i]})
+		}
+	case UnionsTransportMessagesTabID:
+		var newMessages []ogame.UnionsTransportMessage
+		newMessages, nbPage, err = b.extractor.ExtractUnionsTransportMessages(pageHTML)
+		for i := range newMessages {
+			msgs = append(msgs, ogame.Message{ID: newMessages[i].ID, TabID: tabID, CreatedAt: newMessages[i].CreatedAt, UnionsTransport: &newMessages[i]})
+		}
+	case OtherMessagesTabID:
+		var newMessages []ogame.OtherMessage
+		newMessages, nbPage, err = b.extractor.ExtractOtherMessages(pageHTML)
+		for i := range newMessages {
+			msgs = append(msgs, ogame.Message{ID: newMessages[i].ID, TabID: tabID, CreatedAt: newMessages[i].CreatedAt, Other: &newMessages[i]})
+		}
+	case MarketplacePurchasesMessagesTabID, MarketplaceSalesMessagesTabID:
+		var newMessages []ogame.MarketplaceMessage
+		newMessages, nbPage, err = b.extractor.ExtractMarketplaceMessages(pageHTML)
+		for i := range newMessages {
+			msgs = append(msgs, ogame.Message{ID: newMessages[i].ID, TabID: tabID, CreatedAt: newMessages[i].CreatedAt, Marketplace: &newMessages[i]})
+		}
+	default:
+		return nil, 0, ogame.ErrUnsupportedMessagesTab
 	}
-	return msgs, nil
+	return msgs, nbPage, err
 }
 
-func (b *OGame) getCombatReportMessages() ([]ogame.CombatReportSummary, error) {
-	var page int64 = 1
-	var nbPage int64 = 1
-	msgs := make([]ogame.CombatReportSummary, 0)
-	for page <= nbPage {
-		pageHTML, _ := b.getPageMessages(page, CombatReportsMessagesTabID)
-		newMessages, newNbPage := b.extractor.ExtractCombatReportMessagesSummary(pageHTML)
-		msgs = append(msgs, newMessages...)
-		nbPage = newNbPage
-		page++
-	}
-	return msgs, nil
+func (b *OGame) getEspionageReportMessages(opts ...Option) ([]ogame.EspionageReportSummary, error) {
+	return fetchMessagePages(b, EspionageMessagesTabID, func(pageHTML []byte) ([]ogame.EspionageReportSummary, int64, error) {
+		msgs, nbPage := b.extractor.ExtractEspionageReportMessageIDs(pageHTML)
+		return msgs, nbPage, nil
+	}, opts...)
 }
 
-func (b *OGame) getExpeditionMessages() ([]ogame.ExpeditionMessage, error) {
-	var page int64 = 1
-	var nbPage int64 = 1
-	msgs := make([]ogame.ExpeditionMessage, 0)
-	for page <= nbPage {
-		pageHTML, _ := b.getPageMessages(page, ExpeditionsMessagesTabID)
-		newMessages, newNbPage, _ := b.extractor.ExtractExpeditionMessages(pageHTML)
-		msgs = append(msgs, newMessages...)
-		nbPage = newNbPage
-		page++
+func (b *OGame) getCombatReportMessages(opts ...Option) ([]ogame.CombatReportSummary, error) {
+	return fetchMessagePages(b, CombatReportsMessagesTabID, func(pageHTML []byte) ([]ogame.CombatReportSummary, int64, error) {
+		msgs, nbPage := b.extractor.ExtractCombatReportMessagesSummary(pageHTML)
+		return msgs, nbPage, nil
+	}, opts...)
+}
+
+func (b *OGame) getExpeditionMessages(opts ...Option) ([]ogame.ExpeditionMessage, error) {
+	return fetchMessagePages(b, ExpeditionsMessagesTabID, b.extractor.ExtractExpeditionMessages, opts...)
+}
+
+func (b *OGame) getTransportMessages(opts ...Option) ([]ogame.UnionsTransportMessage, error) {
+	return fetchMessagePages(b, UnionsTransportMessagesTabID, b.extractor.ExtractUnionsTransportMessages, opts...)
+}
+
+// joinACS sends ships from celestialID to join the union of an ACS invitation
+func (b *OGame) joinACS(celestialID ogame.CelestialID, invitation ogame.ACSInvitation, ships []ogame.Quantifiable, speed ogame.Speed) (ogame.Fleet, error) {
+	if invitation.UnionID == 0 {
+		return ogame.Fleet{}, errors.New("invalid acs invitation, no union id")
 	}
-	return msgs, nil
+	return b.sendFleet(celestialID, ships, speed, invitation.Target, ogame.GroupedAttack, ogame.Resources{}, 0, invitation.UnionID, false)
+}
+
+func (b *OGame) getOtherMessages(opts ...Option) ([]ogame.OtherMessage, error) {
+	return fetchMessagePages(b, OtherMessagesTabID, b.extractor.ExtractOtherMessages, opts...)
 }
 
 func (b *OGame) collectAllMarketplaceMessages() error {
@@ -3715,17 +5043,31 @@ func (b *OGame) getMarketplaceSalesMessages() ([]ogame.MarketplaceMessage, error
 
 // tabID 26: purchases, 27: sales
 func (b *OGame) getMarketplaceMessages(tabID ogame.MessagesTabID) ([]ogame.MarketplaceMessage, error) {
+	return fetchMessagePages(b, tabID, b.extractor.ExtractMarketplaceMessages)
+}
+
+// getExpeditionStats walks the expedition messages, newest first, until one older than since is found
+func (b *OGame) getExpeditionStats(since time.Time) (ogame.ExpeditionStats, error) {
+	stats := ogame.NewExpeditionStats(since)
 	var page int64 = 1
 	var nbPage int64 = 1
-	msgs := make([]ogame.MarketplaceMessage, 0)
+LOOP:
 	for page <= nbPage {
-		pageHTML, _ := b.getPageMessages(page, tabID)
-		newMessages, newNbPage, _ := b.extractor.ExtractMarketplaceMessages(pageHTML)
-		msgs = append(msgs, newMessages...)
+		pageHTML, err := b.getPageMessages(page, ExpeditionsMessagesTabID)
+		if err != nil {
+			return stats, err
+		}
+		newMessages, newNbPage, _ := b.extractor.ExtractExpeditionMessages(pageHTML)
+		for _, m := range newMessages {
+			if m.CreatedAt.Before(since) {
+				break LOOP
+			}
+			stats.Add(m)
+		}
 		nbPage = newNbPage
 		page++
 	}
-	return msgs, nil
+	return stats, nil
 }
 
 func (b *OGame) getExpeditionMessageAt(t time.Time) (ogame.ExpeditionMessage, error) {
@@ -3770,11 +5112,23 @@ func (b *OGame) getCombatReportFor(coord ogame.Coordinate) (ogame.CombatReportSu
 }
 
 func (b *OGame) getEspionageReport(msgID int64) (ogame.EspionageReport, error) {
+	// Reports never change, a cached report is always valid
+	if report, err := cacheGet[ogame.EspionageReport](b, ReportsCache, utils.FI64(msgID)); err == nil {
+		return report, nil
+	}
 	pageHTML, _ := b.getPageContent(url.Values{"page": {"messages"}, "messageId": {utils.FI64(msgID)}, "tabid": {"20"}, "ajax": {"1"}})
-	return b.extractor.ExtractEspionageReport(pageHTML)
+	report, err := b.extractor.ExtractEspionageReport(pageHTML)
+	if err == nil {
+		cacheSet(b, ReportsCache, utils.FI64(msgID), report)
+	}
+	if err == nil && b.reportStore != nil {
+		b.reportStore.Add(report)
+	}
+	return report, err
 }
 
-func (b *OGame) getEspionageReportFor(coord ogame.Coordinate) (ogame.EspionageReport, error) {
+func (b *OGame) getEspionageReportFor(coord ogame.Coordinate, opts ...Option) (ogame.EspionageReport, error) {
+	cfg := getOptions(opts...)
 	var page int64 = 1
 	var nbPage int64 = 1
 	for page <= nbPage {
@@ -3785,13 +5139,17 @@ func (b *OGame) getEspionageReportFor(coord ogame.Coordinate) (ogame.EspionageRe
 		newMessages, newNbPage := b.extractor.ExtractEspionageReportMessageIDs(pageHTML)
 		for _, m := range newMessages {
 			if m.Target.Equal(coord) {
-				return b.getEspionageReport(m.ID)
+				report, err := b.getEspionageReport(m.ID)
+				if err == nil && cfg.MaxAge > 0 && time.Since(report.Date) > cfg.MaxAge {
+					return report, fmt.Errorf("espionage %w for %s, %s old", ogame.ErrReportTooOld, coord, time.Since(report.Date).Round(time.Second))
+				}
+				return report, err
 			}
 		}
 		nbPage = newNbPage
 		page++
 	}
-	return ogame.EspionageReport{}, errors.New("espionage report not found for " + coord.String())
+	return ogame.EspionageReport{}, fmt.Errorf("espionage %w for %s", ogame.ErrReportNotFound, coord)
 }
 
 func (b *OGame) getDeleteMessagesToken() (string, error) {
@@ -3808,6 +5166,46 @@ func (b *OGame) deleteMessage(msgID int64) error {
 	if err != nil {
 		return err
 	}
+	return b.deleteMessageWithToken(msgID, token)
+}
+
+// deleteMessages deletes the messages of a tab for which filter returns true, returns the number of deleted messages
+func (b *OGame) deleteMessages(tabID ogame.MessagesTabID, filter func(ogame.Message) bool) (int64, error) {
+	// Collect the ids first, deleting messages would shift the pagination
+	ids := make([]int64, 0)
+	var page int64 = 1
+	var nbPage int64 = 1
+	for page <= nbPage {
+		msgs, newNbPage, err := b.getMessagesPage(tabID, page)
+		if err != nil {
+			return 0, err
+		}
+		for _, msg := range msgs {
+			if filter(msg) {
+				ids = append(ids, msg.ID)
+			}
+		}
+		nbPage = newNbPage
+		page++
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	token, err := b.getDeleteMessagesToken()
+	if err != nil {
+		return 0, err
+	}
+	var deleted int64
+	for _, id := range ids {
+		if err := b.deleteMessageWithToken(id, token); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (b *OGame) deleteMessageWithToken(msgID int64, token string) error {
 	payload := url.Values{
 		"messageId": {utils.FI64(msgID)},
 		"action":    {"103"},
@@ -3893,8 +5291,23 @@ func energyNeeded(resourcesBuildings ogame.ResourcesBuildings, resSettings ogame
 }
 
 func productionRatio(temp ogame.Temperature, resourcesBuildings ogame.ResourcesBuildings, resSettings ogame.ResourceSettings, energyTechnology int64) float64 {
-	energyProduced := energyProduced(temp, resourcesBuildings, resSettings, energyTechnology)
-	energyNeeded := energyNeeded(resourcesBuildings, resSettings)
+	return productionRatioWithBonuses(temp, resourcesBuildings, resSettings, energyTechnology, ogame.ProductionBonuses{})
+}
+
+// bonusEnergyProduced energy produced, including the engineer, commanding staff and collector bonuses
+func bonusEnergyProduced(temp ogame.Temperature, resourcesBuildings ogame.ResourcesBuildings, resSettings ogame.ResourceSettings, energyTechnology int64, bonuses ogame.ProductionBonuses) int64 {
+	produced := energyProduced(temp, resourcesBuildings, resSettings, energyTechnology)
+	return produced + int64(float64(produced)*bonuses.EnergyBonus())
+}
+
+// bonusEnergyNeeded energy needed by the mines and the active crawlers
+func bonusEnergyNeeded(resourcesBuildings ogame.ResourcesBuildings, resSettings ogame.ResourceSettings, bonuses ogame.ProductionBonuses) int64 {
+	return energyNeeded(resourcesBuildings, resSettings) + bonuses.CrawlersEnergyConsumption(resourcesBuildings, resSettings.Crawler)
+}
+
+func productionRatioWithBonuses(temp ogame.Temperature, resourcesBuildings ogame.ResourcesBuildings, resSettings ogame.ResourceSettings, energyTechnology int64, bonuses ogame.ProductionBonuses) float64 {
+	energyProduced := bonusEnergyProduced(temp, resourcesBuildings, resSettings, energyTechnology, bonuses)
+	energyNeeded := bonusEnergyNeeded(resourcesBuildings, resSettings, bonuses)
 	ratio := 1.0
 	if energyNeeded > energyProduced {
 		ratio = float64(energyProduced) / float64(energyNeeded)
@@ -3903,38 +5316,204 @@ func productionRatio(temp ogame.Temperature, resourcesBuildings ogame.ResourcesB
 }
 
 func getProductions(resBuildings ogame.ResourcesBuildings, resSettings ogame.ResourceSettings, researches ogame.Researches, universeSpeed int64,
-	temp ogame.Temperature, globalRatio float64) ogame.Resources {
-	energyProduced := energyProduced(temp, resBuildings, resSettings, researches.EnergyTechnology)
-	energyNeeded := energyNeeded(resBuildings, resSettings)
+	temp ogame.Temperature, globalRatio float64, bonuses ogame.ProductionBonuses) ogame.Resources {
+	energyProduced := bonusEnergyProduced(temp, resBuildings, resSettings, researches.EnergyTechnology, bonuses)
+	energyNeeded := bonusEnergyNeeded(resBuildings, resSettings, bonuses)
 	metalSetting := float64(resSettings.MetalMine) / 100
 	crystalSetting := float64(resSettings.CrystalMine) / 100
 	deutSetting := float64(resSettings.DeuteriumSynthesizer) / 100
+	// Bonuses apply to the mines production without plasma technology and basic income
+	minesBonus := bonuses.MinesBonus(resBuildings, resSettings.Crawler)
+	metalBase := ogame.MetalMine.Production(universeSpeed, metalSetting, globalRatio, 0, resBuildings.MetalMine) - ogame.MetalMine.Production(universeSpeed, 0, 0, 0, 0)
+	crystalBase := ogame.CrystalMine.Production(universeSpeed, crystalSetting, globalRatio, 0, resBuildings.CrystalMine) - ogame.CrystalMine.Production(universeSpeed, 0, 0, 0, 0)
+	deutBase := ogame.DeuteriumSynthesizer.Production(universeSpeed, temp.Mean(), deutSetting, globalRatio, 0, resBuildings.DeuteriumSynthesizer)
 	return ogame.Resources{
-		Metal:     ogame.MetalMine.Production(universeSpeed, metalSetting, globalRatio, researches.PlasmaTechnology, resBuildings.MetalMine),
-		Crystal:   ogame.CrystalMine.Production(universeSpeed, crystalSetting, globalRatio, researches.PlasmaTechnology, resBuildings.CrystalMine),
-		Deuterium: ogame.DeuteriumSynthesizer.Production(universeSpeed, temp.Mean(), deutSetting, globalRatio, researches.PlasmaTechnology, resBuildings.DeuteriumSynthesizer) - ogame.FusionReactor.GetFuelConsumption(universeSpeed, float64(resSettings.FusionReactor)/100, resBuildings.FusionReactor),
+		Metal:     ogame.MetalMine.Production(universeSpeed, metalSetting, globalRatio, researches.PlasmaTechnology, resBuildings.MetalMine) + int64(float64(metalBase)*minesBonus),
+		Crystal:   ogame.CrystalMine.Production(universeSpeed, crystalSetting, globalRatio, researches.PlasmaTechnology, resBuildings.CrystalMine) + int64(float64(crystalBase)*minesBonus),
+		Deuterium: ogame.DeuteriumSynthesizer.Production(universeSpeed, temp.Mean(), deutSetting, globalRatio, researches.PlasmaTechnology, resBuildings.DeuteriumSynthesizer) + int64(float64(deutBase)*minesBonus) - ogame.FusionReactor.GetFuelConsumption(universeSpeed, float64(resSettings.FusionReactor)/100, resBuildings.FusionReactor),
 		Energy:    energyProduced - energyNeeded,
 	}
 }
 
+// productionBonuses returns the production bonuses of the bot on the planet, crawlers are counted when withCrawlers is true
+func (b *OGame) productionBonuses(planetID ogame.PlanetID, withCrawlers bool) ogame.ProductionBonuses {
+	bonuses := ogame.ProductionBonuses{
+		CharacterClass:  b.characterClass,
+		Geologist:       b.hasGeologist,
+		Engineer:        b.hasEngineer,
+		CommandingStaff: b.hasCommander && b.hasAdmiral && b.hasEngineer && b.hasGeologist && b.hasTechnocrat,
+	}
+	if withCrawlers {
+		if ships, err := b.getShips(planetID.Celestial()); err == nil {
+			bonuses.Crawlers = ships.Crawler
+		}
+	}
+	return bonuses
+}
+
 func (b *OGame) getResourcesProductions(planetID ogame.PlanetID) (ogame.Resources, error) {
 	planet, _ := b.getPlanet(planetID)
 	resBuildings, _ := b.getResourcesBuildings(planetID.Celestial())
 	researches := b.getResearch()
 	universeSpeed := b.serverData.Speed
 	resSettings, _ := b.getResourceSettings(planetID)
-	ratio := productionRatio(planet.Temperature, resBuildings, resSettings, researches.EnergyTechnology)
-	productions := getProductions(resBuildings, resSettings, researches, universeSpeed, planet.Temperature, ratio)
+	bonuses := b.productionBonuses(planetID, true)
+	ratio := productionRatioWithBonuses(planet.Temperature, resBuildings, resSettings, researches.EnergyTechnology, bonuses)
+	productions := getProductions(resBuildings, resSettings, researches, universeSpeed, planet.Temperature, ratio, bonuses)
 	return productions, nil
 }
 
 func getResourcesProductionsLight(resBuildings ogame.ResourcesBuildings, researches ogame.Researches,
-	resSettings ogame.ResourceSettings, temp ogame.Temperature, universeSpeed int64) ogame.Resources {
-	ratio := productionRatio(temp, resBuildings, resSettings, researches.EnergyTechnology)
-	productions := getProductions(resBuildings, resSettings, researches, universeSpeed, temp, ratio)
+	resSettings ogame.ResourceSettings, temp ogame.Temperature, bonuses ogame.ProductionBonuses, universeSpeed int64) ogame.Resources {
+	ratio := productionRatioWithBonuses(temp, resBuildings, resSettings, researches.EnergyTechnology, bonuses)
+	productions := getProductions(resBuildings, resSettings, researches, universeSpeed, temp, ratio, bonuses)
 	return productions
 }
 
+func (b *OGame) amortizationPlan(celestialIDs []ogame.CelestialID, rates ogame.Ratio) ([]ogame.Investment, error) {
+	type planetData struct {
+		id           ogame.CelestialID
+		temp         ogame.Temperature
+		resBuildings ogame.ResourcesBuildings
+		resSettings  ogame.ResourceSettings
+		facilities   ogame.Facilities
+	}
+	researches := b.getResearch()
+	universeSpeed := b.getUniverseSpeed()
+	planets := make([]planetData, 0, len(celestialIDs))
+	for _, celestialID := range celestialIDs {
+		celestial := b.getCachedCelestial(celestialID)
+		if celestial == nil {
+			return nil, ogame.ErrInvalidPlanetID
+		}
+		planet, ok := celestial.(Planet)
+		if !ok {
+			continue
+		}
+		resBuildings, err := b.getResourcesBuildings(celestialID)
+		if err != nil {
+			return nil, err
+		}
+		resSettings, err := b.getResourceSettings(planet.ID)
+		if err != nil {
+			return nil, err
+		}
+		facilities, err := b.getFacilities(celestialID)
+		if err != nil {
+			return nil, err
+		}
+		planets = append(planets, planetData{celestialID, planet.Temperature, resBuildings, resSettings, facilities})
+	}
+	if len(planets) == 0 {
+		return nil, errors.New("no planet to analyse")
+	}
+	bonuses := b.productionBonuses(0, false)
+	production := func(p planetData, resBuildings ogame.ResourcesBuildings, researches ogame.Researches) ogame.Resources {
+		return getResourcesProductionsLight(resBuildings, researches, p.resSettings, p.temp, bonuses, universeSpeed)
+	}
+
+	var out []ogame.Investment
+	var totalProduction ogame.Resources
+	var totalMines ogame.ResourcesBuildings
+	var bestLab int64
+	for _, p := range planets {
+		current := production(p, p.resBuildings, researches)
+		totalProduction = totalProduction.Add(current)
+		totalMines.MetalMine += p.resBuildings.MetalMine
+		totalMines.CrystalMine += p.resBuildings.CrystalMine
+		totalMines.DeuteriumSynthesizer += p.resBuildings.DeuteriumSynthesizer
+		bestLab = utils.MaxInt(bestLab, p.facilities.ResearchLab)
+		for _, mine := range []ogame.ID{ogame.MetalMineID, ogame.CrystalMineID, ogame.DeuteriumSynthesizerID} {
+			upgraded := p.resBuildings
+			switch mine {
+			case ogame.MetalMineID:
+				upgraded.MetalMine++
+			case ogame.CrystalMineID:
+				upgraded.CrystalMine++
+			case ogame.DeuteriumSynthesizerID:
+				upgraded.DeuteriumSynthesizer++
+			}
+			level := upgraded.ByID(mine)
+			out = append(out, ogame.Investment{
+				CelestialID:      p.id,
+				ID:               mine,
+				Level:            level,
+				Cost:             ogame.Objs.ByID(mine).GetPrice(level),
+				Gain:             production(p, upgraded, researches).Sub(current),
+				ConstructionTime: b.constructionTime(mine, level, p.facilities),
+			})
+		}
+	}
+
+	// Plasma technology increases the production of every planet
+	plasma := researches
+	plasma.PlasmaTechnology++
+	var plasmaGain ogame.Resources
+	for _, p := range planets {
+		plasmaGain = plasmaGain.Add(production(p, p.resBuildings, plasma).Sub(production(p, p.resBuildings, researches)))
+	}
+	out = append(out, ogame.Investment{
+		ID:               ogame.PlasmaTechnologyID,
+		Level:            plasma.PlasmaTechnology,
+		Cost:             ogame.PlasmaTechnology.GetPrice(plasma.PlasmaTechnology),
+		Gain:             plasmaGain,
+		ConstructionTime: b.researchDuration(ogame.PlasmaTechnologyID, plasma.PlasmaTechnology, bestLab),
+	})
+
+	// Astrophysics unlocks a new colony (every odd level), which is assumed to produce as much as an average planet
+	// once its mines are built up to the average levels
+	nbPlanets := int64(len(planets))
+	avgMines := ogame.ResourcesBuildings{
+		MetalMine:            totalMines.MetalMine / nbPlanets,
+		CrystalMine:          totalMines.CrystalMine / nbPlanets,
+		DeuteriumSynthesizer: totalMines.DeuteriumSynthesizer / nbPlanets,
+	}
+	astro := investmentAstrophysics(researches.Astrophysics)
+	astro.Gain = ogame.Resources{
+		Metal:     totalProduction.Metal / nbPlanets,
+		Crystal:   totalProduction.Crystal / nbPlanets,
+		Deuterium: totalProduction.Deuterium / nbPlanets,
+	}
+	for lvl := researches.Astrophysics + 1; lvl <= astro.Level; lvl++ {
+		astro.ConstructionTime += b.researchDuration(ogame.AstrophysicsID, lvl, bestLab)
+	}
+	for _, mine := range []ogame.ID{ogame.MetalMineID, ogame.CrystalMineID, ogame.DeuteriumSynthesizerID} {
+		for lvl := int64(1); lvl <= avgMines.ByID(mine); lvl++ {
+			astro.Cost = astro.Cost.Add(ogame.Objs.ByID(mine).GetPrice(lvl))
+		}
+	}
+	out = append(out, astro)
+
+	for i := range out {
+		out[i].Cost.Energy = 0
+		out[i].Gain.Energy = 0
+		out[i].PaybackTime = ogame.PaybackTime(out[i].Cost, out[i].Gain, rates)
+		if out[i].PaybackTime >= 0 {
+			out[i].PaybackTime += out[i].ConstructionTime
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].PaybackTime < 0 || out[j].PaybackTime < 0 {
+			return out[j].PaybackTime < 0 && out[i].PaybackTime >= 0
+		}
+		return out[i].PaybackTime < out[j].PaybackTime
+	})
+	return out, nil
+}
+
+// investmentAstrophysics returns the astrophysics levels needed for the next colony slot, with their cost
+func investmentAstrophysics(currentLevel int64) ogame.Investment {
+	target := currentLevel + 1
+	if target%2 == 0 {
+		target++
+	}
+	inv := ogame.Investment{ID: ogame.AstrophysicsID, Level: target}
+	for lvl := currentLevel + 1; lvl <= target; lvl++ {
+		inv.Cost = inv.Cost.Add(ogame.Astrophysics.GetPrice(lvl))
+	}
+	return inv
+}
+
 func (b *OGame) getPublicIP() (string, error) {
 	var res struct {
 		IP string `json:"ip"`
@@ -3974,11 +5553,11 @@ func (b *OGame) botLock(lockedBy string) {
 }
 
 func (b *OGame) botUnlock(unlockedBy string) {
-	b.Unlock()
 	if atomic.CompareAndSwapInt32(&b.lockedAtom, 1, 0) {
 		b.state = unlockedBy
 		b.stateChanged(false, unlockedBy)
 	}
+	b.Unlock()
 }
 
 func (b *OGame) addAccount(number int, lang string) (*AddAccountRes, error) {
@@ -3986,6 +5565,89 @@ func (b *OGame) addAccount(number int, lang string) (*AddAccountRes, error) {
 	return AddAccount(b.client, b.ctx, b.lobby, accountGroup, b.bearerToken)
 }
 
+func (b *OGame) getPublicAPI() *publicapi.Client {
+	b.publicAPIMu.Lock()
+	defer b.publicAPIMu.Unlock()
+	if b.publicAPI == nil {
+		b.publicAPI = publicapi.New(b.client, b.server.Number, b.server.Language)
+	}
+	return b.publicAPI
+}
+
+func (b *OGame) getLobbyServers(filters ...ServerFilter) (servers []Server, err error) {
+	err = b.client.WithTransport(b.loginProxyTransport, func(client *httpclient.Client) (err error) {
+		servers, err = GetServers(b.lobby, client, b.ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FilterServers(servers, filters...), nil
+}
+
+func (b *OGame) getLobbyAccounts() (accounts []Account, err error) {
+	if b.bearerToken == "" || b.isBearerTokenExpired() {
+		if _, err := postSessions(b, b.lobby, b.Username, b.password, b.otpSecret); err != nil {
+			return nil, err
+		}
+	}
+	err = b.client.WithTransport(b.loginProxyTransport, func(client *httpclient.Client) (err error) {
+		accounts, err = GetUserAccounts(client, b.ctx, b.lobby, b.bearerToken)
+		return err
+	})
+	return
+}
+
+// register creates a new gameforge lobby account, and use it as the bot credentials
+func (b *OGame) register(email, password, lang string) error {
+	if err := b.withCaptcha(func(client *httpclient.Client, challengeID string) error {
+		return Register(client, b.ctx, b.lobby, email, password, challengeID, lang)
+	}); err != nil {
+		return err
+	}
+	b.Username = email
+	b.password = password
+	b.bearerToken = ""
+	b.bearerTokenExpiry = time.Time{}
+	return nil
+}
+
+// joinServer creates a game account on the server for the lobby account of the bot
+func (b *OGame) joinServer(number int, lang string) (*AddAccountRes, error) {
+	if b.bearerToken == "" || b.isBearerTokenExpired() {
+		if _, err := postSessions(b, b.lobby, b.Username, b.password, b.otpSecret); err != nil {
+			return nil, err
+		}
+	}
+	var res *AddAccountRes
+	var server *Server
+	err := b.client.WithTransport(b.loginProxyTransport, func(client *httpclient.Client) error {
+		servers, err := GetServers(b.lobby, client, b.ctx)
+		if err != nil {
+			return err
+		}
+		for i := range servers {
+			if servers[i].Number == int64(number) && servers[i].Language == lang {
+				server = &servers[i]
+				break
+			}
+		}
+		if server == nil {
+			return errors.New("server not found")
+		}
+		res, err = AddAccount(client, b.ctx, b.lobby, server.AccountGroup, b.bearerToken)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Following logins will use the newly created account
+	b.Universe = server.Name
+	b.language = server.Language
+	b.playerID = int64(res.ID)
+	return res, nil
+}
+
 func (b *OGame) getCachedCelestial(v any) Celestial {
 	switch vv := v.(type) {
 	case Celestial:
@@ -4075,6 +5737,42 @@ func (b *OGame) getTasks() (out taskRunner.TasksOverview) {
 	return b.taskRunnerInst.GetTasks()
 }
 
+type taskCtxHolder struct{ ctx context.Context }
+
+// setTaskCtx sets the context of the running task, nil once the task is done
+func (b *OGame) setTaskCtx(ctx context.Context) {
+	b.taskCtx.Store(taskCtxHolder{ctx: ctx})
+}
+
+// requestCtx returns the context of a request, cancelled when the bot stops or when the running task is cancelled
+func (b *OGame) requestCtx() (context.Context, context.CancelFunc) {
+	parent := b.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	holder, ok := b.taskCtx.Load().(taskCtxHolder)
+	if !ok || holder.ctx == nil {
+		return parent, func() {}
+	}
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-holder.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// taskCtxErr returns context.Canceled if the running task was cancelled
+func (b *OGame) taskCtxErr() error {
+	if holder, ok := b.taskCtx.Load().(taskCtxHolder); ok && holder.ctx != nil {
+		return holder.ctx.Err()
+	}
+	return nil
+}
+
 // Public interface -----------------------------------------------------------
 
 // Enable enables communications with OGame Server
@@ -4109,6 +5807,7 @@ func (b *OGame) GetClient() *httpclient.Client {
 
 // SetClient set the http client used by the bot
 func (b *OGame) SetClient(client *httpclient.Client) {
+	setRedirectPolicy(client)
 	b.client = client
 }
 
@@ -4127,11 +5826,48 @@ func (b *OGame) ValidateAccount(code string) error {
 	return b.validateAccount(code)
 }
 
+// RefreshToken posts a new session to gameforge to get a fresh bearer token.
+// Callbacks registered with OnTokenRefreshed are notified of the new token.
+func (b *OGame) RefreshToken() (string, error) {
+	return b.WithPriority(taskRunner.Normal).RefreshToken()
+}
+
+// GetBearerToken returns the gameforge bearer token currently used by the bot
+func (b *OGame) GetBearerToken() string {
+	return b.bearerToken
+}
+
+// GetBearerTokenExpiry returns the time at which the bearer token is expected to expire.
+// Zero time is returned if the token was not obtained by the bot and does not carry its expiry.
+func (b *OGame) GetBearerTokenExpiry() time.Time {
+	return b.bearerTokenExpiry
+}
+
+// OnTokenRefreshed register a callback that is notified when the bot gets a new bearer token
+func (b *OGame) OnTokenRefreshed(clb func(token string)) {
+	b.tokenCallbacks.add(clb)
+}
+
 // OnStateChange register a callback that is notified when the bot state changes
 func (b *OGame) OnStateChange(clb func(locked bool, actor string)) {
 	b.stateChangeCallbacks = append(b.stateChangeCallbacks, clb)
 }
 
+// OnTxExpired register a callback that is notified when a transaction started with BeginWithTimeout or TxCtx
+// expires before "Done" is called
+func (b *OGame) OnTxExpired(clb func(name string)) {
+	b.txExpiredCallbacks = append(b.txExpiredCallbacks, clb)
+}
+
+func (b *OGame) txExpired(name string) {
+	callbacks := b.txExpiredCallbacks
+	go func() {
+		for _, clb := range callbacks {
+			clb(name)
+		}
+	}()
+}
+
 // GetState returns the current bot state
 func (b *OGame) GetState() (bool, string) {
 	return atomic.LoadInt32(&b.lockedAtom) == 1, b.state
@@ -4152,6 +5888,36 @@ func (b *OGame) AddAccount(number int, lang string) (*AddAccountRes, error) {
 	return b.addAccount(number, lang)
 }
 
+// GetPublicAPI returns a client for the public xml api of the bot's universe.
+// The bot must have logged in at least once so that the universe is known.
+func (b *OGame) GetPublicAPI() *publicapi.Client {
+	return b.getPublicAPI()
+}
+
+// GetLobbyServers returns the servers available in the lobby, that match all the filters.
+// Does not require the bot to be logged in.
+func (b *OGame) GetLobbyServers(filters ...ServerFilter) ([]Server, error) {
+	return b.getLobbyServers(filters...)
+}
+
+// GetLobbyAccounts returns the game accounts of the lobby account.
+// Does not require the bot to be logged in to a universe.
+func (b *OGame) GetLobbyAccounts() ([]Account, error) {
+	return b.getLobbyAccounts()
+}
+
+// Register creates a new gameforge lobby account. The bot will use the new credentials for following logins.
+// The account must then be validated using the code received by email (see ValidateAccount).
+func (b *OGame) Register(email, password, lang string) error {
+	return b.register(email, password, lang)
+}
+
+// JoinServer creates a game account on a server for the bot's lobby account.
+// The bot will use the new game account for following logins.
+func (b *OGame) JoinServer(number int, lang string) (*AddAccountRes, error) {
+	return b.joinServer(number, lang)
+}
+
 // WithPriority ...
 func (b *OGame) WithPriority(priority taskRunner.Priority) Prioritizable {
 	return b.taskRunnerInst.WithPriority(priority)
@@ -4167,6 +5933,16 @@ func (b *OGame) BeginNamed(name string) Prioritizable {
 	return b.WithPriority(taskRunner.Normal).BeginNamed(name)
 }
 
+// BeginWithTimeout begins a new transaction that releases the lock by itself after d if "Done" was not called
+func (b *OGame) BeginWithTimeout(d time.Duration) Prioritizable {
+	return b.WithPriority(taskRunner.Normal).BeginWithTimeout(d)
+}
+
+// TxCtx locks the bot during the transaction, the lock is released when clb returns, or when ctx is done
+func (b *OGame) TxCtx(ctx context.Context, clb func(tx Prioritizable) error) error {
+	return b.WithPriority(taskRunner.Normal).TxCtx(ctx, clb)
+}
+
 // SetInitiator ...
 func (b *OGame) SetInitiator(initiator string) Prioritizable {
 	return nil
@@ -4249,6 +6025,11 @@ func (b *OGame) GetResearchSpeed() int64 {
 	return b.serverData.ResearchDurationDivisor
 }
 
+// ResearchDuration gets the duration of a research level given an effective research lab level
+func (b *OGame) ResearchDuration(id ogame.ID, level, researchLab int64) time.Duration {
+	return b.researchDuration(id, level, researchLab)
+}
+
 // GetNbSystems gets the number of systems
 func (b *OGame) GetNbSystems() int64 {
 	return b.serverData.Systems
@@ -4269,6 +6050,36 @@ func (b *OGame) IsPioneers() bool {
 	return b.lobby == LobbyPioneers
 }
 
+// ProbeRaids either or not espionage probes can carry resources, from the probe cargo of the server data
+// or the lobby settings of the server
+func (b *OGame) ProbeRaids() bool {
+	return b.serverData.ProbeCargo > 0 || b.server.Settings.EspionageProbeRaids == 1
+}
+
+// MaxSlots returns the fleet and expedition slots computed from the cached researches, the character class,
+// the Admiral and the given items bonus. Compare it with GetSlots using Slots.Discrepancy.
+func (b *OGame) MaxSlots(items ogame.SlotsBonus) ogame.Slots {
+	return ogame.MaxSlots(b.getCachedResearch(), b.characterClass, b.hasAdmiral, items)
+}
+
+// ColonyScorer returns a scorer of the free positions for a new colony, from the cached planets and rank of the player.
+// Threats are looked for 5 systems around each slot.
+func (b *OGame) ColonyScorer(weights ogame.ColonyScoreWeights) ogame.ColonyScorer {
+	planets := make([]ogame.Coordinate, 0)
+	for _, planet := range b.GetCachedPlanets() {
+		planets = append(planets, planet.GetCoordinate())
+	}
+	return ogame.ColonyScorer{
+		Weights:     weights,
+		Planets:     planets,
+		Rank:        b.Player.Rank,
+		NbSystems:   b.serverData.Systems,
+		DonutSystem: b.serverData.DonutSystem,
+		ThreatRange: 5,
+		BonusFields: b.serverData.BonusFields,
+	}
+}
+
 // IsDonutGalaxy shortcut to get ogame galaxy donut config
 func (b *OGame) IsDonutGalaxy() bool {
 	return b.isDonutGalaxy()
@@ -4333,8 +6144,21 @@ func (b *OGame) GetPlanets() []Planet {
 // GetCachedPlanets return planets from cached value
 func (b *OGame) GetCachedPlanets() []Planet {
 	b.planetsMu.RLock()
-	defer b.planetsMu.RUnlock()
-	return b.planets
+	planets := b.planets
+	b.planetsMu.RUnlock()
+	if len(planets) == 0 {
+		if cached, err := cacheGet[[]ogame.Planet](b, PlanetsCache, ""); err == nil {
+			b.planetsMu.Lock()
+			b.planets = convertPlanets(b, cached)
+			planets = b.planets
+			b.planetsMu.Unlock()
+			b.touchCache(PlanetsCache)
+		}
+	} else if b.isCacheExpired(PlanetsCache) {
+		// Do not block, GetCachedPlanets is also used while holding the tasks runner
+		b.refreshCachesInBackground()
+	}
+	return planets
 }
 
 // GetCachedMoons return moons from cached value
@@ -4380,9 +6204,12 @@ func (b *OGame) RecruitOfficer(typ, days int64) error {
 	return b.WithPriority(taskRunner.Normal).RecruitOfficer(typ, days)
 }
 
-// Abandon a planet
-func (b *OGame) Abandon(v any) error {
-	return b.WithPriority(taskRunner.Normal).Abandon(v)
+// Abandon a planet. Warning: this is irreversible.
+// The planet is not abandoned, and a *ogame.AbandonNotSafeError is returned, if it is the last planet, if ships or
+// more than DefaultAbandonMaxResources resources are on the planet or its moon, or if a fleet is coming to it.
+// Use the Force option to skip these checks.
+func (b *OGame) Abandon(v any, opts ...Option) error {
+	return b.WithPriority(taskRunner.Normal).Abandon(v, opts...)
 }
 
 // GetCelestial get the player's planet/moon using the coordinate
@@ -4498,8 +6325,8 @@ func (b *OGame) GetSlots() ogame.Slots {
 	return b.WithPriority(taskRunner.Normal).GetSlots()
 }
 
-// Build builds any ogame objects (building, technology, ship, defence)
-func (b *OGame) Build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) error {
+// Build builds any ogame objects (building, technology, ship, defence), and returns when the construction will be done
+func (b *OGame) Build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) (ogame.ConstructionETA, error) {
 	return b.WithPriority(taskRunner.Normal).Build(celestialID, id, nbr)
 }
 
@@ -4508,7 +6335,7 @@ func (b *OGame) TechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (o
 	return b.WithPriority(taskRunner.Normal).TechnologyDetails(celestialID, id)
 }
 
-// TearDown tears down any ogame building
+// TearDown tears down any ogame building, ships, defenses and researches return a *ogame.TearDownNotAllowedError
 func (b *OGame) TearDown(celestialID ogame.CelestialID, id ogame.ID) error {
 	return b.WithPriority(taskRunner.Normal).TearDown(celestialID, id)
 }
@@ -4524,7 +6351,7 @@ func (b *OGame) BuildProduction(celestialID ogame.CelestialID, id ogame.ID, nbr
 }
 
 // BuildBuilding ensure what is being built is a building
-func (b *OGame) BuildBuilding(celestialID ogame.CelestialID, buildingID ogame.ID) error {
+func (b *OGame) BuildBuilding(celestialID ogame.CelestialID, buildingID ogame.ID) (ogame.ConstructionETA, error) {
 	return b.WithPriority(taskRunner.Normal).BuildBuilding(celestialID, buildingID)
 }
 
@@ -4559,7 +6386,7 @@ func (b *OGame) CancelResearch(celestialID ogame.CelestialID) error {
 }
 
 // BuildTechnology ensure that we're trying to build a technology
-func (b *OGame) BuildTechnology(celestialID ogame.CelestialID, technologyID ogame.ID) error {
+func (b *OGame) BuildTechnology(celestialID ogame.CelestialID, technologyID ogame.ID) (ogame.ConstructionETA, error) {
 	return b.WithPriority(taskRunner.Normal).BuildTechnology(celestialID, technologyID)
 }
 
@@ -4605,14 +6432,21 @@ func (b *OGame) GetCombatReportSummaryFor(coord ogame.Coordinate) (ogame.CombatR
 	return b.WithPriority(taskRunner.Normal).GetCombatReportSummaryFor(coord)
 }
 
-// GetEspionageReportFor gets the latest espionage report for a given coordinate
-func (b *OGame) GetEspionageReportFor(coord ogame.Coordinate) (ogame.EspionageReport, error) {
-	return b.WithPriority(taskRunner.Normal).GetEspionageReportFor(coord)
+// GetEspionageReportFor gets the latest espionage report for a given coordinate.
+// With MaxAge, an older report is returned along with ogame.ErrReportTooOld.
+// With AutoSpy, probes are sent when the report is missing or too old, the bot is not locked while they fly.
+func (b *OGame) GetEspionageReportFor(coord ogame.Coordinate, opts ...Option) (ogame.EspionageReport, error) {
+	report, err := b.WithPriority(taskRunner.Normal).GetEspionageReportFor(coord, opts...)
+	cfg := getOptions(opts...)
+	if cfg.AutoSpyFrom != 0 && (errors.Is(err, ogame.ErrReportTooOld) || errors.Is(err, ogame.ErrReportNotFound)) {
+		return b.spyAndWaitReport(coord, cfg)
+	}
+	return report, err
 }
 
-// GetExpeditionMessages gets the expedition messages
-func (b *OGame) GetExpeditionMessages() ([]ogame.ExpeditionMessage, error) {
-	return b.WithPriority(taskRunner.Normal).GetExpeditionMessages()
+// GetExpeditionMessages gets the expedition messages, use Concurrency to fetch the pages in parallel
+func (b *OGame) GetExpeditionMessages(opts ...Option) ([]ogame.ExpeditionMessage, error) {
+	return b.WithPriority(taskRunner.Normal).GetExpeditionMessages(opts...)
 }
 
 // GetExpeditionMessageAt gets the expedition message for time t
@@ -4630,9 +6464,9 @@ func (b *OGame) CollectMarketplaceMessage(msg ogame.MarketplaceMessage) error {
 	return b.WithPriority(taskRunner.Normal).CollectMarketplaceMessage(msg)
 }
 
-// GetEspionageReportMessages gets the summary of each espionage reports
-func (b *OGame) GetEspionageReportMessages() ([]ogame.EspionageReportSummary, error) {
-	return b.WithPriority(taskRunner.Normal).GetEspionageReportMessages()
+// GetEspionageReportMessages gets the summary of each espionage reports, use Concurrency to fetch the pages in parallel
+func (b *OGame) GetEspionageReportMessages(opts ...Option) ([]ogame.EspionageReportSummary, error) {
+	return b.WithPriority(taskRunner.Normal).GetEspionageReportMessages(opts...)
 }
 
 // GetEspionageReport gets a detailed espionage report
@@ -4655,10 +6489,10 @@ func (b *OGame) GetResourcesProductions(planetID ogame.PlanetID) (ogame.Resource
 	return b.WithPriority(taskRunner.Normal).GetResourcesProductions(planetID)
 }
 
-// GetResourcesProductionsLight gets the planet resources production
+// GetResourcesProductionsLight gets the planet resources production, including the crawlers, officers and class bonuses
 func (b *OGame) GetResourcesProductionsLight(resBuildings ogame.ResourcesBuildings, researches ogame.Researches,
-	resSettings ogame.ResourceSettings, temp ogame.Temperature) ogame.Resources {
-	return b.WithPriority(taskRunner.Normal).GetResourcesProductionsLight(resBuildings, researches, resSettings, temp)
+	resSettings ogame.ResourceSettings, temp ogame.Temperature, bonuses ogame.ProductionBonuses) ogame.Resources {
+	return b.WithPriority(taskRunner.Normal).GetResourcesProductionsLight(resBuildings, researches, resSettings, temp, bonuses)
 }
 
 // FlightTime calculate flight time and fuel needed
@@ -4668,7 +6502,7 @@ func (b *OGame) FlightTime(origin, destination ogame.Coordinate, speed ogame.Spe
 
 // Distance return distance between two coordinates
 func (b *OGame) Distance(origin, destination ogame.Coordinate) int64 {
-	return Distance(origin, destination, b.serverData.Galaxies, b.serverData.Systems, b.serverData.DonutGalaxy, b.serverData.DonutSystem)
+	return b.distance(origin, destination)
 }
 
 // RegisterWSCallback ...
@@ -4685,19 +6519,20 @@ func (b *OGame) RemoveWSCallback(id string) {
 	delete(b.wsCallbacks, id)
 }
 
-// RegisterChatCallback register a callback that is called when chat messages are received
-func (b *OGame) RegisterChatCallback(fn func(msg ogame.ChatMsg)) {
-	b.chatCallbacks = append(b.chatCallbacks, fn)
+// RegisterChatCallback register a callback that is called when chat messages are received, close the subscription to remove it
+func (b *OGame) RegisterChatCallback(fn func(msg ogame.ChatMsg)) *Subscription {
+	return b.chatCallbacks.add(fn)
 }
 
-// RegisterAuctioneerCallback register a callback that is called when auctioneer packets are received
-func (b *OGame) RegisterAuctioneerCallback(fn func(packet any)) {
-	b.auctioneerCallbacks = append(b.auctioneerCallbacks, fn)
+// RegisterAuctioneerCallback register a callback that is called when auctioneer packets are received, close the subscription to remove it
+func (b *OGame) RegisterAuctioneerCallback(fn func(packet any)) *Subscription {
+	return b.auctioneerCallbacks.add(fn)
 }
 
-// RegisterHTMLInterceptor ...
-func (b *OGame) RegisterHTMLInterceptor(fn func(method, url string, params, payload url.Values, pageHTML []byte)) {
-	b.interceptorCallbacks = append(b.interceptorCallbacks, fn)
+// RegisterHTMLInterceptor registers a read-only callback called with every processed page (see Use to alter requests),
+// close the subscription to remove it
+func (b *OGame) RegisterHTMLInterceptor(fn func(method, url string, params, payload url.Values, pageHTML []byte)) *Subscription {
+	return b.interceptorCallbacks.add(fn)
 }
 
 // Phalanx scan a coordinate from a moon to get fleets information
@@ -4769,6 +6604,16 @@ func (b *OGame) Highscore(category, typ, page int64) (ogame.Highscore, error) {
 	return b.WithPriority(taskRunner.Normal).Highscore(category, typ, page)
 }
 
+// GetFullHighscore gets all the pages of a highscore
+func (b *OGame) GetFullHighscore(category, typ int64) (ogame.FullHighscore, error) {
+	return b.WithPriority(taskRunner.Normal).GetFullHighscore(category, typ)
+}
+
+// GetHighscorePosition gets the highscore position of a player
+func (b *OGame) GetHighscorePosition(category, typ, playerID int64) (ogame.HighscorePlayer, error) {
+	return b.WithPriority(taskRunner.Normal).GetHighscorePosition(category, typ, playerID)
+}
+
 // GetAllResources gets the resources of all planets and moons
 func (b *OGame) GetAllResources() (map[ogame.CelestialID]ogame.Resources, error) {
 	return b.WithPriority(taskRunner.Normal).GetAllResources()
@@ -4779,6 +6624,27 @@ func (b *OGame) GetTasks() taskRunner.TasksOverview {
 	return b.getTasks()
 }
 
+// GetTasksDetails returns the running task followed by the queued ones, in processing order
+func (b *OGame) GetTasksDetails() []taskRunner.TaskInfo {
+	return b.taskRunnerInst.Tasks()
+}
+
+// SetTaskAging makes the queued tasks gain one priority level for every d they wait, so low priority tasks
+// are never blocked forever by a flow of higher priority ones. 0 (default) disables it.
+func (b *OGame) SetTaskAging(d time.Duration) {
+	b.taskRunnerInst.SetAging(d)
+}
+
+// CancelTask cancels a queued or running task, the requests of a cancelled task fail with context.Canceled
+func (b *OGame) CancelTask(id int64) error {
+	return b.taskRunnerInst.CancelTask(id)
+}
+
+// Drain blocks until there is no more queued or running task
+func (b *OGame) Drain() {
+	b.taskRunnerInst.Drain()
+}
+
 // GetDMCosts returns fast build with DM information
 func (b *OGame) GetDMCosts(celestialID ogame.CelestialID) (ogame.DMCosts, error) {
 	return b.WithPriority(taskRunner.Normal).GetDMCosts(celestialID)
@@ -4828,3 +6694,294 @@ func (b *OGame) GetLfBuildings(celestialID ogame.CelestialID, opts ...Option) (o
 func (b *OGame) GetLfResearch(celestialID ogame.CelestialID, opts ...Option) (ogame.LfResearches, error) {
 	return b.WithPriority(taskRunner.Normal).GetLfResearch(celestialID, opts...)
 }
+
+// RenamePlanet renames a planet or a moon
+func (b *OGame) RenamePlanet(celestialID ogame.CelestialID, newName string) error {
+	return b.WithPriority(taskRunner.Normal).RenamePlanet(celestialID, newName)
+}
+
+// RelocatePlanet moves a planet to an empty destination, the move happens after a 24h countdown
+func (b *OGame) RelocatePlanet(planetID ogame.PlanetID, dest ogame.Coordinate) (ogame.PlanetRelocation, error) {
+	return b.WithPriority(taskRunner.Normal).RelocatePlanet(planetID, dest)
+}
+
+// CancelPlanetRelocation cancels an in-progress planet relocation
+func (b *OGame) CancelPlanetRelocation(planetID ogame.PlanetID) error {
+	return b.WithPriority(taskRunner.Normal).CancelPlanetRelocation(planetID)
+}
+
+// GetPlanetRelocation gets the in-progress relocation of a planet, if any
+func (b *OGame) GetPlanetRelocation(planetID ogame.PlanetID) (ogame.PlanetRelocation, error) {
+	return b.WithPriority(taskRunner.Normal).GetPlanetRelocation(planetID)
+}
+
+// GetOfficers gets the hiring state and expiry date of all officers
+func (b *OGame) GetOfficers() ([]ogame.Officer, error) {
+	return b.WithPriority(taskRunner.Normal).GetOfficers()
+}
+
+// AmortizationPlan ranks the next mines, plasma technology and astrophysics investments by payback time
+func (b *OGame) AmortizationPlan(celestialIDs []ogame.CelestialID, rates ogame.Ratio) ([]ogame.Investment, error) {
+	return b.WithPriority(taskRunner.Normal).AmortizationPlan(celestialIDs, rates)
+}
+
+// OnFleetArrival registers a callback executed when the fleet lands at its destination, and when it is back home.
+// The fleet is fetched again at its arrival time to make sure it did land before calling clb.
+func (b *OGame) OnFleetArrival(fleetID ogame.FleetID, clb func(ogame.Fleet)) {
+	b.onFleetArrival(fleetID, clb)
+}
+
+// OnConstructionFinished registers a callback executed when a building, research or shipyard item completes on the celestial.
+// Closing the returned subscription unregisters the callback, the celestial stops being watched once it has no callbacks left.
+func (b *OGame) OnConstructionFinished(celestialID ogame.CelestialID, clb func(id ogame.ID)) *Subscription {
+	return b.onConstructionFinished(celestialID, clb)
+}
+
+// GetTechnologyDetails gets cost, duration, energy needs, requirements state and tear down cost of the next level of a technology
+func (b *OGame) GetTechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechnologyDetails, error) {
+	return b.WithPriority(taskRunner.Normal).GetTechnologyDetails(celestialID, id)
+}
+
+// GetTechTree gets the requirements tree of a technology with the current levels on the celestial
+func (b *OGame) GetTechTree(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechTreeNode, error) {
+	return b.WithPriority(taskRunner.Normal).GetTechTree(celestialID, id)
+}
+
+// GetShopItems gets all the items of the shop, with their categories, cost, cooldown and amount in inventory
+func (b *OGame) GetShopItems(celestialID ogame.CelestialID) ([]ogame.ShopItem, error) {
+	return b.WithPriority(taskRunner.Normal).GetShopItems(celestialID)
+}
+
+// GetInventory gets the items owned by the player
+func (b *OGame) GetInventory(celestialID ogame.CelestialID) ([]ogame.ShopItem, error) {
+	return b.WithPriority(taskRunner.Normal).GetInventory(celestialID)
+}
+
+// BuyItem buys an item with dark matter and activates it, like the "Buy & Activate" button
+func (b *OGame) BuyItem(ref string, celestialID ogame.CelestialID) error {
+	return b.WithPriority(taskRunner.Normal).BuyItem(ref, celestialID)
+}
+
+// DoAuctionWS bids on the auctioneer websocket for lower latency, using the token of the given auction.
+// Falls back to DoAuction if the websocket is not connected.
+func (b *OGame) DoAuctionWS(auction ogame.Auction, bid map[ogame.CelestialID]ogame.Resources) error {
+	return b.WithPriority(taskRunner.Normal).DoAuctionWS(auction, bid)
+}
+
+// GetMarketplaceOffers gets all the listings of a marketplace tab
+func (b *OGame) GetMarketplaceOffers(tab ogame.MarketplaceTab, celestialID ogame.CelestialID) ([]ogame.MarketplaceOffer, error) {
+	return b.WithPriority(taskRunner.Normal).GetMarketplaceOffers(tab, celestialID)
+}
+
+// TradeResources exchanges resources with the merchant (Merchant -> Resources).
+// give must contain a single resource type, want is what is received at the merchant rates.
+func (b *OGame) TradeResources(celestialID ogame.CelestialID, give, want ogame.Resources) error {
+	return b.WithPriority(taskRunner.Normal).TradeResources(celestialID, give, want)
+}
+
+// GetOfferOfTheDay gets the offer of the day price, rarity and exchange cost without buying it
+func (b *OGame) GetOfferOfTheDay() (ogame.OfferOfTheDay, error) {
+	return b.WithPriority(taskRunner.Normal).GetOfferOfTheDay()
+}
+
+// RerollOfferOfTheDay buys the offer of the day and exchanges the received item for dark matter
+// until keep returns true or no exchange is left, then takes the last item
+func (b *OGame) RerollOfferOfTheDay(keep func(ogame.OfferOfTheDayItem) bool) (ogame.OfferOfTheDayItem, error) {
+	return b.WithPriority(taskRunner.Normal).RerollOfferOfTheDay(keep)
+}
+
+// GetPhalanxCoverage gets the systems covered by the sensor phalanx of all the moons
+func (b *OGame) GetPhalanxCoverage() (ogame.PhalanxCoverage, error) {
+	return b.WithPriority(taskRunner.Normal).GetPhalanxCoverage()
+}
+
+// DestroyMoon sends deathstars on a destroy mission to the moon at target.
+// Returns the chances to destroy the moon and to lose the deathstars, computed from the moon diameter.
+func (b *OGame) DestroyMoon(celestialID ogame.CelestialID, target ogame.Coordinate, deathstars int64) (ogame.Fleet, ogame.MoonDestructionChance, error) {
+	return b.WithPriority(taskRunner.Normal).DestroyMoon(celestialID, target, deathstars)
+}
+
+// MoonshotShips returns how many ships of the given type must be destroyed to reach the moon chance (in percent), using the server debris factor
+func (b *OGame) MoonshotShips(id ogame.ID, chance float64) int64 {
+	return b.moonshotShips(id, chance)
+}
+
+// CargoCapacity returns the cargo capacity of ships, taking hyperspace technology, the player class and the probe raids setting into account
+func (b *OGame) CargoCapacity(ships ogame.ShipsInfos) int64 {
+	return b.cargoCapacity(ships)
+}
+
+// CargoShipsNeeded returns how many ships of the given type are needed to carry amount resources
+func (b *OGame) CargoShipsNeeded(id ogame.ID, amount int64) int64 {
+	return b.cargoShipsNeeded(id, amount)
+}
+
+// BestSpeedFor returns the slowest speed, and so the cheapest in fuel, for the fleet to arrive before arriveBy.
+// Returns ogame.ErrDeadlineUnreachable with the full speed flight time if the deadline cannot be met.
+func (b *OGame) BestSpeedFor(origin, destination ogame.Coordinate, ships ogame.ShipsInfos, missionID ogame.MissionID, arriveBy time.Time) (speed ogame.Speed, secs, fuel int64, err error) {
+	return b.bestSpeedFor(origin, destination, ships, missionID, arriveBy)
+}
+
+// GetEventList get all the fleet movements of the event list (own, friendly and hostile)
+func (b *OGame) GetEventList(opts ...Option) ([]ogame.FleetEvent, error) {
+	return b.WithPriority(taskRunner.Normal).GetEventList(opts...)
+}
+
+// GetMessagesPage gets the parsed messages of a page of a messages tab, and the number of pages of the tab
+func (b *OGame) GetMessagesPage(tabID ogame.MessagesTabID, page int64) ([]ogame.Message, int64, error) {
+	return b.WithPriority(taskRunner.Normal).GetMessagesPage(tabID, page)
+}
+
+// SubscribeMessages creates a subscription pushing the new messages of the given tabs, call Start on it to start polling
+func (b *OGame) SubscribeMessages(tabs ...ogame.MessagesTabID) *MessageSubscription {
+	sub := NewMessageSubscription(b, tabs...)
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+	sub.SetCursors(b.importedState.MessageCursors)
+	b.messageSubscriptions = append(b.messageSubscriptions, sub)
+	return sub
+}
+
+// DeleteMessages deletes the messages of a tab for which filter returns true, returns the number of deleted messages.
+// eg: delete espionage reports older than 2 days
+//
+//	bot.DeleteMessages(wrapper.EspionageMessagesTabID, func(msg ogame.Message) bool { return time.Since(msg.CreatedAt) > 48*time.Hour })
+func (b *OGame) DeleteMessages(tabID ogame.MessagesTabID, filter func(ogame.Message) bool) (int64, error) {
+	return b.WithPriority(taskRunner.Normal).DeleteMessages(tabID, filter)
+}
+
+// GetExpeditionStats gets the totals per outcome of the expedition messages received since the given time
+func (b *OGame) GetExpeditionStats(since time.Time) (ogame.ExpeditionStats, error) {
+	return b.WithPriority(taskRunner.Normal).GetExpeditionStats(since)
+}
+
+// RunStrategies starts driving the strategies every minute, call Stop on the returned runner to stop them
+func (b *OGame) RunStrategies(strategies ...Strategy) *StrategyRunner {
+	runner := NewStrategyRunner(b, strategies...)
+	b.stateMu.Lock()
+	runner.SetSeenAttacks(b.importedState.SeenAttacks)
+	b.strategyRunners = append(b.strategyRunners, runner)
+	b.stateMu.Unlock()
+	runner.Start()
+	return runner
+}
+
+// RefreshCaches fetches the planets and researches again, regardless of their TTL
+func (b *OGame) RefreshCaches() error {
+	return b.WithPriority(taskRunner.Normal).RefreshCaches()
+}
+
+// RefreshServerData fetches the server data again, and switches the extractor if the server was upgraded
+func (b *OGame) RefreshServerData() (ServerData, error) {
+	return b.WithPriority(taskRunner.Normal).RefreshServerData()
+}
+
+// UnsetVacationMode disables vacation mode, returns a *ogame.VacationModeLockedError if its minimum duration is not over
+func (b *OGame) UnsetVacationMode() error {
+	return b.WithPriority(taskRunner.Normal).UnsetVacationMode()
+}
+
+// ChangeNickname changes the player name, it can only be changed once per week
+func (b *OGame) ChangeNickname(newNick string) error {
+	return b.WithPriority(taskRunner.Normal).ChangeNickname(newNick)
+}
+
+// GetDarkMatter returns the dark matter available on the account
+func (b *OGame) GetDarkMatter() (int64, error) {
+	return b.WithPriority(taskRunner.Normal).GetDarkMatter()
+}
+
+// ForecastResources returns the resources the celestial will have at the given time,
+// extrapolated from its last fetched resources, production and storage capacity.
+// The resources are fetched again after an action of the bot (build, send fleet, trade...)
+func (b *OGame) ForecastResources(celestialID ogame.CelestialID, at time.Time) (ogame.Resources, error) {
+	return b.WithPriority(taskRunner.Normal).ForecastResources(celestialID, at)
+}
+
+// GetStorageCapacity returns the metal, crystal and deuterium storage capacity of a celestial, computed from its storage buildings
+func (b *OGame) GetStorageCapacity(celestialID ogame.CelestialID, options ...Option) (ogame.Resources, error) {
+	return b.WithPriority(taskRunner.Normal).GetStorageCapacity(celestialID, options...)
+}
+
+// EmpireSnapshot gets the empire page of the planets and moons at once, to be compared with ogame.DiffEmpire
+func (b *OGame) EmpireSnapshot() (ogame.EmpireSnapshot, error) {
+	return b.WithPriority(taskRunner.Normal).EmpireSnapshot()
+}
+
+// GalaxyRange fetches the systems [fromSystem, toSystem] of a galaxy, with at most Concurrency (default 3) parallel requests
+// spaced by Pacing (default 300ms). When some systems fail, the others are returned along with a *GalaxyRangeError
+func (b *OGame) GalaxyRange(galaxy, fromSystem, toSystem int64, opts ...Option) ([]ogame.SystemInfos, error) {
+	return b.WithPriority(taskRunner.Normal).GalaxyRange(galaxy, fromSystem, toSystem, opts...)
+}
+
+// HarvestExpeditionDebris sends pathfinders from celestialID to recycle the expedition debris field (position 16) of the system
+func (b *OGame) HarvestExpeditionDebris(celestialID ogame.CelestialID, galaxy, system int64, speed ogame.Speed) (ogame.Fleet, error) {
+	return b.WithPriority(taskRunner.Normal).HarvestExpeditionDebris(celestialID, galaxy, system, speed)
+}
+
+// GetMissiles returns the anti-ballistic and interplanetary missiles stored in the missile silo, and the silo capacity
+func (b *OGame) GetMissiles(celestialID ogame.CelestialID) (ogame.Missiles, error) {
+	return b.WithPriority(taskRunner.Normal).GetMissiles(celestialID)
+}
+
+// GetChatContacts gets the conversations of the chat page
+func (b *OGame) GetChatContacts() ([]ogame.ChatContact, error) {
+	return b.WithPriority(taskRunner.Normal).GetChatContacts()
+}
+
+// GetChatHistory gets the messages of the conversation with playerID, older than the message beforeID (0 for the latest ones)
+func (b *OGame) GetChatHistory(playerID, beforeID int64) ([]ogame.ChatMsg, error) {
+	return b.WithPriority(taskRunner.Normal).GetChatHistory(playerID, beforeID)
+}
+
+// GetOtherMessages gets the messages of the "Other" tab (moon destructions, fleet returns, officers, tutorial)
+func (b *OGame) GetOtherMessages(opts ...Option) ([]ogame.OtherMessage, error) {
+	return b.WithPriority(taskRunner.Normal).GetOtherMessages(opts...)
+}
+
+// GetTransportMessages gets the messages of the "Unions/Transport" tab (transport arrivals, ACS invitations)
+func (b *OGame) GetTransportMessages(opts ...Option) ([]ogame.UnionsTransportMessage, error) {
+	return b.WithPriority(taskRunner.Normal).GetTransportMessages(opts...)
+}
+
+// JoinACS accepts an ACS invitation by sending ships from celestialID to join the union
+func (b *OGame) JoinACS(celestialID ogame.CelestialID, invitation ogame.ACSInvitation, ships []ogame.Quantifiable, speed ogame.Speed) (ogame.Fleet, error) {
+	return b.WithPriority(taskRunner.Normal).JoinACS(celestialID, invitation, ships, speed)
+}
+
+// GetExpeditionFleet returns the fleet able to carry back the biggest expedition find, sized from the points of the top 1 player.
+// withPathfinder adds a Pathfinder, which doubles the find cap.
+func (b *OGame) GetExpeditionFleet(cargoID ogame.ID, withPathfinder bool) (ogame.ShipsInfos, error) {
+	return b.WithPriority(taskRunner.Normal).GetExpeditionFleet(cargoID, withPathfinder)
+}
+
+// PanicSave sends every ship of the celestial, loaded with its resources, on the cheapest mission allowed by the policy.
+// Own celestials targeted by an attack are not used as destination.
+// Meant to be called when an attack is about to hit (eg: from a HostileTracker), it runs with the Critical priority.
+func (b *OGame) PanicSave(celestialID ogame.CelestialID, policy PanicSavePolicy) (ogame.Fleet, error) {
+	return b.WithPriority(taskRunner.Critical).PanicSave(celestialID, policy)
+}
+
+// BuildProductionWithProgress orders ships or defenses in chunks the shipyard accepts, calls clb after each chunk
+// and stops when ctx is done. Each chunk is verified against the production queue.
+func (b *OGame) BuildProductionWithProgress(ctx context.Context, celestialID ogame.CelestialID, id ogame.ID, nbr int64, clb func(ogame.BuildProgress)) error {
+	return b.WithPriority(taskRunner.Normal).BuildProductionWithProgress(ctx, celestialID, id, nbr, clb)
+}
+
+// TearDownPlan returns the building levels of a celestial worth tearing down, the ones refunding more than they cost,
+// most profitable first. The RefundRatio option is required, ErrRefundRatioRequired is returned without it.
+func (b *OGame) TearDownPlan(celestialID ogame.CelestialID, opts ...Option) ([]ogame.TearDownStep, error) {
+	return b.WithPriority(taskRunner.Normal).TearDownPlan(celestialID, opts...)
+}
+
+// GetCachedTechs same as GetTechs, but returns the cached techs of the celestial when they are fresh.
+// They are fetched again after InvalidateTechs, a build or a tear down on the celestial, or once older than the TechsCache TTL (DefaultTechsCacheTTL unless set).
+func (b *OGame) GetCachedTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
+	return b.WithPriority(taskRunner.Normal).GetCachedTechs(celestialID)
+}
+
+// GetPlanetInfos refreshes a single celestial (name, diameter, fields, temperature, image) with one page load,
+// the cached planets list is updated along the way
+func (b *OGame) GetPlanetInfos(celestialID ogame.CelestialID) (Celestial, error) {
+	return b.WithPriority(taskRunner.Normal).GetPlanetInfos(celestialID)
+}
@@ -2,14 +2,38 @@ package wrapper
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/alaingilbert/ogame/pkg/extractor/v6"
+	"github.com/alaingilbert/ogame/pkg/extractor/v7"
+	"github.com/alaingilbert/ogame/pkg/extractor/v874"
+	"github.com/alaingilbert/ogame/pkg/extractor/v9"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
 	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/publicapi"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
 	"github.com/alaingilbert/ogame/pkg/utils"
 	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"gopkg.in/yaml.v3"
 	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func BenchmarkUserInfoRegex(b *testing.B) {
@@ -243,3 +267,2235 @@ func TestVersion(t *testing.T) {
 func TestFindSlowestSpeed(t *testing.T) {
 	assert.Equal(t, int64(8000), findSlowestSpeed(ogame.ShipsInfos{SmallCargo: 1, LargeCargo: 1}, ogame.Researches{CombustionDrive: 6}, false, false))
 }
+
+func TestFilterServers(t *testing.T) {
+	servers := []Server{
+		{Language: "en", Number: 1, Name: "Andromeda"},
+		{Language: "en", Number: 2, Name: "Barym", SignupClosed: 1},
+		{Language: "fr", Number: 3, Name: "Capella"},
+	}
+	servers[0].Settings.EconomySpeed = float64(8)
+	servers[1].Settings.EconomySpeed = "x8"
+	servers[2].Settings.EconomySpeed = float64(1)
+	assert.Equal(t, int64(8), servers[1].GetEconomySpeed())
+	assert.Equal(t, 3, len(FilterServers(servers)))
+	assert.Equal(t, 2, len(FilterServers(servers, ServerLanguage("en"))))
+	assert.Equal(t, 2, len(FilterServers(servers, ServerSpeed(8))))
+	res := FilterServers(servers, ServerLanguage("en"), ServerOpenForRegistration())
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, "Andromeda", res[0].Name)
+}
+
+func TestRenamePlanetInvalidName(t *testing.T) {
+	bot, _ := NewNoLogin("", "", "", "", "", "", "", 0, nil)
+	assert.Equal(t, ogame.ErrInvalidPlanetName, bot.renamePlanet(1, "a"))
+	assert.Equal(t, ogame.ErrInvalidPlanetName, bot.renamePlanet(1, "Too long planet name for ogame"))
+	assert.Equal(t, ogame.ErrInvalidPlanetName, bot.renamePlanet(1, "Bad<name>"))
+	assert.Equal(t, ogame.ErrInvalidPlanetID, bot.renamePlanet(1, "Good name"))
+}
+
+func TestOfficerRenewerNeedsRenewal(t *testing.T) {
+	r := NewOfficerRenewer(nil, ogame.OfficerCommander).SetRenewBefore(6 * time.Hour)
+	assert.True(t, r.isSelected(ogame.OfficerCommander))
+	assert.False(t, r.isSelected(ogame.OfficerAdmiral))
+	needsRenewal, err := r.needsRenewal(ogame.Officer{Type: ogame.OfficerCommander})
+	assert.NoError(t, err)
+	assert.True(t, needsRenewal)
+	needsRenewal, err = r.needsRenewal(ogame.Officer{Type: ogame.OfficerCommander, Active: true})
+	assert.Equal(t, ErrOfficerExpiryUnknown, err)
+	assert.False(t, needsRenewal)
+	needsRenewal, err = r.needsRenewal(ogame.Officer{Type: ogame.OfficerCommander, Active: true, ExpireAt: time.Now().Add(24 * time.Hour)})
+	assert.NoError(t, err)
+	assert.False(t, needsRenewal)
+	needsRenewal, err = r.needsRenewal(ogame.Officer{Type: ogame.OfficerCommander, Active: true, ExpireAt: time.Now().Add(2 * time.Hour)})
+	assert.NoError(t, err)
+	assert.True(t, needsRenewal)
+}
+
+func TestProductionWait(t *testing.T) {
+	assert.Equal(t, 2*time.Hour, productionWait(ogame.Resources{Metal: 2000, Crystal: 500}, ogame.Resources{Metal: 1000, Crystal: 1000}))
+	assert.Equal(t, time.Duration(0), productionWait(ogame.Resources{Deuterium: 10}, ogame.Resources{Metal: 1000}))
+	assert.Equal(t, time.Duration(0), productionWait(ogame.Resources{}, ogame.Resources{}))
+}
+
+func TestInvestmentAstrophysics(t *testing.T) {
+	inv := investmentAstrophysics(2)
+	assert.Equal(t, int64(3), inv.Level)
+	assert.Equal(t, ogame.Astrophysics.GetPrice(3), inv.Cost)
+	inv = investmentAstrophysics(3)
+	assert.Equal(t, int64(5), inv.Level)
+	assert.Equal(t, ogame.Astrophysics.GetPrice(4).Add(ogame.Astrophysics.GetPrice(5)), inv.Cost)
+}
+
+func TestConstructionWatcherUpdate(t *testing.T) {
+	var finished []ogame.ID
+	now := time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)
+	w := &constructionWatcher{}
+	w.callbacks.add(func(id ogame.ID) { finished = append(finished, id) })
+	w.update(constructionsSnapshot{queues: map[int]construction{queueKindBuilding: {ogame.MetalMineID, now.Add(time.Hour)}}, shipyardID: ogame.LightFighterID, shipyardLen: 2})
+	assert.Empty(t, finished)
+	w.update(constructionsSnapshot{queues: map[int]construction{queueKindBuilding: {ogame.MetalMineID, now.Add(time.Hour + time.Second)}}, shipyardID: ogame.LightFighterID, shipyardLen: 2})
+	assert.Empty(t, finished) // same construction, countdown rounding
+	w.update(constructionsSnapshot{queues: map[int]construction{queueKindBuilding: {ogame.CrystalMineID, now.Add(time.Hour)}, queueKindResearch: {ogame.EnergyTechnologyID, now.Add(time.Hour)}}, shipyardID: ogame.LightFighterID, shipyardLen: 2})
+	assert.Equal(t, []ogame.ID{ogame.MetalMineID}, finished)
+	w.update(constructionsSnapshot{queues: map[int]construction{queueKindBuilding: {ogame.CrystalMineID, now.Add(3 * time.Hour)}, queueKindResearch: {ogame.EnergyTechnologyID, now.Add(time.Hour)}}, shipyardID: ogame.LightFighterID, shipyardLen: 1})
+	assert.Equal(t, []ogame.ID{ogame.MetalMineID, ogame.CrystalMineID, ogame.LightFighterID}, finished) // next level, next batch of the same ship
+	w.update(constructionsSnapshot{queues: map[int]construction{}})
+	assert.ElementsMatch(t, []ogame.ID{ogame.MetalMineID, ogame.CrystalMineID, ogame.LightFighterID, ogame.CrystalMineID, ogame.EnergyTechnologyID, ogame.LightFighterID}, finished)
+}
+
+func TestConstructionWatcherStop(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	watcher := func() *constructionWatcher {
+		b.constructionWatchMu.Lock()
+		defer b.constructionWatchMu.Unlock()
+		return b.constructionWatchers[1]
+	}
+	waitStopped := func(w *constructionWatcher) {
+		select {
+		case <-w.stopCh:
+		case <-time.After(time.Second):
+			t.Fatal("watcher was not stopped")
+		}
+	}
+
+	sub1 := b.OnConstructionFinished(1, func(ogame.ID) {})
+	sub2 := b.OnConstructionFinished(1, func(ogame.ID) {})
+	w := watcher()
+	assert.NotNil(t, w)
+	sub1.Close()
+	assert.Equal(t, w, watcher()) // still has a callback
+	sub2.Close()
+	assert.Nil(t, watcher())
+	waitStopped(w)
+	sub2.Close()
+
+	// Disabling the bot pauses the watcher, it is started again with its callbacks on enable
+	sub := b.OnConstructionFinished(1, func(ogame.ID) {})
+	w = watcher()
+	b.constructionWatchMu.Lock()
+	runDoneCh := w.runDoneCh
+	b.constructionWatchMu.Unlock()
+	b.disable()
+	select {
+	case <-runDoneCh:
+	case <-time.After(time.Second):
+		t.Fatal("watcher is still running")
+	}
+	assert.Equal(t, w, watcher())
+	b.enable()
+	b.constructionWatchMu.Lock()
+	runDoneCh = w.runDoneCh
+	b.constructionWatchMu.Unlock()
+	select {
+	case <-runDoneCh:
+		t.Fatal("watcher was not restarted")
+	default:
+	}
+	assert.Equal(t, 1, w.callbacks.len())
+	sub.Close()
+	waitStopped(w)
+	<-runDoneCh
+	assert.Nil(t, watcher())
+}
+
+func TestItemNeedsActivation(t *testing.T) {
+	active := []ogame.ActiveItem{{Ref: "abc", TimeRemaining: 3600}}
+	assert.False(t, itemNeedsActivation(active, "abc", 10*time.Minute))
+	assert.True(t, itemNeedsActivation(active, "abc", 2*time.Hour))
+	assert.True(t, itemNeedsActivation(active, "def", 10*time.Minute))
+	assert.True(t, inventoryHas([]ogame.ShopItem{{Ref: "abc", Amount: 1}}, "abc"))
+	assert.False(t, inventoryHas([]ogame.ShopItem{{Ref: "abc", Amount: 0}}, "abc"))
+}
+
+func TestMessageSuccessToItemActivationResult(t *testing.T) {
+	var msg MessageSuccess
+	err := json.Unmarshal([]byte(`{"buff":"b1","status":"active","duration":3600,"totalDuration":7200,"buffId":"42","item":{"ref":"abc","name":"Gold Metal Booster","amount":2,"costs":"12.500","duration":3600},"message":"ok"}`), &msg)
+	assert.NoError(t, err)
+	assert.Equal(t, 3600, msg.Duration)
+	res, err := msg.ToItemActivationResult()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3600), res.Duration)
+	assert.Equal(t, int64(7200), res.TotalDuration)
+	assert.Equal(t, "42", res.BuffID)
+	assert.Equal(t, "abc", res.Item.Ref)
+	assert.Equal(t, int64(2), res.Item.Amount)
+	assert.Equal(t, int64(12500), res.Item.Costs)
+	assert.Equal(t, "ok", res.Message)
+}
+
+func TestTraderSellResources(t *testing.T) {
+	rates := ogame.Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+	give := ogame.Resources{Metal: 100000}
+	needed, err := ogame.TraderResourcesCost(give, ogame.Resources{Crystal: 1000}, rates)
+	assert.NoError(t, err)
+	assert.Equal(t, ogame.Resources{Metal: 1500}, traderSellResources(give, needed))
+	assert.Equal(t, ogame.Resources{Deuterium: 300}, traderSellResources(ogame.Resources{Deuterium: 300}, 500))
+}
+
+func TestAuctionBidAmount(t *testing.T) {
+	assert.Equal(t, int64(4000), auctionBidAmount(ogame.Auction{MinimumBid: 5000, AlreadyBid: 1000, DeficitBid: 1000}))
+	assert.Equal(t, int64(2000), auctionBidAmount(ogame.Auction{MinimumBid: 5000, AlreadyBid: 4000, DeficitBid: 2000}))
+	assert.True(t, auctionItemWhitelisted(ogame.Auction{CurrentItem: "gold metal booster"}, nil))
+	assert.True(t, auctionItemWhitelisted(ogame.Auction{CurrentItem: "gold metal booster"}, []string{"gold"}))
+	assert.False(t, auctionItemWhitelisted(ogame.Auction{CurrentItem: "bronze metal booster"}, []string{"gold"}))
+}
+
+func TestAuctionBidWSPayload(t *testing.T) {
+	payload, err := auctionBidWSPayload("abc", map[ogame.CelestialID]ogame.Resources{123: {Metal: 1000, Deuterium: 5}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"planets":{"123":{"metal":1000,"crystal":0,"deuterium":5}},"honor":0,"token":"abc"}`, string(payload))
+}
+
+func TestSendAuctionBidWS(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.Player.PlayerID = 123
+	received := make(chan string, 1)
+	ackPlayerID := int64(123) // atomic
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var msg string
+		for websocket.Message.Receive(ws, &msg) == nil {
+			received <- msg
+			newBid := ogame.AuctioneerNewBid{Sum: 1000}
+			newBid.Player.ID = atomic.LoadInt64(&ackPlayerID)
+			b.deliverAuctioneerPacket(newBid)
+		}
+	}))
+	defer srv.Close()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	assert.NoError(t, err)
+	defer ws.Close()
+	b.ws = ws
+	atomic.StoreInt32(&b.chatConnectedAtom, 1)
+
+	sent, err := b.sendAuctionBidWS("abc", map[ogame.CelestialID]ogame.Resources{123: {Metal: 1000}})
+	assert.True(t, sent)
+	assert.NoError(t, err)
+	assert.Contains(t, <-received, `5::/auctioneer:{"name":"bid"`)
+
+	// Another player's bid does not confirm ours
+	atomic.StoreInt64(&ackPlayerID, 456)
+	defer func(timeout time.Duration) { auctionBidWSTimeout = timeout }(auctionBidWSTimeout)
+	auctionBidWSTimeout = 50 * time.Millisecond
+	sent, err = b.sendAuctionBidWS("abc", map[ogame.CelestialID]ogame.Resources{123: {Metal: 1000}})
+	assert.True(t, sent)
+	assert.ErrorIs(t, err, ErrAuctionBidNotConfirmed)
+	<-received
+
+	atomic.StoreInt32(&b.chatConnectedAtom, 0)
+	sent, err = b.sendAuctionBidWS("abc", map[ogame.CelestialID]ogame.Resources{123: {Metal: 1000}})
+	assert.False(t, sent)
+	assert.Error(t, err)
+}
+
+func TestMarketplaceOfferAcceptable(t *testing.T) {
+	rates := ogame.Ratio{Metal: 3, Crystal: 2, Deuterium: 1}
+	offer := ogame.MarketplaceOffer{ItemType: ogame.MarketplaceResourcesItemType, ItemID: 3, Quantity: 1000, PriceType: 1, Price: 2000}
+	assert.True(t, marketplaceOfferAcceptable(offer, rates, 1.2, 0, 0))
+	assert.False(t, marketplaceOfferAcceptable(offer, rates, 1.6, 0, 0))
+	assert.True(t, marketplaceOfferAcceptable(offer, rates, 1.2, 5000, 3000))
+	assert.False(t, marketplaceOfferAcceptable(offer, rates, 1.2, 5000, 3001))
+}
+
+func TestJumpGateManagerNextAvailableAt(t *testing.T) {
+	m := NewJumpGateManager(nil)
+	assert.True(t, m.NextAvailableAt(123).IsZero())
+	m.setWait(123, 60)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), m.NextAvailableAt(123), time.Second)
+	m.setWait(123, 0)
+	assert.True(t, m.NextAvailableAt(123).IsZero())
+}
+
+func TestDiffPhalanxScans(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	target := ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}
+	other := ogame.Coordinate{Galaxy: 1, System: 5, Position: 8, Type: ogame.PlanetType}
+	staying := ogame.Fleet{Mission: ogame.Transport, Origin: target, Destination: other, ArrivalTime: now.Add(time.Hour)}
+	recalled := ogame.Fleet{Mission: ogame.Attack, Origin: target, Destination: other, ArrivalTime: now.Add(time.Minute)}
+	arrived := ogame.Fleet{Mission: ogame.Transport, Origin: target, Destination: other, ArrivalTime: now.Add(-time.Minute)}
+	launched := ogame.Fleet{Mission: ogame.Spy, Origin: target, Destination: other, ArrivalTime: now.Add(2 * time.Hour)}
+	incoming := ogame.Fleet{Mission: ogame.Attack, Origin: other, Destination: target, ArrivalTime: now.Add(time.Hour)}
+	l, r := diffPhalanxScans(target, []ogame.Fleet{staying, recalled, arrived}, []ogame.Fleet{staying, launched, incoming}, now)
+	assert.Equal(t, []ogame.Fleet{launched}, l)
+	assert.Equal(t, []ogame.Fleet{recalled}, r)
+}
+
+func TestCalcFlightTimeFractionalSpeed(t *testing.T) {
+	origin := ogame.Coordinate{Galaxy: 1, System: 1, Position: 1, Type: ogame.PlanetType}
+	dest := ogame.Coordinate{Galaxy: 1, System: 5, Position: 3, Type: ogame.PlanetType}
+	ships := ogame.ShipsInfos{LargeCargo: 10}
+	techs := ogame.Researches{CombustionDrive: 10}
+	secs50, fuel50 := CalcFlightTime(origin, dest, 1, 499, false, false, 1, 0.5, 1, ships, techs, ogame.General)
+	secs55, fuel55 := CalcFlightTime(origin, dest, 1, 499, false, false, 1, 0.55, 1, ships, techs, ogame.General)
+	secs60, fuel60 := CalcFlightTime(origin, dest, 1, 499, false, false, 1, 0.6, 1, ships, techs, ogame.General)
+	assert.True(t, secs50 > secs55 && secs55 > secs60)
+	assert.True(t, fuel50 < fuel55 && fuel55 < fuel60)
+	secs, fuel := CalcFlightTime(origin, dest, 1, 499, false, false, 1, 0, 1, ships, techs, ogame.General)
+	assert.Equal(t, int64(0), secs)
+	assert.Equal(t, int64(0), fuel)
+}
+
+func TestAppendNewMessages(t *testing.T) {
+	msgs := []ogame.Message{{ID: 12}, {ID: 11}, {ID: 10}, {ID: 9}}
+	out, done := appendNewMessages(nil, msgs, 10)
+	assert.True(t, done)
+	assert.Equal(t, []ogame.Message{{ID: 12}, {ID: 11}}, out)
+	out, done = appendNewMessages(nil, msgs, 0)
+	assert.False(t, done)
+	assert.Equal(t, 4, len(out))
+}
+
+func TestReportStoreDiffReports(t *testing.T) {
+	coord := ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}
+	lf1, lf2 := int64(10), int64(4)
+	store := NewReportStore()
+	store.Add(ogame.EspionageReport{ID: 2, Coordinate: coord, Date: time.Unix(200, 0), Resources: ogame.Resources{Metal: 500}, HasFleetInformation: true, LightFighter: &lf2})
+	store.Add(ogame.EspionageReport{ID: 1, Coordinate: coord, Date: time.Unix(100, 0), Resources: ogame.Resources{Metal: 1000}, HasFleetInformation: true, LightFighter: &lf1})
+	store.Add(ogame.EspionageReport{ID: 1, Coordinate: coord, Date: time.Unix(100, 0)})
+	reports := store.Reports(coord)
+	assert.Equal(t, 2, len(reports))
+	assert.Equal(t, int64(1), reports[0].ID)
+	latest, ok := store.Latest(coord)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), latest.ID)
+	diff, err := store.DiffReports(coord, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-500), diff.Resources.Metal)
+	assert.Equal(t, int64(-6), diff.Ships.LightFighter)
+	assert.Nil(t, diff.Defenses)
+	_, err = store.DiffReports(coord, 1, 3)
+	assert.Error(t, err)
+}
+
+type strategyRecorder struct {
+	BaseStrategy
+	attacks  []ogame.AttackEvent
+	returned []ogame.Fleet
+}
+
+func (s *strategyRecorder) OnAttackDetected(_ Wrapper, attack ogame.AttackEvent) {
+	s.attacks = append(s.attacks, attack)
+}
+
+func (s *strategyRecorder) OnFleetReturned(_ Wrapper, fleet ogame.Fleet) {
+	s.returned = append(s.returned, fleet)
+}
+
+func TestStrategyRunnerTickPollError(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	b.SetLoginPolicy(LoginPolicy{MaxAttempts: 1})
+	eventList, _ := ioutil.ReadFile("../../samples/unversioned/eventlist_attack.html")
+	failMovement, movementRequested := false, false
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			movementRequested = movementRequested || strings.Contains(req.URL, "movement")
+			if strings.Contains(req.URL, "eventList") && failMovement {
+				return &Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: eventList}, nil
+			}
+			return nil, errors.New("network error")
+		}
+	})
+	strategy := &strategyRecorder{}
+	var errs []error
+	r := NewStrategyRunner(b, strategy).OnError(func(_ Strategy, err error) { errs = append(errs, err) })
+	r.SetSeenAttacks([]int64{5})
+	r.fleets = []ogame.Fleet{{ID: 1, BackTime: time.Now().Add(-time.Minute)}}
+
+	r.Tick() // event list fails
+	assert.False(t, movementRequested)
+	failMovement = true
+	r.Tick() // movement page fails
+	assert.True(t, movementRequested)
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, []int64{5}, r.SeenAttacks())
+	assert.Equal(t, 1, len(r.fleets))
+	assert.Empty(t, strategy.attacks)
+	assert.Empty(t, strategy.returned)
+}
+
+func TestNewStrategyAttacks(t *testing.T) {
+	seen := make(map[int64]struct{})
+	out := newStrategyAttacks(seen, []ogame.AttackEvent{{ID: 1}, {ID: 2}})
+	assert.Equal(t, 2, len(out))
+	out = newStrategyAttacks(seen, []ogame.AttackEvent{{ID: 2}, {ID: 3}})
+	assert.Equal(t, []ogame.AttackEvent{{ID: 3}}, out)
+	_, ok := seen[1]
+	assert.False(t, ok)
+}
+
+func TestReturnedFleets(t *testing.T) {
+	now := time.Unix(1000, 0)
+	prev := []ogame.Fleet{
+		{ID: 1, BackTime: time.Unix(900, 0)},
+		{ID: 2, BackTime: time.Unix(1100, 0)},
+		{ID: 3},
+		{ID: 4, BackTime: time.Unix(900, 0)},
+	}
+	curr := []ogame.Fleet{{ID: 4}}
+	assert.Equal(t, []ogame.Fleet{prev[0]}, returnedFleets(prev, curr, now))
+}
+
+func TestExportImportState(t *testing.T) {
+	b := &OGame{}
+	b.planets = convertPlanets(b, []ogame.Planet{{ID: 1, Name: "Home", Moon: &ogame.Moon{ID: 2, Name: "Moon"}}})
+	b.researches = &ogame.Researches{EnergyTechnology: 5}
+	b.characterClass = ogame.Collector
+	b.SubscribeMessages(EspionageMessagesTabID).SetCursors(map[ogame.MessagesTabID]int64{EspionageMessagesTabID: 123})
+	data, err := b.ExportState()
+	assert.NoError(t, err)
+
+	b2 := &OGame{}
+	assert.NoError(t, b2.ImportState(data))
+	assert.Equal(t, 1, len(b2.planets))
+	assert.Equal(t, "Home", b2.planets[0].Name)
+	assert.Equal(t, ogame.MoonID(2), b2.planets[0].Moon.ID)
+	assert.Equal(t, int64(5), b2.researches.EnergyTechnology)
+	assert.Equal(t, ogame.Collector, b2.characterClass)
+	sub := b2.SubscribeMessages(EspionageMessagesTabID)
+	assert.Equal(t, map[ogame.MessagesTabID]int64{EspionageMessagesTabID: 123}, sub.Cursors())
+}
+
+func TestCacheReadThroughPlanets(t *testing.T) {
+	cache := NewMemoryCache()
+	b := &OGame{Universe: "Bellatrix", Username: "user"}
+	b.SetCache(cache)
+	cacheSet(b, PlanetsCache, "", []ogame.Planet{{ID: 1, Name: "Home"}})
+
+	b2 := &OGame{Universe: "Bellatrix", Username: "user"}
+	b2.SetCache(cache)
+	planets := b2.GetCachedPlanets()
+	assert.Equal(t, 1, len(planets))
+	assert.Equal(t, "Home", planets[0].Name)
+
+	b3 := &OGame{Universe: "Other", Username: "user"}
+	b3.SetCache(cache)
+	assert.Equal(t, 0, len(b3.GetCachedPlanets()))
+
+	assert.NoError(t, cache.Delete(PlanetsCache, b.cacheKey("")))
+	_, err := cacheGet[[]ogame.Planet](b, PlanetsCache, "")
+	assert.Equal(t, errCacheMiss, err)
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Now()
+	assert.False(t, cacheExpired(0, now.Add(-24*time.Hour), now))
+	assert.False(t, cacheExpired(time.Hour, now.Add(-30*time.Minute), now))
+	assert.True(t, cacheExpired(time.Hour, now.Add(-2*time.Hour), now))
+
+	b := &OGame{}
+	b.researches = &ogame.Researches{EnergyTechnology: 5}
+	b.touchCache(ResearchesCache)
+	assert.False(t, b.isCacheExpired(ResearchesCache))
+	b.SetCacheTTL(ResearchesCache, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	assert.True(t, b.isCacheExpired(ResearchesCache))
+	b.SetCacheTTL(ResearchesCache, 0)
+	assert.False(t, b.isCacheExpired(ResearchesCache))
+	b.InvalidateCache(ResearchesCache)
+	assert.True(t, b.isCacheExpired(ResearchesCache))
+	assert.Nil(t, b.researches)
+
+	cache := NewMemoryCache()
+	b.SetCache(cache)
+	cacheSet(b, PlanetsCache, "", []ogame.Planet{{ID: 1}})
+	_, err := cacheGet[[]ogame.Planet](b, PlanetsCache, "")
+	assert.NoError(t, err)
+	b.SetCacheTTL(PlanetsCache, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, err = cacheGet[[]ogame.Planet](b, PlanetsCache, "")
+	assert.Equal(t, errCacheMiss, err)
+}
+
+func TestDiffPlanets(t *testing.T) {
+	moon := &ogame.Moon{ID: 11}
+	prev := []ogame.Planet{{ID: 1}, {ID: 2}, {ID: 3, Moon: &ogame.Moon{ID: 13}}}
+	curr := []ogame.Planet{{ID: 1, Moon: moon}, {ID: 3}, {ID: 4}}
+	events := diffPlanets(prev, curr)
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, MoonAddedEvent, events[0].Type)
+	assert.Equal(t, moon, events[0].Moon)
+	assert.Equal(t, MoonLostEvent, events[1].Type)
+	assert.Equal(t, ogame.MoonID(13), events[1].Moon.ID)
+	assert.Equal(t, PlanetAddedEvent, events[2].Type)
+	assert.Equal(t, ogame.PlanetID(4), events[2].Planet.ID)
+	assert.Equal(t, PlanetLostEvent, events[3].Type)
+	assert.Equal(t, ogame.PlanetID(2), events[3].Planet.ID)
+	assert.Equal(t, 0, len(diffPlanets(curr, curr)))
+}
+
+func TestSetResearchesEvent(t *testing.T) {
+	b := &OGame{}
+	ch := make(chan CacheEvent, 1)
+	b.OnCacheChange(func(e CacheEvent) { ch <- e })
+	b.setResearches(ogame.Researches{EnergyTechnology: 1})
+	b.setResearches(ogame.Researches{EnergyTechnology: 1})
+	b.setResearches(ogame.Researches{EnergyTechnology: 2})
+	select {
+	case e := <-ch:
+		assert.Equal(t, ResearchesChangedEvent, e.Type)
+		assert.Equal(t, int64(1), e.PrevResearches.EnergyTechnology)
+		assert.Equal(t, int64(2), e.Researches.EnergyTechnology)
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+	}
+	assert.Equal(t, 0, len(ch))
+}
+
+func TestRefreshServerDataVersionChanged(t *testing.T) {
+	b := &OGame{extractor: v874.NewExtractor(), logger: log.New(ioutil.Discard, "", 0)}
+	b.extractor.SetLanguage("fr")
+	b.serverData.Version = "8.7.4"
+	b.getServerDataWrapper = func(func() (ServerData, error)) (ServerData, error) {
+		return ServerData{Version: "9.0.1"}, nil
+	}
+	ch := make(chan [2]string, 1)
+	b.OnVersionChanged(func(oldVersion, newVersion string) { ch <- [2]string{oldVersion, newVersion} })
+	serverData, err := b.refreshServerData()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), serverData.SpeedFleetWar)
+	assert.IsType(t, &v9.Extractor{}, b.extractor)
+	assert.Equal(t, "fr", b.extractor.GetLanguage())
+	select {
+	case versions := <-ch:
+		assert.Equal(t, [2]string{"8.7.4", "9.0.1"}, versions)
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+	}
+}
+
+func TestExtractVacationModeLock(t *testing.T) {
+	pageHTML, _ := ioutil.ReadFile("../../samples/v6/es/preferences_vacation.html")
+	until, locked := extractVacationModeLock(pageHTML, time.UTC)
+	assert.True(t, locked)
+	assert.Equal(t, time.Date(2019, 9, 4, 7, 54, 28, 0, time.UTC), until)
+
+	pageHTML, _ = ioutil.ReadFile("../../samples/unversioned/preferences.html")
+	_, locked = extractVacationModeLock(pageHTML, time.UTC)
+	assert.False(t, locked)
+}
+
+func TestIsValidNickname(t *testing.T) {
+	assert.True(t, isValidNickname("Constable Telesto"))
+	assert.True(t, isValidNickname("a_b-c d"))
+	assert.False(t, isValidNickname("ab"))
+	assert.False(t, isValidNickname("_abc"))
+	assert.False(t, isValidNickname("abc "))
+	assert.False(t, isValidNickname("ab__cd"))
+	assert.False(t, isValidNickname("a_b_c_d_e"))
+	assert.False(t, isValidNickname("abc$"))
+	assert.False(t, isValidNickname("abcdefghijklmnopqrstu"))
+}
+
+func TestChangeNickname(t *testing.T) {
+	// The preferences page embeds the same name change form as the changenick overlay
+	formHTML, _ := ioutil.ReadFile("../../samples/unversioned/preferences_reverse.html")
+	b, _ := NewNoLogin("user", "secret", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	pageHTML := formHTML
+	var payload url.Values
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			if req.Method == http.MethodPost {
+				payload = req.Payload
+				return &Response{StatusCode: http.StatusOK, Body: []byte(`{"status":"success"}`)}, nil
+			}
+			return &Response{StatusCode: http.StatusOK, Body: pageHTML}, nil
+		}
+	})
+	assert.NoError(t, b.changeNickname("New Name"))
+	assert.Equal(t, "New Name", payload.Get("db_character"))
+	assert.Equal(t, "secret", payload.Get("db_character_password"))
+	assert.Equal(t, "7f5a17edc1ff1e6b5b88376deae439fa", payload.Get("token"))
+
+	pageHTML = []byte(`<div class="notice">You can change your name again on 23.10.2026 12:00:00</div>`)
+	err := b.changeNickname("Other Name")
+	assert.ErrorIs(t, err, ogame.ErrNicknameChangeCooldown)
+	var cooldownErr *ogame.NicknameChangeCooldownError
+	assert.True(t, errors.As(err, &cooldownErr))
+	assert.Equal(t, time.Date(2026, 10, 23, 12, 0, 0, 0, time.UTC), cooldownErr.Until)
+}
+
+func TestDarkMatterLedger(t *testing.T) {
+	var ledger darkMatterLedger
+	ledger.record("UseDM", 1, "research", 1000)
+	before := time.Now()
+	time.Sleep(time.Millisecond)
+	ledger.record("BuyItem", 1, "abc", 2500)
+	assert.Equal(t, 2, len(ledger.since(time.Time{})))
+	entries := ledger.since(before)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "BuyItem", entries[0].Action)
+	assert.Equal(t, int64(2500), entries[0].Amount)
+}
+
+func TestTearDownPlanRequiresRefundRatio(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	_, err := b.tearDownPlan(1)
+	assert.Equal(t, ogame.ErrRefundRatioRequired, err)
+}
+
+func TestForecastResourcesFromCache(t *testing.T) {
+	b := &OGame{}
+	details := ogame.ResourcesDetails{}
+	details.Metal.Available, details.Metal.StorageCapacity, details.Metal.CurrentProduction = 1000, 100000, 1000
+	fetchedAt := time.Now()
+	b.resourcesDetails = map[ogame.CelestialID]cachedResourcesDetails{1: {details: details, fetchedAt: fetchedAt}}
+	res, err := b.forecastResources(1, fetchedAt.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3000), res.Metal)
+	_, err = b.forecastResources(2, fetchedAt)
+	assert.Equal(t, ogame.ErrInvalidPlanetID, err)
+}
+
+func TestForecastResourcesInvalidatedByActions(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	b.SetLoginPolicy(LoginPolicy{MaxAttempts: 1})
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: []byte(`{}`)}, nil
+		}
+	})
+	cached := func() bool {
+		b.resourcesDetailsMu.Lock()
+		defer b.resourcesDetailsMu.Unlock()
+		_, found := b.resourcesDetails[1]
+		return found
+	}
+	reset := func() {
+		b.resourcesDetailsMu.Lock()
+		b.resourcesDetails = map[ogame.CelestialID]cachedResourcesDetails{1: {fetchedAt: time.Now()}}
+		b.resourcesDetailsMu.Unlock()
+	}
+
+	reset()
+	_, err := b.getPageContent(url.Values{"page": {"ajax"}, "component": {"technologytree"}}, SkipHumanize)
+	assert.NoError(t, err)
+	assert.True(t, cached()) // reading a page keeps the resources
+
+	_, err = b.getPageContent(url.Values{"page": {"ajax"}, "component": {"buffActivation"}, "token": {"abc"}}, SkipHumanize)
+	assert.NoError(t, err)
+	assert.False(t, cached()) // action with a token
+
+	reset()
+	_, err = b.postPageContent(url.Values{"page": {"ajax"}, "component": {"traderresources"}, "ajax": {"1"}}, url.Values{"give": {"1"}}, SkipHumanize)
+	assert.NoError(t, err)
+	assert.False(t, cached()) // trade
+}
+
+func TestStorageOverflows(t *testing.T) {
+	details := ogame.ResourcesDetails{}
+	details.Metal.Available, details.Metal.StorageCapacity, details.Metal.CurrentProduction = 8000, 10000, 1000
+	details.Crystal.Available, details.Crystal.StorageCapacity, details.Crystal.CurrentProduction = 1000, 10000, 1000
+	details.Deuterium.Available, details.Deuterium.StorageCapacity = 10000, 10000
+	overflows := storageOverflows(1, details, 3*time.Hour)
+	assert.Equal(t, []StorageOverflow{
+		{CelestialID: 1, Resource: ogame.MetalStorageID, FullIn: 2 * time.Hour},
+		{CelestialID: 1, Resource: ogame.DeuteriumTankID, FullIn: 0},
+	}, overflows)
+}
+
+func TestGetResourcesProductionsLightBonuses(t *testing.T) {
+	resBuildings := ogame.ResourcesBuildings{MetalMine: 20, CrystalMine: 15, DeuteriumSynthesizer: 10, SolarPlant: 25}
+	resSettings := ogame.ResourceSettings{MetalMine: 100, CrystalMine: 100, DeuteriumSynthesizer: 100, SolarPlant: 100, Crawler: 100}
+	temp := ogame.Temperature{Min: -23, Max: 17}
+	base := getResourcesProductionsLight(resBuildings, ogame.Researches{}, resSettings, temp, ogame.ProductionBonuses{}, 1)
+	metalBase := ogame.MetalMine.Production(1, 1, 1, 0, 20)
+	assert.Equal(t, metalBase, base.Metal)
+	geologist := getResourcesProductionsLight(resBuildings, ogame.Researches{}, resSettings, temp, ogame.ProductionBonuses{Geologist: true}, 1)
+	assert.Equal(t, base.Metal+int64(float64(metalBase-30)*0.1), geologist.Metal)
+	withCrawlers := getResourcesProductionsLight(resBuildings, ogame.Researches{}, resSettings, temp, ogame.ProductionBonuses{Crawlers: 10}, 1)
+	assert.Greater(t, withCrawlers.Metal, base.Metal)
+	assert.Equal(t, base.Energy-500, withCrawlers.Energy)
+}
+
+func TestGalaxyRangeErrors(t *testing.T) {
+	b := &OGame{}
+	systems, err := b.galaxyRange(1, 3, 1, Concurrency(2), Pacing(time.Millisecond))
+	assert.Equal(t, 0, len(systems))
+	var rangeErr *GalaxyRangeError
+	assert.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, 3, len(rangeErr.Errors))
+	assert.Contains(t, rangeErr.Errors, int64(1))
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to fetch systems 1: "))
+}
+
+func TestGalaxyInfosReadThrough(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.server.Settings.UniverseSize = 5
+	b.serverData.Systems = 499
+	b.SetCache(NewMemoryCache())
+	var cached ogame.SystemInfos
+	cached.Tmpgalaxy, cached.Tmpsystem = 1, 2
+	cached.Tmpplanets[3] = &ogame.PlanetInfos{ID: 123}
+	cacheSet(b, GalaxyCache, galaxyCacheKey(1, 2), cached)
+
+	_, err := b.galaxyInfos(1, 2) // no TTL, the game is asked and the bot is not logged in
+	assert.Error(t, err)
+
+	b.SetCacheTTL(GalaxyCache, time.Hour)
+	res, err := b.galaxyInfos(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), res.Position(4).ID)
+	_, err = b.galaxyInfos(1, 2, SkipCache)
+	assert.Error(t, err)
+	_, err = b.galaxyInfos(1, 3)
+	assert.Error(t, err)
+}
+
+func TestPlanFarmRaids(t *testing.T) {
+	defenceless := func(coord ogame.Coordinate, metal int64) ogame.EspionageReport {
+		return ogame.EspionageReport{Coordinate: coord, Resources: ogame.Resources{Metal: metal}, IsInactive: true, HasFleetInformation: true, HasDefensesInformation: true}
+	}
+	c1 := ogame.Coordinate{Galaxy: 1, System: 1, Position: 1, Type: ogame.PlanetType}
+	c2 := ogame.Coordinate{Galaxy: 1, System: 1, Position: 2, Type: ogame.PlanetType}
+	c3 := ogame.Coordinate{Galaxy: 1, System: 1, Position: 3, Type: ogame.PlanetType}
+	defended := defenceless(c3, 1000000)
+	defended.HasDefensesInformation = false
+	reports := []ogame.EspionageReport{defenceless(c1, 20000), defenceless(c2, 100000), defended}
+	targetInfo := func(report ogame.EspionageReport) TargetInfo {
+		target := NewTargetInfo(report, ogame.Collector)
+		target.Cargos = ogame.CargoShipsNeeded(ogame.SmallCargoID, target.Loot.Total(), ogame.Researches{}, false, false, false)
+		return target
+	}
+	raids := planFarmRaids(reports, DefaultTargetScorer{}, 5000, targetInfo)
+	assert.Equal(t, 2, len(raids))
+	assert.Equal(t, c2, raids[0].Target)
+	assert.Equal(t, int64(50000), raids[0].Loot.Metal)
+	assert.Equal(t, int64(10), raids[0].Cargos)
+	assert.Equal(t, c1, raids[1].Target)
+	assert.Equal(t, int64(2), raids[1].Cargos)
+
+	assert.False(t, raids[0].Combat)
+
+	raids = planFarmRaids(reports, DefaultTargetScorer{}, 20000, targetInfo)
+	assert.Equal(t, 1, len(raids))
+
+	raids = planFarmRaids(reports, TargetScorerFunc(func(TargetInfo) float64 { return 1 }), 20000, targetInfo)
+	assert.Equal(t, 2, len(raids))
+	assert.False(t, raids[0].Combat)
+	assert.Equal(t, c3, raids[1].Target)
+	assert.True(t, raids[1].Combat) // no defenses information, a combat may happen
+}
+
+func TestTearDownPage(t *testing.T) {
+	page, err := tearDownPage(ogame.MetalMineID)
+	assert.NoError(t, err)
+	assert.Equal(t, SuppliesPageName, page)
+	page, err = tearDownPage(ogame.RoboticsFactoryID)
+	assert.NoError(t, err)
+	assert.Equal(t, FacilitiesPageName, page)
+	page, err = tearDownPage(ogame.ResidentialSectorID)
+	assert.NoError(t, err)
+	assert.Equal(t, LfBuildingsPageName, page)
+
+	for _, id := range []ogame.ID{ogame.RocketLauncherID, ogame.InterplanetaryMissilesID, ogame.SmallCargoID, ogame.EspionageTechnologyID} {
+		_, err = tearDownPage(id)
+		assert.ErrorIs(t, err, ogame.ErrTearDownNotAllowed)
+		var notAllowedErr *ogame.TearDownNotAllowedError
+		assert.ErrorAs(t, err, &notAllowedErr)
+		assert.Equal(t, id, notAllowedErr.ID)
+	}
+}
+
+func TestSlotManager(t *testing.T) {
+	m := NewSlotManager()
+	m.Reserve("fleetsave", 1, ogame.Park, ogame.Transport)
+	m.Reserve("expeditions", 2, ogame.Expedition)
+	slots := ogame.Slots{InUse: 10, Total: 14, ExpInUse: 1, ExpTotal: 3}
+	assert.NoError(t, m.Check(ogame.Attack, slots))     // 4 free, 3 reserved
+	assert.NoError(t, m.Check(ogame.Expedition, slots)) // 4 free, 1 reserved, 2 expedition slots
+	slots.InUse = 11
+	assert.ErrorIs(t, m.Check(ogame.Attack, slots), ogame.ErrSlotsReserved)
+	assert.NoError(t, m.Check(ogame.Park, slots))
+	slots.ExpInUse = 3
+	assert.ErrorIs(t, m.Check(ogame.Expedition, slots), ogame.ErrSlotsReserved)
+	assert.NoError(t, m.Check(ogame.Attack, slots)) // no expedition slot left to keep free
+	slots.InUse, slots.ExpInUse = 12, 2
+	assert.ErrorIs(t, m.Check(ogame.Attack, slots), ogame.ErrSlotsReserved) // 2 free, 1 reserved, 1 expedition slot kept free
+	m.Release("expeditions")
+	assert.NoError(t, m.Check(ogame.Attack, slots))
+	assert.Equal(t, []SlotReservation{{Name: "fleetsave", Slots: 1, Missions: []ogame.MissionID{ogame.Park, ogame.Transport}}}, m.Reservations())
+
+	m.track(ogame.Fleet{ID: 1, BackTime: time.Now().Add(time.Hour)})
+	m.track(ogame.Fleet{ID: 2, BackTime: time.Now().Add(-time.Minute)})
+	assert.Equal(t, int64(1), m.InUse())
+}
+
+func TestTxExpiration(t *testing.T) {
+	b := &OGame{logger: log.New(ioutil.Discard, "", 0)}
+	b.taskRunnerInst = taskRunner.NewTaskRunner(context.Background(), func() *Prioritize { return &Prioritize{bot: b} })
+	expiredCh := make(chan string, 1)
+	b.OnTxExpired(func(name string) { expiredCh <- name })
+
+	tx := b.BeginWithTimeout(20 * time.Millisecond)
+	select {
+	case name := <-expiredCh:
+		assert.Equal(t, "Tx", name)
+	case <-time.After(time.Second):
+		t.Fatal("transaction did not expire")
+	}
+	b.Begin().Done() // lock was released
+	tx.Done()        // late Done is a no-op
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := b.TxCtx(ctx, func(tx Prioritizable) error {
+		cancel()
+		select {
+		case <-expiredCh:
+		case <-time.After(time.Second):
+			t.Fatal("transaction did not expire")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	b.Begin().Done()
+
+	assert.NoError(t, b.TxCtx(context.Background(), func(tx Prioritizable) error { return nil }))
+	select {
+	case <-expiredCh:
+		t.Fatal("transaction should not expire")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTxExpirationDuringCall(t *testing.T) {
+	b := &OGame{logger: log.New(ioutil.Discard, "", 0)}
+	b.taskRunnerInst = taskRunner.NewTaskRunner(context.Background(), func() *Prioritize { return &Prioritize{bot: b} })
+	expiredCh := make(chan string, 1)
+	b.OnTxExpired(func(name string) { expiredCh <- name })
+	waitFor := func(ch <-chan struct{}, msg string) {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal(msg)
+		}
+	}
+
+	tx := b.BeginWithTimeout(20 * time.Millisecond).(*Prioritize)
+	started, cancelled, release, callDone := make(chan struct{}), make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go func() {
+		tx.begin("Blocking")
+		close(started)
+		ctx, cancel := b.requestCtx()
+		defer cancel()
+		<-ctx.Done() // requests of the running call are cancelled
+		close(cancelled)
+		<-release
+		tx.done()
+		close(callDone)
+	}()
+	waitFor(started, "call did not start")
+	waitFor(cancelled, "running call was not cancelled")
+	otherDone := make(chan struct{})
+	go func() {
+		b.Begin().Done()
+		close(otherDone)
+	}()
+	select {
+	case <-otherDone:
+		t.Fatal("lock was released under the running call")
+	case <-expiredCh:
+		t.Fatal("transaction expired before the running call returned")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	waitFor(callDone, "call did not finish")
+	select {
+	case <-expiredCh:
+	case <-time.After(time.Second):
+		t.Fatal("transaction did not expire")
+	}
+	waitFor(otherDone, "lock was not released after the running call")
+
+	// Calls made after the expiration still hold the lock while they run
+	tx.begin("After")
+	otherDone = make(chan struct{})
+	go func() {
+		b.Begin().Done()
+		close(otherDone)
+	}()
+	select {
+	case <-otherDone:
+		t.Fatal("call made after the expiration is not serialized")
+	case <-time.After(50 * time.Millisecond):
+	}
+	tx.done()
+	waitFor(otherDone, "lock was not released after the call")
+
+	tx.Done()
+	b.Begin().Done()
+}
+
+func TestCronSchedule(t *testing.T) {
+	at := func(s string) time.Time {
+		v, _ := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+		return v
+	}
+	next := func(spec, after string) time.Time {
+		schedule, err := parseCronSchedule(spec)
+		assert.NoError(t, err)
+		return schedule.next(at(after))
+	}
+	assert.Equal(t, at("2022-10-10 23:50"), next("50 23 * * *", "2022-10-10 12:00"))
+	assert.Equal(t, at("2022-10-11 23:50"), next("50 23 * * *", "2022-10-10 23:50"))
+	assert.Equal(t, at("2022-10-10 12:15"), next("*/15 * * * *", "2022-10-10 12:00"))
+	assert.Equal(t, at("2022-10-10 18:00"), next("0 */6 * * *", "2022-10-10 12:00"))
+	assert.Equal(t, at("2022-10-15 08:00"), next("0 8 * * 6", "2022-10-10 12:00"))    // next saturday
+	assert.Equal(t, at("2022-10-16 00:00"), next("0 0 * * 7", "2022-10-10 12:00"))    // 7 is sunday
+	assert.Equal(t, at("2022-10-15 00:00"), next("0 0 15 * 6", "2022-10-13 12:00"))   // day of month or day of week
+	assert.Equal(t, at("2022-10-17 09:30"), next("30 9 * * 1-5", "2022-10-14 10:00")) // weekdays
+	assert.Equal(t, at("2022-11-01 00:00"), next("@monthly", "2022-10-10 12:00"))
+	assert.Equal(t, at("2022-10-10 18:00"), next("@every 6h", "2022-10-10 12:00"))
+	assert.True(t, next("0 0 30 2 *", "2022-10-10 12:00").IsZero())
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "a * * * *", "5-1 * * * *", "@every -1h"} {
+		_, err := parseCronSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSchedulerDueJobs(t *testing.T) {
+	s := NewScheduler(nil).SetLocation(time.UTC)
+	cronID, err := s.Cron("cron", "@hourly", taskRunner.Low, func(Prioritizable) error { return nil })
+	assert.NoError(t, err)
+	now := time.Now()
+	s.At("once", now.Add(-time.Second), taskRunner.Important, func(Prioritizable) error { return nil })
+	_, err = s.Cron("never", "0 0 30 2 *", taskRunner.Low, func(Prioritizable) error { return nil })
+	assert.Error(t, err)
+
+	due := s.dueJobs(now)
+	assert.Equal(t, 1, len(due))
+	assert.Equal(t, "once", due[0].Name)
+	assert.Equal(t, 1, len(s.Jobs()))
+
+	due = s.dueJobs(now.Add(time.Hour))
+	assert.Equal(t, 1, len(due))
+	assert.Equal(t, cronID, due[0].ID)
+	assert.True(t, s.Jobs()[0].Next.After(now.Add(time.Hour)))
+}
+
+func TestFleetArrived(t *testing.T) {
+	outbound := ogame.Fleet{ID: 1, Mission: ogame.Transport}
+	returning := ogame.Fleet{ID: 1, Mission: ogame.Transport, ReturnFlight: true}
+	now := time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)
+	arrived := func(prev ogame.Fleet, holding bool, curr *ogame.Fleet) [2]bool {
+		a, h := fleetArrived(prev, holding, curr, now)
+		return [2]bool{a, h}
+	}
+	assert.Equal(t, [2]bool{false, false}, arrived(outbound, false, &outbound)) // server did not process the arrival yet
+	assert.Equal(t, [2]bool{true, false}, arrived(outbound, false, &returning)) // landed, flying back
+	assert.Equal(t, [2]bool{true, false}, arrived(outbound, false, nil))        // deployed
+	assert.Equal(t, [2]bool{false, false}, arrived(returning, false, &returning))
+	assert.Equal(t, [2]bool{true, false}, arrived(returning, false, nil)) // back home
+
+	// Expedition and acs defend stay at the destination, ReturnFlight is false during the hold
+	expedition := ogame.Fleet{ID: 2, Mission: ogame.Expedition, ArrivalTime: now.Add(time.Minute)}
+	assert.Equal(t, [2]bool{false, false}, arrived(expedition, false, &expedition)) // not there yet
+	expedition.ArrivalTime = now.Add(-time.Second)
+	assert.Equal(t, [2]bool{true, true}, arrived(expedition, false, &expedition)) // landed, holding
+	assert.Equal(t, [2]bool{false, true}, arrived(expedition, true, &expedition)) // still holding
+	expeditionBack := ogame.Fleet{ID: 2, Mission: ogame.Expedition, ReturnFlight: true}
+	assert.Equal(t, [2]bool{false, false}, arrived(expedition, true, &expeditionBack)) // hold is over, flying back
+	assert.Equal(t, [2]bool{true, false}, arrived(expeditionBack, false, nil))         // back home
+	acs := ogame.Fleet{ID: 3, Mission: ogame.ParkInThatAlly, ArrivalTime: now.Add(-time.Second)}
+	assert.Equal(t, [2]bool{true, true}, arrived(acs, false, &acs))
+}
+
+func TestRuleEngineLoadJSON(t *testing.T) {
+	scheduler := NewScheduler(nil)
+	engine := NewRuleEngine(nil, scheduler)
+	err := engine.LoadJSON([]byte(`[
+		{"name": "deut", "schedule": "@every 10m",
+		 "conditions": [{"metric": "deuterium", "celestial": 123, "op": ">", "value": 1000000}],
+		 "actions": [{"type": "notify", "message": "deuterium storage almost full"}]},
+		{"name": "save", "schedule": "* * * * *", "priority": 4,
+		 "conditions": [{"metric": "hostile_eta", "op": "<", "value": 600}],
+		 "actions": [{"type": "send_fleet", "celestial": 123, "ships": [{"ID": 202, "Nbr": 10}], "where": {"Galaxy": 1, "System": 2, "Position": 3, "Type": 1}, "mission": 4}]}
+	]`))
+	assert.NoError(t, err)
+	jobs := scheduler.Jobs()
+	assert.Equal(t, 2, len(jobs))
+	assert.Equal(t, "save", jobs[0].Name)
+	assert.Equal(t, taskRunner.Critical, jobs[0].Priority)
+	assert.Equal(t, taskRunner.Normal, jobs[1].Priority)
+
+	assert.NoError(t, engine.AddRule(Rule{Name: "deut", Schedule: "@hourly", Actions: []RuleAction{{Type: RuleActionNotify}}}))
+	assert.Equal(t, 2, len(scheduler.Jobs()))
+	engine.RemoveRule("deut")
+	assert.Equal(t, 1, len(scheduler.Jobs()))
+
+	assert.Error(t, engine.LoadJSON([]byte(`[{"name": "a", "schedule": "@hourly", "actions": [{"type": "notify"}]}, {"name": "b", "schedule": "@hourly", "conditions": [{"metric": "foo", "op": ">"}], "actions": [{"type": "notify"}]}]`)))
+	assert.Equal(t, 1, len(scheduler.Jobs()))
+	assert.Error(t, validateRule(Rule{Name: "a", Schedule: "bad", Actions: []RuleAction{{Type: RuleActionNotify}}}))
+	assert.Error(t, validateRule(Rule{Name: "a", Schedule: "@hourly"}))
+	assert.Error(t, validateRule(Rule{Name: "a", Schedule: "@hourly", Conditions: []RuleCondition{{Metric: "metal", Op: "=~"}}, Actions: []RuleAction{{Type: RuleActionNotify}}}))
+
+	assert.True(t, compareRule("<", 1, 2))
+	assert.False(t, compareRule(">=", 1, 2))
+	assert.True(t, compareRule("!=", 1, 2))
+	assert.False(t, compareRule("<", math.Inf(1), 600))
+}
+
+func TestRuleEngineLoadYAML(t *testing.T) {
+	scheduler := NewScheduler(nil)
+	engine := NewRuleEngine(nil, scheduler)
+	err := engine.LoadYAML([]byte(`
+- name: deut
+  schedule: "@every 10m"
+  cooldown: 1h
+  conditions:
+    - {metric: deuterium, celestial: 123, op: ">", value: 1000000}
+  actions:
+    - {type: notify, message: deuterium storage almost full}
+- name: save
+  schedule: "* * * * *"
+  priority: 4
+  edge: true
+  conditions:
+    - {metric: hostile_eta, op: "<", value: 600}
+  actions:
+    - type: send_fleet
+      celestial: 123
+      ships: [{id: 202, nbr: 10}]
+      where: {galaxy: 1, system: 2, position: 3, type: 1}
+      mission: 4
+`))
+	assert.NoError(t, err)
+	jobs := scheduler.Jobs()
+	assert.Equal(t, 2, len(jobs))
+	assert.Equal(t, "save", jobs[0].Name)
+	assert.Equal(t, taskRunner.Critical, jobs[0].Priority)
+
+	var rules []Rule
+	assert.NoError(t, yaml.Unmarshal([]byte(`[{name: save, schedule: "@hourly", edge: true, actions: [{type: send_fleet, ships: [{id: 202, nbr: 10}], where: {galaxy: 1, system: 2, position: 3, type: 1}, mission: 4}]}]`), &rules))
+	assert.True(t, rules[0].Edge)
+	assert.Equal(t, []ogame.Quantifiable{{ID: ogame.SmallCargoID, Nbr: 10}}, rules[0].Actions[0].Ships)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}, rules[0].Actions[0].Where)
+	assert.Equal(t, ogame.Park, rules[0].Actions[0].Mission)
+
+	assert.Error(t, engine.LoadYAML([]byte(`[{name: a, schedule: "@hourly", cooldown: soon, actions: [{type: notify}]}]`)))
+	assert.Equal(t, 2, len(scheduler.Jobs()))
+}
+
+// ruleTx serves the metrics of the rule engine and records its actions
+type ruleTx struct {
+	Prioritizable
+	deuterium int64
+	attacks   []ogame.AttackEvent
+	err       error
+	sent      []ogame.Coordinate
+	built     []ogame.ID
+}
+
+func (tx *ruleTx) GetResources(ogame.CelestialID) (ogame.Resources, error) {
+	return ogame.Resources{Deuterium: tx.deuterium}, tx.err
+}
+
+func (tx *ruleTx) GetAttacks(...Option) ([]ogame.AttackEvent, error) { return tx.attacks, tx.err }
+
+func (tx *ruleTx) GetSlots() ogame.Slots { return ogame.Slots{InUse: 3, Total: 5} }
+
+func (tx *ruleTx) IsUnderAttack() (bool, error) { return len(tx.attacks) > 0, tx.err }
+
+func (tx *ruleTx) SendFleet(_ ogame.CelestialID, _ []ogame.Quantifiable, _ ogame.Speed, where ogame.Coordinate,
+	_ ogame.MissionID, _ ogame.Resources, _, _ int64) (ogame.Fleet, error) {
+	tx.sent = append(tx.sent, where)
+	return ogame.Fleet{}, nil
+}
+
+func (tx *ruleTx) Build(_ ogame.CelestialID, id ogame.ID, _ int64) (ogame.ConstructionETA, error) {
+	tx.built = append(tx.built, id)
+	return ogame.ConstructionETA{}, errors.New("not enough resources")
+}
+
+func TestRuleEngineEvaluate(t *testing.T) {
+	engine := NewRuleEngine(nil, NewScheduler(nil))
+	var notified []string
+	engine.OnNotify(func(rule, message string) { notified = append(notified, rule+": "+message) })
+	tx := &ruleTx{deuterium: 2000000}
+	deut := Rule{Name: "deut", Conditions: []RuleCondition{{Metric: RuleMetricDeuterium, CelestialID: 123, Op: ">", Value: 1000000}},
+		Actions: []RuleAction{{Type: RuleActionNotify, Message: "full"}}}
+
+	// Level triggered, executed at every evaluation the conditions are met
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.Equal(t, []string{"deut: full", "deut: full"}, notified)
+	tx.deuterium = 10
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.Equal(t, 2, len(notified))
+
+	// Edge triggered, executed again once the conditions were not met
+	notified = nil
+	deut.Edge = true
+	tx.deuterium = 2000000
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.Equal(t, 1, len(notified))
+	tx.deuterium = 10
+	assert.NoError(t, engine.evaluate(tx, deut))
+	tx.deuterium = 2000000
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.Equal(t, 2, len(notified))
+
+	// Cooldown
+	notified = nil
+	deut.Edge = false
+	deut.Cooldown = "1h"
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.Equal(t, 0, len(notified)) // executed less than 1h ago
+	engine.mu.Lock()
+	engine.states[deut.Name] = ruleState{met: true, executedAt: time.Now().Add(-time.Hour)}
+	engine.mu.Unlock()
+	assert.NoError(t, engine.evaluate(tx, deut))
+	assert.Equal(t, 1, len(notified))
+
+	// A metric cannot be fetched, nothing is executed
+	tx.err = errors.New("network error")
+	assert.Error(t, engine.evaluate(tx, Rule{Name: "eta", Conditions: []RuleCondition{{Metric: RuleMetricHostileETA, Op: "<", Value: 600}},
+		Actions: []RuleAction{{Type: RuleActionNotify}}}))
+	assert.Equal(t, 1, len(notified))
+	tx.err = nil
+
+	// No hostile fleet, the eta is +Inf
+	save := Rule{Name: "save", Conditions: []RuleCondition{{Metric: RuleMetricHostileETA, Op: "<", Value: 600}, {Metric: RuleMetricFreeSlots, Op: ">=", Value: 2}},
+		Actions: []RuleAction{{Type: RuleActionSendFleet, CelestialID: 123, Where: ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType}}}}
+	assert.NoError(t, engine.evaluate(tx, save))
+	assert.Empty(t, tx.sent)
+	tx.attacks = []ogame.AttackEvent{{ArriveIn: 900}, {ArriveIn: 300}}
+	assert.NoError(t, engine.evaluate(tx, save))
+	assert.Equal(t, []ogame.Coordinate{{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType}}, tx.sent)
+
+	// Failing actions are reported, the next ones are not executed
+	build := Rule{Name: "build", Conditions: []RuleCondition{{Metric: RuleMetricUnderAttack, Op: "==", Value: 1}},
+		Actions: []RuleAction{{Type: RuleActionBuild, ID: ogame.MetalMineID}, {Type: RuleActionNotify, Message: "built"}}}
+	err := engine.evaluate(tx, build)
+	assert.EqualError(t, err, "rule build, action build: not enough resources")
+	assert.Equal(t, []ogame.ID{ogame.MetalMineID}, tx.built)
+	assert.Equal(t, 1, len(notified))
+}
+
+func TestHumanizer(t *testing.T) {
+	var nilHumanizer *humanizer
+	assert.Equal(t, time.Duration(0), nilHumanizer.delay(time.Now()))
+	assert.Equal(t, time.Minute, nilHumanizer.interval(time.Minute))
+
+	h := newHumanizer()
+	assert.Equal(t, time.Duration(0), h.delay(time.Now()))
+	assert.Equal(t, "", h.pageView())
+	h.setProfile(&HumanizeProfile{MinDelay: time.Second, MaxDelay: 2 * time.Second, Jitter: 0.2, PageViewChance: 1, PageViews: []string{OverviewPageName}})
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), h.delay(now)) // no previous request
+	wait := h.delay(now)
+	assert.True(t, wait >= time.Second && wait < 2*time.Second)
+	wait2 := h.delay(now)
+	assert.True(t, wait2 >= time.Second+wait && wait2 < 2*time.Second+wait) // waits after the pending request
+	assert.Equal(t, time.Duration(0), h.delay(now.Add(time.Hour)))
+	for i := 0; i < 100; i++ {
+		d := h.interval(time.Minute)
+		assert.True(t, d >= 48*time.Second && d <= 72*time.Second)
+	}
+	assert.Equal(t, OverviewPageName, h.pageView())
+}
+
+func TestLoginPolicy(t *testing.T) {
+	assert.Equal(t, int64(10), LoginPolicy{}.maxAttempts())
+	assert.Equal(t, time.Second, LoginPolicy{}.backoff(1))
+	assert.Equal(t, 8*time.Second, LoginPolicy{}.backoff(4))
+	assert.Equal(t, time.Minute, LoginPolicy{}.backoff(9))
+	assert.Equal(t, 5*time.Second, LoginPolicy{Backoff: func(int64) time.Duration { return 5 * time.Second }}.backoff(3))
+
+	b := &OGame{}
+	var captchaCalls []int64
+	var blockedErr error
+	b.SetLoginPolicy(LoginPolicy{
+		MaxCaptchaFailures: 2,
+		OnCaptcha:          func(failures int64, err error) { captchaCalls = append(captchaCalls, failures) },
+		OnBlocked:          func(err error) { blockedErr = err },
+	})
+	assert.NoError(t, b.handleReloginError(errors.New("network error")))
+	assert.NoError(t, b.handleReloginError(NewCaptchaRequiredError("abc")))
+	assert.False(t, b.reloginStopped())
+	assert.Nil(t, blockedErr)
+	assert.ErrorIs(t, b.handleReloginError(fmt.Errorf("failed to solve captcha challenge: %w", ogame.ErrCaptchaFailed)), ogame.ErrTooManyCaptchaFailures)
+	assert.Equal(t, []int64{1, 2}, captchaCalls)
+	assert.Equal(t, ogame.ErrTooManyCaptchaFailures, blockedErr)
+	assert.True(t, b.reloginStopped())
+	b.loginAttempted(errors.New("failed"))
+	assert.True(t, b.reloginStopped())
+	b.loginAttempted(nil)
+	assert.False(t, b.reloginStopped())
+
+	assert.Equal(t, ogame.ErrAccountBlocked, b.handleReloginError(ogame.ErrAccountBlocked))
+	assert.Equal(t, ogame.ErrAccountBlocked, blockedErr)
+}
+
+func TestBlackboxIsOptIn(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	blackbox, err := b.getBlackbox()
+	assert.NoError(t, err)
+	assert.Equal(t, "", blackbox)
+	b.SetBlackboxProvider(func() (string, error) { return "tra:abc", nil })
+	blackbox, _ = b.getBlackbox()
+	assert.Equal(t, "tra:abc", blackbox)
+
+	req, err := postSessionsReq("env", "game", "user@example.com", "pass", "", "", "")
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(req.Body)
+	vals, _ := url.ParseQuery(string(body))
+	_, found := vals["blackbox"]
+	assert.False(t, found)
+
+	req, _ = postSessionsReq("env", "game", "user@example.com", "pass", "", "", "tra:abc")
+	body, _ = ioutil.ReadAll(req.Body)
+	vals, _ = url.ParseQuery(string(body))
+	assert.Equal(t, "tra:abc", vals.Get("blackbox"))
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func makeBearerToken(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256"}`)) + "." + enc.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix()))) + ".sig"
+}
+
+func TestBearerTokenExpiry(t *testing.T) {
+	exp := time.Unix(time.Now().Add(2*time.Hour).Unix(), 0)
+	assert.Equal(t, exp, bearerTokenExpiry(makeBearerToken(exp)))
+	assert.True(t, bearerTokenExpiry("6c5e3c4b-1a2b-4c3d-9e8f-0123456789ab").IsZero())
+	assert.True(t, bearerTokenExpiry("a.b.c").IsZero())
+
+	b, _ := NewNoLogin("user", "pass", "", makeBearerToken(exp), "s1", "en", "", 0, nil)
+	assert.Equal(t, exp, b.GetBearerTokenExpiry())
+	assert.False(t, b.isBearerTokenExpired())
+	b.bearerTokenExpiry = time.Now().Add(bearerTokenRefreshMargin - time.Minute)
+	assert.True(t, b.isBearerTokenExpired())
+	b, _ = NewNoLogin("user", "pass", "", "6c5e3c4b-1a2b-4c3d-9e8f-0123456789ab", "s1", "en", "", 0, nil)
+	assert.True(t, b.GetBearerTokenExpiry().IsZero())
+	assert.False(t, b.isBearerTokenExpired())
+}
+
+func TestBearerTokenRefresh(t *testing.T) {
+	exp2 := time.Unix(time.Now().Add(48*time.Hour).Unix(), 0)
+	tokens := []string{makeBearerToken(time.Now().Add(bearerTokenRefreshMargin + 2*time.Second)), makeBearerToken(exp2)}
+	var sessions int32
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	defer b.disable()
+	b.client.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "configuration.js") {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`"gameEnvironmentId":"env","platformGameId":"game"`))}, nil
+		}
+		i := atomic.AddInt32(&sessions, 1) - 1
+		return &http.Response{StatusCode: http.StatusCreated, Body: ioutil.NopCloser(strings.NewReader(`{"token":"` + tokens[i] + `"}`))}, nil
+	}))
+	refreshed := make(chan string, 2)
+	b.OnTokenRefreshed(func(token string) { refreshed <- token })
+
+	token, err := b.RefreshToken()
+	assert.NoError(t, err)
+	assert.Equal(t, tokens[0], token)
+	assert.Equal(t, tokens[0], <-refreshed)
+	select {
+	case token = <-refreshed: // refreshed by the timer, bearerTokenRefreshMargin before the expiry
+	case <-time.After(5 * time.Second):
+		t.Fatal("bearer token not refreshed")
+	}
+	assert.Equal(t, tokens[1], token)
+	assert.Equal(t, tokens[1], b.GetBearerToken())
+	assert.Equal(t, exp2, b.GetBearerTokenExpiry())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sessions))
+}
+
+func TestRegister(t *testing.T) {
+	var body []byte
+	registerRes := `{"migrationRequired":false}`
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.client.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.Equal(t, "https://lobby.ogame.gameforge.com/api/users", req.URL.String())
+		body, _ = ioutil.ReadAll(req.Body)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(registerRes))}, nil
+	}))
+
+	assert.NoError(t, b.Register("new@example.com", "Password123", "fr"))
+	assert.JSONEq(t, `{"credentials":{"email":"new@example.com","password":"Password123"},"language":"fr","kid":""}`, string(body))
+	assert.Equal(t, "new@example.com", b.Username)
+	assert.Equal(t, "Password123", b.password)
+
+	// The credentials are kept when the registration fails
+	registerRes = `{"error":"email_used"}`
+	assert.ErrorIs(t, b.Register("used@example.com", "Password123", "en"), ErrEmailUsed)
+	registerRes = `{"error":"email_invalid"}`
+	assert.ErrorIs(t, b.Register("invalid", "Password123", "en"), ErrEmailInvalid)
+	assert.Equal(t, "new@example.com", b.Username)
+}
+
+func TestJoinServer(t *testing.T) {
+	var accountGroups []string
+	b, _ := NewNoLogin("user", "pass", "", makeBearerToken(time.Now().Add(time.Hour)), "s1", "en", "", 0, nil)
+	b.client.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+		switch req.URL.Path {
+		case "/api/servers":
+			res.Body = ioutil.NopCloser(strings.NewReader(`[{"language":"en","number":181,"accountGroup":"en_181","name":"Andromeda"}]`))
+		case "/api/users/me/accounts":
+			assert.Equal(t, "Bearer "+b.bearerToken, req.Header.Get("authorization"))
+			var payload struct{ AccountGroup string }
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			accountGroups = append(accountGroups, payload.AccountGroup)
+			res.Body = ioutil.NopCloser(strings.NewReader(`{"id":123,"server":{"language":"en","number":181},"accountGroup":"en_181"}`))
+			if len(accountGroups) > 1 {
+				res.StatusCode = http.StatusBadRequest
+				res.Body = ioutil.NopCloser(strings.NewReader(`{}`))
+			}
+		}
+		return res, nil
+	}))
+
+	res, err := b.JoinServer(181, "en")
+	assert.NoError(t, err)
+	assert.Equal(t, 123, res.ID)
+	assert.Equal(t, []string{"en_181"}, accountGroups)
+	assert.Equal(t, "Andromeda", b.Universe)
+	assert.Equal(t, int64(123), b.playerID)
+
+	// Already joined
+	_, err = b.JoinServer(181, "en")
+	assert.EqualError(t, err, "invalid request, account already in lobby ?")
+
+	// The account is not created on a server that does not exist
+	b.Universe = "s1"
+	_, err = b.JoinServer(999, "en")
+	assert.EqualError(t, err, "server not found")
+	assert.Equal(t, 2, len(accountGroups))
+	assert.Equal(t, "s1", b.Universe)
+}
+
+func TestMobileSessionsReq(t *testing.T) {
+	installationID := NewMobileInstallationID()
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, installationID)
+	req, err := mobileSessionsReq("env", "game", "user@example.com", "pass", "", "challenge", installationID)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gameforge.com/api/v1/auth/thin/sessions", req.URL.String())
+	assert.Equal(t, mobileUserAgent, req.Header.Get("User-Agent"))
+	assert.Equal(t, installationID, req.Header.Get("tnt-installation-id"))
+	assert.Equal(t, "challenge", req.Header.Get("gf-challenge-id"))
+	assert.Equal(t, "", req.Header.Get("tnt-2fa-code"))
+	assert.NoError(t, req.ParseForm())
+	assert.Equal(t, "user@example.com", req.PostForm.Get("identity"))
+	assert.Equal(t, "env", req.PostForm.Get("gameEnvironmentId"))
+	assert.False(t, req.PostForm.Has("blackbox"))
+
+	// The client keeps the mobile user agent
+	var gotUA string
+	client := httpclient.NewClient()
+	client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		gotUA = r.Header.Get("User-Agent")
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+	}))
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, mobileUserAgent, gotUA)
+	assert.Equal(t, "mobile", MobileLogin.String())
+}
+
+func TestMobileInstallationIDPersisted(t *testing.T) {
+	cookiesFilename := t.TempDir() + "/cookies.json"
+	b, err := NewNoLogin("user", "pass", "", "", "s1", "en", cookiesFilename, 0, nil)
+	assert.NoError(t, err)
+	b.SetLoginStrategy(MobileLogin)
+	installationID := b.mobileInstallationID
+	assert.NotEqual(t, "", installationID)
+
+	// Restart, the device keeps its installation id
+	b, err = NewNoLogin("user", "pass", "", "", "s1", "en", cookiesFilename, 0, nil)
+	assert.NoError(t, err)
+	b.SetLoginStrategy(MobileLogin)
+	assert.Equal(t, installationID, b.mobileInstallationID)
+}
+
+func TestHeaderProfile(t *testing.T) {
+	profile := HeaderProfileFor("user|s1|en")
+	assert.Equal(t, profile, HeaderProfileFor("user|s1|en"))
+	assert.NotEqual(t, "", profile.UserAgent)
+
+	cookiesFilename := t.TempDir() + "/cookies.json"
+	b, err := NewNoLogin("user", "pass", "", "", "s1", "en", cookiesFilename, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, HeaderProfile{}, b.GetHeaderProfile())
+	assert.Equal(t, defaultUserAgent, b.GetClient().UserAgent())
+	_, err = os.Stat(cookiesFilename + ".profile.json")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, b.EnableHeaderProfile())
+	assert.Equal(t, profile, b.GetHeaderProfile())
+	assert.Equal(t, profile.UserAgent, b.GetClient().UserAgent())
+
+	custom := HeaderProfile{Name: "custom", UserAgent: "custom-agent", Headers: map[string]string{"Accept-Language": "fr-FR"}}
+	assert.NoError(t, b.SetHeaderProfile(custom))
+
+	// Restart, the persisted profile is reused
+	b, err = NewNoLogin("user", "pass", "", "", "s1", "en", cookiesFilename, 0, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, b.EnableHeaderProfile())
+	assert.Equal(t, custom, b.GetHeaderProfile())
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	b.setProfileHeaders(req)
+	assert.Equal(t, "fr-FR", req.Header.Get("Accept-Language"))
+}
+
+func TestMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Custom") + "|" + r.URL.Query().Get("page")))
+	}))
+	defer srv.Close()
+	b, err := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	assert.NoError(t, err)
+
+	var order []string
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			order = append(order, "outer")
+			req.Header.Set("X-Custom", "value")
+			resp, err := next(req)
+			if err == nil {
+				resp.Body = append(resp.Body, []byte("|observed")...)
+			}
+			return resp, err
+		}
+	})
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			order = append(order, "inner")
+			if req.Vals.Get("page") == "cached" {
+				return &Response{StatusCode: http.StatusOK, Body: []byte("short-circuit")}, nil
+			}
+			return next(req)
+		}
+	})
+	by, err := b.execRequest(http.MethodGet, srv.URL+"?page=overview", nil, url.Values{"page": {"overview"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "value|overview|observed", string(by))
+	assert.Equal(t, []string{"outer", "inner"}, order)
+
+	by, err = b.execRequest(http.MethodGet, srv.URL+"?page=cached", nil, url.Values{"page": {"cached"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "short-circuit|observed", string(by))
+}
+
+func TestLoginWithPriorityDoesNotDeadlock(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.SetGetServerDataWrapper(func(func() (ServerData, error)) (ServerData, error) {
+		return ServerData{Version: "9.0.0"}, nil
+	})
+	b.SetLoginWrapper(func(func() (bool, error)) error {
+		return b.loginPart2(Server{Number: 1, Language: "en"})
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- b.WithPriority(taskRunner.Normal).Login()
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("login through WithPriority deadlocked")
+	}
+
+	tx := b.Begin()
+	defer tx.Done()
+	got := make(chan *publicapi.Client, 1)
+	go func() { got <- b.GetPublicAPI() }()
+	select {
+	case client := <-got:
+		assert.NotNil(t, client)
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetPublicAPI blocked while a transaction holds the bot")
+	}
+}
+
+func TestLifecycleCallbacks(t *testing.T) {
+	b := &OGame{}
+	var events []string
+	b.OnLoginSuccess(func() { events = append(events, "success") })
+	b.OnLoginFailure(func(err error) { events = append(events, "failure: "+err.Error()) })
+	b.OnRelogin(func() { events = append(events, "relogin") })
+	b.OnLogout(func() { events = append(events, "logout") })
+	b.loginAttempted(ogame.ErrAccountBlocked)
+	b.loginAttempted(nil)
+	b.reloggedIn()
+	b.loggedOut()
+	assert.Equal(t, []string{"failure: account is blocked", "success", "relogin", "logout"}, events)
+}
+
+func TestChatResponderMatch(t *testing.T) {
+	r := NewChatResponder(nil).
+		On(`(?i)\bbot\b`, "no").
+		OnFrom(7, `ping`, "pong").
+		OnAlliance(3, `hello`, "hi all").
+		SetCooldown(time.Minute).
+		SetMaxPerHour(3)
+	now := time.Now()
+	_, ok := r.match(ogame.ChatMsg{SenderID: 1, Text: "are you a BOT?"}, now)
+	assert.False(t, ok) // not started
+	r.running = true
+
+	rule, ok := r.match(ogame.ChatMsg{SenderID: 1, Text: "are you a BOT?"}, now)
+	assert.True(t, ok)
+	assert.Equal(t, "no", rule.Reply)
+	_, ok = r.match(ogame.ChatMsg{SenderID: 1, Text: "bot?"}, now.Add(30*time.Second))
+	assert.False(t, ok) // cooldown
+	_, ok = r.match(ogame.ChatMsg{SenderID: 2, Text: "ping"}, now)
+	assert.False(t, ok) // ping only from 7
+	_, ok = r.match(ogame.ChatMsg{SenderID: 2, AssociationID: 3, Text: "bot"}, now)
+	assert.False(t, ok) // private rule does not apply to the alliance chat
+	rule, ok = r.match(ogame.ChatMsg{SenderID: 2, AssociationID: 3, Text: "hello guys"}, now)
+	assert.True(t, ok)
+	assert.Equal(t, "hi all", rule.Reply)
+	rule, ok = r.match(ogame.ChatMsg{SenderID: 7, Text: "ping"}, now)
+	assert.True(t, ok)
+	assert.Equal(t, "pong", rule.Reply)
+	_, ok = r.match(ogame.ChatMsg{SenderID: 1, Text: "bot"}, now.Add(2*time.Minute))
+	assert.False(t, ok) // 3 replies in the last hour
+	_, ok = r.match(ogame.ChatMsg{SenderID: 1, Text: "bot"}, now.Add(61*time.Minute))
+	assert.True(t, ok)
+}
+
+func TestSocketIOPacket(t *testing.T) {
+	p, err := parseSocketIOPacket(`0{"sid":"abc","pingInterval":25000,"pingTimeout":20000}`)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(eioOpen), p.Engine)
+	assert.Equal(t, `{"sid":"abc","pingInterval":25000,"pingTimeout":20000}`, p.Data)
+
+	p, _ = parseSocketIOPacket("3probe")
+	assert.Equal(t, byte(eioPong), p.Engine)
+	assert.Equal(t, "probe", p.Data)
+
+	p, _ = parseSocketIOPacket(`43/chat,12[true]`)
+	assert.Equal(t, byte(sioAck), p.Type)
+	assert.Equal(t, "/chat", p.Namespace)
+	assert.Equal(t, int64(12), p.AckID)
+	assert.Equal(t, "[true]", p.Data)
+
+	p, _ = parseSocketIOPacket(`41/auctioneer`)
+	assert.Equal(t, byte(sioDisconnect), p.Type)
+	assert.Equal(t, "/auctioneer", p.Namespace)
+
+	p, _ = parseSocketIOPacket(`42/chat,["chat",{"senderId":1}]`)
+	name, args, err := p.event()
+	assert.NoError(t, err)
+	assert.Equal(t, "chat", name)
+	assert.Equal(t, 1, len(args))
+
+	_, err = parseSocketIOPacket("x")
+	assert.Error(t, err)
+	_, err = parseSocketIOPacket("49")
+	assert.Error(t, err)
+
+	assert.Equal(t, "40/chat,", encodeSocketIOPacket(sioConnect, "/chat", -1, ""))
+	assert.Equal(t, `42/chat,3["authorize","sess"]`, encodeSocketIOPacket(sioEvent, "/chat", 3, `["authorize","sess"]`))
+	assert.Equal(t, `42["x"]`, encodeSocketIOPacket(sioEvent, "/", -1, `["x"]`))
+
+	pck := parseAuctioneerEvent("new bid", map[string]any{"sum": 2000.0, "price": 3000.0, "bids": 2.0, "auctionId": "13355",
+		"player": map[string]any{"id": 106734.0, "name": "Someone"}})
+	bid, ok := pck.(ogame.AuctioneerNewBid)
+	assert.True(t, ok)
+	assert.Equal(t, int64(13355), bid.AuctionID)
+	assert.Equal(t, int64(106734), bid.Player.ID)
+	assert.Equal(t, ogame.AuctioneerNextAuction{Secs: 598}, parseAuctioneerEvent("timeLeft", `Next auction in:<br /><span class="nextAuction" id="nextAuction">598</span>`))
+	assert.Nil(t, parseAuctioneerEvent("unknown", nil))
+}
+
+func TestChatReconnectPolicy(t *testing.T) {
+	policy := ChatReconnectPolicy{}
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, time.Minute, policy.delay(20))
+	policy = ChatReconnectPolicy{MinDelay: 5 * time.Second, MaxDelay: 15 * time.Second}
+	assert.Equal(t, 10*time.Second, policy.delay(2))
+	assert.Equal(t, 15*time.Second, policy.delay(3))
+}
+
+func TestParseAuctioneerNewAuction(t *testing.T) {
+	var arg any
+	_ = json.Unmarshal([]byte(`{"info":"<span style=\"color:#99CC00;\"><b>approx. 45m</b></span> remaining until the auction ends","item":{"uuid":"118d34e685b5d1472267696d1010a393a59aed03","image":"bdb4508609de1df58bf4a6108fff73078c89f777","rarity":"rare"},"oldAuction":{"item":{"uuid":"8a4f9e8309e1078f7f5ced47d558d30ae15b4a1b","imageSmall":"014827f6d1d5b78b1edd0d4476db05639e7d9367","rarity":"epic"}},"auctionId":18550}`), &arg)
+	evt := parseAuctioneerEvent("new auction", arg)
+	newAuction, ok := evt.(ogame.AuctioneerNewAuction)
+	assert.True(t, ok)
+	assert.Equal(t, int64(18550), newAuction.AuctionID)
+	assert.Equal(t, int64(45*60), newAuction.Approx)
+	assert.Equal(t, "118d34e685b5d1472267696d1010a393a59aed03", newAuction.Item.UUID)
+	assert.Equal(t, "rare", newAuction.Item.Rarity)
+	assert.Equal(t, "014827f6d1d5b78b1edd0d4476db05639e7d9367", newAuction.OldItem.Image)
+	assert.Equal(t, "epic", newAuction.OldItem.Rarity)
+
+	_ = json.Unmarshal([]byte(`{"sum":2000,"bids":2,"info":"Next auction in:<br />\n<span class=\"nextAuction\" id=\"nextAuction\">1390</span>","time":"06:36"}`), &arg)
+	finished, ok := parseAuctioneerEvent("auction finished", arg).(ogame.AuctioneerAuctionFinished)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1390), finished.NextAuction)
+	assert.Equal(t, "06:36", finished.Time)
+}
+
+func TestConnectDisconnectChat(t *testing.T) {
+	bot, _ := NewNoLogin("", "", "", "", "", "", "", 0, nil)
+	assert.Error(t, bot.ConnectChat()) // chat host is only known after login
+	bot.DisconnectChat()
+	bot.DisconnectChat()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&bot.chatDisabledAtom))
+	assert.False(t, bot.isChatConnected())
+}
+
+func TestCallbackSubscription(t *testing.T) {
+	bot, _ := NewNoLogin("", "", "", "", "", "", "", 0, nil)
+	var got []string
+	sub1 := bot.RegisterChatCallback(func(msg ogame.ChatMsg) { got = append(got, "1:"+msg.Text) })
+	bot.RegisterChatCallback(func(msg ogame.ChatMsg) { panic("boom") })
+	sub3 := bot.RegisterChatCallback(func(msg ogame.ChatMsg) { got = append(got, "3:"+msg.Text) })
+	bot.deliverChatMsg(ogame.ChatMsg{Text: "a"})
+	assert.Equal(t, []string{"1:a", "3:a"}, got) // the panicking callback does not prevent the others
+	sub1.Close()
+	sub1.Close()
+	bot.deliverChatMsg(ogame.ChatMsg{Text: "b"})
+	assert.Equal(t, []string{"1:a", "3:a", "3:b"}, got)
+	sub3.Close()
+	assert.Equal(t, 1, bot.chatCallbacks.len())
+
+	events, sub := bot.SubscribeAuctioneer()
+	bot.deliverAuctioneerPacket(ogame.AuctioneerNextAuction{Secs: 10})
+	bot.deliverAuctioneerPacket("raw")
+	assert.Equal(t, ogame.AuctioneerNextAuction{Secs: 10}, <-events)
+	sub.Close()
+	bot.deliverAuctioneerPacket(ogame.AuctioneerNextAuction{Secs: 5})
+	assert.Equal(t, 0, len(events))
+}
+
+func TestFetchMessagePages(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			return &Response{StatusCode: http.StatusOK, Body: []byte(req.Payload.Get("pagination"))}, nil
+		}
+	})
+	extract := func(pageHTML []byte) ([]string, int64, error) {
+		page := string(pageHTML)
+		return []string{page + "a", page + "b"}, 5, nil
+	}
+	msgs, err := fetchMessagePages(b, EspionageMessagesTabID, extract, Concurrency(3))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1a", "1b", "2a", "2b", "3a", "3b", "4a", "4b", "5a", "5b"}, msgs)
+	assert.Equal(t, 3, maxInFlight)
+
+	maxInFlight = 0
+	msgs, err = fetchMessagePages(b, EspionageMessagesTabID, extract)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, len(msgs))
+	assert.Equal(t, 1, maxInFlight)
+}
+
+func TestFetchMessagePagesRelogin(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	b.SetLoginPolicy(LoginPolicy{Backoff: func(int64) time.Duration { return 0 }})
+	var logins int32
+	b.SetLoginWrapper(func(func() (bool, error)) error {
+		atomic.AddInt32(&logins, 1)
+		return nil
+	})
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			page := req.Payload.Get("pagination")
+			if atomic.LoadInt32(&logins) == 0 && (page == "3" || page == "4") {
+				return nil, ogame.ErrNotLogged
+			}
+			return &Response{StatusCode: http.StatusOK, Body: []byte(page)}, nil
+		}
+	})
+	extract := func(pageHTML []byte) ([]string, int64, error) {
+		return []string{string(pageHTML)}, 5, nil
+	}
+	msgs, err := fetchMessagePages(b, EspionageMessagesTabID, extract, Concurrency(3))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, msgs)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("target"))
+	}))
+	defer srv.Close()
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	resp, err := b.doRequest(&Request{Method: http.MethodPost, URL: srv.URL + "/redirect", Payload: url.Values{}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode) // post requests do not follow redirects
+	resp, err = b.doRequest(&Request{Method: http.MethodGet, URL: srv.URL + "/redirect"})
+	assert.NoError(t, err)
+	assert.Equal(t, "target", string(resp.Body))
+}
+
+func TestEspionageReportMaxAge(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	b.SetCache(NewMemoryCache())
+	pageHTML, _ := ioutil.ReadFile("../../samples/unversioned/messages_loot_percentage.html")
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK, Body: pageHTML}, nil
+		}
+	})
+	msgs, _ := b.extractor.ExtractEspionageReportMessageIDs(pageHTML)
+	assert.NotEmpty(t, msgs)
+	target := msgs[0].Target
+	cacheSet(b, ReportsCache, utils.FI64(msgs[0].ID), ogame.EspionageReport{Coordinate: target, Date: time.Now().Add(-2 * time.Hour)})
+
+	report, err := b.getEspionageReportFor(target)
+	assert.NoError(t, err)
+	assert.Equal(t, target, report.Coordinate)
+
+	report, err = b.getEspionageReportFor(target, MaxAge(time.Hour))
+	assert.True(t, errors.Is(err, ogame.ErrReportTooOld))
+	assert.Equal(t, target, report.Coordinate)
+
+	_, err = b.getEspionageReportFor(target, MaxAge(3*time.Hour))
+	assert.NoError(t, err)
+
+	_, err = b.getEspionageReportFor(ogame.Coordinate{Galaxy: 9, System: 499, Position: 15, Type: ogame.PlanetType}, MaxAge(time.Hour))
+	assert.True(t, errors.Is(err, ogame.ErrReportNotFound))
+}
+
+func TestBuildTrashsimURL(t *testing.T) {
+	report := ogame.EspionageReport{
+		Resources:           ogame.Resources{Metal: 1000, Crystal: 500},
+		Coordinate:          ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType},
+		HasFleetInformation: true,
+		LightFighter:        utils.I64Ptr(4),
+	}
+	serverData := ServerData{Language: "fr", Galaxies: 9, Systems: 499, RapidFire: true, DebrisFactor: 0.3}
+	link := BuildTrashsimURL(report, ogame.ShipsInfos{SmallCargo: 10}, ogame.Researches{WeaponsTechnology: 12}, serverData)
+	assert.True(t, strings.HasPrefix(link, "https://trashsim.oplanet.eu/fr#prefill="))
+	by, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(link, "https://trashsim.oplanet.eu/fr#prefill="))
+	assert.NoError(t, err)
+	var prefill trashsimPrefill
+	assert.NoError(t, json.Unmarshal(by, &prefill))
+	assert.Equal(t, int64(10), prefill.Attackers[0].Ships["202"].Count)
+	assert.Equal(t, int64(12), prefill.Attackers[0].Research["109"].Level)
+	assert.Equal(t, int64(4), prefill.Defenders[0].Ships["204"].Count)
+	assert.Nil(t, prefill.Defenders[0].Research)
+	assert.Equal(t, int64(1000), prefill.Defenders[0].Resources.Metal.Value)
+	assert.Equal(t, int64(3), prefill.Defenders[0].Planet.Position)
+	assert.Equal(t, int64(1), prefill.Settings.RapidFire)
+	assert.Equal(t, int64(499), prefill.Settings.Systems)
+}
+
+func TestDefaultTargetScorer(t *testing.T) {
+	report := ogame.EspionageReport{Resources: ogame.Resources{Metal: 20000}, IsInactive: true, HasFleetInformation: true, HasDefensesInformation: true}
+	target := NewTargetInfo(report, ogame.NoClass)
+	assert.Equal(t, int64(0), target.DefenseValue)
+	target.FlightTime = 1800
+	target.Fuel = 1000
+	assert.Equal(t, 9000.0, DefaultTargetScorer{}.Score(target))
+	assert.Equal(t, 0.0, DefaultTargetScorer{MaxFlightTime: 600}.Score(target))
+
+	report.RocketLauncher = utils.I64Ptr(2)
+	target = NewTargetInfo(report, ogame.NoClass)
+	assert.Equal(t, int64(4000), target.DefenseValue)
+	assert.Equal(t, 0.0, DefaultTargetScorer{}.Score(target))
+	assert.True(t, DefaultTargetScorer{MaxRisk: 0.5}.Score(target) > 0)
+
+	report.HasDefensesInformation = false
+	assert.Equal(t, 0.0, DefaultTargetScorer{MaxRisk: 1}.Score(NewTargetInfo(report, ogame.NoClass)))
+
+	scorer := TargetScorerFunc(func(target TargetInfo) float64 { return float64(target.Loot.Metal) })
+	assert.Equal(t, 10000.0, scorer.Score(target))
+}
+
+func TestGetExpeditionFleet(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.serverData.TopScore = 2000000
+	b.serverData.Speed = 1
+	fleet, err := b.getExpeditionFleet(ogame.LargeCargoID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ogame.ShipsInfos{LargeCargo: 72}, fleet)
+	b.characterClass = ogame.Discoverer
+	fleet, err = b.getExpeditionFleet(ogame.LargeCargoID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), fleet.Pathfinder)
+	assert.Equal(t, int64(216), fleet.LargeCargo)
+}
+
+func TestMaxSlotsWrapper(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.researches = &ogame.Researches{ComputerTechnology: 5, Astrophysics: 4}
+	b.touchCache(ResearchesCache)
+	b.characterClass = ogame.General
+	b.hasAdmiral = true
+	assert.Equal(t, ogame.Slots{Total: 9, ExpTotal: 3}, b.MaxSlots(ogame.SlotsBonus{}))
+}
+
+func TestTrackHostiles(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	a1 := ogame.AttackEvent{ID: 1, MissionType: ogame.Attack, ArriveIn: 600}
+	a2 := ogame.AttackEvent{ID: 2, MissionType: ogame.Attack, ArrivalTime: now.Add(time.Hour)}
+	hostiles, events := trackHostiles(map[int64]TrackedHostile{}, []ogame.AttackEvent{a1, a2}, now)
+	assert.Equal(t, 2, len(hostiles))
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, HostileDetected, events[0].Type)
+	assert.Equal(t, now.Add(10*time.Minute), hostiles[1].ArrivalTime)
+
+	// countdown corrected without event, then the union is delayed
+	now = now.Add(time.Minute)
+	a1.ArriveIn = 541
+	hostiles, events = trackHostiles(hostiles, []ogame.AttackEvent{a1, a2}, now)
+	assert.Equal(t, 0, len(events))
+	assert.Equal(t, now.Add(541*time.Second), hostiles[1].ArrivalTime)
+	a1.ArriveIn = 900
+	hostiles, events = trackHostiles(hostiles, []ogame.AttackEvent{a1, a2}, now)
+	assert.Equal(t, []HostileEventType{HostileDelayed}, []HostileEventType{events[0].Type})
+
+	// attack 2 recalled
+	hostiles, events = trackHostiles(hostiles, []ogame.AttackEvent{a1}, now)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, HostileRecalled, events[0].Type)
+	assert.Equal(t, int64(2), events[0].Hostile.Attack.ID)
+
+	// attack 1 hits
+	_, events = trackHostiles(hostiles, nil, now.Add(time.Hour))
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, HostileImpact, events[0].Type)
+}
+
+func TestPanicSaveMissions(t *testing.T) {
+	origin := ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}
+	moon := ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType}
+	other := ogame.Coordinate{Galaxy: 2, System: 5, Position: 8, Type: ogame.PlanetType}
+	celestials := []ogame.Coordinate{origin, moon, other}
+	ships := ogame.ShipsInfos{LargeCargo: 10}
+	assert.Equal(t, []panicSaveMission{{where: moon, mission: ogame.Park}}, panicSaveMissions(origin, celestials, ships, PanicSaveOwnMoon))
+	assert.Equal(t, 2, len(panicSaveMissions(origin, celestials, ships, PanicSaveDeploy)))
+	assert.Equal(t, 0, len(panicSaveMissions(origin, celestials, ships, PanicSaveHarvest)))
+	ships.Recycler = 1
+	assert.Equal(t, []panicSaveMission{{where: origin.Debris(), mission: ogame.RecycleDebrisField}}, panicSaveMissions(origin, celestials, ships, PanicSaveHarvest))
+	assert.Equal(t, 0, len(panicSaveMissions(moon, []ogame.Coordinate{origin, moon}, ships, PanicSaveOwnMoon)))
+}
+
+func TestPanicSaveSkipsAttackedDestinations(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	b.SetLoginPolicy(LoginPolicy{MaxAttempts: 1})
+	b.extractor = v7.NewExtractor()
+	b.serverData.Galaxies = 9
+	b.serverData.Systems = 499
+	b.serverData.GlobalDeuteriumSaveFactor = 1
+	b.serverData.SpeedFleetPeaceful = 1
+	// Planets [9:297:12] (33795776) and [9:297:9], 6 small cargo, 1 colony ship and 73 deuterium
+	shipyardHTML, _ := ioutil.ReadFile("../../samples/v7/shipyard.html")
+	resourcesJSON, _ := ioutil.ReadFile("../../samples/v7/fetchResources.html")
+	eventListHTML, _ := ioutil.ReadFile("../../samples/unversioned/eventlist_attack.html") // attack on [1:157:12]
+	var eventList atomic.Value
+	eventList.Store(eventListHTML)
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			var body []byte
+			switch {
+			case strings.Contains(req.URL, "component="+ShipyardPageName):
+				body = shipyardHTML
+			case strings.Contains(req.URL, FetchResourcesPageName):
+				body = resourcesJSON
+			case strings.Contains(req.URL, EventListAjaxPageName):
+				body = eventList.Load().([]byte)
+			default:
+				return nil, errors.New("unreachable")
+			}
+			return &Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body}, nil
+		}
+	})
+	b.planets = convertPlanets(b, []ogame.Planet{{ID: 33795776, Coordinate: ogame.Coordinate{Galaxy: 9, System: 297, Position: 12, Type: ogame.PlanetType}}})
+	policy := PanicSavePolicy{Destinations: []PanicSaveDestination{PanicSaveDeploy}}
+
+	order, err := b.panicSaveOrder(33795776, policy)
+	assert.NoError(t, err)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 9, System: 297, Position: 9, Type: ogame.PlanetType}, order.mission.where)
+	assert.Equal(t, ogame.Park, order.mission.mission)
+	assert.Equal(t, int64(6), order.ships.SmallCargo)
+	assert.Equal(t, int64(0), order.ships.Crawler)
+
+	// The only other planet is attacked
+	eventList.Store(bytes.Replace(eventListHTML, []byte("[1:157:12]"), []byte("[9:297:9]"), 1))
+	_, err = b.panicSaveOrder(33795776, policy)
+	assert.ErrorIs(t, err, ErrNoPanicSaveDestination)
+
+	// Targeted destinations cannot be known, no fleet is sent
+	eventList.Store([]byte(nil))
+	_, err = b.panicSave(33795776, policy)
+	assert.Error(t, err)
+}
+
+func TestConstructionETA(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.serverData.Speed = 1
+	techs := cachedTechs{
+		ResourcesBuildings: ogame.ResourcesBuildings{MetalMine: 9},
+		Facilities:         ogame.Facilities{RoboticsFactory: 2, Shipyard: 4, ResearchLab: 3},
+		Researches:         ogame.Researches{EnergyTechnology: 2},
+	}
+	startedAt := time.Unix(1700000000, 0)
+	eta := b.constructionETA(ogame.MetalMineID, 0, techs, 0, startedAt)
+	assert.Equal(t, int64(10), eta.Nbr)
+	assert.Equal(t, b.constructionTime(ogame.MetalMineID, 10, ogame.Facilities{RoboticsFactory: 2, Shipyard: 4, ResearchLab: 3}), eta.Duration)
+	assert.True(t, eta.Duration > 0)
+	assert.Equal(t, startedAt.Add(eta.Duration), eta.FinishAt)
+
+	eta = b.constructionETA(ogame.EnergyTechnologyID, 0, techs, 0, startedAt)
+	assert.Equal(t, int64(3), eta.Nbr)
+	assert.Equal(t, b.researchDuration(ogame.EnergyTechnologyID, 3, 3), eta.Duration)
+
+	// The level in front of it in the queue is not built yet
+	eta = b.constructionETA(ogame.EnergyTechnologyID, 0, techs, ogame.EnergyTechnologyID, startedAt)
+	assert.Equal(t, int64(4), eta.Nbr)
+	eta = b.constructionETA(ogame.EnergyTechnologyID, 0, techs, ogame.LaserTechnologyID, startedAt)
+	assert.Equal(t, int64(3), eta.Nbr)
+
+	eta = b.constructionETA(ogame.LightFighterID, 5, techs, 0, startedAt)
+	assert.Equal(t, int64(5), eta.Nbr)
+	assert.True(t, eta.Duration > 0)
+}
+
+func TestWithConstructionETA(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	b.SetLoginPolicy(LoginPolicy{MaxAttempts: 1})
+	b.extractor = v6.NewExtractor()
+	b.serverData.Speed = 1
+	// Crystal mine being built for 731s, combustion drive researched for 927s.
+	// The sample predates the page marker used to recognize the overview page.
+	overviewHTML, _ := ioutil.ReadFile("../../samples/unversioned/overview_active.html")
+	overviewHTML = append([]byte(`<script>var currentPage = "overview";</script>`), overviewHTML...)
+	var requests, failing int32
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			atomic.AddInt32(&requests, 1)
+			if atomic.LoadInt32(&failing) == 1 {
+				return nil, errors.New("unreachable")
+			}
+			return &Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: overviewHTML}, nil
+		}
+	})
+	b.setCachedTechs(123, cachedTechs{ResourcesBuildings: ogame.ResourcesBuildings{MetalMine: 9, CrystalMine: 5}, Facilities: ogame.Facilities{RoboticsFactory: 2}})
+	built := false
+	eta, err := b.withConstructionETA(123, ogame.MetalMineID, 0, func() error {
+		built = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, built)
+	assert.Equal(t, "", eta.Err)
+	assert.Equal(t, int64(10), eta.Nbr)
+	assert.True(t, eta.Duration > 0)
+	assert.WithinDuration(t, b.serverNow().Add(731*time.Second), eta.StartedAt, 2*time.Second) // behind the crystal mine
+	assert.Equal(t, eta.StartedAt.Add(eta.Duration), eta.FinishAt)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests)) // levels read from the cache, only the queue is fetched
+
+	// Same building as the one in construction, the next level
+	eta, err = b.withConstructionETA(123, ogame.CrystalMineID, 0, func() error {
+		b.InvalidateTechs(123)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), eta.Nbr)
+	assert.Equal(t, b.constructionTime(ogame.CrystalMineID, 7, ogame.Facilities{RoboticsFactory: 2}), eta.Duration)
+
+	// Techs cannot be loaded, the construction is started and the failure is reported
+	atomic.StoreInt32(&failing, 1)
+	before := b.serverNow()
+	eta, err = b.withConstructionETA(123, ogame.MetalMineID, 0, func() error { return nil })
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", eta.Err)
+	assert.Equal(t, time.Duration(0), eta.Duration)
+	assert.WithinDuration(t, before, eta.StartedAt, 2*time.Second)
+
+	_, err = b.withConstructionETA(123, ogame.MetalMineID, 0, func() error { return errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+}
+
+func TestServerNow(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt64(&b.serverClockOffsetAtom, int64(-time.Hour))
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), b.serverNow(), time.Second)
+}
+
+func TestLfConstructionTime(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.serverData.Speed = 1
+	facilities := ogame.Facilities{RoboticsFactory: 2}
+	base := b.constructionTime(ogame.MetalMineID, 20, facilities)
+	assert.Equal(t, base, b.lfConstructionTime(ogame.MetalMineID, 20, facilities, ogame.LfBuildings{}))
+	reduced := b.lfConstructionTime(ogame.MetalMineID, 20, facilities, ogame.LfBuildings{Megalith: 10})
+	assert.Equal(t, time.Duration(math.Floor(base.Seconds()*0.9))*time.Second, reduced)
+	assert.Equal(t, b.constructionTime(ogame.ResidentialSectorID, 20, facilities),
+		b.lfConstructionTime(ogame.ResidentialSectorID, 20, facilities, ogame.LfBuildings{Megalith: 10}))
+}
+
+func TestBuyItem(t *testing.T) {
+	buffActivationHTML, _ := ioutil.ReadFile("../../samples/unversioned/buffActivation.html")
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	var inventoryPayloads []url.Values
+	inventoryRes := `{"message":"The expansion was activated successfully.","error":false,"newToken":"abc"}`
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			if req.Vals.Get("page") == "inventory" {
+				inventoryPayloads = append(inventoryPayloads, req.Payload)
+				return &Response{StatusCode: http.StatusOK, Body: []byte(inventoryRes)}, nil
+			}
+			return &Response{StatusCode: http.StatusOK, Body: buffActivationHTML}, nil
+		}
+	})
+
+	// Gold Metal Booster, canBeBoughtAndActivated
+	assert.NoError(t, b.buyItem("05294270032e5dc968672425ab5611998c409166", 0))
+	assert.Equal(t, 1, len(inventoryPayloads))
+	assert.Equal(t, "130459f4da044998841cdb5680af8a42", inventoryPayloads[0].Get("token"))
+	assert.Equal(t, "05294270032e5dc968672425ab5611998c409166", inventoryPayloads[0].Get("item"))
+	assert.Equal(t, "ingame", inventoryPayloads[0].Get("referrerPage"))
+	entries := b.GetDarkMatterLedger(time.Time{})
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, int64(25000), entries[0].Amount)
+
+	assert.EqualError(t, b.buyItem("unknown", 0), "item not found unknown")
+	assert.Equal(t, 1, len(inventoryPayloads))
+
+	inventoryRes = `{"message":"An error occurred during the expansion purchase.","error":true,"newToken":"def"}`
+	assert.EqualError(t, b.buyItem("05294270032e5dc968672425ab5611998c409166", 0), "An error occurred during the expansion purchase.")
+	assert.Equal(t, 1, len(b.GetDarkMatterLedger(time.Time{})))
+}
+
+func TestGetCachedTechs(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	var requests int64
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			requests++
+			return &Response{StatusCode: http.StatusOK, Body: []byte(`{"1":12,"14":3}`)}, nil
+		}
+	})
+	assert.True(t, b.TechsUpdatedAt(123).IsZero())
+	resourcesBuildings, facilities, _, _, _, _, err := b.getCachedTechs(123)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), resourcesBuildings.MetalMine)
+	assert.Equal(t, int64(3), facilities.RoboticsFactory)
+	assert.Equal(t, int64(1), requests)
+	assert.False(t, b.TechsUpdatedAt(123).IsZero())
+
+	_, _, _, _, _, _, _ = b.getCachedTechs(123)
+	assert.Equal(t, int64(1), requests)
+
+	b.InvalidateTechs(123)
+	assert.True(t, b.TechsUpdatedAt(123).IsZero())
+	_, _, _, _, _, _, _ = b.getCachedTechs(123)
+	assert.Equal(t, int64(2), requests)
+
+	b.SetCacheTTL(TechsCache, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, _, _, _, _, _, _ = b.getCachedTechs(123)
+	assert.Equal(t, int64(3), requests)
+}
+
+func TestCachedTechsDefaultTTLAndPersistentCache(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	assert.Equal(t, DefaultTechsCacheTTL, b.getCacheTTL(TechsCache))
+	assert.Equal(t, time.Duration(0), b.getCacheTTL(PlanetsCache))
+
+	b.setCachedTechs(123, cachedTechs{})
+	b.techsCacheMu.Lock()
+	b.techsCache[123] = techsCacheEntry{updatedAt: time.Now().Add(-DefaultTechsCacheTTL - time.Second)}
+	b.techsCacheMu.Unlock()
+	_, ok := b.cachedTechs(123)
+	assert.False(t, ok) // expires without TTL set
+
+	// Entries served from the persistent cache report when they were fetched
+	cache := NewMemoryCache()
+	b.SetCache(cache)
+	cacheSet(b, TechsCache, "456", cachedTechs{ResourcesBuildings: ogame.ResourcesBuildings{MetalMine: 3}})
+	assert.True(t, b.TechsUpdatedAt(456).IsZero())
+	techs, ok := b.cachedTechs(456)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), techs.ResourcesBuildings.MetalMine)
+	_, storedAt, _, _ := cache.Get(TechsCache, b.cacheKey("456"))
+	assert.Equal(t, storedAt, b.TechsUpdatedAt(456))
+}
+
+func TestAbandonReasons(t *testing.T) {
+	coord := ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}
+	assert.Equal(t, []string{}, abandonReasons(coord, 2, ogame.ShipsInfos{SolarSatellite: 10}, ogame.Resources{Metal: 500}, nil, 1000))
+
+	events := []ogame.FleetEvent{
+		{ID: 1, Relation: ogame.FriendlyEvent, MissionType: ogame.Transport, Destination: ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.MoonType}},
+		{ID: 2, Relation: ogame.FriendlyEvent, MissionType: ogame.Attack, ReturnFlight: true, Origin: coord},
+		{ID: 3, Relation: ogame.HostileEvent, MissionType: ogame.Attack, Destination: coord},
+		{ID: 4, Relation: ogame.FriendlyEvent, MissionType: ogame.RecycleDebrisField, Destination: coord.Debris()},
+	}
+	reasons := abandonReasons(coord, 1, ogame.ShipsInfos{LargeCargo: 5}, ogame.Resources{Metal: 5000}, events, 1000)
+	assert.Equal(t, []string{
+		"last planet",
+		"5 ships stationed",
+		"5000 resources stored",
+		"fleet 1 " + ogame.Transport.String() + " coming",
+		"fleet 2 " + ogame.Attack.String() + " coming",
+	}, reasons)
+
+	assert.Equal(t, int64(DefaultAbandonMaxResources), abandonMaxResources(getOptions()))
+	assert.Equal(t, int64(0), abandonMaxResources(getOptions(AbandonMaxResources(0))))
+	assert.Equal(t, []string{"1 resources stored"}, abandonReasons(coord, 2, ogame.ShipsInfos{}, ogame.Resources{Metal: 1}, nil, 0))
+
+	err := ogame.NewAbandonNotSafeError(coord, reasons[:1])
+	assert.True(t, errors.Is(err, ogame.ErrAbandonNotSafe))
+	assert.EqualError(t, err, "abandon not safe for [P:1:2:3]: last planet")
+}
+
+func TestGetPlanetInfos(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	pageHTML, _ := ioutil.ReadFile("../../samples/v7.6.6/en/overview_with_active_items.html")
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK, Body: pageHTML}, nil
+		}
+	})
+	celestial, err := b.getPlanetInfos(33765791)
+	assert.NoError(t, err)
+	moon, ok := celestial.(*Moon)
+	assert.True(t, ok)
+	assert.Equal(t, int64(9), moon.GetFields().Total)
+	assert.NotEqual(t, ogame.Temperature{}, moon.GetTemperature())
+	assert.Equal(t, 8, len(b.GetCachedPlanets()))
+
+	_, err = b.getPlanetInfos(123)
+	assert.Error(t, err)
+}
+
+func TestServerDataSettings(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	assert.True(t, b.serverData.IsMarketplaceEnabled())
+	assert.False(t, b.ProbeRaids())
+
+	b.serverData.Version = "10.4.0"
+	b.serverData.ProbeCargo = 5
+	assert.True(t, b.ProbeRaids())
+	assert.True(t, b.serverData.IsMarketplaceEnabled())
+	disabled := false
+	b.serverData.MarketplaceEnabled = &disabled
+	assert.Equal(t, int64(5), b.CargoCapacity(ogame.ShipsInfos{EspionageProbe: 1}))
+	_, err := b.GetMarketplaceOffers(ogame.MarketplaceBuyingTab, 0)
+	assert.ErrorIs(t, err, ogame.ErrMarketplaceDisabled)
+	assert.ErrorIs(t, b.BuyMarketplace(1, 0), ogame.ErrMarketplaceDisabled)
+}
+
+func TestDistanceIgnoredSystems(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	b.serverData.Galaxies = 9
+	b.serverData.Systems = 499
+	b.serverData.DonutSystem = true
+	b.SetCache(NewMemoryCache())
+	origin := ogame.Coordinate{Galaxy: 1, System: 10, Position: 8, Type: ogame.PlanetType}
+	destination := ogame.Coordinate{Galaxy: 1, System: 13, Position: 8, Type: ogame.PlanetType}
+	active := ogame.SystemInfos{}
+	active.Tmpplanets[0] = &ogame.PlanetInfos{}
+	inactive := ogame.SystemInfos{}
+	inactive.Tmpplanets[0] = &ogame.PlanetInfos{Inactive: true}
+	cacheSet(b, GalaxyCache, galaxyCacheKey(1, 11), ogame.SystemInfos{})
+	cacheSet(b, GalaxyCache, galaxyCacheKey(1, 12), inactive)
+	cacheSet(b, GalaxyCache, galaxyCacheKey(1, 498), ogame.SystemInfos{})
+	cacheSet(b, GalaxyCache, galaxyCacheKey(1, 499), active)
+
+	assert.Equal(t, int64(2700+95*3), b.Distance(origin, destination))
+	b.serverData.FleetIgnoreEmptySystems = true
+	assert.Equal(t, int64(2700+95*2), b.Distance(origin, destination))
+	b.serverData.FleetIgnoreInactiveSystems = true
+	assert.Equal(t, int64(2700+95*1), b.Distance(origin, destination))
+	// Around the donut 12 systems, 1 to 9 are unknown, 499 is active and 498 is empty
+	assert.Equal(t, int64(2700+95*11), b.Distance(origin, ogame.Coordinate{Galaxy: 1, System: 497, Position: 8, Type: ogame.PlanetType}))
+	// Never less than one system
+	assert.Equal(t, int64(2700+95*1), b.Distance(ogame.Coordinate{Galaxy: 1, System: 11, Position: 8, Type: ogame.PlanetType}, destination))
+	secs, _ := b.CalcFlightTime(origin, destination, 1, ogame.ShipsInfos{SmallCargo: 1}, ogame.Transport)
+	expected, _ := calcFlightTimeForDistance(2700+95*1, b.serverData.GlobalDeuteriumSaveFactor, 1,
+		GetFleetSpeedForMission(b.serverData, ogame.Transport), ogame.ShipsInfos{SmallCargo: 1}, b.GetCachedResearch(), b.characterClass)
+	assert.Equal(t, expected, secs)
+}
+
+func TestQueueKindOf(t *testing.T) {
+	tests := []struct {
+		id       ogame.ID
+		expected int
+	}{
+		{ogame.MetalMineID, queueKindBuilding},
+		{ogame.RoboticsFactoryID, queueKindBuilding},
+		{ogame.EnergyTechnologyID, queueKindResearch},
+		{ogame.ResidentialSectorID, queueKindLfBuilding},
+		{ogame.IntergalacticEnvoysID, queueKindLfResearch},
+		{ogame.LightFighterID, -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, queueKindOf(tt.id), tt.id.String())
+	}
+}
+
+func TestBuildProductionWithProgress(t *testing.T) {
+	b, _ := NewNoLogin("user", "pass", "", "", "s1", "en", "", 0, nil)
+	atomic.StoreInt32(&b.isLoggedInAtom, 1)
+	b.serverURL = "https://s1-en.ogame.gameforge.com"
+	pageHTML, _ := ioutil.ReadFile("../../samples/v7.1/en/shipyard_queue.html")
+	b.Use(func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK, Body: pageHTML}, nil
+		}
+	})
+	err := b.buildProductionWithProgress(context.Background(), 123, ogame.MetalMineID, 10, nil)
+	assert.EqualError(t, err, "invalid id "+ogame.MetalMineID.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = b.buildProductionWithProgress(ctx, 123, ogame.LightFighterID, 10, nil)
+	assert.Equal(t, context.Canceled, err)
+
+	// the queue of the sample page does not change, the chunk is reported then detected as not queued
+	var progress []ogame.BuildProgress
+	err = b.buildProductionWithProgress(context.Background(), 123, ogame.LightFighterID, 10, func(p ogame.BuildProgress) {
+		progress = append(progress, p)
+	})
+	assert.True(t, errors.Is(err, errChunkNotQueued))
+	assert.Equal(t, 1, len(progress))
+	assert.Equal(t, int64(10), progress[0].Ordered)
+}
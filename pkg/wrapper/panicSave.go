@@ -0,0 +1,152 @@
+package wrapper
+
+import (
+	"errors"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// PanicSaveDestination kind of destination PanicSave can send the fleet to
+type PanicSaveDestination int
+
+// PanicSave destinations
+const (
+	PanicSaveOwnMoon PanicSaveDestination = iota // deploy to another moon of the player
+	PanicSaveDeploy                              // deploy to any other celestial of the player
+	PanicSaveHarvest                             // recycle the debris field of the celestial position, needs recyclers or pathfinders
+)
+
+// PanicSavePolicy how PanicSave gets the fleet away from an attack
+type PanicSavePolicy struct {
+	Destinations  []PanicSaveDestination // tried in order, the first one giving an affordable mission is used
+	Speed         ogame.Speed            // 10% if not set, the cheapest
+	KeepDeuterium int64                  // deuterium left on the celestial, on top of the fuel
+}
+
+// DefaultPanicSavePolicy tries an own moon, then any own celestial, then the debris field, at 10% speed
+var DefaultPanicSavePolicy = PanicSavePolicy{
+	Destinations: []PanicSaveDestination{PanicSaveOwnMoon, PanicSaveDeploy, PanicSaveHarvest},
+	Speed:        ogame.TenPercent,
+}
+
+// ErrNoPanicSaveDestination returned by PanicSave when none of the policy destinations can be reached
+var ErrNoPanicSaveDestination = errors.New("no panic save destination")
+
+type panicSaveMission struct {
+	where   ogame.Coordinate
+	mission ogame.MissionID
+}
+
+// panicSaveMissions returns the missions matching destination, from origin
+func panicSaveMissions(origin ogame.Coordinate, celestials []ogame.Coordinate, ships ogame.ShipsInfos, destination PanicSaveDestination) []panicSaveMission {
+	out := make([]panicSaveMission, 0)
+	switch destination {
+	case PanicSaveOwnMoon, PanicSaveDeploy:
+		for _, coord := range celestials {
+			if coord.Equal(origin) || (destination == PanicSaveOwnMoon && !coord.IsMoon()) {
+				continue
+			}
+			out = append(out, panicSaveMission{where: coord, mission: ogame.Park})
+		}
+	case PanicSaveHarvest:
+		if ships.Recycler > 0 || ships.Pathfinder > 0 {
+			out = append(out, panicSaveMission{where: origin.Debris(), mission: ogame.RecycleDebrisField})
+		}
+	}
+	return out
+}
+
+// panicSaveOrder fleet sent by panicSave
+type panicSaveOrder struct {
+	ships   ogame.ShipsInfos
+	speed   ogame.Speed
+	mission panicSaveMission
+	payload ogame.Resources
+}
+
+func (b *OGame) panicSave(celestialID ogame.CelestialID, policy PanicSavePolicy) (ogame.Fleet, error) {
+	order, err := b.panicSaveOrder(celestialID, policy)
+	if err != nil {
+		return ogame.Fleet{}, err
+	}
+	return b.sendFleet(celestialID, order.ships.ToQuantifiables(), order.speed, order.mission.where, order.mission.mission, order.payload, 0, 0, false)
+}
+
+// panicSaveOrder picks the mission of panicSave, own celestials targeted by an attack are not used as destination
+func (b *OGame) panicSaveOrder(celestialID ogame.CelestialID, policy PanicSavePolicy) (panicSaveOrder, error) {
+	origin := b.getCachedCelestial(celestialID)
+	if origin == nil {
+		return panicSaveOrder{}, ogame.ErrInvalidPlanetID
+	}
+	ships, err := b.getShips(celestialID)
+	if err != nil {
+		return panicSaveOrder{}, err
+	}
+	ships.SolarSatellite = 0
+	ships.Crawler = 0
+	if !ships.HasFlyableShips() {
+		return panicSaveOrder{}, ogame.ErrNoShipSelected
+	}
+	resources, err := b.getResources(celestialID)
+	if err != nil {
+		return panicSaveOrder{}, err
+	}
+	attacks, err := b.getAttacks()
+	if err != nil {
+		return panicSaveOrder{}, err
+	}
+	speed := policy.Speed
+	if speed == 0 {
+		speed = ogame.TenPercent
+	}
+	celestials := make([]ogame.Coordinate, 0)
+	for _, celestial := range b.getCachedCelestials() {
+		coord := celestial.GetCoordinate()
+		if !isAttacked(attacks, coord) {
+			celestials = append(celestials, coord)
+		}
+	}
+	researches := b.getCachedResearch()
+	availableDeuterium := resources.Deuterium - policy.KeepDeuterium
+
+	// Cheapest mission of the first destination kind that can be afforded
+	var best *panicSaveMission
+	var bestFuel int64
+	for _, destination := range policy.Destinations {
+		for _, m := range panicSaveMissions(origin.GetCoordinate(), celestials, ships, destination) {
+			_, fuel := b.calcFlightTime(origin.GetCoordinate(), m.where, float64(speed)/10, ships, m.mission, researches)
+			if fuel > availableDeuterium || (best != nil && fuel >= bestFuel) {
+				continue
+			}
+			m := m
+			best, bestFuel = &m, fuel
+		}
+		if best != nil {
+			break
+		}
+	}
+	if best == nil {
+		return panicSaveOrder{}, ErrNoPanicSaveDestination
+	}
+
+	// Load as much resources as the ships can carry, deuterium first, keeping the fuel
+	cargo := b.cargoCapacity(ships)
+	var payload ogame.Resources
+	payload.Deuterium = utils.MaxInt(0, utils.MinInt(cargo, availableDeuterium-bestFuel))
+	cargo -= payload.Deuterium
+	payload.Crystal = utils.MinInt(cargo, resources.Crystal)
+	cargo -= payload.Crystal
+	payload.Metal = utils.MinInt(cargo, resources.Metal)
+	return panicSaveOrder{ships: ships, speed: speed, mission: *best, payload: payload}, nil
+}
+
+// isAttacked returns either or not one of the attacks targets coord
+func isAttacked(attacks []ogame.AttackEvent, coord ogame.Coordinate) bool {
+	for _, attack := range attacks {
+		if attack.Destination.Equal(coord) {
+			return true
+		}
+	}
+	return false
+}
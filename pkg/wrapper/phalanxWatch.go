@@ -0,0 +1,196 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// PhalanxEventType kind of event emitted by PhalanxWatch
+type PhalanxEventType int
+
+// Phalanx events
+const (
+	PhalanxFleetLaunched PhalanxEventType = iota // a fleet left the watched planet
+	PhalanxFleetRecalled                         // a fleet of the watched planet was recalled before reaching its destination
+)
+
+// PhalanxEvent fleet movement change detected between two scans of a target
+type PhalanxEvent struct {
+	Type   PhalanxEventType
+	Target ogame.Coordinate
+	Fleet  ogame.Fleet
+}
+
+// PhalanxWatch repeatedly scans targets with the sensor phalanx of a moon and reports
+// the fleets launched or recalled from the targets between two scans.
+//
+//	watch := wrapper.NewPhalanxWatch(bot, moonID, []ogame.Coordinate{{1, 2, 3, ogame.PlanetType}}, 5*time.Minute)
+//	watch.OnEvent(func(e wrapper.PhalanxEvent) { fmt.Println(e.Type, e.Fleet.Destination) })
+//	watch.Start()
+//	defer watch.Stop()
+type PhalanxWatch struct {
+	b              Wrapper
+	moonID         ogame.MoonID
+	targets        []ogame.Coordinate
+	interval       time.Duration
+	scans          map[ogame.Coordinate][]ogame.Fleet
+	mu             sync.Mutex
+	cancel         context.CancelFunc
+	callbacks      []func(PhalanxEvent)
+	errorCallbacks []func(ogame.Coordinate, error)
+}
+
+// NewPhalanxWatch creates a watch scanning targets from moonID every interval
+func NewPhalanxWatch(b Wrapper, moonID ogame.MoonID, targets []ogame.Coordinate, interval time.Duration) *PhalanxWatch {
+	return &PhalanxWatch{
+		b:        b,
+		moonID:   moonID,
+		targets:  targets,
+		interval: interval,
+		scans:    make(map[ogame.Coordinate][]ogame.Fleet),
+	}
+}
+
+// OnEvent registers a callback executed when a fleet is launched or recalled from a target
+func (w *PhalanxWatch) OnEvent(clb func(PhalanxEvent)) *PhalanxWatch {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, clb)
+	return w
+}
+
+// OnError registers a callback executed when a target cannot be scanned
+func (w *PhalanxWatch) OnError(clb func(ogame.Coordinate, error)) *PhalanxWatch {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.errorCallbacks = append(w.errorCallbacks, clb)
+	return w
+}
+
+// Start starts scanning in the background, until Stop is called
+func (w *PhalanxWatch) Start() {
+	w.mu.Lock()
+	if w.cancel != nil {
+		w.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.mu.Unlock()
+	go func() {
+		for {
+			w.Sweep()
+			select {
+			case <-time.After(w.b.HumanizeInterval(w.interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background scans
+func (w *PhalanxWatch) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
+
+// Sweep scans every target once, as long as the moon has enough deuterium
+func (w *PhalanxWatch) Sweep() {
+	if !w.b.IsLoggedIn() {
+		return
+	}
+	resources, err := w.b.GetResources(w.moonID.Celestial())
+	if err != nil {
+		w.emitError(ogame.Coordinate{}, err)
+		return
+	}
+	deuterium := resources.Deuterium
+	for _, target := range w.targets {
+		if deuterium < ogame.SensorPhalanx.ScanConsumption() {
+			w.emitError(target, errors.New("not enough deuterium"))
+			continue
+		}
+		fleets, err := w.b.Phalanx(w.moonID, target)
+		if err != nil {
+			w.emitError(target, err)
+			continue
+		}
+		deuterium -= ogame.SensorPhalanx.ScanConsumption()
+		w.mu.Lock()
+		prev, scanned := w.scans[target]
+		w.scans[target] = fleets
+		callbacks := w.callbacks
+		w.mu.Unlock()
+		if !scanned {
+			continue
+		}
+		launched, recalled := diffPhalanxScans(target, prev, fleets, time.Now())
+		for _, fleet := range launched {
+			for _, clb := range callbacks {
+				clb(PhalanxEvent{Type: PhalanxFleetLaunched, Target: target, Fleet: fleet})
+			}
+		}
+		for _, fleet := range recalled {
+			for _, clb := range callbacks {
+				clb(PhalanxEvent{Type: PhalanxFleetRecalled, Target: target, Fleet: fleet})
+			}
+		}
+	}
+}
+
+func (w *PhalanxWatch) emitError(target ogame.Coordinate, err error) {
+	w.mu.Lock()
+	callbacks := w.errorCallbacks
+	w.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(target, err)
+	}
+}
+
+// phalanxFleetKey identifies a fleet across scans, the phalanx does not give fleet ids
+type phalanxFleetKey struct {
+	Mission      ogame.MissionID
+	ReturnFlight bool
+	Origin       ogame.Coordinate
+	Destination  ogame.Coordinate
+	ArrivalTime  int64
+}
+
+func newPhalanxFleetKey(fleet ogame.Fleet) phalanxFleetKey {
+	return phalanxFleetKey{fleet.Mission, fleet.ReturnFlight, fleet.Origin, fleet.Destination, fleet.ArrivalTime.Unix()}
+}
+
+// diffPhalanxScans returns the outgoing fleets of target that appeared since the previous scan,
+// and the ones that disappeared before reaching their destination.
+func diffPhalanxScans(target ogame.Coordinate, prev, curr []ogame.Fleet, now time.Time) (launched, recalled []ogame.Fleet) {
+	isOutgoing := func(fleet ogame.Fleet) bool {
+		return !fleet.ReturnFlight && fleet.Origin.Equal(target)
+	}
+	prevKeys := make(map[phalanxFleetKey]struct{})
+	for _, fleet := range prev {
+		prevKeys[newPhalanxFleetKey(fleet)] = struct{}{}
+	}
+	currKeys := make(map[phalanxFleetKey]struct{})
+	for _, fleet := range curr {
+		key := newPhalanxFleetKey(fleet)
+		currKeys[key] = struct{}{}
+		if _, ok := prevKeys[key]; !ok && isOutgoing(fleet) {
+			launched = append(launched, fleet)
+		}
+	}
+	for _, fleet := range prev {
+		if _, ok := currKeys[newPhalanxFleetKey(fleet)]; !ok && isOutgoing(fleet) && fleet.ArrivalTime.After(now) {
+			recalled = append(recalled, fleet)
+		}
+	}
+	return
+}
@@ -51,8 +51,8 @@ func (p Planet) GetFacilities(options ...Option) (ogame.Facilities, error) {
 	return p.ogame.GetFacilities(p.ID.Celestial(), options...)
 }
 
-// Build builds any ogame objects (building, technology, ship, defence)
-func (p Planet) Build(id ogame.ID, nbr int64) error {
+// Build builds any ogame objects (building, technology, ship, defence), and returns when the construction will be done
+func (p Planet) Build(id ogame.ID, nbr int64) (ogame.ConstructionETA, error) {
 	return p.ogame.Build(ogame.CelestialID(p.ID), id, nbr)
 }
 
@@ -67,7 +67,7 @@ func (p Planet) BuildCancelable(id ogame.ID) error {
 }
 
 // BuildBuilding ensure what is being built is a building
-func (p Planet) BuildBuilding(buildingID ogame.ID) error {
+func (p Planet) BuildBuilding(buildingID ogame.ID) (ogame.ConstructionETA, error) {
 	return p.ogame.BuildBuilding(ogame.CelestialID(p.ID), buildingID)
 }
 
@@ -82,7 +82,7 @@ func (p Planet) BuildShips(shipID ogame.ID, nbr int64) error {
 }
 
 // BuildTechnology ensure that we're trying to build a technology
-func (p Planet) BuildTechnology(technologyID ogame.ID) error {
+func (p Planet) BuildTechnology(technologyID ogame.ID) (ogame.ConstructionETA, error) {
 	return p.ogame.BuildTechnology(p.ID.Celestial(), technologyID)
 }
 
@@ -167,3 +167,8 @@ func (p Planet) GetLfResearch(options ...Option) (ogame.LfResearches, error) {
 func (p Planet) GetTechs() (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
 	return p.ogame.GetTechs(p.ID.Celestial())
 }
+
+// Rename renames the planet
+func (p Planet) Rename(newName string) error {
+	return p.ogame.RenamePlanet(p.ID.Celestial(), newName)
+}
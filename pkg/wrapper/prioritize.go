@@ -1,12 +1,14 @@
 package wrapper
 
 import (
+	"context"
 	"net/http"
 	"net/url"
-	"sync/atomic"
+	"sync"
 	"time"
 
 	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
 )
 
 // Prioritize ...
@@ -15,13 +17,25 @@ type Prioritize struct {
 	initiator    string
 	name         string
 	taskIsDoneCh chan struct{}
-	isTx         int32
+	taskHandle   *taskRunner.TaskHandle
+	txMu         sync.Mutex // protects isTx, released, pending and pendingDone
+	isTx         int32      // depth of the calls holding the lock, the transaction itself included
+	released     bool       // the lock was released because the transaction expired
+	pending      int32      // calls that were running when the transaction expired
+	pendingDone  chan struct{}
+	closeDoneCh  sync.Once
+	stopExpiry   context.CancelFunc
+	cancelTx     context.CancelFunc // cancels the requests of the transaction
 }
 
 func (b *Prioritize) SetTaskDoneCh(ch chan struct{}) {
 	b.taskIsDoneCh = ch
 }
 
+func (b *Prioritize) SetTaskHandle(h *taskRunner.TaskHandle) {
+	b.taskHandle = h
+}
+
 // SetInitiator ...
 func (b *Prioritize) SetInitiator(initiator string) Prioritizable {
 	b.initiator = initiator
@@ -43,23 +57,125 @@ func (b *Prioritize) BeginNamed(name string) Prioritizable {
 
 // Done terminate the transaction, release the lock.
 func (b *Prioritize) Done() {
+	b.txMu.Lock()
+	if b.released {
+		b.txMu.Unlock()
+		return // the lock was already released when the transaction expired
+	}
+	b.txMu.Unlock()
 	b.done()
 }
 
+// BeginWithTimeout begins a new transaction that releases the lock by itself after d if "Done" was not called.
+// Calls made after the expiration are no longer part of the transaction.
+func (b *Prioritize) BeginWithTimeout(d time.Duration) Prioritizable {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	return b.beginWithContext(ctx, cancel)
+}
+
+// TxCtx locks the bot during the transaction, the lock is released when clb returns, or when ctx is done
+func (b *Prioritize) TxCtx(ctx context.Context, clb func(Prioritizable) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	tx := b.beginWithContext(ctx, cancel)
+	defer tx.Done()
+	return clb(tx)
+}
+
+func (b *Prioritize) beginWithContext(ctx context.Context, cancel context.CancelFunc) *Prioritize {
+	tx := b.begin("Tx")
+	tx.stopExpiry = cancel
+	parent := context.Background()
+	if tx.taskHandle != nil {
+		parent = tx.taskHandle.Context()
+	}
+	var txCtx context.Context
+	txCtx, tx.cancelTx = context.WithCancel(parent)
+	tx.bot.setTaskCtx(txCtx)
+	go func() {
+		<-ctx.Done()
+		tx.expire()
+	}()
+	return tx
+}
+
+// expire releases the lock of a transaction that is still running.
+// The requests of a call running at that time are cancelled, the lock is released once that call returns.
+func (b *Prioritize) expire() {
+	b.txMu.Lock()
+	if b.released || b.isTx <= 0 {
+		b.txMu.Unlock()
+		return // transaction is done
+	}
+	b.released = true
+	b.pending = b.isTx - 1
+	b.isTx = 0
+	pendingDone := make(chan struct{})
+	if b.pending == 0 {
+		close(pendingDone)
+	}
+	b.pendingDone = pendingDone
+	b.txMu.Unlock()
+	b.cancelTx()
+	<-pendingDone
+	b.bot.warn("transaction " + b.name + " expired, releasing the lock")
+	b.bot.txExpired(b.name)
+	b.bot.setTaskCtx(nil)
+	b.bot.botUnlock(b.name)
+	b.closeDoneCh.Do(func() { close(b.taskIsDoneCh) })
+}
+
 func (b *Prioritize) begin(name string) *Prioritize {
-	if atomic.AddInt32(&b.isTx, 1) == 1 {
+	b.txMu.Lock()
+	if b.released {
+		b.txMu.Unlock()
+		// Transaction expired, every call takes the lock by itself and is still serialized with the other tasks
+		b.bot.botLock(b.name)
+		return b
+	}
+	b.isTx++
+	first := b.isTx == 1
+	b.txMu.Unlock()
+	if first {
 		if b.initiator != "" {
 			b.name = b.initiator + ":"
 		}
 		b.name += name
 		b.bot.botLock(b.name)
+		if b.taskHandle != nil {
+			b.taskHandle.SetName(b.name)
+			b.bot.setTaskCtx(b.taskHandle.Context())
+		}
 	}
 	return b
 }
 
 func (b *Prioritize) done() {
-	if atomic.AddInt32(&b.isTx, -1) == 0 {
-		defer close(b.taskIsDoneCh)
+	b.txMu.Lock()
+	if b.released {
+		if b.pending > 0 {
+			b.pending--
+			if b.pending == 0 {
+				close(b.pendingDone)
+			}
+			b.txMu.Unlock()
+			return // call started before the expiration, expire releases the lock
+		}
+		b.txMu.Unlock()
+		b.bot.botUnlock(b.name)
+		return
+	}
+	b.isTx--
+	last := b.isTx == 0
+	b.txMu.Unlock()
+	if last {
+		defer b.closeDoneCh.Do(func() { close(b.taskIsDoneCh) })
+		if b.stopExpiry != nil {
+			b.stopExpiry()
+		}
+		b.bot.setTaskCtx(nil)
+		if b.cancelTx != nil {
+			b.cancelTx()
+		}
 		b.bot.botUnlock(b.name)
 	}
 }
@@ -103,6 +219,13 @@ func (b *Prioritize) Logout() {
 	b.bot.logout()
 }
 
+// RefreshToken posts a new session to gameforge to get a fresh bearer token
+func (b *Prioritize) RefreshToken() (string, error) {
+	b.begin("RefreshToken")
+	defer b.done()
+	return b.bot.refreshToken()
+}
+
 // GetPageContent gets the html for a specific ogame page
 func (b *Prioritize) GetPageContent(vals url.Values) ([]byte, error) {
 	b.begin("GetPageContent")
@@ -178,10 +301,10 @@ func (b *Prioritize) RecruitOfficer(typ, days int64) error {
 }
 
 // Abandon a planet. Warning: this is irreversible
-func (b *Prioritize) Abandon(v any) error {
+func (b *Prioritize) Abandon(v any, opts ...Option) error {
 	b.begin("Abandon")
 	defer b.done()
-	return b.bot.abandon(v)
+	return b.bot.abandon(v, opts...)
 }
 
 // GetCelestial get the player's planet/moon using the coordinate
@@ -327,11 +450,11 @@ func (b *Prioritize) GetSlots() ogame.Slots {
 	return b.bot.getSlots()
 }
 
-// Build builds any ogame objects (building, technology, ship, defence)
-func (b *Prioritize) Build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) error {
+// Build builds any ogame objects (building, technology, ship, defence), and returns when the construction will be done
+func (b *Prioritize) Build(celestialID ogame.CelestialID, id ogame.ID, nbr int64) (ogame.ConstructionETA, error) {
 	b.begin("Build")
 	defer b.done()
-	return b.bot.build(celestialID, id, nbr)
+	return b.bot.withConstructionETA(celestialID, id, nbr, func() error { return b.bot.build(celestialID, id, nbr) })
 }
 
 // TechnologyDetails extract details from ajax window when clicking supplies/facilities/techs/lf...
@@ -341,7 +464,7 @@ func (b *Prioritize) TechnologyDetails(celestialID ogame.CelestialID, id ogame.I
 	return b.bot.technologyDetails(celestialID, id)
 }
 
-// TearDown tears down any ogame building
+// TearDown tears down any ogame building, ships, defenses and researches return a *ogame.TearDownNotAllowedError
 func (b *Prioritize) TearDown(celestialID ogame.CelestialID, id ogame.ID) error {
 	b.begin("TearDown")
 	defer b.done()
@@ -363,10 +486,10 @@ func (b *Prioritize) BuildProduction(celestialID ogame.CelestialID, id ogame.ID,
 }
 
 // BuildBuilding ensure what is being built is a building
-func (b *Prioritize) BuildBuilding(celestialID ogame.CelestialID, buildingID ogame.ID) error {
+func (b *Prioritize) BuildBuilding(celestialID ogame.CelestialID, buildingID ogame.ID) (ogame.ConstructionETA, error) {
 	b.begin("BuildBuilding")
 	defer b.done()
-	return b.bot.buildBuilding(celestialID, buildingID)
+	return b.bot.withConstructionETA(celestialID, buildingID, 0, func() error { return b.bot.buildBuilding(celestialID, buildingID) })
 }
 
 // BuildDefense builds a defense unit
@@ -412,10 +535,10 @@ func (b *Prioritize) CancelResearch(celestialID ogame.CelestialID) error {
 }
 
 // BuildTechnology ensure that we're trying to build a technology
-func (b *Prioritize) BuildTechnology(celestialID ogame.CelestialID, technologyID ogame.ID) error {
+func (b *Prioritize) BuildTechnology(celestialID ogame.CelestialID, technologyID ogame.ID) (ogame.ConstructionETA, error) {
 	b.begin("BuildTechnology")
 	defer b.done()
-	return b.bot.buildTechnology(celestialID, technologyID)
+	return b.bot.withConstructionETA(celestialID, technologyID, 0, func() error { return b.bot.buildTechnology(celestialID, technologyID) })
 }
 
 // GetResources gets user resources
@@ -476,18 +599,19 @@ func (b *Prioritize) GetCombatReportSummaryFor(coord ogame.Coordinate) (ogame.Co
 	return b.bot.getCombatReportFor(coord)
 }
 
-// GetEspionageReportFor gets the latest espionage report for a given coordinate
-func (b *Prioritize) GetEspionageReportFor(coord ogame.Coordinate) (ogame.EspionageReport, error) {
+// GetEspionageReportFor gets the latest espionage report for a given coordinate.
+// With MaxAge, an older report is returned along with ogame.ErrReportTooOld (AutoSpy is handled by OGame.GetEspionageReportFor).
+func (b *Prioritize) GetEspionageReportFor(coord ogame.Coordinate, opts ...Option) (ogame.EspionageReport, error) {
 	b.begin("GetEspionageReportFor")
 	defer b.done()
-	return b.bot.getEspionageReportFor(coord)
+	return b.bot.getEspionageReportFor(coord, opts...)
 }
 
-// GetEspionageReportMessages gets the summary of each espionage reports
-func (b *Prioritize) GetEspionageReportMessages() ([]ogame.EspionageReportSummary, error) {
+// GetEspionageReportMessages gets the summary of each espionage reports, use Concurrency to fetch the pages in parallel
+func (b *Prioritize) GetEspionageReportMessages(opts ...Option) ([]ogame.EspionageReportSummary, error) {
 	b.begin("GetEspionageReportMessages")
 	defer b.done()
-	return b.bot.getEspionageReportMessages()
+	return b.bot.getEspionageReportMessages(opts...)
 }
 
 // CollectAllMarketplaceMessages collect all marketplace messages
@@ -505,11 +629,11 @@ func (b *Prioritize) CollectMarketplaceMessage(msg ogame.MarketplaceMessage) err
 	return err
 }
 
-// GetExpeditionMessages gets the expedition messages
-func (b *Prioritize) GetExpeditionMessages() ([]ogame.ExpeditionMessage, error) {
+// GetExpeditionMessages gets the expedition messages, use Concurrency to fetch the pages in parallel
+func (b *Prioritize) GetExpeditionMessages(opts ...Option) ([]ogame.ExpeditionMessage, error) {
 	b.begin("GetExpeditionMessages")
 	defer b.done()
-	return b.bot.getExpeditionMessages()
+	return b.bot.getExpeditionMessages(opts...)
 }
 
 // GetExpeditionMessageAt gets the expedition message for time t
@@ -547,12 +671,12 @@ func (b *Prioritize) GetResourcesProductions(planetID ogame.PlanetID) (ogame.Res
 	return b.bot.getResourcesProductions(planetID)
 }
 
-// GetResourcesProductionsLight gets the planet resources production
+// GetResourcesProductionsLight gets the planet resources production, including the crawlers, officers and class bonuses
 func (b *Prioritize) GetResourcesProductionsLight(resBuildings ogame.ResourcesBuildings, researches ogame.Researches,
-	resSettings ogame.ResourceSettings, temp ogame.Temperature) ogame.Resources {
+	resSettings ogame.ResourceSettings, temp ogame.Temperature, bonuses ogame.ProductionBonuses) ogame.Resources {
 	b.begin("GetResourcesProductionsLight")
 	defer b.done()
-	return getResourcesProductionsLight(resBuildings, researches, resSettings, temp, b.bot.serverData.Speed)
+	return getResourcesProductionsLight(resBuildings, researches, resSettings, temp, bonuses, b.bot.serverData.Speed)
 }
 
 // FlightTime calculate flight time and fuel needed
@@ -560,9 +684,7 @@ func (b *Prioritize) FlightTime(origin, destination ogame.Coordinate, speed ogam
 	b.begin("FlightTime")
 	defer b.done()
 	researches := b.bot.getCachedResearch()
-	return CalcFlightTime(origin, destination, b.bot.serverData.Galaxies, b.bot.serverData.Systems,
-		b.bot.serverData.DonutGalaxy, b.bot.serverData.DonutSystem, b.bot.serverData.GlobalDeuteriumSaveFactor,
-		float64(speed)/10, GetFleetSpeedForMission(b.bot.serverData, missionID), ships, researches, b.bot.characterClass)
+	return b.bot.calcFlightTime(origin, destination, float64(speed)/10, ships, missionID, researches)
 }
 
 // Phalanx scan a coordinate from a moon to get fleets information
@@ -652,6 +774,20 @@ func (b *Prioritize) Highscore(category, typ, page int64) (ogame.Highscore, erro
 	return b.bot.highscore(category, typ, page)
 }
 
+// GetFullHighscore gets all the pages of a highscore
+func (b *Prioritize) GetFullHighscore(category, typ int64) (ogame.FullHighscore, error) {
+	b.begin("GetFullHighscore")
+	defer b.done()
+	return b.bot.getFullHighscore(category, typ)
+}
+
+// GetHighscorePosition gets the highscore position of a player
+func (b *Prioritize) GetHighscorePosition(category, typ, playerID int64) (ogame.HighscorePlayer, error) {
+	b.begin("GetHighscorePosition")
+	defer b.done()
+	return b.bot.getHighscorePosition(category, typ, playerID)
+}
+
 // GetAllResources ...
 func (b *Prioritize) GetAllResources() (map[ogame.CelestialID]ogame.Resources, error) {
 	b.begin("GetAllResources")
@@ -728,3 +864,328 @@ func (b *Prioritize) GetLfResearch(celestialID ogame.CelestialID, options ...Opt
 	defer b.done()
 	return b.bot.getLfResearch(celestialID, options...)
 }
+
+// RenamePlanet renames a planet or a moon
+func (b *Prioritize) RenamePlanet(celestialID ogame.CelestialID, newName string) error {
+	b.begin("RenamePlanet")
+	defer b.done()
+	return b.bot.renamePlanet(celestialID, newName)
+}
+
+// RelocatePlanet moves a planet to an empty destination, the move happens after a 24h countdown
+func (b *Prioritize) RelocatePlanet(planetID ogame.PlanetID, dest ogame.Coordinate) (ogame.PlanetRelocation, error) {
+	b.begin("RelocatePlanet")
+	defer b.done()
+	return b.bot.relocatePlanet(planetID, dest)
+}
+
+// CancelPlanetRelocation cancels an in-progress planet relocation
+func (b *Prioritize) CancelPlanetRelocation(planetID ogame.PlanetID) error {
+	b.begin("CancelPlanetRelocation")
+	defer b.done()
+	return b.bot.cancelPlanetRelocation(planetID)
+}
+
+// GetPlanetRelocation gets the in-progress relocation of a planet, if any
+func (b *Prioritize) GetPlanetRelocation(planetID ogame.PlanetID) (ogame.PlanetRelocation, error) {
+	b.begin("GetPlanetRelocation")
+	defer b.done()
+	return b.bot.getPlanetRelocation(planetID)
+}
+
+// GetOfficers gets the hiring state and expiry date of all officers
+func (b *Prioritize) GetOfficers() ([]ogame.Officer, error) {
+	b.begin("GetOfficers")
+	defer b.done()
+	return b.bot.getOfficers()
+}
+
+// AmortizationPlan ranks the next mines, plasma technology and astrophysics investments by payback time
+func (b *Prioritize) AmortizationPlan(celestialIDs []ogame.CelestialID, rates ogame.Ratio) ([]ogame.Investment, error) {
+	b.begin("AmortizationPlan")
+	defer b.done()
+	return b.bot.amortizationPlan(celestialIDs, rates)
+}
+
+// GetTechnologyDetails gets cost, duration, energy needs, requirements state and tear down cost of the next level of a technology
+func (b *Prioritize) GetTechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechnologyDetails, error) {
+	b.begin("GetTechnologyDetails")
+	defer b.done()
+	return b.bot.getTechnologyDetails(celestialID, id)
+}
+
+// GetTechTree gets the requirements tree of a technology with the current levels on the celestial
+func (b *Prioritize) GetTechTree(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechTreeNode, error) {
+	b.begin("GetTechTree")
+	defer b.done()
+	return b.bot.getTechTree(celestialID, id)
+}
+
+// GetShopItems gets all the items of the shop, with their categories, cost, cooldown and amount in inventory
+func (b *Prioritize) GetShopItems(celestialID ogame.CelestialID) ([]ogame.ShopItem, error) {
+	b.begin("GetShopItems")
+	defer b.done()
+	return b.bot.getShopItems(celestialID)
+}
+
+// GetInventory gets the items owned by the player
+func (b *Prioritize) GetInventory(celestialID ogame.CelestialID) ([]ogame.ShopItem, error) {
+	b.begin("GetInventory")
+	defer b.done()
+	return b.bot.getInventory(celestialID)
+}
+
+// BuyItem buys an item with dark matter and activates it, like the "Buy & Activate" button
+func (b *Prioritize) BuyItem(ref string, celestialID ogame.CelestialID) error {
+	b.begin("BuyItem")
+	defer b.done()
+	return b.bot.buyItem(ref, celestialID)
+}
+
+// DoAuctionWS bids on the auctioneer websocket for lower latency, using the token of the given auction.
+// Falls back to DoAuction if the websocket is not connected, returns ErrAuctionBidNotConfirmed if the bid was sent
+// but the auctioneer did not broadcast it in time.
+func (b *Prioritize) DoAuctionWS(auction ogame.Auction, bid map[ogame.CelestialID]ogame.Resources) error {
+	b.begin("DoAuctionWS")
+	defer b.done()
+	return b.bot.doAuctionWS(auction, bid)
+}
+
+// GetMarketplaceOffers gets all the listings of a marketplace tab
+func (b *Prioritize) GetMarketplaceOffers(tab ogame.MarketplaceTab, celestialID ogame.CelestialID) ([]ogame.MarketplaceOffer, error) {
+	b.begin("GetMarketplaceOffers")
+	defer b.done()
+	return b.bot.getMarketplaceOffers(tab, celestialID)
+}
+
+// TradeResources exchanges resources with the merchant (Merchant -> Resources).
+// give must contain a single resource type, want is what is received at the merchant rates.
+func (b *Prioritize) TradeResources(celestialID ogame.CelestialID, give, want ogame.Resources) error {
+	b.begin("TradeResources")
+	defer b.done()
+	return b.bot.tradeResources(celestialID, give, want)
+}
+
+// GetOfferOfTheDay gets the offer of the day price, rarity and exchange cost without buying it
+func (b *Prioritize) GetOfferOfTheDay() (ogame.OfferOfTheDay, error) {
+	b.begin("GetOfferOfTheDay")
+	defer b.done()
+	return b.bot.getOfferOfTheDay()
+}
+
+// RerollOfferOfTheDay buys the offer of the day and exchanges the received item for dark matter
+// until keep returns true or no exchange is left, then takes the last item
+func (b *Prioritize) RerollOfferOfTheDay(keep func(ogame.OfferOfTheDayItem) bool) (ogame.OfferOfTheDayItem, error) {
+	b.begin("RerollOfferOfTheDay")
+	defer b.done()
+	return b.bot.rerollOfferOfTheDay(keep)
+}
+
+// GetPhalanxCoverage gets the systems covered by the sensor phalanx of all the moons
+func (b *Prioritize) GetPhalanxCoverage() (ogame.PhalanxCoverage, error) {
+	b.begin("GetPhalanxCoverage")
+	defer b.done()
+	return b.bot.getPhalanxCoverage()
+}
+
+// DestroyMoon sends deathstars on a destroy mission to the moon at target.
+// Returns the chances to destroy the moon and to lose the deathstars, computed from the moon diameter.
+func (b *Prioritize) DestroyMoon(celestialID ogame.CelestialID, target ogame.Coordinate, deathstars int64) (ogame.Fleet, ogame.MoonDestructionChance, error) {
+	b.begin("DestroyMoon")
+	defer b.done()
+	return b.bot.destroyMoon(celestialID, target, deathstars)
+}
+
+// GetEventList get all the fleet movements of the event list (own, friendly and hostile)
+func (b *Prioritize) GetEventList(opts ...Option) ([]ogame.FleetEvent, error) {
+	b.begin("GetEventList")
+	defer b.done()
+	return b.bot.getEventList(opts...)
+}
+
+// GetMessagesPage gets the parsed messages of a page of a messages tab, and the number of pages of the tab
+func (b *Prioritize) GetMessagesPage(tabID ogame.MessagesTabID, page int64) ([]ogame.Message, int64, error) {
+	b.begin("GetMessagesPage")
+	defer b.done()
+	return b.bot.getMessagesPage(tabID, page)
+}
+
+// DeleteMessages deletes the messages of a tab for which filter returns true, returns the number of deleted messages.
+// eg: delete espionage reports older than 2 days
+//
+//	bot.DeleteMessages(wrapper.EspionageMessagesTabID, func(msg ogame.Message) bool { return time.Since(msg.CreatedAt) > 48*time.Hour })
+func (b *Prioritize) DeleteMessages(tabID ogame.MessagesTabID, filter func(ogame.Message) bool) (int64, error) {
+	b.begin("DeleteMessages")
+	defer b.done()
+	return b.bot.deleteMessages(tabID, filter)
+}
+
+// GetExpeditionStats gets the totals per outcome of the expedition messages received since the given time
+func (b *Prioritize) GetExpeditionStats(since time.Time) (ogame.ExpeditionStats, error) {
+	b.begin("GetExpeditionStats")
+	defer b.done()
+	return b.bot.getExpeditionStats(since)
+}
+
+// RefreshCaches fetches the planets and researches again, regardless of their TTL
+func (b *Prioritize) RefreshCaches() error {
+	b.begin("RefreshCaches")
+	defer b.done()
+	return b.bot.refreshCaches()
+}
+
+// RefreshServerData fetches the server data again, and switches the extractor if the server was upgraded
+func (b *Prioritize) RefreshServerData() (ServerData, error) {
+	b.begin("RefreshServerData")
+	defer b.done()
+	return b.bot.refreshServerData()
+}
+
+// UnsetVacationMode disables vacation mode, returns a *ogame.VacationModeLockedError if its minimum duration is not over
+func (b *Prioritize) UnsetVacationMode() error {
+	b.begin("UnsetVacationMode")
+	defer b.done()
+	return b.bot.unsetVacationMode()
+}
+
+// ChangeNickname changes the player name, it can only be changed once per week
+func (b *Prioritize) ChangeNickname(newNick string) error {
+	b.begin("ChangeNickname")
+	defer b.done()
+	return b.bot.changeNickname(newNick)
+}
+
+// GetDarkMatter returns the dark matter available on the account
+func (b *Prioritize) GetDarkMatter() (int64, error) {
+	b.begin("GetDarkMatter")
+	defer b.done()
+	return b.bot.getDarkMatter()
+}
+
+// ForecastResources returns the resources the celestial will have at the given time,
+// extrapolated from its last fetched resources, production and storage capacity.
+// The resources are fetched again after an action of the bot (build, send fleet, trade...)
+func (b *Prioritize) ForecastResources(celestialID ogame.CelestialID, at time.Time) (ogame.Resources, error) {
+	b.begin("ForecastResources")
+	defer b.done()
+	return b.bot.forecastResources(celestialID, at)
+}
+
+// GetStorageCapacity returns the metal, crystal and deuterium storage capacity of a celestial, computed from its storage buildings
+func (b *Prioritize) GetStorageCapacity(celestialID ogame.CelestialID, options ...Option) (ogame.Resources, error) {
+	b.begin("GetStorageCapacity")
+	defer b.done()
+	return b.bot.getStorageCapacity(celestialID, options...)
+}
+
+// EmpireSnapshot gets the empire page of the planets and moons at once, to be compared with ogame.DiffEmpire
+func (b *Prioritize) EmpireSnapshot() (ogame.EmpireSnapshot, error) {
+	b.begin("EmpireSnapshot")
+	defer b.done()
+	return b.bot.empireSnapshot()
+}
+
+// GalaxyRange fetches the systems [fromSystem, toSystem] of a galaxy, with at most Concurrency (default 3) parallel requests
+// spaced by Pacing (default 300ms). When some systems fail, the others are returned along with a *GalaxyRangeError
+func (b *Prioritize) GalaxyRange(galaxy, fromSystem, toSystem int64, opts ...Option) ([]ogame.SystemInfos, error) {
+	b.begin("GalaxyRange")
+	defer b.done()
+	return b.bot.galaxyRange(galaxy, fromSystem, toSystem, opts...)
+}
+
+// HarvestExpeditionDebris sends pathfinders from celestialID to recycle the expedition debris field (position 16) of the system
+func (b *Prioritize) HarvestExpeditionDebris(celestialID ogame.CelestialID, galaxy, system int64, speed ogame.Speed) (ogame.Fleet, error) {
+	b.begin("HarvestExpeditionDebris")
+	defer b.done()
+	return b.bot.harvestExpeditionDebris(celestialID, galaxy, system, speed)
+}
+
+// GetMissiles returns the anti-ballistic and interplanetary missiles stored in the missile silo, and the silo capacity
+func (b *Prioritize) GetMissiles(celestialID ogame.CelestialID) (ogame.Missiles, error) {
+	b.begin("GetMissiles")
+	defer b.done()
+	return b.bot.getMissiles(celestialID)
+}
+
+// GetChatContacts gets the conversations of the chat page
+func (b *Prioritize) GetChatContacts() ([]ogame.ChatContact, error) {
+	b.begin("GetChatContacts")
+	defer b.done()
+	return b.bot.getChatContacts()
+}
+
+// GetChatHistory gets the messages of the conversation with playerID, older than the message beforeID (0 for the latest ones)
+func (b *Prioritize) GetChatHistory(playerID, beforeID int64) ([]ogame.ChatMsg, error) {
+	b.begin("GetChatHistory")
+	defer b.done()
+	return b.bot.getChatHistory(playerID, beforeID)
+}
+
+// GetOtherMessages gets the messages of the "Other" tab (moon destructions, fleet returns, officers, tutorial)
+func (b *Prioritize) GetOtherMessages(opts ...Option) ([]ogame.OtherMessage, error) {
+	b.begin("GetOtherMessages")
+	defer b.done()
+	return b.bot.getOtherMessages(opts...)
+}
+
+// GetTransportMessages gets the messages of the "Unions/Transport" tab (transport arrivals, ACS invitations)
+func (b *Prioritize) GetTransportMessages(opts ...Option) ([]ogame.UnionsTransportMessage, error) {
+	b.begin("GetTransportMessages")
+	defer b.done()
+	return b.bot.getTransportMessages(opts...)
+}
+
+// JoinACS accepts an ACS invitation by sending ships from celestialID to join the union
+func (b *Prioritize) JoinACS(celestialID ogame.CelestialID, invitation ogame.ACSInvitation, ships []ogame.Quantifiable, speed ogame.Speed) (ogame.Fleet, error) {
+	b.begin("JoinACS")
+	defer b.done()
+	return b.bot.joinACS(celestialID, invitation, ships, speed)
+}
+
+// GetExpeditionFleet returns the fleet able to carry back the biggest expedition find, sized from the points of the top 1 player.
+// withPathfinder adds a Pathfinder, which doubles the find cap.
+func (b *Prioritize) GetExpeditionFleet(cargoID ogame.ID, withPathfinder bool) (ogame.ShipsInfos, error) {
+	b.begin("GetExpeditionFleet")
+	defer b.done()
+	return b.bot.getExpeditionFleet(cargoID, withPathfinder)
+}
+
+// PanicSave sends every ship of the celestial, loaded with its resources, on the cheapest mission allowed by the policy.
+// Meant to be called when an attack is about to hit (eg: from a HostileTracker).
+func (b *Prioritize) PanicSave(celestialID ogame.CelestialID, policy PanicSavePolicy) (ogame.Fleet, error) {
+	b.begin("PanicSave")
+	defer b.done()
+	return b.bot.panicSave(celestialID, policy)
+}
+
+// BuildProductionWithProgress orders ships or defenses in chunks the shipyard accepts, calls clb after each chunk
+// and stops when ctx is done. Each chunk is verified against the production queue.
+func (b *Prioritize) BuildProductionWithProgress(ctx context.Context, celestialID ogame.CelestialID, id ogame.ID, nbr int64, clb func(ogame.BuildProgress)) error {
+	b.begin("BuildProductionWithProgress")
+	defer b.done()
+	return b.bot.buildProductionWithProgress(ctx, celestialID, id, nbr, clb)
+}
+
+// TearDownPlan returns the building levels of a celestial worth tearing down, the ones refunding more than they cost,
+// most profitable first. The RefundRatio option is required, ErrRefundRatioRequired is returned without it.
+func (b *Prioritize) TearDownPlan(celestialID ogame.CelestialID, opts ...Option) ([]ogame.TearDownStep, error) {
+	b.begin("TearDownPlan")
+	defer b.done()
+	return b.bot.tearDownPlan(celestialID, opts...)
+}
+
+// GetCachedTechs same as GetTechs, but returns the cached techs of the celestial when they are fresh.
+// They are fetched again after InvalidateTechs, a build or a tear down on the celestial, or once older than the TechsCache TTL (DefaultTechsCacheTTL unless set).
+func (b *Prioritize) GetCachedTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
+	b.begin("GetCachedTechs")
+	defer b.done()
+	return b.bot.getCachedTechs(celestialID)
+}
+
+// GetPlanetInfos refreshes a single celestial (name, diameter, fields, temperature, image) with one page load,
+// the cached planets list is updated along the way
+func (b *Prioritize) GetPlanetInfos(celestialID ogame.CelestialID) (Celestial, error) {
+	b.begin("GetPlanetInfos")
+	defer b.done()
+	return b.bot.getPlanetInfos(celestialID)
+}
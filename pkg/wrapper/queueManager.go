@@ -0,0 +1,323 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// QueueGoal a level to reach for a building or a research on a celestial
+type QueueGoal struct {
+	CelestialID ogame.CelestialID
+	ID          ogame.ID
+	Level       int64
+}
+
+// QueueEventType kind of event emitted by the QueueManager
+type QueueEventType int
+
+// Queue events
+const (
+	QueueEventStarted   QueueEventType = iota // construction of the next level started
+	QueueEventWaiting                         // waiting for resources or for the queue to be free
+	QueueEventCompleted                       // goal level reached, goal removed from the queue
+	QueueEventError                           // something went wrong while processing the goal
+)
+
+// QueueEvent progress event emitted by the QueueManager
+type QueueEvent struct {
+	Type    QueueEventType
+	Goal    QueueGoal
+	Level   int64           // level being built, or current level once completed
+	Missing ogame.Resources // resources missing to start the construction
+	WaitFor time.Duration   // estimated wait before the goal can progress
+	Err     error
+}
+
+// QueueManager builds buildings and researches up to the pushed goals, waiting for resources when needed.
+// Goals are processed in order, one at a time per celestial and queue type.
+type QueueManager struct {
+	b             Wrapper
+	goals         []QueueGoal
+	checkInterval time.Duration
+	mu            sync.Mutex
+	cancel        context.CancelFunc
+	triggerCh     chan struct{}
+	callbacks     []func(QueueEvent)
+}
+
+// NewQueueManager creates a new queue manager
+func NewQueueManager(b Wrapper) *QueueManager {
+	return &QueueManager{b: b, checkInterval: 5 * time.Minute, triggerCh: make(chan struct{}, 1)}
+}
+
+// Trigger wakes up the background processing, so the queue is processed right away.
+// Useful together with OnConstructionFinished to start the next goal as soon as a construction completes:
+//
+//	bot.OnConstructionFinished(celestialID, func(ogame.ID) { queue.Trigger() })
+func (q *QueueManager) Trigger() {
+	select {
+	case q.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+// Push adds goals at the end of the queue
+func (q *QueueManager) Push(goals ...QueueGoal) error {
+	for _, goal := range goals {
+		if queueKindOf(goal.ID) < 0 {
+			return errors.New("invalid id " + goal.ID.String())
+		}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.goals = append(q.goals, goals...)
+	return nil
+}
+
+// Goals returns the goals that are not completed yet
+func (q *QueueManager) Goals() []QueueGoal {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]QueueGoal{}, q.goals...)
+}
+
+// Clear removes all goals
+func (q *QueueManager) Clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.goals = nil
+}
+
+// SetCheckInterval sets the maximum time between two checks
+func (q *QueueManager) SetCheckInterval(d time.Duration) *QueueManager {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.checkInterval = d
+	return q
+}
+
+// OnEvent registers a callback executed for every progress event
+func (q *QueueManager) OnEvent(clb func(QueueEvent)) *QueueManager {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.callbacks = append(q.callbacks, clb)
+	return q
+}
+
+// Start processes the queue in the background, until Stop is called
+func (q *QueueManager) Start() {
+	q.mu.Lock()
+	if q.cancel != nil {
+		q.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+	q.mu.Unlock()
+	go func() {
+		for {
+			wait := q.Process()
+			select {
+			case <-time.After(wait):
+			case <-q.triggerCh:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background processing
+func (q *QueueManager) Stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cancel != nil {
+		q.cancel()
+		q.cancel = nil
+	}
+}
+
+// Process does one pass over the queue and returns the time to wait before the next pass
+func (q *QueueManager) Process() time.Duration {
+	q.mu.Lock()
+	wait := q.checkInterval
+	goals := append([]QueueGoal{}, q.goals...)
+	q.mu.Unlock()
+
+	type queueKey struct {
+		celestialID ogame.CelestialID
+		kind        int
+	}
+	handled := make(map[queueKey]bool)
+	for _, goal := range goals {
+		key := queueKey{goal.CelestialID, queueKindOf(goal.ID)}
+		if handled[key] {
+			continue
+		}
+		handled[key] = true
+		evt := q.processGoal(goal)
+		if evt.Type == QueueEventCompleted {
+			q.remove(goal)
+			handled[key] = false
+		}
+		q.emit(evt)
+		if evt.WaitFor > 0 && evt.WaitFor < wait {
+			wait = evt.WaitFor
+		}
+	}
+	return wait
+}
+
+func (q *QueueManager) processGoal(goal QueueGoal) QueueEvent {
+	evt := QueueEvent{Goal: goal}
+	level, err := q.currentLevel(goal.CelestialID, goal.ID)
+	if err != nil {
+		evt.Type, evt.Err = QueueEventError, err
+		return evt
+	}
+	if level >= goal.Level {
+		evt.Type, evt.Level = QueueEventCompleted, level
+		return evt
+	}
+	evt.Level = level + 1
+	if countdown := q.queueCountdown(goal.CelestialID, goal.ID); countdown > 0 {
+		evt.Type, evt.WaitFor = QueueEventWaiting, time.Duration(countdown)*time.Second
+		return evt
+	}
+	price := ogame.Objs.ByID(goal.ID).GetPrice(level + 1)
+	resources, err := q.b.GetResources(goal.CelestialID)
+	if err != nil {
+		evt.Type, evt.Err = QueueEventError, err
+		return evt
+	}
+	if !resources.CanAfford(price) {
+		evt.Type, evt.Missing = QueueEventWaiting, price.Sub(resources)
+		evt.WaitFor = q.estimateWait(goal.CelestialID, evt.Missing)
+		return evt
+	}
+	if err := q.b.BuildCancelable(goal.CelestialID, goal.ID); err != nil {
+		evt.Type, evt.Err = QueueEventError, err
+		return evt
+	}
+	evt.Type = QueueEventStarted
+	return evt
+}
+
+func (q *QueueManager) currentLevel(celestialID ogame.CelestialID, id ogame.ID) (int64, error) {
+	if id.IsLfTech() {
+		lfResearches, err := q.b.GetLfResearch(celestialID)
+		if err != nil {
+			return 0, err
+		}
+		return lfResearches.ByID(id), nil
+	}
+	resourcesBuildings, facilities, _, _, researches, lfBuildings, err := q.b.GetTechs(celestialID)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case id.IsResourceBuilding():
+		return resourcesBuildings.ByID(id), nil
+	case id.IsFacility():
+		return facilities.ByID(id), nil
+	case id.IsLfBuilding():
+		return lfBuildings.ByID(id), nil
+	default:
+		return researches.ByID(id), nil
+	}
+}
+
+// queueCountdown returns the seconds left in the construction queue used by id
+func (q *QueueManager) queueCountdown(celestialID ogame.CelestialID, id ogame.ID) int64 {
+	_, buildingCountdown, _, researchCountdown, _, lfBuildingCountdown, _, lfResearchCountdown := q.b.ConstructionsBeingBuilt(celestialID)
+	switch queueKindOf(id) {
+	case queueKindBuilding:
+		return buildingCountdown
+	case queueKindResearch:
+		return researchCountdown
+	case queueKindLfBuilding:
+		return lfBuildingCountdown
+	default:
+		return lfResearchCountdown
+	}
+}
+
+// estimateWait estimates the time needed to produce the missing resources, 0 if it cannot be estimated
+func (q *QueueManager) estimateWait(celestialID ogame.CelestialID, missing ogame.Resources) time.Duration {
+	celestial := q.b.GetCachedCelestial(celestialID)
+	if celestial == nil || celestial.GetType() != ogame.PlanetType {
+		return 0
+	}
+	production, err := q.b.GetResourcesProductions(ogame.PlanetID(celestialID))
+	if err != nil {
+		return 0
+	}
+	return productionWait(missing, production)
+}
+
+// productionWait time to produce missing resources given an hourly production
+func productionWait(missing, hourlyProduction ogame.Resources) time.Duration {
+	var hours float64
+	for _, v := range [][2]int64{
+		{missing.Metal, hourlyProduction.Metal},
+		{missing.Crystal, hourlyProduction.Crystal},
+		{missing.Deuterium, hourlyProduction.Deuterium},
+	} {
+		if v[0] <= 0 {
+			continue
+		}
+		if v[1] <= 0 {
+			return 0
+		}
+		if h := float64(v[0]) / float64(v[1]); h > hours {
+			hours = h
+		}
+	}
+	return time.Duration(hours * float64(time.Hour)).Round(time.Second)
+}
+
+func (q *QueueManager) remove(goal QueueGoal) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, g := range q.goals {
+		if g == goal {
+			q.goals = append(q.goals[:i], q.goals[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *QueueManager) emit(evt QueueEvent) {
+	q.mu.Lock()
+	callbacks := q.callbacks
+	q.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(evt)
+	}
+}
+
+const (
+	queueKindBuilding = iota
+	queueKindResearch
+	queueKindLfBuilding
+	queueKindLfResearch
+)
+
+func queueKindOf(id ogame.ID) int {
+	// IsBuilding includes the lifeform buildings, they have their own queue
+	switch {
+	case id.IsLfBuilding():
+		return queueKindLfBuilding
+	case id.IsLfTech():
+		return queueKindLfResearch
+	case id.IsBuilding():
+		return queueKindBuilding
+	case id.IsTech():
+		return queueKindResearch
+	}
+	return -1
+}
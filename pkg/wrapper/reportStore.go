@@ -0,0 +1,115 @@
+package wrapper
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// ReportDiff changes between two espionage reports of the same coordinate (To - From)
+type ReportDiff struct {
+	Coordinate ogame.Coordinate
+	From       time.Time
+	To         time.Time
+	Resources  ogame.Resources      // metal, crystal and deuterium differences, can be negative
+	Ships      *ogame.ShipsInfos    // nil when one of the reports has no fleet information
+	Defenses   *ogame.DefensesInfos // nil when one of the reports has no defenses information
+}
+
+// ReportStore archives the espionage reports per coordinate, oldest first.
+// Set it on the bot with SetReportStore to archive every report fetched by the bot.
+//
+//	store := wrapper.NewReportStore()
+//	bot.SetReportStore(store)
+//	reports := store.Reports(coord)
+//	diff, _ := store.DiffReports(coord, reports[0].ID, reports[len(reports)-1].ID)
+type ReportStore struct {
+	mu      sync.RWMutex
+	reports map[ogame.Coordinate][]ogame.EspionageReport
+}
+
+// NewReportStore creates an empty report store
+func NewReportStore() *ReportStore {
+	return &ReportStore{reports: make(map[ogame.Coordinate][]ogame.EspionageReport)}
+}
+
+// Add archives a report, a report already archived is ignored
+func (s *ReportStore) Add(report ogame.EspionageReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reports := s.reports[report.Coordinate]
+	for _, r := range reports {
+		if r.ID == report.ID {
+			return
+		}
+	}
+	reports = append(reports, report)
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Date.Before(reports[j].Date) })
+	s.reports[report.Coordinate] = reports
+}
+
+// Reports returns the archived reports of a coordinate, oldest first
+func (s *ReportStore) Reports(coord ogame.Coordinate) []ogame.EspionageReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]ogame.EspionageReport{}, s.reports[coord]...)
+}
+
+// Latest returns the most recent report of a coordinate
+func (s *ReportStore) Latest(coord ogame.Coordinate) (ogame.EspionageReport, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	reports := s.reports[coord]
+	if len(reports) == 0 {
+		return ogame.EspionageReport{}, false
+	}
+	return reports[len(reports)-1], true
+}
+
+// DiffReports returns the changes between the reports a and b (by report id) of a coordinate
+func (s *ReportStore) DiffReports(coord ogame.Coordinate, a, b int64) (ReportDiff, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var from, to *ogame.EspionageReport
+	for i, r := range s.reports[coord] {
+		if r.ID == a {
+			from = &s.reports[coord][i]
+		}
+		if r.ID == b {
+			to = &s.reports[coord][i]
+		}
+	}
+	if from == nil || to == nil {
+		return ReportDiff{}, errors.New("report not found for " + coord.String())
+	}
+	return diffReports(*from, *to), nil
+}
+
+func diffReports(from, to ogame.EspionageReport) ReportDiff {
+	diff := ReportDiff{
+		Coordinate: to.Coordinate,
+		From:       from.Date,
+		To:         to.Date,
+		Resources: ogame.Resources{ // Resources.Sub does not go below 0
+			Metal:     to.Metal - from.Metal,
+			Crystal:   to.Crystal - from.Crystal,
+			Deuterium: to.Deuterium - from.Deuterium,
+		},
+	}
+	if fromShips, toShips := from.ShipsInfos(), to.ShipsInfos(); fromShips != nil && toShips != nil {
+		diff.Ships = new(ogame.ShipsInfos)
+		for _, ship := range ogame.Ships {
+			diff.Ships.Set(ship.GetID(), toShips.ByID(ship.GetID())-fromShips.ByID(ship.GetID()))
+		}
+	}
+	if fromDefenses, toDefenses := from.DefensesInfos(), to.DefensesInfos(); fromDefenses != nil && toDefenses != nil {
+		diff.Defenses = new(ogame.DefensesInfos)
+		for _, defense := range ogame.Defenses {
+			diff.Defenses.Set(defense.GetID(), toDefenses.ByID(defense.GetID())-fromDefenses.ByID(defense.GetID()))
+		}
+	}
+	return diff
+}
@@ -0,0 +1,125 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// ResearchPlan where and how long a research level would take
+type ResearchPlan struct {
+	ID          ogame.ID
+	Level       int64
+	Planet      Planet
+	ResearchLab int64 // effective research lab level, including the intergalactic research network
+	Duration    time.Duration
+}
+
+// ResearchPlanner picks the best planet to launch researches from and can chain researches
+type ResearchPlanner struct {
+	b            Wrapper
+	pollInterval time.Duration
+	callbacks    []func(ResearchPlan)
+}
+
+// NewResearchPlanner creates a new research planner
+func NewResearchPlanner(b Wrapper) *ResearchPlanner {
+	return &ResearchPlanner{b: b, pollInterval: 30 * time.Second}
+}
+
+// SetPollInterval sets the maximum time between two checks for research completion
+func (p *ResearchPlanner) SetPollInterval(d time.Duration) *ResearchPlanner {
+	p.pollInterval = d
+	return p
+}
+
+// OnResearchStarted registers a callback executed every time a research of a chain is started
+func (p *ResearchPlanner) OnResearchStarted(clb func(ResearchPlan)) *ResearchPlanner {
+	p.callbacks = append(p.callbacks, clb)
+	return p
+}
+
+// Plan finds the planet with the highest effective research lab to research the next level of id
+func (p *ResearchPlanner) Plan(id ogame.ID) (ResearchPlan, error) {
+	if !id.IsTech() {
+		return ResearchPlan{}, errors.New("invalid technology id " + id.String())
+	}
+	planets := p.b.GetCachedPlanets()
+	if len(planets) == 0 {
+		return ResearchPlan{}, errors.New("no planet available")
+	}
+	labs := make([]int64, len(planets))
+	for i, planet := range planets {
+		facilities, err := p.b.GetFacilities(planet.GetID())
+		if err != nil {
+			return ResearchPlan{}, err
+		}
+		labs[i] = facilities.ResearchLab
+	}
+	researches := p.b.GetResearch()
+	minLab := ogame.Objs.ByID(id).GetRequirements()[ogame.ResearchLabID]
+	plan := ResearchPlan{ID: id, Level: researches.ByID(id) + 1, ResearchLab: -1}
+	for i, planet := range planets {
+		if labs[i] < minLab {
+			continue
+		}
+		lab := ogame.EffectiveResearchLab(labs, i, researches.IntergalacticResearchNetwork, minLab)
+		if lab > plan.ResearchLab {
+			plan.Planet, plan.ResearchLab = planet, lab
+		}
+	}
+	if plan.ResearchLab < 0 {
+		return ResearchPlan{}, errors.New("no planet meets the research lab requirement")
+	}
+	plan.Duration = p.b.ResearchDuration(id, plan.Level, plan.ResearchLab)
+	return plan, nil
+}
+
+// Chain launches researches one after the other from the best planet, waiting for each one to complete.
+// It blocks until all researches are done, an error occurs or ctx is cancelled.
+func (p *ResearchPlanner) Chain(ctx context.Context, ids ...ogame.ID) error {
+	for _, id := range ids {
+		if err := p.waitResearchQueue(ctx); err != nil {
+			return err
+		}
+		plan, err := p.Plan(id)
+		if err != nil {
+			return err
+		}
+		if _, err := p.b.BuildTechnology(plan.Planet.GetID(), id); err != nil {
+			return err
+		}
+		for _, clb := range p.callbacks {
+			clb(plan)
+		}
+	}
+	return p.waitResearchQueue(ctx)
+}
+
+// waitResearchQueue waits until no research is in progress on any planet
+func (p *ResearchPlanner) waitResearchQueue(ctx context.Context) error {
+	for {
+		var countdown int64
+		for _, planet := range p.b.GetCachedPlanets() {
+			_, _, researchID, researchCountdown, _, _, _, _ := p.b.ConstructionsBeingBuilt(planet.GetID())
+			if researchID != 0 {
+				countdown = researchCountdown
+				break
+			}
+		}
+		if countdown == 0 {
+			return nil
+		}
+		wait := time.Duration(countdown+1) * time.Second
+		if wait > p.pollInterval {
+			wait = p.pollInterval
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
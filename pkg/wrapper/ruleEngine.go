@@ -0,0 +1,337 @@
+package wrapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule metrics
+const (
+	RuleMetricMetal       = "metal"
+	RuleMetricCrystal     = "crystal"
+	RuleMetricDeuterium   = "deuterium"
+	RuleMetricEnergy      = "energy"
+	RuleMetricHostileETA  = "hostile_eta"  // seconds before the closest hostile fleet arrives, +Inf if none
+	RuleMetricFreeSlots   = "free_slots"   // fleet slots not in use
+	RuleMetricUnderAttack = "under_attack" // 1 if a hostile fleet is incoming, 0 otherwise
+)
+
+// Rule actions
+const (
+	RuleActionSendFleet = "send_fleet"
+	RuleActionBuild     = "build"
+	RuleActionNotify    = "notify"
+)
+
+// RuleCondition compares a metric of the bot state with a value, eg: {"metric": "deuterium", "celestial": 123, "op": ">", "value": 1000000}
+type RuleCondition struct {
+	Metric      string            `json:"metric" yaml:"metric"`
+	CelestialID ogame.CelestialID `json:"celestial,omitempty" yaml:"celestial,omitempty"` // for the resources metrics
+	Op          string            `json:"op" yaml:"op"`                                   // <, <=, >, >=, ==, !=
+	Value       float64           `json:"value" yaml:"value"`
+}
+
+// RuleAction action executed when all the conditions of a rule are met
+type RuleAction struct {
+	Type        string               `json:"type" yaml:"type"`
+	CelestialID ogame.CelestialID    `json:"celestial,omitempty" yaml:"celestial,omitempty"` // origin of the fleet, or where to build
+	ID          ogame.ID             `json:"id,omitempty" yaml:"id,omitempty"`               // build
+	Nbr         int64                `json:"nbr,omitempty" yaml:"nbr,omitempty"`             // build
+	Ships       []ogame.Quantifiable `json:"ships,omitempty" yaml:"ships,omitempty"`         // send_fleet
+	Speed       ogame.Speed          `json:"speed,omitempty" yaml:"speed,omitempty"`         // send_fleet, 100% if not set
+	Where       ogame.Coordinate     `json:"where" yaml:"where"`                             // send_fleet
+	Mission     ogame.MissionID      `json:"mission,omitempty" yaml:"mission,omitempty"`     // send_fleet
+	Resources   ogame.Resources      `json:"resources" yaml:"resources"`                     // send_fleet
+	Message     string               `json:"message,omitempty" yaml:"message,omitempty"`     // notify
+}
+
+// Rule executes its actions when all its conditions are met, conditions are evaluated following the cron
+// expression Schedule (see Scheduler).
+// Without Edge nor Cooldown, the actions are executed at every evaluation the conditions are met.
+type Rule struct {
+	Name       string              `json:"name" yaml:"name"`
+	Schedule   string              `json:"schedule" yaml:"schedule"`
+	Priority   taskRunner.Priority `json:"priority,omitempty" yaml:"priority,omitempty"` // Normal if not set
+	Edge       bool                `json:"edge,omitempty" yaml:"edge,omitempty"`         // only execute when the conditions were not met at the previous evaluation
+	Cooldown   string              `json:"cooldown,omitempty" yaml:"cooldown,omitempty"` // minimum duration between two executions (eg: "30m")
+	Conditions []RuleCondition     `json:"conditions" yaml:"conditions"`
+	Actions    []RuleAction        `json:"actions" yaml:"actions"`
+}
+
+// RuleEngine evaluates rules using a Scheduler, a batteries-included alternative to writing Go for simple automations.
+// Rules are loaded from json (LoadJSON) or yaml (LoadYAML), or added with AddRule.
+//
+//	engine := wrapper.NewRuleEngine(bot, scheduler)
+//	engine.OnNotify(func(rule, msg string) { fmt.Println(rule, msg) })
+//	err := engine.LoadJSON([]byte(`[{"name": "deut", "schedule": "@every 10m",
+//	  "conditions": [{"metric": "deuterium", "celestial": 123, "op": ">", "value": 1000000}],
+//	  "actions": [{"type": "notify", "message": "deuterium storage almost full"}]}]`))
+//	scheduler.Start()
+type RuleEngine struct {
+	b               Wrapper
+	scheduler       *Scheduler
+	mu              sync.Mutex
+	jobs            map[string]int64     // rule name -> scheduler job id
+	states          map[string]ruleState // rule name -> result of the previous evaluation
+	notifyCallbacks []func(rule, message string)
+}
+
+// ruleState result of the previous evaluation of a rule, for Edge and Cooldown
+type ruleState struct {
+	met        bool
+	executedAt time.Time
+}
+
+// NewRuleEngine creates a rule engine registering its rules in scheduler
+func NewRuleEngine(b Wrapper, scheduler *Scheduler) *RuleEngine {
+	return &RuleEngine{b: b, scheduler: scheduler, jobs: make(map[string]int64), states: make(map[string]ruleState)}
+}
+
+// OnNotify registers a callback executed by the notify actions
+func (e *RuleEngine) OnNotify(clb func(rule, message string)) *RuleEngine {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.notifyCallbacks = append(e.notifyCallbacks, clb)
+	return e
+}
+
+// LoadJSON adds the rules of a json array, nothing is added if one of the rules is invalid
+func (e *RuleEngine) LoadJSON(data []byte) error {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+	return e.addRules(rules)
+}
+
+// LoadYAML adds the rules of a yaml sequence, nothing is added if one of the rules is invalid
+//
+//	err := engine.LoadYAML([]byte(`
+//	- name: deut
+//	  schedule: "@every 10m"
+//	  cooldown: 1h
+//	  conditions:
+//	    - {metric: deuterium, celestial: 123, op: ">", value: 1000000}
+//	  actions:
+//	    - {type: notify, message: deuterium storage almost full}`))
+func (e *RuleEngine) LoadYAML(data []byte) error {
+	var rules []Rule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+	return e.addRules(rules)
+}
+
+func (e *RuleEngine) addRules(rules []Rule) error {
+	for _, rule := range rules {
+		if err := validateRule(rule); err != nil {
+			return err
+		}
+	}
+	for _, rule := range rules {
+		if err := e.AddRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddRule adds a rule, a rule with the same name is replaced
+func (e *RuleEngine) AddRule(rule Rule) error {
+	if err := validateRule(rule); err != nil {
+		return err
+	}
+	priority := rule.Priority
+	if priority == 0 {
+		priority = taskRunner.Normal
+	}
+	jobID, err := e.scheduler.Cron(rule.Name, rule.Schedule, priority, func(tx Prioritizable) error {
+		return e.evaluate(tx, rule)
+	})
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	prevJobID, exists := e.jobs[rule.Name]
+	e.jobs[rule.Name] = jobID
+	delete(e.states, rule.Name)
+	e.mu.Unlock()
+	if exists {
+		e.scheduler.Remove(prevJobID)
+	}
+	return nil
+}
+
+// RemoveRule removes a rule
+func (e *RuleEngine) RemoveRule(name string) {
+	e.mu.Lock()
+	jobID, exists := e.jobs[name]
+	delete(e.jobs, name)
+	delete(e.states, name)
+	e.mu.Unlock()
+	if exists {
+		e.scheduler.Remove(jobID)
+	}
+}
+
+// evaluate executes the actions of the rule if all its conditions are met, and Edge and Cooldown allow it
+func (e *RuleEngine) evaluate(tx Prioritizable, rule Rule) error {
+	met := true
+	for _, cond := range rule.Conditions {
+		value, err := ruleMetric(tx, cond)
+		if err != nil {
+			return err
+		}
+		if !compareRule(cond.Op, value, cond.Value) {
+			met = false
+			break
+		}
+	}
+	cooldown, _ := time.ParseDuration(rule.Cooldown) // validated by AddRule
+	now := time.Now()
+	e.mu.Lock()
+	state := e.states[rule.Name]
+	wasMet := state.met
+	state.met = met
+	execute := met && !(rule.Edge && wasMet) && (state.executedAt.IsZero() || now.Sub(state.executedAt) >= cooldown)
+	if execute {
+		state.executedAt = now
+	}
+	e.states[rule.Name] = state
+	e.mu.Unlock()
+	if !execute {
+		return nil
+	}
+	for _, action := range rule.Actions {
+		if err := e.execute(tx, rule, action); err != nil {
+			return fmt.Errorf("rule %s, action %s: %w", rule.Name, action.Type, err)
+		}
+	}
+	return nil
+}
+
+func (e *RuleEngine) execute(tx Prioritizable, rule Rule, action RuleAction) error {
+	switch action.Type {
+	case RuleActionSendFleet:
+		speed := action.Speed
+		if speed == 0 {
+			speed = ogame.HundredPercent
+		}
+		_, err := tx.SendFleet(action.CelestialID, action.Ships, speed, action.Where, action.Mission, action.Resources, 0, 0)
+		return err
+	case RuleActionBuild:
+		_, err := tx.Build(action.CelestialID, action.ID, action.Nbr)
+		return err
+	case RuleActionNotify:
+		e.mu.Lock()
+		callbacks := e.notifyCallbacks
+		e.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(rule.Name, action.Message)
+		}
+	}
+	return nil
+}
+
+// ruleMetric fetches the current value of the metric of a condition
+func ruleMetric(tx Prioritizable, cond RuleCondition) (float64, error) {
+	switch cond.Metric {
+	case RuleMetricMetal, RuleMetricCrystal, RuleMetricDeuterium, RuleMetricEnergy:
+		res, err := tx.GetResources(cond.CelestialID)
+		if err != nil {
+			return 0, err
+		}
+		return float64(map[string]int64{
+			RuleMetricMetal:     res.Metal,
+			RuleMetricCrystal:   res.Crystal,
+			RuleMetricDeuterium: res.Deuterium,
+			RuleMetricEnergy:    res.Energy,
+		}[cond.Metric]), nil
+	case RuleMetricHostileETA:
+		attacks, err := tx.GetAttacks()
+		if err != nil {
+			return 0, err
+		}
+		eta := math.Inf(1)
+		for _, attack := range attacks {
+			eta = math.Min(eta, float64(attack.ArriveIn))
+		}
+		return eta, nil
+	case RuleMetricFreeSlots:
+		slots := tx.GetSlots()
+		return float64(slots.Total - slots.InUse), nil
+	case RuleMetricUnderAttack:
+		underAttack, err := tx.IsUnderAttack()
+		if err != nil {
+			return 0, err
+		}
+		if underAttack {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, errors.New("unknown metric " + cond.Metric)
+}
+
+func compareRule(op string, a, b float64) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	}
+	return false
+}
+
+func validateRule(rule Rule) error {
+	if rule.Name == "" {
+		return errors.New("rule without name")
+	}
+	if _, err := parseCronSchedule(rule.Schedule); err != nil {
+		return fmt.Errorf("rule %s: %w", rule.Name, err)
+	}
+	if rule.Cooldown != "" {
+		if cooldown, err := time.ParseDuration(rule.Cooldown); err != nil || cooldown < 0 {
+			return fmt.Errorf("rule %s: invalid cooldown %s", rule.Name, rule.Cooldown)
+		}
+	}
+	for _, cond := range rule.Conditions {
+		switch cond.Metric {
+		case RuleMetricMetal, RuleMetricCrystal, RuleMetricDeuterium, RuleMetricEnergy, RuleMetricHostileETA, RuleMetricFreeSlots, RuleMetricUnderAttack:
+		default:
+			return fmt.Errorf("rule %s: unknown metric %s", rule.Name, cond.Metric)
+		}
+		switch cond.Op {
+		case "<", "<=", ">", ">=", "==", "!=":
+		default:
+			return fmt.Errorf("rule %s: unknown operator %s", rule.Name, cond.Op)
+		}
+	}
+	if len(rule.Actions) == 0 {
+		return fmt.Errorf("rule %s: no action", rule.Name)
+	}
+	for _, action := range rule.Actions {
+		switch action.Type {
+		case RuleActionSendFleet, RuleActionBuild, RuleActionNotify:
+		default:
+			return fmt.Errorf("rule %s: unknown action %s", rule.Name, action.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// ScheduledJob information about a job registered in a Scheduler
+type ScheduledJob struct {
+	ID       int64
+	Name     string
+	Spec     string // cron expression, empty for one-shot jobs
+	Priority taskRunner.Priority
+	Next     time.Time // next execution
+	LastRun  time.Time // zero if the job never ran
+}
+
+type scheduledJob struct {
+	ScheduledJob
+	schedule cronSchedule // nil for one-shot jobs
+	fn       func(Prioritizable) error
+}
+
+// Scheduler executes jobs at given times, or repeatedly following cron expressions.
+// Each execution runs in a transaction queued with the priority of the job, so jobs are ordered with the
+// other tasks of the bot.
+//
+// Cron expressions have 5 fields "minute hour day-of-month month day-of-week", each field accepts
+// "*", values, ranges "1-5", lists "1,3,5" and steps "*/15" or "0-30/10". The descriptors
+// "@hourly", "@daily", "@weekly", "@monthly" and "@every <duration>" are also supported.
+//
+//	scheduler := wrapper.NewScheduler(bot)
+//	scheduler.Cron("fleetsave", "50 23 * * *", taskRunner.Important, fleetsave)
+//	scheduler.Cron("scan", "@every 6h", taskRunner.Low, scanGalaxy)
+//	scheduler.At("build", buildAt, taskRunner.Normal, build)
+//	scheduler.Start()
+//	defer scheduler.Stop()
+type Scheduler struct {
+	b              Wrapper
+	location       *time.Location
+	lastID         int64
+	jobs           map[int64]*scheduledJob
+	wakeCh         chan struct{}
+	mu             sync.Mutex
+	cancel         context.CancelFunc
+	errorCallbacks []func(ScheduledJob, error)
+}
+
+// NewScheduler creates a scheduler, cron expressions are evaluated in the local time zone by default
+func NewScheduler(b Wrapper) *Scheduler {
+	return &Scheduler{
+		b:        b,
+		location: time.Local,
+		jobs:     make(map[int64]*scheduledJob),
+		wakeCh:   make(chan struct{}, 1),
+	}
+}
+
+// SetLocation sets the time zone used to evaluate the cron expressions (eg: bot.Location() for the server time)
+func (s *Scheduler) SetLocation(loc *time.Location) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.location = loc
+	now := time.Now()
+	for _, job := range s.jobs {
+		if job.schedule != nil {
+			job.Next = job.schedule.next(now.In(loc))
+		}
+	}
+	s.wake()
+	return s
+}
+
+// OnError registers a callback executed when a job returns an error
+func (s *Scheduler) OnError(clb func(ScheduledJob, error)) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorCallbacks = append(s.errorCallbacks, clb)
+	return s
+}
+
+// Cron registers a job executed following the cron expression spec, returns the id of the job
+func (s *Scheduler) Cron(name, spec string, priority taskRunner.Priority, fn func(Prioritizable) error) (int64, error) {
+	schedule, err := parseCronSchedule(spec)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := schedule.next(time.Now().In(s.location))
+	if next.IsZero() {
+		return 0, errors.New("cron spec " + spec + " never matches")
+	}
+	s.lastID++
+	job := &scheduledJob{schedule: schedule, fn: fn}
+	job.ID, job.Name, job.Spec, job.Priority, job.Next = s.lastID, name, spec, priority, next
+	s.jobs[job.ID] = job
+	s.wake()
+	return job.ID, nil
+}
+
+// At registers a job executed once at the given time, returns the id of the job
+func (s *Scheduler) At(name string, at time.Time, priority taskRunner.Priority, fn func(Prioritizable) error) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	job := &scheduledJob{fn: fn}
+	job.ID, job.Name, job.Priority, job.Next = s.lastID, name, priority, at
+	s.jobs[job.ID] = job
+	s.wake()
+	return job.ID
+}
+
+// Remove unregisters a job
+func (s *Scheduler) Remove(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	s.wake()
+}
+
+// Jobs returns the registered jobs, next to run first
+func (s *Scheduler) Jobs() []ScheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		out = append(out, job.ScheduledJob)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Next.Before(out[j].Next) })
+	return out
+}
+
+// Start executes the jobs in the background, until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.mu.Unlock()
+	go func() {
+		for {
+			for _, job := range s.dueJobs(time.Now()) {
+				go s.run(job)
+			}
+			var timerCh <-chan time.Time
+			if next, ok := s.nextRun(); ok {
+				timerCh = time.After(time.Until(next))
+			}
+			select {
+			case <-timerCh:
+			case <-s.wakeCh:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops executing the jobs, running jobs are not interrupted
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// wake makes the background loop recompute its next run, must be called with the lock held
+func (s *Scheduler) wake() {
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) nextRun() (next time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if !ok || job.Next.Before(next) {
+			next, ok = job.Next, true
+		}
+	}
+	return
+}
+
+// dueJobs returns the jobs to execute at now, schedules their next execution and removes the one-shot jobs
+func (s *Scheduler) dueJobs(now time.Time) (out []scheduledJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, job := range s.jobs {
+		if job.Next.After(now) {
+			continue
+		}
+		job.LastRun = now
+		out = append(out, *job)
+		if job.schedule != nil {
+			job.Next = job.schedule.next(now.In(s.location))
+		}
+		if job.schedule == nil || job.Next.IsZero() {
+			delete(s.jobs, id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return
+}
+
+func (s *Scheduler) run(job scheduledJob) {
+	if err := s.b.WithPriority(job.Priority).SetInitiator(job.Name).Tx(job.fn); err != nil {
+		s.mu.Lock()
+		callbacks := s.errorCallbacks
+		s.mu.Unlock()
+		for _, clb := range callbacks {
+			clb(job.ScheduledJob, err)
+		}
+	}
+}
+
+// cronSchedule computes the next execution of a job
+type cronSchedule interface {
+	next(after time.Time) time.Time
+}
+
+// everySchedule "@every <duration>" schedule
+type everySchedule time.Duration
+
+func (e everySchedule) next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// fieldsSchedule 5 fields cron expression, each field is a set of accepted values
+type fieldsSchedule struct {
+	minutes, hours, doms, months, dows map[int]bool
+	domStar, dowStar                   bool
+}
+
+func (f fieldsSchedule) matchDay(t time.Time) bool {
+	domMatch := f.doms[t.Day()]
+	dowMatch := f.dows[int(t.Weekday())]
+	if f.domStar || f.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func (f fieldsSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !f.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !f.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !f.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !f.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func parseCronSchedule(spec string) (cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, errors.New("invalid cron spec " + spec + ", duration must be positive")
+		}
+		return everySchedule(d), nil
+	}
+	if expr, ok := cronDescriptors[spec]; ok {
+		spec = expr
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.New("invalid cron spec " + spec + ", expected 5 fields")
+	}
+	var f fieldsSchedule
+	var err error
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+	sets := [5]*map[int]bool{&f.minutes, &f.hours, &f.doms, &f.months, &f.dows}
+	for i, field := range fields {
+		if *sets[i], err = parseCronField(field, bounds[i][0], bounds[i][1]); err != nil {
+			return nil, fmt.Errorf("invalid cron spec %s: %w", spec, err)
+		}
+	}
+	if f.dows[7] { // 7 is sunday too
+		f.dows[0] = true
+	}
+	f.domStar = strings.HasPrefix(fields[2], "*")
+	f.dowStar = strings.HasPrefix(fields[4], "*")
+	return f, nil
+}
+
+// parseCronField parses a field such as "*", "5", "1-5", "*/15", "0-30/10" or "1,3,5"
+func parseCronField(field string, minVal, maxVal int) (map[int]bool, error) {
+	out := make(map[int]bool)
+	if minVal == 0 && maxVal == 6 {
+		maxVal = 7 // day of week accepts 7 for sunday
+	}
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx != -1 {
+			var err error
+			if step, err = strconv.Atoi(part[idx+1:]); err != nil || step <= 0 {
+				return nil, errors.New("invalid step in " + part)
+			}
+			rangePart = part[:idx]
+		}
+		lo, hi := minVal, maxVal
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, errors.New("invalid value in " + part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, errors.New("invalid value in " + part)
+				}
+			} else if step > 1 {
+				hi = maxVal
+			}
+		}
+		if lo < minVal || hi > maxVal || lo > hi {
+			return nil, errors.New("value out of range in " + part)
+		}
+		for v := lo; v <= hi; v += step {
+			out[v] = true
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,89 @@
+package wrapper
+
+import (
+	"context"
+	"time"
+
+	version "github.com/hashicorp/go-version"
+)
+
+// OnVersionChanged registers a callback executed when a server data refresh detects that the server was upgraded.
+// Callbacks are executed in their own goroutine, so they can call the bot.
+func (b *OGame) OnVersionChanged(clb func(oldVersion, newVersion string)) {
+	b.serverDataRefreshMu.Lock()
+	defer b.serverDataRefreshMu.Unlock()
+	b.versionCallbacks = append(b.versionCallbacks, clb)
+}
+
+// StartServerDataRefresher fetches the server data every interval in the background, until StopServerDataRefresher is called.
+// The refresh is skipped while the bot is disabled or logged out.
+func (b *OGame) StartServerDataRefresher(interval time.Duration) {
+	b.serverDataRefreshMu.Lock()
+	defer b.serverDataRefreshMu.Unlock()
+	if b.serverDataRefreshStop != nil {
+		return
+	}
+	// Not derived from the bot context, which is cancelled when the bot is disabled
+	ctx, cancel := context.WithCancel(context.Background())
+	b.serverDataRefreshStop = cancel
+	go func() {
+		for {
+			select {
+			case <-time.After(b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+			if !b.IsEnabled() || !b.IsLoggedIn() {
+				continue
+			}
+			if _, err := b.RefreshServerData(); err != nil {
+				b.error("failed to refresh server data:", err)
+			}
+		}
+	}()
+}
+
+// StopServerDataRefresher stops the background server data refresh
+func (b *OGame) StopServerDataRefresher() {
+	b.serverDataRefreshMu.Lock()
+	defer b.serverDataRefreshMu.Unlock()
+	if b.serverDataRefreshStop != nil {
+		b.serverDataRefreshStop()
+		b.serverDataRefreshStop = nil
+	}
+}
+
+func (b *OGame) refreshServerData() (ServerData, error) {
+	serverData, err := b.getServerDataWrapper(func() (ServerData, error) {
+		return GetServerData(b.client, b.ctx, b.server.Number, b.server.Language)
+	})
+	if err != nil {
+		return ServerData{}, err
+	}
+	serverData = normalizeServerData(serverData)
+	oldVersion := b.serverData.Version
+	b.serverData = serverData
+	if serverData.Version == oldVersion {
+		return serverData, nil
+	}
+	b.debug("server version changed from " + oldVersion + " to " + serverData.Version)
+	if ogVersion, err := version.NewVersion(serverData.Version); err == nil {
+		if e := extractorForVersion(ogVersion); e != nil {
+			e.SetLanguage(b.extractor.GetLanguage())
+			e.SetLocation(b.extractor.GetLocation())
+			e.SetLifeformEnabled(b.extractor.GetLifeformEnabled())
+			b.extractor = e
+		}
+	} else {
+		b.error("failed to parse ogame version: " + err.Error())
+	}
+	b.serverDataRefreshMu.Lock()
+	callbacks := b.versionCallbacks
+	b.serverDataRefreshMu.Unlock()
+	go func() {
+		for _, clb := range callbacks {
+			clb(oldVersion, serverData.Version)
+		}
+	}()
+	return serverData, nil
+}
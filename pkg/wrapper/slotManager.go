@@ -0,0 +1,156 @@
+package wrapper
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// SlotReservation fleet slots kept free for some missions
+type SlotReservation struct {
+	Name     string
+	Slots    int64
+	Missions []ogame.MissionID // missions allowed to use the reserved slots
+}
+
+// allows returns true if mission can use the reserved slots
+func (r SlotReservation) allows(mission ogame.MissionID) bool {
+	for _, m := range r.Missions {
+		if m == mission {
+			return true
+		}
+	}
+	return false
+}
+
+// SlotManager keeps fleet slots free for the missions that need them (eg: fleetsaves, expeditions),
+// and tracks the missions sent by the bot. Set it on the bot with SetSlotManager, SendFleet then fails
+// with ogame.ErrSlotsReserved instead of using a slot reserved for another mission.
+//
+//	slots := wrapper.NewSlotManager()
+//	slots.Reserve("fleetsave", 1, ogame.Park, ogame.Transport)
+//	slots.Reserve("expeditions", 2, ogame.Expedition)
+//	bot.SetSlotManager(slots)
+type SlotManager struct {
+	mu           sync.Mutex
+	reservations map[string]SlotReservation
+	fleets       []ogame.Fleet
+}
+
+// NewSlotManager creates a slot manager without any reservation
+func NewSlotManager() *SlotManager {
+	return &SlotManager{reservations: make(map[string]SlotReservation)}
+}
+
+// Reserve keeps nbr slots free for the given missions, a reservation with the same name is replaced
+func (m *SlotManager) Reserve(name string, nbr int64, missions ...ogame.MissionID) *SlotManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reservations[name] = SlotReservation{Name: name, Slots: nbr, Missions: missions}
+	return m
+}
+
+// Release removes a reservation
+func (m *SlotManager) Release(name string) *SlotManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.reservations, name)
+	return m
+}
+
+// Reservations returns the reservations sorted by name
+func (m *SlotManager) Reservations() []SlotReservation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]SlotReservation, 0, len(m.reservations))
+	for _, r := range m.reservations {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Fleets returns the missions sent by the bot that are not back yet
+func (m *SlotManager) Fleets() []ogame.Fleet {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune(time.Now())
+	return append([]ogame.Fleet{}, m.fleets...)
+}
+
+// InUse returns how many slots are used by the missions sent by the bot
+func (m *SlotManager) InUse() int64 {
+	return int64(len(m.Fleets()))
+}
+
+// Check returns ogame.ErrSlotsReserved if sending mission would use a slot reserved for another mission
+func (m *SlotManager) Check(mission ogame.MissionID, slots ogame.Slots) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if freeSlotsFor(mission, slots, m.reservations) <= 0 {
+		return ogame.ErrSlotsReserved
+	}
+	return nil
+}
+
+// track records a mission sent by the bot
+func (m *SlotManager) track(fleet ogame.Fleet) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune(time.Now())
+	m.fleets = append(m.fleets, fleet)
+}
+
+// prune removes the fleets that are back, must be called with the lock held
+func (m *SlotManager) prune(now time.Time) {
+	fleets := m.fleets[:0]
+	for _, fleet := range m.fleets {
+		backAt := fleet.BackTime
+		if backAt.IsZero() {
+			backAt = fleet.ArrivalTime
+		}
+		if backAt.After(now) {
+			fleets = append(fleets, fleet)
+		}
+	}
+	m.fleets = fleets
+}
+
+// expeditionOnly returns true if only expeditions can use the reserved slots
+func (r SlotReservation) expeditionOnly() bool {
+	for _, m := range r.Missions {
+		if m != ogame.Expedition {
+			return false
+		}
+	}
+	return len(r.Missions) > 0
+}
+
+// freeSlotsFor returns how many slots mission can use, once the slots reserved for other missions are set aside.
+// Expedition reservations only set aside the expedition slots that are still free.
+func freeSlotsFor(mission ogame.MissionID, slots ogame.Slots, reservations map[string]SlotReservation) int64 {
+	free := slots.Total - slots.InUse
+	expFree := utils.MaxInt(slots.ExpTotal-slots.ExpInUse, 0)
+	for _, r := range reservations {
+		if r.allows(mission) {
+			continue
+		}
+		if r.expeditionOnly() {
+			free -= utils.MinInt(r.Slots, expFree)
+		} else {
+			free -= r.Slots
+		}
+	}
+	if mission == ogame.Expedition {
+		free = utils.MinInt(free, expFree)
+	}
+	return free
+}
+
+// SetSlotManager sets the slot manager checked before sending a fleet, nil to disable
+func (b *OGame) SetSlotManager(manager *SlotManager) {
+	b.slotManager = manager
+}
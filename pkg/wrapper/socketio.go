@@ -0,0 +1,503 @@
+package wrapper
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/utils"
+	"golang.org/x/net/websocket"
+)
+
+// Engine.io (EIO=4) packet types
+const (
+	eioOpen    = '0'
+	eioClose   = '1'
+	eioPing    = '2'
+	eioPong    = '3'
+	eioMessage = '4'
+	eioUpgrade = '5'
+	eioNoop    = '6'
+)
+
+// Socket.io packet types, carried by the engine.io message packets
+const (
+	sioConnect      = '0'
+	sioDisconnect   = '1'
+	sioEvent        = '2'
+	sioAck          = '3'
+	sioConnectError = '4'
+)
+
+// socket.io namespaces the bot subscribes to
+var chatNamespaces = []string{"/chat", "/auctioneer"}
+
+// socketIOPacket a decoded websocket frame
+type socketIOPacket struct {
+	Engine    byte
+	Type      byte   // socket.io packet type, only for engine message packets
+	Namespace string // "/" if not set
+	AckID     int64  // -1 if not set
+	Data      string // json payload of message packets, raw payload of the other engine packets (eg: "probe")
+}
+
+// parseSocketIOPacket decodes a frame such as `2`, `3probe`, `40/chat,{"sid":"x"}`, `43/chat,1[true]` or `42/chat,["chat",{...}]`
+func parseSocketIOPacket(frame string) (p socketIOPacket, err error) {
+	if frame == "" {
+		return p, errors.New("empty frame")
+	}
+	p.Engine, p.Namespace, p.AckID = frame[0], "/", -1
+	if p.Engine < eioOpen || p.Engine > eioNoop {
+		return p, errors.New("unknown engine packet " + frame)
+	}
+	rest := frame[1:]
+	if p.Engine != eioMessage {
+		p.Data = rest
+		return p, nil
+	}
+	if rest == "" {
+		return p, errors.New("empty message packet")
+	}
+	p.Type, rest = rest[0], rest[1:]
+	if p.Type < sioConnect || p.Type > sioConnectError {
+		return p, errors.New("unknown socket.io packet " + frame)
+	}
+	if strings.HasPrefix(rest, "/") {
+		idx := strings.Index(rest, ",")
+		if idx == -1 {
+			p.Namespace, rest = rest, ""
+		} else {
+			p.Namespace, rest = rest[:idx], rest[idx+1:]
+		}
+	}
+	i := 0
+	for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+		i++
+	}
+	if i > 0 {
+		p.AckID, _ = strconv.ParseInt(rest[:i], 10, 64)
+	}
+	p.Data = rest[i:]
+	return p, nil
+}
+
+// encodeSocketIOPacket encodes a socket.io packet in an engine.io message frame, ackID -1 for none
+func encodeSocketIOPacket(typ byte, namespace string, ackID int64, data string) string {
+	out := string([]byte{eioMessage, typ})
+	if namespace != "" && namespace != "/" {
+		out += namespace + ","
+	}
+	if ackID >= 0 {
+		out += utils.FI64(ackID)
+	}
+	return out + data
+}
+
+// event returns the name and the arguments of an event packet
+func (p socketIOPacket) event() (name string, args []json.RawMessage, err error) {
+	var arr []json.RawMessage
+	if err = json.Unmarshal([]byte(p.Data), &arr); err != nil {
+		return
+	}
+	if len(arr) == 0 {
+		return "", nil, errors.New("empty event")
+	}
+	if err = json.Unmarshal(arr[0], &name); err != nil {
+		return
+	}
+	return name, arr[1:], nil
+}
+
+// ChatReconnectPolicy how the bot reconnects to the chat websocket when the connection is lost
+type ChatReconnectPolicy struct {
+	MinDelay    time.Duration // delay before the first reconnection, 1s if not set
+	MaxDelay    time.Duration // the delay doubles after every failed attempt up to MaxDelay, 60s if not set
+	MaxAttempts int64         // consecutive failed attempts before giving up, 0 to retry forever
+}
+
+// delay returns the delay before the given reconnection attempt (starting at 1)
+func (p ChatReconnectPolicy) delay(attempt int64) time.Duration {
+	minDelay, maxDelay := p.MinDelay, p.MaxDelay
+	if minDelay <= 0 {
+		minDelay = time.Second
+	}
+	if maxDelay <= 0 {
+		maxDelay = time.Minute
+	}
+	d := minDelay
+	for i := int64(1); i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// SetChatReconnectPolicy sets how the bot reconnects to the chat websocket
+func (b *OGame) SetChatReconnectPolicy(policy ChatReconnectPolicy) {
+	b.chatReconnectPolicy.Store(policy)
+}
+
+func (b *OGame) getChatReconnectPolicy() ChatReconnectPolicy {
+	policy, _ := b.chatReconnectPolicy.Load().(ChatReconnectPolicy)
+	return policy
+}
+
+// OnChatDisconnected registers a callback called when the chat websocket is disconnected,
+// err is nil if the bot closed the connection itself (logout)
+func (b *OGame) OnChatDisconnected(clb func(err error)) {
+	b.chatDisconnectedCallbacks = append(b.chatDisconnectedCallbacks, clb)
+}
+
+func (b *OGame) chatDisconnected(err error) {
+	for _, clb := range b.chatDisconnectedCallbacks {
+		clb(err)
+	}
+}
+
+// chatLoop keeps the chat websocket connected until closeCh is closed, following the reconnect policy
+func (b *OGame) chatLoop(host, port string, closeCh chan struct{}) {
+	attempt := int64(0)
+	for {
+		select {
+		case <-closeCh:
+			return
+		default:
+		}
+		handshakeOK, err := b.connectChat(host, port, closeCh)
+		select {
+		case <-closeCh:
+			b.chatDisconnected(nil)
+			return
+		default:
+		}
+		b.chatDisconnected(err)
+		if handshakeOK {
+			attempt = 0
+		}
+		attempt++
+		policy := b.getChatReconnectPolicy()
+		if policy.MaxAttempts > 0 && attempt > policy.MaxAttempts {
+			b.error("chat reconnection aborted after", policy.MaxAttempts, "attempts")
+			return
+		}
+		select {
+		case <-time.After(policy.delay(attempt)):
+		case <-closeCh:
+			return
+		}
+	}
+}
+
+// connectChat connects to the chat websocket and reads it until the connection is lost,
+// returns true if the handshake succeeded
+func (b *OGame) connectChat(host, port string, closeCh chan struct{}) (bool, error) {
+	if b.IsV8() || b.IsV9() {
+		return b.connectChatV8(host, port, closeCh)
+	}
+	return b.connectChatV7(host, port, closeCh)
+}
+
+func (b *OGame) connectChatV8(host, port string, closeCh chan struct{}) (bool, error) {
+	token := yeast(time.Now().UnixNano() / 1000000)
+	req, err := http.NewRequest(http.MethodGet, "https://"+host+":"+port+"/socket.io/?EIO=4&transport=polling&t="+token, nil)
+	if err != nil {
+		return false, err
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, errors.New("failed to get socket.io token: " + err.Error())
+	}
+	defer resp.Body.Close()
+	by, _ := ioutil.ReadAll(resp.Body)
+	openPacket, err := parseSocketIOPacket(string(by))
+	if err != nil || openPacket.Engine != eioOpen {
+		return false, errors.New("failed to get websocket sid: " + string(by))
+	}
+	var handshake struct {
+		Sid          string `json:"sid"`
+		PingInterval int64  `json:"pingInterval"`
+		PingTimeout  int64  `json:"pingTimeout"`
+	}
+	if err := json.Unmarshal([]byte(openPacket.Data), &handshake); err != nil || handshake.Sid == "" {
+		return false, errors.New("failed to get websocket sid: " + string(by))
+	}
+	pingTimeout := time.Duration(handshake.PingInterval+handshake.PingTimeout) * time.Millisecond
+	if pingTimeout <= 0 {
+		pingTimeout = 45 * time.Second
+	}
+
+	origin := "https://" + host + ":" + port + "/"
+	wssURL := "wss://" + host + ":" + port + "/socket.io/?EIO=4&transport=websocket&sid=" + handshake.Sid
+	b.ws, err = websocket.Dial(wssURL, "", origin)
+	if err != nil {
+		return false, errors.New("failed to dial websocket: " + err.Error())
+	}
+	defer b.ws.Close()
+	_ = websocket.Message.Send(b.ws, string(eioPing)+"probe")
+
+	lastPing := time.Now()
+	for {
+		select {
+		case <-closeCh:
+			return true, nil
+		default:
+		}
+
+		var buf string
+		if err := b.ws.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			b.error("failed to set read deadline:", err)
+		}
+		if err := websocket.Message.Receive(b.ws, &buf); err != nil {
+			if strings.HasSuffix(err.Error(), "i/o timeout") {
+				if time.Since(lastPing) > pingTimeout {
+					return true, errors.New("chat ping timeout")
+				}
+				continue
+			}
+			if err == io.EOF || strings.HasSuffix(err.Error(), "use of closed network connection") {
+				return true, err
+			}
+			b.error("chat unexpected error", err)
+			return true, err // connection reset by peer
+		}
+		for _, clb := range b.wsCallbacks {
+			go clb([]byte(buf))
+		}
+		p, err := parseSocketIOPacket(buf)
+		if err != nil {
+			b.debug("unknown frame received:", buf)
+			continue
+		}
+		switch p.Engine {
+		case eioPing:
+			lastPing = time.Now()
+			_ = websocket.Message.Send(b.ws, string(eioPong)+p.Data)
+		case eioPong:
+			if p.Data == "probe" {
+				_ = websocket.Message.Send(b.ws, string(eioUpgrade))
+				for _, namespace := range chatNamespaces {
+					_ = websocket.Message.Send(b.ws, encodeSocketIOPacket(sioConnect, namespace, -1, ""))
+				}
+			}
+		case eioClose:
+			return true, errors.New("chat closed by the server")
+		case eioMessage:
+			b.handleSocketIOPacket(p)
+		}
+	}
+}
+
+// handleSocketIOPacket handles the socket.io packets of the chat and auctioneer namespaces
+func (b *OGame) handleSocketIOPacket(p socketIOPacket) {
+	switch p.Type {
+	case sioConnect:
+		b.debug("got", p.Namespace, "sid")
+		if p.Namespace == "/chat" {
+			_ = websocket.Message.Send(b.ws, encodeSocketIOPacket(sioEvent, "/chat", b.sessionChatCounter, `["authorize","`+b.ogameSession+`"]`))
+			b.sessionChatCounter++
+		}
+	case sioDisconnect:
+		b.debug("disconnected from", p.Namespace, ", subscribing again")
+		_ = websocket.Message.Send(b.ws, encodeSocketIOPacket(sioConnect, p.Namespace, -1, ""))
+	case sioConnectError:
+		b.error("failed to connect to", p.Namespace, p.Data)
+	case sioAck:
+		if p.Namespace == "/chat" {
+			if p.Data == "[true]" {
+				b.debug("chat connected")
+			} else {
+				b.error("Failed to connect to chat")
+			}
+		}
+	case sioEvent:
+		name, args, err := p.event()
+		if err != nil {
+			b.debug("invalid event received:", p.Data)
+			return
+		}
+		switch p.Namespace {
+		case "/chat":
+			if name != "chat" || len(args) == 0 {
+				return
+			}
+			var chatMsg ogame.ChatMsg
+			if err := json.Unmarshal(args[0], &chatMsg); err != nil {
+				b.error("Unable to unmarshal chat payload", err, string(args[0]))
+				return
+			}
+			b.deliverChatMsg(chatMsg)
+		case "/auctioneer":
+			var pck any = p.Data
+			if len(args) > 0 {
+				var arg any
+				_ = json.Unmarshal(args[0], &arg)
+				if typed := parseAuctioneerEvent(name, arg); typed != nil {
+					pck = typed
+				}
+			}
+			b.deliverAuctioneerPacket(pck)
+		}
+	}
+}
+
+// parseAuctioneerEvent converts an auctioneer event to its typed packet, nil if the event is unknown
+func parseAuctioneerEvent(name string, arg any) ogame.AuctioneerEvent {
+	switch name {
+	case "new bid":
+		if firstArg, ok := arg.(map[string]any); ok {
+			pck := ogame.AuctioneerNewBid{
+				Sum:       int64(utils.DoCastF64(firstArg["sum"])),
+				Price:     int64(utils.DoCastF64(firstArg["price"])),
+				Bids:      int64(utils.DoCastF64(firstArg["bids"])),
+				AuctionID: utils.DoParseI64(utils.DoCastStr(firstArg["auctionId"])),
+			}
+			if player, ok := firstArg["player"].(map[string]any); ok {
+				pck.Player.ID = int64(utils.DoCastF64(player["id"]))
+				pck.Player.Name = utils.DoCastStr(player["name"])
+				pck.Player.Link = utils.DoCastStr(player["link"])
+			}
+			return pck
+		}
+	case "timeLeft":
+		if timeLeftMsg, ok := arg.(string); ok {
+			if strings.Contains(timeLeftMsg, "color:") {
+				doc, _ := goquery.NewDocumentFromReader(strings.NewReader(timeLeftMsg))
+				approx := utils.DoParseI64(regexp.MustCompile(`\d+`).FindString(doc.Find("b").Text()))
+				return ogame.AuctioneerTimeRemaining{Approx: approx * 60}
+			} else if strings.Contains(timeLeftMsg, "nextAuction") {
+				doc, _ := goquery.NewDocumentFromReader(strings.NewReader(timeLeftMsg))
+				secs := utils.DoParseI64(regexp.MustCompile(`\d+`).FindString(doc.Find("span").Text()))
+				return ogame.AuctioneerNextAuction{Secs: secs}
+			}
+		}
+	case "new auction":
+		if firstArg, ok := arg.(map[string]any); ok {
+			pck := ogame.AuctioneerNewAuction{
+				AuctionID: int64(utils.DoCastF64(firstArg["auctionId"])),
+			}
+			if infoMsg, ok := firstArg["info"].(string); ok {
+				doc, _ := goquery.NewDocumentFromReader(strings.NewReader(infoMsg))
+				approx := utils.DoParseI64(regexp.MustCompile(`\d+`).FindString(doc.Find("b").Text()))
+				pck.Approx = approx * 60
+			}
+			pck.Item = parseAuctionItem(firstArg["item"])
+			if oldAuction, ok := firstArg["oldAuction"].(map[string]any); ok {
+				pck.OldItem = parseAuctionItem(oldAuction["item"])
+			}
+			return pck
+		}
+	case "auction finished":
+		if firstArg, ok := arg.(map[string]any); ok {
+			pck := ogame.AuctioneerAuctionFinished{
+				Sum:  int64(utils.DoCastF64(firstArg["sum"])),
+				Bids: int64(utils.DoCastF64(firstArg["bids"])),
+				Time: utils.DoCastStr(firstArg["time"]),
+			}
+			if infoMsg, ok := firstArg["info"].(string); ok {
+				doc, _ := goquery.NewDocumentFromReader(strings.NewReader(infoMsg))
+				pck.NextAuction = utils.DoParseI64(regexp.MustCompile(`\d+`).FindString(doc.Find("span").Text()))
+			}
+			if player, ok := firstArg["player"].(map[string]any); ok {
+				pck.Player.ID = int64(utils.DoCastF64(player["id"]))
+				pck.Player.Name = utils.DoCastStr(player["name"])
+				pck.Player.Link = utils.DoCastStr(player["link"])
+			}
+			return pck
+		}
+	}
+	return nil
+}
+
+// parseAuctionItem parses the "item" object of the auctioneer events
+func parseAuctionItem(v any) (item ogame.AuctionItem) {
+	if m, ok := v.(map[string]any); ok {
+		item.UUID = utils.DoCastStr(m["uuid"])
+		item.Image = utils.DoCastStr(m["image"])
+		if item.Image == "" {
+			item.Image = utils.DoCastStr(m["imageSmall"])
+		}
+		item.Rarity = utils.DoCastStr(m["rarity"])
+	}
+	return
+}
+
+// SubscribeAuctioneer returns a channel receiving the typed auctioneer events, close the subscription to stop receiving.
+// Events are dropped if the channel buffer is full, the consumer must keep up.
+func (b *OGame) SubscribeAuctioneer() (<-chan ogame.AuctioneerEvent, *Subscription) {
+	ch := make(chan ogame.AuctioneerEvent, 100)
+	sub := b.RegisterAuctioneerCallback(func(packet any) {
+		if evt, ok := packet.(ogame.AuctioneerEvent); ok {
+			select {
+			case ch <- evt:
+			default:
+			}
+		}
+	})
+	return ch, sub
+}
+
+// isChatConnected returns true if the chat websocket is connected
+func (b *OGame) isChatConnected() bool {
+	return b.ws != nil && atomic.LoadInt32(&b.chatConnectedAtom) == 1
+}
+
+// startChat starts the chat websocket goroutine, or re-subscribes to the chat if it is already running
+func (b *OGame) startChat() error {
+	b.chatMu.Lock()
+	defer b.chatMu.Unlock()
+	if b.chatHost == "" {
+		return errors.New("chat host unknown, not logged in")
+	}
+	if atomic.LoadInt32(&b.chatConnectedAtom) == 1 {
+		b.ReconnectChat()
+		return nil
+	}
+	closeCh := make(chan struct{})
+	b.closeChatCh = closeCh
+	atomic.StoreInt32(&b.chatConnectedAtom, 1)
+	go func(host, port string) {
+		b.chatLoop(host, port, closeCh)
+		b.chatMu.Lock()
+		defer b.chatMu.Unlock()
+		if b.closeChatCh == closeCh { // gave up reconnecting
+			b.stopChat()
+		}
+	}(b.chatHost, b.chatPort)
+	return nil
+}
+
+// stopChat closes the chat websocket, must be called with chatMu held
+func (b *OGame) stopChat() {
+	if atomic.CompareAndSwapInt32(&b.chatConnectedAtom, 1, 0) {
+		close(b.closeChatCh)
+		if b.ws != nil {
+			_ = b.ws.Close()
+		}
+	}
+}
+
+// ConnectChat connects the chat/auctioneer websocket, eg: after DisconnectChat or when the bot was created with DisableChat
+func (b *OGame) ConnectChat() error {
+	atomic.StoreInt32(&b.chatDisabledAtom, 0)
+	return b.startChat()
+}
+
+// DisconnectChat closes the chat/auctioneer websocket, it is not reconnected on the next login until ConnectChat is called
+func (b *OGame) DisconnectChat() {
+	atomic.StoreInt32(&b.chatDisabledAtom, 1)
+	b.chatMu.Lock()
+	defer b.chatMu.Unlock()
+	b.stopChat()
+}
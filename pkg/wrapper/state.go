@@ -0,0 +1,93 @@
+package wrapper
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// BotState snapshot of the bot caches, used to resume a restarted process without fetching everything again
+type BotState struct {
+	ExportedAt            time.Time
+	Universe              string
+	PlayerID              int64
+	Player                ogame.UserInfos
+	Planets               []ogame.Planet
+	Researches            *ogame.Researches
+	Preferences           ogame.Preferences
+	ServerData            ServerData
+	CharacterClass        ogame.CharacterClass
+	IsVacationModeEnabled bool
+	HasCommander          bool
+	HasAdmiral            bool
+	HasEngineer           bool
+	HasGeologist          bool
+	HasTechnocrat         bool
+	MessageCursors        map[ogame.MessagesTabID]int64 // last message seen by the subscriptions created with SubscribeMessages
+	SeenAttacks           []int64                       // attacks already reported by the runners created with RunStrategies
+}
+
+// ExportState serializes the bot caches to json
+func (b *OGame) ExportState() ([]byte, error) {
+	state := BotState{
+		ExportedAt:            time.Now(),
+		Universe:              b.Universe,
+		PlayerID:              b.playerID,
+		Player:                b.Player,
+		Researches:            b.researches,
+		Preferences:           b.CachedPreferences,
+		ServerData:            b.serverData,
+		CharacterClass:        b.characterClass,
+		IsVacationModeEnabled: b.isVacationModeEnabled,
+		HasCommander:          b.hasCommander,
+		HasAdmiral:            b.hasAdmiral,
+		HasEngineer:           b.hasEngineer,
+		HasGeologist:          b.hasGeologist,
+		HasTechnocrat:         b.hasTechnocrat,
+		MessageCursors:        make(map[ogame.MessagesTabID]int64),
+	}
+	b.planetsMu.RLock()
+	state.Planets = planetsToOgame(b.planets)
+	b.planetsMu.RUnlock()
+	b.stateMu.Lock()
+	for _, sub := range b.messageSubscriptions {
+		for tabID, lastID := range sub.Cursors() {
+			state.MessageCursors[tabID] = lastID
+		}
+	}
+	for _, runner := range b.strategyRunners {
+		state.SeenAttacks = append(state.SeenAttacks, runner.SeenAttacks()...)
+	}
+	b.stateMu.Unlock()
+	return json.Marshal(state)
+}
+
+// ImportState restores the bot caches from a json exported with ExportState.
+// Message cursors and seen attacks are applied to the subscriptions and runners created afterward.
+func (b *OGame) ImportState(data []byte) error {
+	var state BotState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	b.planetsMu.Lock()
+	b.planets = convertPlanets(b, state.Planets)
+	b.planetsMu.Unlock()
+	b.touchCache(PlanetsCache)
+	b.Player = state.Player
+	b.researches = state.Researches
+	b.touchCache(ResearchesCache)
+	b.CachedPreferences = state.Preferences
+	b.serverData = state.ServerData
+	b.characterClass = state.CharacterClass
+	b.isVacationModeEnabled = state.IsVacationModeEnabled
+	b.hasCommander = state.HasCommander
+	b.hasAdmiral = state.HasAdmiral
+	b.hasEngineer = state.HasEngineer
+	b.hasGeologist = state.HasGeologist
+	b.hasTechnocrat = state.HasTechnocrat
+	b.stateMu.Lock()
+	b.importedState = state
+	b.stateMu.Unlock()
+	return nil
+}
@@ -0,0 +1,163 @@
+package wrapper
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// StorageOverflow resource of a celestial predicted to reach its storage capacity soon
+type StorageOverflow struct {
+	CelestialID ogame.CelestialID
+	Resource    ogame.ID      // MetalStorageID, CrystalStorageID or DeuteriumTankID
+	FullIn      time.Duration // 0 if the storage is already full
+}
+
+// StorageWatcher periodically checks the resources of every celestial and reports the ones
+// that will be full within the horizon.
+//
+//	watcher := wrapper.NewStorageWatcher(bot, 6*time.Hour)
+//	watcher.OnOverflow(func(o wrapper.StorageOverflow) { fmt.Println(o.CelestialID, o.Resource, o.FullIn) })
+//	watcher.Start()
+//	defer watcher.Stop()
+type StorageWatcher struct {
+	b                 Wrapper
+	horizon           time.Duration
+	interval          time.Duration
+	mu                sync.Mutex
+	cancel            context.CancelFunc
+	overflowCallbacks []func(StorageOverflow)
+	errorCallbacks    []func(ogame.CelestialID, error)
+}
+
+// NewStorageWatcher creates a watcher reporting the storages full within horizon
+func NewStorageWatcher(b Wrapper, horizon time.Duration) *StorageWatcher {
+	return &StorageWatcher{
+		b:        b,
+		horizon:  horizon,
+		interval: 30 * time.Minute,
+	}
+}
+
+// SetInterval sets how often the celestials are checked
+func (w *StorageWatcher) SetInterval(d time.Duration) *StorageWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.interval = d
+	return w
+}
+
+// OnOverflow registers a callback executed for every storage full within the horizon, at each check
+func (w *StorageWatcher) OnOverflow(clb func(StorageOverflow)) *StorageWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.overflowCallbacks = append(w.overflowCallbacks, clb)
+	return w
+}
+
+// OnError registers a callback executed when the resources of a celestial cannot be fetched
+func (w *StorageWatcher) OnError(clb func(ogame.CelestialID, error)) *StorageWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.errorCallbacks = append(w.errorCallbacks, clb)
+	return w
+}
+
+// Start starts checking in the background, until Stop is called
+func (w *StorageWatcher) Start() {
+	w.mu.Lock()
+	if w.cancel != nil {
+		w.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.mu.Unlock()
+	go func() {
+		for {
+			w.Check()
+			w.mu.Lock()
+			interval := w.interval
+			w.mu.Unlock()
+			select {
+			case <-time.After(w.b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks
+func (w *StorageWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
+
+// Check fetches the resources of every celestial once and reports the storages full within the horizon
+func (w *StorageWatcher) Check() {
+	if !w.b.IsLoggedIn() {
+		return
+	}
+	for _, celestial := range w.b.GetCachedCelestials() {
+		celestialID := celestial.GetID()
+		details, err := w.b.GetResourcesDetails(celestialID)
+		if err != nil {
+			w.emitError(celestialID, err)
+			continue
+		}
+		overflows := storageOverflows(celestialID, details, w.horizon)
+		w.mu.Lock()
+		callbacks := w.overflowCallbacks
+		w.mu.Unlock()
+		for _, overflow := range overflows {
+			for _, clb := range callbacks {
+				clb(overflow)
+			}
+		}
+	}
+}
+
+func (w *StorageWatcher) emitError(celestialID ogame.CelestialID, err error) {
+	w.mu.Lock()
+	callbacks := w.errorCallbacks
+	w.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(celestialID, err)
+	}
+}
+
+// storageOverflows returns the resources of the celestial that will be full within horizon
+func storageOverflows(celestialID ogame.CelestialID, details ogame.ResourcesDetails, horizon time.Duration) (out []StorageOverflow) {
+	for _, res := range []struct {
+		id                                     ogame.ID
+		available, storageCapacity, production int64
+	}{
+		{ogame.MetalStorageID, details.Metal.Available, details.Metal.StorageCapacity, details.Metal.CurrentProduction},
+		{ogame.CrystalStorageID, details.Crystal.Available, details.Crystal.StorageCapacity, details.Crystal.CurrentProduction},
+		{ogame.DeuteriumTankID, details.Deuterium.Available, details.Deuterium.StorageCapacity, details.Deuterium.CurrentProduction},
+	} {
+		if res.storageCapacity <= 0 {
+			continue
+		}
+		var fullIn time.Duration
+		if res.available < res.storageCapacity {
+			if res.production <= 0 {
+				continue
+			}
+			hours := float64(res.storageCapacity-res.available) / float64(res.production)
+			fullIn = time.Duration(math.Ceil(hours * float64(time.Hour)))
+		}
+		if fullIn <= horizon {
+			out = append(out, StorageOverflow{CelestialID: celestialID, Resource: res.id, FullIn: fullIn})
+		}
+	}
+	return
+}
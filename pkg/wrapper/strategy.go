@@ -0,0 +1,238 @@
+package wrapper
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// Strategy a reusable behavior driven by a StrategyRunner (eg: fleetsaver, farmer, expeditions).
+// Embed BaseStrategy to only implement the hooks you need.
+type Strategy interface {
+	OnStart(b Wrapper) error                              // called once when the runner starts, an error disables the strategy
+	OnAttackDetected(b Wrapper, attack ogame.AttackEvent) // called once per new hostile fleet
+	OnFleetReturned(b Wrapper, fleet ogame.Fleet)         // called once a fleet is back home
+	OnTick(b Wrapper)                                     // called at every tick, after the other hooks
+}
+
+// BaseStrategy no-op implementation of Strategy
+type BaseStrategy struct{}
+
+// OnStart ...
+func (BaseStrategy) OnStart(Wrapper) error { return nil }
+
+// OnAttackDetected ...
+func (BaseStrategy) OnAttackDetected(Wrapper, ogame.AttackEvent) {}
+
+// OnFleetReturned ...
+func (BaseStrategy) OnFleetReturned(Wrapper, ogame.Fleet) {}
+
+// OnTick ...
+func (BaseStrategy) OnTick(Wrapper) {}
+
+// StrategyRunner polls the event list and the fleets at every tick and drives the strategies.
+// Strategies are called in the order they were added.
+//
+//	runner := wrapper.NewStrategyRunner(bot, fleetSaver, expeditions).SetTickInterval(time.Minute)
+//	runner.Start()
+//	defer runner.Stop()
+type StrategyRunner struct {
+	b              Wrapper
+	strategies     []Strategy
+	tickInterval   time.Duration
+	seenAttacks    map[int64]struct{}
+	fleets         []ogame.Fleet
+	mu             sync.Mutex
+	cancel         context.CancelFunc
+	errorCallbacks []func(Strategy, error)
+}
+
+// NewStrategyRunner creates a runner for the given strategies
+func NewStrategyRunner(b Wrapper, strategies ...Strategy) *StrategyRunner {
+	return &StrategyRunner{
+		b:            b,
+		strategies:   strategies,
+		tickInterval: time.Minute,
+		seenAttacks:  make(map[int64]struct{}),
+	}
+}
+
+// SetTickInterval sets how often the strategies are driven
+func (r *StrategyRunner) SetTickInterval(d time.Duration) *StrategyRunner {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tickInterval = d
+	return r
+}
+
+// OnError registers a callback executed when a strategy fails to start (strategy is nil when polling fails)
+func (r *StrategyRunner) OnError(clb func(Strategy, error)) *StrategyRunner {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errorCallbacks = append(r.errorCallbacks, clb)
+	return r
+}
+
+// Start starts the strategies and drives them in the background, until Stop is called
+func (r *StrategyRunner) Start() {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	started := make([]Strategy, 0, len(r.strategies))
+	strategies := r.strategies
+	r.mu.Unlock()
+	for _, strategy := range strategies {
+		if err := strategy.OnStart(r.b); err != nil {
+			r.emitError(strategy, err)
+			continue
+		}
+		started = append(started, strategy)
+	}
+	r.mu.Lock()
+	r.strategies = started
+	r.mu.Unlock()
+	go func() {
+		for {
+			r.Tick()
+			r.mu.Lock()
+			interval := r.tickInterval
+			r.mu.Unlock()
+			select {
+			case <-time.After(r.b.HumanizeInterval(interval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops driving the strategies
+func (r *StrategyRunner) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// SeenAttacks returns the ids of the attacks already reported to the strategies
+func (r *StrategyRunner) SeenAttacks() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]int64, 0, len(r.seenAttacks))
+	for id := range r.seenAttacks {
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// SetSeenAttacks sets the ids of the attacks already reported to the strategies, eg: to resume from an exported state
+func (r *StrategyRunner) SetSeenAttacks(ids []int64) *StrategyRunner {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.seenAttacks[id] = struct{}{}
+	}
+	return r
+}
+
+// Tick polls the attacks and fleets once and calls the strategies hooks
+func (r *StrategyRunner) Tick() {
+	if !r.b.IsLoggedIn() {
+		return
+	}
+	// A failed poll would forget the attacks and report every fleet as returned, the tick is skipped
+	attacks, err := r.b.GetAttacks()
+	if err != nil {
+		r.emitError(nil, err)
+		return
+	}
+	fleets, err := fetchFleets(r.b)
+	if err != nil {
+		r.emitError(nil, err)
+		return
+	}
+	r.mu.Lock()
+	newAttacks := newStrategyAttacks(r.seenAttacks, attacks)
+	returned := returnedFleets(r.fleets, fleets, time.Now())
+	r.fleets = fleets
+	strategies := r.strategies
+	r.mu.Unlock()
+	for _, strategy := range strategies {
+		for _, attack := range newAttacks {
+			strategy.OnAttackDetected(r.b, attack)
+		}
+		for _, fleet := range returned {
+			strategy.OnFleetReturned(r.b, fleet)
+		}
+		strategy.OnTick(r.b)
+	}
+}
+
+// fetchFleets returns the fleets of the player, and the error of the movement page when the wrapper can report it
+func fetchFleets(b Wrapper) ([]ogame.Fleet, error) {
+	bot, ok := b.(*OGame)
+	if !ok {
+		fleets, _ := b.GetFleets()
+		return fleets, nil
+	}
+	var fleets []ogame.Fleet
+	err := bot.Tx(func(Prioritizable) (err error) {
+		fleets, _, err = bot.getFleetsErr()
+		return err
+	})
+	return fleets, err
+}
+
+func (r *StrategyRunner) emitError(strategy Strategy, err error) {
+	r.mu.Lock()
+	callbacks := r.errorCallbacks
+	r.mu.Unlock()
+	for _, clb := range callbacks {
+		clb(strategy, err)
+	}
+}
+
+// newStrategyAttacks returns the attacks not seen yet and updates seen, attacks no longer listed are forgotten
+func newStrategyAttacks(seen map[int64]struct{}, attacks []ogame.AttackEvent) (out []ogame.AttackEvent) {
+	curr := make(map[int64]struct{})
+	for _, attack := range attacks {
+		curr[attack.ID] = struct{}{}
+		if _, ok := seen[attack.ID]; !ok {
+			out = append(out, attack)
+		}
+	}
+	for id := range seen {
+		if _, ok := curr[id]; !ok {
+			delete(seen, id)
+		}
+	}
+	for id := range curr {
+		seen[id] = struct{}{}
+	}
+	return
+}
+
+// returnedFleets returns the fleets of prev that are gone from curr once their return time is reached
+func returnedFleets(prev, curr []ogame.Fleet, now time.Time) (out []ogame.Fleet) {
+	currIDs := make(map[ogame.FleetID]struct{})
+	for _, fleet := range curr {
+		currIDs[fleet.ID] = struct{}{}
+	}
+	for _, fleet := range prev {
+		if _, ok := currIDs[fleet.ID]; ok || fleet.BackTime.IsZero() || fleet.BackTime.After(now) {
+			continue
+		}
+		out = append(out, fleet)
+	}
+	return
+}
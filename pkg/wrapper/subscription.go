@@ -0,0 +1,92 @@
+package wrapper
+
+import (
+	"sync"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// Subscription handle of a registered callback, Close unregisters it
+type Subscription struct {
+	once  sync.Once
+	close func()
+}
+
+// Close unregisters the callback, it is safe to call Close more than once
+func (s *Subscription) Close() {
+	if s == nil || s.close == nil {
+		return
+	}
+	s.once.Do(s.close)
+}
+
+type callbackEntry[T any] struct {
+	id int64
+	fn T
+}
+
+// callbackList list of callbacks that can be unregistered, safe for concurrent use
+type callbackList[T any] struct {
+	mu      sync.Mutex
+	nextID  int64
+	entries []callbackEntry[T]
+}
+
+func (l *callbackList[T]) add(fn T) *Subscription {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nextID++
+	id := l.nextID
+	l.entries = append(l.entries, callbackEntry[T]{id: id, fn: fn})
+	return &Subscription{close: func() { l.remove(id) }}
+}
+
+func (l *callbackList[T]) remove(id int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, entry := range l.entries {
+		if entry.id == id {
+			l.entries = append(l.entries[:i:i], l.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// list returns a copy of the callbacks, so they can be called without holding the lock
+func (l *callbackList[T]) list() []T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]T, len(l.entries))
+	for i, entry := range l.entries {
+		out[i] = entry.fn
+	}
+	return out
+}
+
+func (l *callbackList[T]) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// safeCall calls fn, a panicking callback is logged instead of crashing the bot
+func (b *OGame) safeCall(name string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.error(name, "callback panicked:", r)
+		}
+	}()
+	fn()
+}
+
+func (b *OGame) deliverChatMsg(msg ogame.ChatMsg) {
+	for _, clb := range b.chatCallbacks.list() {
+		b.safeCall("chat", func() { clb(msg) })
+	}
+}
+
+func (b *OGame) deliverAuctioneerPacket(packet any) {
+	for _, clb := range b.auctioneerCallbacks.list() {
+		b.safeCall("auctioneer", func() { clb(packet) })
+	}
+}
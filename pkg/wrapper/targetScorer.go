@@ -0,0 +1,69 @@
+package wrapper
+
+import (
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// TargetInfo raid candidate given to a TargetScorer
+type TargetInfo struct {
+	Report         ogame.EspionageReport
+	CharacterClass ogame.CharacterClass
+	Loot           ogame.Resources // expected plunder
+	Cargos         int64           // cargo ships needed to carry the loot
+	FlightTime     int64           // one way flight time in seconds
+	Fuel           int64           // deuterium consumed by the raid
+	DefenseValue   int64           // value of the ships and defenses on the target, -1 if the report does not include them
+}
+
+// NewTargetInfo returns the TargetInfo of a report, without flight time and fuel
+func NewTargetInfo(report ogame.EspionageReport, characterClass ogame.CharacterClass) TargetInfo {
+	info := TargetInfo{Report: report, CharacterClass: characterClass, Loot: report.Loot(characterClass), DefenseValue: -1}
+	infos := report.Infos()
+	if infos.ShipsInfos != nil && infos.DefensesInfos != nil {
+		info.DefenseValue = infos.ShipsInfos.FleetValue() + infos.DefensesInfos.AttackableValue()
+	}
+	return info
+}
+
+// TargetScorer scores a raid candidate, the best targets are raided first and the ones scored 0 or less are not raided
+type TargetScorer interface {
+	Score(TargetInfo) float64
+}
+
+// TargetScorerFunc adapts a function to the TargetScorer interface
+type TargetScorerFunc func(TargetInfo) float64
+
+// Score calls f(target)
+func (f TargetScorerFunc) Score(target TargetInfo) float64 {
+	return f(target)
+}
+
+// DefaultTargetScorer scores defenceless targets by the resources gained (loot minus fuel) per hour of round trip.
+// Targets with ships or defenses, or whose report does not include them, are scored 0.
+type DefaultTargetScorer struct {
+	MaxFlightTime int64   // seconds, targets further away are scored 0, 0 for no limit
+	MaxRisk       float64 // defense value to loot ratio accepted (eg: 0.1 accepts a few rocket launchers), 0 for defenceless targets only
+}
+
+// Score implements TargetScorer
+func (s DefaultTargetScorer) Score(target TargetInfo) float64 {
+	loot := target.Loot.Total()
+	if target.DefenseValue < 0 || loot <= 0 {
+		return 0
+	}
+	if float64(target.DefenseValue) > s.MaxRisk*float64(loot) {
+		return 0
+	}
+	if s.MaxFlightTime > 0 && target.FlightTime > s.MaxFlightTime {
+		return 0
+	}
+	profit := loot - target.Fuel
+	if profit <= 0 {
+		return 0
+	}
+	roundTrip := 2 * target.FlightTime
+	if roundTrip < 1 {
+		roundTrip = 1
+	}
+	return float64(profit) * 3600 / float64(roundTrip)
+}
@@ -0,0 +1,145 @@
+package wrapper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+const trashsimBaseURL = "https://trashsim.oplanet.eu/"
+
+type trashsimCount struct {
+	Count int64 `json:"count"`
+}
+
+type trashsimLevel struct {
+	Level int64 `json:"level"`
+}
+
+type trashsimPlanet struct {
+	Galaxy   int64 `json:"galaxy"`
+	System   int64 `json:"system"`
+	Position int64 `json:"position"`
+}
+
+type trashsimResources struct {
+	Metal     trashsimAmount `json:"metal"`
+	Crystal   trashsimAmount `json:"crystal"`
+	Deuterium trashsimAmount `json:"deuterium"`
+}
+
+type trashsimAmount struct {
+	Value int64 `json:"value"`
+}
+
+type trashsimParty struct {
+	Class     ogame.CharacterClass     `json:"class"`
+	Planet    *trashsimPlanet          `json:"planet,omitempty"`
+	Research  map[string]trashsimLevel `json:"research,omitempty"`
+	Ships     map[string]trashsimCount `json:"ships,omitempty"`
+	Resources *trashsimResources       `json:"resources,omitempty"`
+}
+
+type trashsimSettings struct {
+	SpeedFleetPeaceful  int64   `json:"speed_fleet_peaceful"`
+	SpeedFleetWar       int64   `json:"speed_fleet_war"`
+	SpeedFleetHolding   int64   `json:"speed_fleet_holding"`
+	Galaxies            int64   `json:"galaxies"`
+	Systems             int64   `json:"systems"`
+	RapidFire           int64   `json:"rapid_fire"`
+	DefToTF             int64   `json:"def_to_tf"`
+	DebrisFactor        float64 `json:"debris_factor"`
+	DebrisFactorDef     float64 `json:"debris_factor_def"`
+	RepairFactor        float64 `json:"repair_factor"`
+	DonutGalaxy         int64   `json:"donut_galaxy"`
+	DonutSystem         int64   `json:"donut_system"`
+	DeuteriumSaveFactor float64 `json:"deuterium_save_factor"`
+}
+
+type trashsimPrefill struct {
+	Attackers []trashsimParty  `json:"0"`
+	Defenders []trashsimParty  `json:"1"`
+	Settings  trashsimSettings `json:"settings"`
+}
+
+func trashsimFlag(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func trashsimResearch(techs ogame.Researches) map[string]trashsimLevel {
+	out := make(map[string]trashsimLevel)
+	for _, id := range []ogame.ID{ogame.WeaponsTechnologyID, ogame.ShieldingTechnologyID, ogame.ArmourTechnologyID,
+		ogame.CombustionDriveID, ogame.ImpulseDriveID, ogame.HyperspaceDriveID, ogame.HyperspaceTechnologyID} {
+		out[strconv.FormatInt(int64(id), 10)] = trashsimLevel{Level: techs.ByID(id)}
+	}
+	return out
+}
+
+func trashsimShips(ships *ogame.ShipsInfos, defenses *ogame.DefensesInfos) map[string]trashsimCount {
+	out := make(map[string]trashsimCount)
+	if ships != nil {
+		for _, ship := range ogame.Ships {
+			if nbr := ships.ByID(ship.GetID()); nbr > 0 {
+				out[strconv.FormatInt(int64(ship.GetID()), 10)] = trashsimCount{Count: nbr}
+			}
+		}
+	}
+	if defenses != nil {
+		for _, defense := range ogame.Defenses {
+			if nbr := defenses.ByID(defense.GetID()); nbr > 0 {
+				out[strconv.FormatInt(int64(defense.GetID()), 10)] = trashsimCount{Count: nbr}
+			}
+		}
+	}
+	return out
+}
+
+// BuildTrashsimURL returns a trashsim link prefilled with the attacker fleet and researches, the defender described
+// by the espionage report and the universe settings. The sections the report does not include are left empty.
+// To simulate with the report api key instead, use ogame.TrashsimURL.
+func BuildTrashsimURL(report ogame.EspionageReport, attacker ogame.ShipsInfos, techs ogame.Researches, serverData ServerData) string {
+	infos := report.Infos()
+	defender := trashsimParty{
+		Class:  report.CharacterClass,
+		Planet: &trashsimPlanet{Galaxy: report.Coordinate.Galaxy, System: report.Coordinate.System, Position: report.Coordinate.Position},
+		Ships:  trashsimShips(infos.ShipsInfos, infos.DefensesInfos),
+		Resources: &trashsimResources{
+			Metal:     trashsimAmount{Value: report.Metal},
+			Crystal:   trashsimAmount{Value: report.Crystal},
+			Deuterium: trashsimAmount{Value: report.Deuterium},
+		},
+	}
+	if infos.Researches != nil {
+		defender.Research = trashsimResearch(*infos.Researches)
+	}
+	prefill := trashsimPrefill{
+		Attackers: []trashsimParty{{Research: trashsimResearch(techs), Ships: trashsimShips(&attacker, nil)}},
+		Defenders: []trashsimParty{defender},
+		Settings: trashsimSettings{
+			SpeedFleetPeaceful:  serverData.SpeedFleetPeaceful,
+			SpeedFleetWar:       serverData.SpeedFleetWar,
+			SpeedFleetHolding:   serverData.SpeedFleetHolding,
+			Galaxies:            serverData.Galaxies,
+			Systems:             serverData.Systems,
+			RapidFire:           trashsimFlag(serverData.RapidFire),
+			DefToTF:             trashsimFlag(serverData.DefToTF),
+			DebrisFactor:        serverData.DebrisFactor,
+			DebrisFactorDef:     serverData.DebrisFactorDef,
+			RepairFactor:        serverData.RepairFactor,
+			DonutGalaxy:         trashsimFlag(serverData.DonutGalaxy),
+			DonutSystem:         trashsimFlag(serverData.DonutSystem),
+			DeuteriumSaveFactor: serverData.GlobalDeuteriumSaveFactor,
+		},
+	}
+	by, _ := json.Marshal(prefill)
+	lang := serverData.Language
+	if lang == "" {
+		lang = "en"
+	}
+	return trashsimBaseURL + lang + "#prefill=" + base64.StdEncoding.EncodeToString(by)
+}
@@ -1,6 +1,8 @@
 package wrapper
 
 import (
+	"time"
+
 	"github.com/alaingilbert/ogame/pkg/ogame"
 )
 
@@ -8,7 +10,17 @@ type Options struct {
 	DebugGalaxy     bool
 	SkipInterceptor bool
 	SkipRetry       bool
+	SkipHumanize    bool
+	SkipCache       bool
 	ChangePlanet    ogame.CelestialID // cp parameter
+	Concurrency     int64             // maximum parallel requests of bulk calls
+	Pacing          time.Duration     // minimum delay between two requests of bulk calls
+	MaxAge          time.Duration     // maximum age of a report lookup, 0 for any age
+	AutoSpyFrom     ogame.CelestialID // celestial sending probes when the report is missing or too old
+	AutoSpyProbes   int64
+	RefundRatio     float64 // part of the construction price given back by a tear down
+	Force           bool    // skip the safety checks of irreversible actions such as Abandon
+	MaxResources    *int64  // resources a planet can hold and still be abandoned, nil for DefaultAbandonMaxResources
 }
 
 // Option functions to be passed to public interface to change behaviors
@@ -29,9 +41,69 @@ func SkipRetry(opt *Options) {
 	opt.SkipRetry = true
 }
 
+// SkipHumanize option to skip the humanized delay of the request
+func SkipHumanize(opt *Options) {
+	opt.SkipHumanize = true
+}
+
+// SkipCache option to fetch the data from the game even if it is cached, used by the calls relying on the current state
+func SkipCache(opt *Options) {
+	opt.SkipCache = true
+}
+
 // ChangePlanet set the cp parameter
 func ChangePlanet(celestialID ogame.CelestialID) Option {
 	return func(opt *Options) {
 		opt.ChangePlanet = celestialID
 	}
 }
+
+// Concurrency set the maximum number of parallel requests of bulk calls such as GalaxyRange
+func Concurrency(n int64) Option {
+	return func(opt *Options) {
+		opt.Concurrency = n
+	}
+}
+
+// Pacing set the minimum delay between two requests of bulk calls such as GalaxyRange
+func Pacing(d time.Duration) Option {
+	return func(opt *Options) {
+		opt.Pacing = d
+	}
+}
+
+// MaxAge set the maximum age of the report returned by a report lookup such as GetEspionageReportFor,
+// older reports make the lookup fail with ogame.ErrReportTooOld
+func MaxAge(d time.Duration) Option {
+	return func(opt *Options) {
+		opt.MaxAge = d
+	}
+}
+
+// Force option to skip the safety checks of irreversible actions such as Abandon
+func Force(opt *Options) {
+	opt.Force = true
+}
+
+// AbandonMaxResources set the resources a planet can hold and still be abandoned, 0 to refuse any resources
+func AbandonMaxResources(v int64) Option {
+	return func(opt *Options) {
+		opt.MaxResources = &v
+	}
+}
+
+// RefundRatio set the part of the construction price of a level given back when it is torn down, used by TearDownPlan
+func RefundRatio(ratio float64) Option {
+	return func(opt *Options) {
+		opt.RefundRatio = ratio
+	}
+}
+
+// AutoSpy send probes from celestialID when a report lookup such as GetEspionageReportFor finds no report
+// or one older than MaxAge, and wait for the new report
+func AutoSpy(celestialID ogame.CelestialID, probes int64) Option {
+	return func(opt *Options) {
+		opt.AutoSpyFrom = celestialID
+		opt.AutoSpyProbes = probes
+	}
+}